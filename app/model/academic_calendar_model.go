@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// ===================== ACADEMIC SEMESTER ========================
+// Tabel: academic_semesters
+// Kalender akademik: rentang tanggal setiap semester, mis. "2025/2026 Ganjil".
+// Achievement masuk ke semester yang memuat tanggal kegiatannya (EventDate).
+// Rentang antar semester tidak boleh tumpang tindih.
+
+type AcademicSemester struct {
+	ID           string    `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`                   // "<academic_year> <term>"
+	AcademicYear string    `json:"academic_year" db:"academic_year"` // mis. "2025/2026"
+	Term         string    `json:"term" db:"term"`                   // lihat konstanta SemesterTerm*
+	StartDate    time.Time `json:"start_date" db:"start_date"`
+	EndDate      time.Time `json:"end_date" db:"end_date"` // inklusif
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Contains - Tanggal (tanpa jam) berada di dalam semester
+func (s *AcademicSemester) Contains(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return day >= s.StartDate.Format("2006-01-02") && day <= s.EndDate.Format("2006-01-02")
+}
+
+const (
+	SemesterTermGanjil = "Ganjil"
+	SemesterTermGenap  = "Genap"
+)
+
+// ===================== ACADEMIC SEMESTER REQUEST ========================
+
+type AcademicSemesterRequest struct {
+	AcademicYear string `json:"academic_year" validate:"required,len=9"`            // "2025/2026"
+	Term         string `json:"term" validate:"required,oneof=Ganjil Genap"`        // Ganjil / Genap
+	StartDate    string `json:"start_date" validate:"required,datetime=2006-01-02"` // YYYY-MM-DD
+	EndDate      string `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
@@ -12,25 +12,71 @@ import (
 
 type Achievement struct {
 	ID              primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
-	StudentID       string                 `bson:"studentId" json:"student_id"`
+	StudentID       string                 `bson:"studentId" json:"student_id"`             // pembuat (pemilik isi untuk prestasi tim)
+	Team            []TeamMember           `bson:"team,omitempty" json:"team,omitempty"`    // kosong = prestasi individu
 	AchievementType string                 `bson:"achievementType" json:"achievement_type"` // 'academic', 'competition', 'organization', 'publication', 'certification', 'other'
 	Title           string                 `bson:"title" json:"title"`
 	Description     string                 `bson:"description" json:"description"`
 	Details         map[string]interface{} `bson:"details" json:"details"` // Field dinamis
 	Attachments     []Attachment           `bson:"attachments" json:"attachments"`
 	Tags            []string               `bson:"tags" json:"tags"`
-	Points          int                    `bson:"points" json:"points"`
+	EventDate       time.Time              `bson:"eventDate" json:"event_date"`                            // tanggal kegiatan (menentukan periode & semester)
+	EventEndDate    *time.Time             `bson:"eventEndDate,omitempty" json:"event_end_date,omitempty"` // nil = kegiatan satu hari
+	Points          int                    `bson:"points" json:"points"`                                   // lama (diisi mahasiswa); poin resmi di AchievementReference.Points
 	CreatedAt       time.Time              `bson:"createdAt" json:"created_at"`
 	UpdatedAt       time.Time              `bson:"updatedAt" json:"updated_at"`
+	DeletedAt       *time.Time             `bson:"deletedAt,omitempty" json:"deleted_at,omitempty"` // di trash; dihapus permanen setelah masa retensi
+}
+
+// TeamMember - Anggota prestasi tim. Setiap anggota (termasuk pembuat) punya
+// achievement_references sendiri yang diverifikasi dosen walinya masing-masing.
+type TeamMember struct {
+	StudentID string `bson:"studentId" json:"student_id" validate:"required"`
+	Role      string `bson:"role" json:"role" validate:"required,max=50"` // mis. "ketua", "anggota"
 }
 
 type Attachment struct {
 	FileName   string    `bson:"fileName" json:"file_name"`
 	FileURL    string    `bson:"fileUrl" json:"file_url"`
 	FileType   string    `bson:"fileType" json:"file_type"`
+	SHA256     string    `bson:"sha256,omitempty" json:"sha256,omitempty"` // hash isi file (deteksi duplikat)
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploaded_at"`
 }
 
+// ===================== ACHIEVEMENT VERSION (MONGODB) ========================
+// Collection: achievement_versions
+// Snapshot isi achievement setiap kali disimpan (create, update, upload).
+// StatusHistoryID menunjuk transisi status terakhir saat snapshot dibuat,
+// sehingga perubahan setelah "revision requested" bisa dilacak.
+
+type AchievementVersion struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID   string             `bson:"achievementId" json:"achievement_id"` // _id di collection achievements
+	Version         int                `bson:"version" json:"version"`
+	Snapshot        Achievement        `bson:"snapshot" json:"snapshot"`
+	Status          string             `bson:"status" json:"status"`
+	StatusHistoryID *string            `bson:"statusHistoryId,omitempty" json:"status_history_id,omitempty"`
+	EditedBy        string             `bson:"editedBy" json:"edited_by"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"created_at"`
+}
+
+// AchievementDiff - Perbedaan field-level antara dua versi
+type AchievementDiff struct {
+	FromVersion        int           `json:"from_version"`
+	ToVersion          int           `json:"to_version"`
+	Fields             []FieldChange `json:"fields"`
+	TagsAdded          []string      `json:"tags_added"`
+	TagsRemoved        []string      `json:"tags_removed"`
+	AttachmentsAdded   []Attachment  `json:"attachments_added"`
+	AttachmentsRemoved []Attachment  `json:"attachments_removed"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"` // mis. "title", "details.competitionLevel"
+	Old   interface{} `json:"old"`   // nil = field baru
+	New   interface{} `json:"new"`   // nil = field dihapus
+}
+
 // ===================== ACHIEVEMENT REFERENCE (POSTGRESQL) ========================
 // Tabel: achievement_references
 // Link antara student dan achievement di MongoDB + status workflow
@@ -39,15 +85,94 @@ type AchievementReference struct {
 	ID                 string     `json:"id" db:"id"`
 	StudentID          string     `json:"student_id" db:"student_id"`
 	MongoAchievementID string     `json:"mongo_achievement_id" db:"mongo_achievement_id"`
-	Status             string     `json:"status" db:"status"` // 'draft', 'submitted', 'verified', 'rejected', 'deleted'
+	Status             string     `json:"status" db:"status"` // lihat konstanta AchievementStatus*
 	SubmittedAt        *time.Time `json:"submitted_at,omitempty" db:"submitted_at"`
 	VerifiedAt         *time.Time `json:"verified_at,omitempty" db:"verified_at"`
 	VerifiedBy         *string    `json:"verified_by,omitempty" db:"verified_by"`
+	OnBehalfOf         *string    `json:"on_behalf_of,omitempty" db:"on_behalf_of"` // dosen wali yang diwakili (delegasi)
 	RejectionNote      *string    `json:"rejection_note,omitempty" db:"rejection_note"`
+	PipelineID         *string    `json:"pipeline_id,omitempty" db:"pipeline_id"`           // nil = pipeline default (dosen wali saja)
+	CurrentStage       *int       `json:"current_stage,omitempty" db:"current_stage"`       // posisi stage (mulai 1) selama submitted
+	Points             *int       `json:"points,omitempty" db:"points"`                     // dari point rule saat submit, nil = belum disubmit
+	PointRuleID        *string    `json:"point_rule_id,omitempty" db:"point_rule_id"`       // nil = tidak ada rule yang cocok / disesuaikan verifikator
+	PointsFrozenAt     *time.Time `json:"points_frozen_at,omitempty" db:"points_frozen_at"` // diisi saat verified, poin tidak berubah lagi
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`             // diisi saat status deleted; hanya dibaca query trash (lihat TrashedAt)
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// AwardedPoints - Poin hasil rule / penyesuaian verifikator (0 jika belum disubmit)
+func (r *AchievementReference) AwardedPoints() int {
+	if r.Points == nil {
+		return 0
+	}
+	return *r.Points
+}
+
+// State - Status & stage reference sebelum diubah. Dipakai UpdateReference
+// untuk menolak transisi jika baris sudah diubah request lain.
+func (r *AchievementReference) State() ReferenceState {
+	state := ReferenceState{Status: r.Status}
+	if r.CurrentStage != nil {
+		stage := *r.CurrentStage
+		state.CurrentStage = &stage
+	}
+	return state
+}
+
+// ReferenceState - Status & stage yang diharapkan masih berlaku saat transisi ditulis
+type ReferenceState struct {
+	Status       string
+	CurrentStage *int
+}
+
+// TrashedAt - Waktu masuk trash. Data lama tanpa deleted_at memakai updated_at
+// (saat status diubah ke deleted).
+func (r *AchievementReference) TrashedAt() time.Time {
+	if r.DeletedAt == nil {
+		return r.UpdatedAt
+	}
+	return *r.DeletedAt
+}
+
+// TrashItem - Achievement di trash milik mahasiswa (GET /achievements/trash)
+type TrashItem struct {
+	ID              string    `json:"id"` // achievement_references.id (dipakai untuk restore)
+	Title           string    `json:"title"`
+	AchievementType string    `json:"achievement_type"`
+	IsTeam          bool      `json:"is_team"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	PurgeAt         time.Time `json:"purge_at"` // setelah ini dihapus permanen beserta file lampiran
+}
+
+// Status workflow achievement_references (transisi yang sah: service/achievement_workflow.go)
+const (
+	AchievementStatusDraft             = "draft"
+	AchievementStatusSubmitted         = "submitted"
+	AchievementStatusVerified          = "verified"
+	AchievementStatusRejected          = "rejected"
+	AchievementStatusRevisionRequested = "revision_requested"
+	AchievementStatusDeleted           = "deleted"
+)
+
+// ===================== ACHIEVEMENT STATUS HISTORY (POSTGRESQL) ========================
+// Tabel: achievement_status_history
+// Append-only, satu baris per transisi status. Ditulis dalam transaksi yang
+// sama dengan perubahan achievement_references.
+
+type AchievementStatusHistory struct {
+	ID                     string    `json:"id" db:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id" db:"achievement_reference_id"`
+	FromStatus             *string   `json:"from_status,omitempty" db:"from_status"` // nil = baru dibuat
+	ToStatus               string    `json:"to_status" db:"to_status"`
+	ActorID                *string   `json:"actor_id,omitempty" db:"actor_id"`
+	ActorRole              string    `json:"actor_role,omitempty" db:"actor_role"`
+	OnBehalfOf             *string   `json:"on_behalf_of,omitempty" db:"on_behalf_of"` // dosen wali yang diwakili (delegasi)
+	Note                   *string   `json:"note,omitempty" db:"note"`
+	Stage                  *int      `json:"stage,omitempty" db:"stage"` // stage pipeline tempat aksi verifikasi dilakukan
+	CreatedAt              time.Time `json:"created_at" db:"created_at"`
+}
+
 // ===================== CREATE ACHIEVEMENT REQUEST ========================
 
 type AchievementCreateRequest struct {
@@ -56,7 +181,9 @@ type AchievementCreateRequest struct {
 	Description     string                 `json:"description" validate:"required"`
 	Details         map[string]interface{} `json:"details"`
 	Tags            []string               `json:"tags"`
-	Points          int                    `json:"points"`
+	Team            []TeamMember           `json:"team" validate:"omitempty,max=20,dive"` // harus memuat pembuat; kosong = individu
+	EventDate       string                 `json:"event_date" validate:"required,datetime=2006-01-02"`
+	EventEndDate    string                 `json:"event_end_date" validate:"omitempty,datetime=2006-01-02"` // kegiatan lebih dari satu hari
 }
 
 // ===================== UPDATE ACHIEVEMENT REQUEST ========================
@@ -67,7 +194,9 @@ type AchievementUpdateRequest struct {
 	Description     string                 `json:"description,omitempty"`
 	Details         map[string]interface{} `json:"details,omitempty"`
 	Tags            []string               `json:"tags,omitempty"`
-	Points          int                    `json:"points,omitempty"`
+	Team            []TeamMember           `json:"team,omitempty" validate:"omitempty,max=20,dive"` // ganti seluruh daftar anggota
+	EventDate       string                 `json:"event_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EventEndDate    *string                `json:"event_end_date,omitempty"` // "" = hapus (kegiatan satu hari)
 }
 
 // ===================== VERIFY/REJECT REQUEST ========================
@@ -80,25 +209,54 @@ type RejectAchievementRequest struct {
 	RejectionNote string `json:"rejection_note" validate:"required"`
 }
 
+type RequestRevisionRequest struct {
+	Note string `json:"note" validate:"required"`
+}
+
+// BulkDecisionRequest - Verify / reject banyak achievement sekaligus.
+// Catatan per item (Notes[id]) menggantikan catatan bersama (Note).
+type BulkDecisionRequest struct {
+	IDs   []string          `json:"ids" validate:"required,min=1,max=100,dive,required"`
+	Note  string            `json:"note"`
+	Notes map[string]string `json:"notes"`
+}
+
+// NoteFor - Catatan untuk satu item ("" jika tidak ada)
+func (r *BulkDecisionRequest) NoteFor(id string) string {
+	if note := r.Notes[id]; note != "" {
+		return note
+	}
+	return r.Note
+}
+
 // ===================== ACHIEVEMENT RESPONSE ========================
 
 type AchievementResponse struct {
-	ID              string                 `json:"id"`
-	StudentID       string                 `json:"student_id"`
-	AchievementType string                 `json:"achievement_type"`
-	Title           string                 `json:"title"`
-	Description     string                 `json:"description"`
-	Details         map[string]interface{} `json:"details"`
-	Attachments     []Attachment           `json:"attachments"`
-	Tags            []string               `json:"tags"`
-	Points          int                    `json:"points"`
-	Status          string                 `json:"status"`
-	SubmittedAt     *string                `json:"submitted_at,omitempty"`
-	VerifiedAt      *string                `json:"verified_at,omitempty"`
-	VerifiedBy      *string                `json:"verified_by,omitempty"`
-	RejectionNote   *string                `json:"rejection_note,omitempty"`
-	CreatedAt       string                 `json:"created_at"`
-	UpdatedAt       string                 `json:"updated_at"`
+	ID                string                 `json:"id"`
+	StudentID         string                 `json:"student_id"`
+	AchievementType   string                 `json:"achievement_type"`
+	Title             string                 `json:"title"`
+	Description       string                 `json:"description"`
+	Details           map[string]interface{} `json:"details"`
+	Attachments       []Attachment           `json:"attachments"`
+	Tags              []string               `json:"tags"`
+	Team              []TeamMember           `json:"team,omitempty"`
+	OwnerID           *string                `json:"owner_id,omitempty"` // pembuat prestasi tim
+	EventDate         string                 `json:"event_date,omitempty"`
+	EventEndDate      *string                `json:"event_end_date,omitempty"`
+	Semester          *string                `json:"semester,omitempty"` // semester kalender akademik yang memuat event_date
+	Points            int                    `json:"points"`
+	Status            string                 `json:"status"`
+	SubmittedAt       *string                `json:"submitted_at,omitempty"`
+	VerifiedAt        *string                `json:"verified_at,omitempty"`
+	VerifiedBy        *string                `json:"verified_by,omitempty"`
+	OnBehalfOf        *string                `json:"on_behalf_of,omitempty"`
+	RejectionNote     *string                `json:"rejection_note,omitempty"`
+	PipelineID        *string                `json:"pipeline_id,omitempty"`
+	CurrentStage      *int                   `json:"current_stage,omitempty"`      // hanya saat submitted
+	PossibleDuplicate bool                   `json:"possible_duplicate,omitempty"` // hanya untuk verifikator
+	CreatedAt         string                 `json:"created_at"`
+	UpdatedAt         string                 `json:"updated_at"`
 }
 
 // ===================== ACHIEVEMENT LIST RESPONSE ========================
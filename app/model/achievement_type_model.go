@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ===================== ACHIEVEMENT TYPE ========================
+// Tabel: achievement_types
+// Registry jenis prestasi yang dikelola admin. DetailsSchema adalah JSON
+// Schema (subset, lihat utils.JSONSchema) untuk field Details achievement.
+// Jenis yang tidak aktif tidak bisa dipakai untuk achievement baru.
+
+type AchievementType struct {
+	Code          string          `json:"code" db:"code"` // mis. 'competition'
+	Name          string          `json:"name" db:"name"`
+	Description   string          `json:"description" db:"description"`
+	DetailsSchema json.RawMessage `json:"details_schema" db:"details_schema" swaggertype:"object"`
+	IsActive      bool            `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ===================== ACHIEVEMENT TYPE REQUEST ========================
+
+type AchievementTypeRequest struct {
+	Name          string          `json:"name" validate:"required"`
+	Description   string          `json:"description"`
+	DetailsSchema json.RawMessage `json:"details_schema" validate:"required" swaggertype:"object"`
+	IsActive      *bool           `json:"is_active"` // default true
+}
+
+type AchievementTypeCreateRequest struct {
+	Code string `json:"code" validate:"required,max=50"`
+	AchievementTypeRequest
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ===================== AUDIT LOG ENTITY ========================
+// Tabel: audit_logs
+// Catatan aksi penting (lockout, perubahan role/permission, dll)
+
+type AuditLog struct {
+	ID         string    `json:"id" db:"id"`
+	ActorID    *string   `json:"actor_id,omitempty" db:"actor_id"` // nil = aksi oleh sistem
+	Action     string    `json:"action" db:"action"`
+	TargetType string    `json:"target_type" db:"target_type"`
+	TargetID   string    `json:"target_id" db:"target_id"`
+	Details    string    `json:"details,omitempty" db:"details"` // JSON
+	IPAddress  string    `json:"ip_address,omitempty" db:"ip_address"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
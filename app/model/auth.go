@@ -1,12 +1,17 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 // ===================== LOGIN REQUEST =======================
 
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	Role     string `json:"role,omitempty"` // opsional: batasi sesi ke satu role
 }
 
 // ===================== REFRESH TOKEN REQUEST ================
@@ -19,9 +24,10 @@ type RefreshTokenRequest struct {
 // Menghasilkan token + data user
 
 type LoginResponse struct {
-	Token        string       `json:"token"`
-	RefreshToken string       `json:"refreshToken"`
-	User         UserResponse `json:"user"`
+	Token         string       `json:"token"`
+	RefreshToken  string       `json:"refreshToken"`
+	User          UserResponse `json:"user"`
+	RecoveryCodes []string     `json:"recovery_codes,omitempty"` // hanya saat enrollment MFA lewat login
 }
 
 // ===================== API RESPONSE WRAPPER ==================
@@ -37,10 +43,13 @@ type APIResponse struct {
 // ===================== JWT ACCESS TOKEN CLAIMS ===============
 
 type JWTClaims struct {
-	UserID      string   `json:"user_id"`
-	Username    string   `json:"username"`
-	Role        string   `json:"role"`
-	Permissions []string `json:"permissions"`
+	UserID      string              `json:"user_id"`
+	Username    string              `json:"username"`
+	Roles       []string            `json:"roles"`
+	RoleScopes  map[string][]string `json:"role_scopes,omitempty"` // role -> program studi; role tanpa entry tidak dibatasi
+	Permissions []string            `json:"permissions"`           // gabungan permission semua role di sesi
+	TokenType   string              `json:"typ"`
+	SessionID   string              `json:"sid,omitempty"` // family_id refresh token dari sesi ini
 
 	jwt.RegisteredClaims
 }
@@ -48,6 +57,66 @@ type JWTClaims struct {
 // ===================== JWT REFRESH TOKEN CLAIMS ==============
 
 type RefreshTokenClaims struct {
-	UserID string `json:"user_id"`
+	UserID    string `json:"user_id"`
+	TokenType string `json:"typ"`
+	SessionID string `json:"sid"`
+	Role      string `json:"role,omitempty"` // role yang dipilih saat login (kosong = semua role)
 	jwt.RegisteredClaims
 }
+
+// ===================== REFRESH TOKEN ENTITY ==================
+// Tabel: refresh_tokens
+// ID = jti dari refresh token, FamilyID = satu sesi login (dipakai untuk rotasi)
+
+type RefreshToken struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	FamilyID   string     `json:"family_id" db:"family_id"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty" db:"used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	ReplacedBy *string    `json:"replaced_by,omitempty" db:"replaced_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== TOKEN REVOCATION ENTITY ===============
+// Tabel: token_revocations
+// Kind "token": satu access token (jti) direvoke
+// Kind "user": semua access token user yang diterbitkan sebelum RevokedBefore
+
+type TokenRevocation struct {
+	ID            string     `json:"id" db:"id"`
+	Kind          string     `json:"kind" db:"kind"`
+	JTI           *string    `json:"jti,omitempty" db:"jti"`
+	UserID        *string    `json:"user_id,omitempty" db:"user_id"`
+	RevokedBefore *time.Time `json:"revoked_before,omitempty" db:"revoked_before"`
+	Reason        string     `json:"reason" db:"reason"`
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== LOGIN LOCKOUT ENTITY ==================
+// Tabel: login_lockouts
+// Satu baris per username atau per IP client
+
+type LoginLockout struct {
+	KeyType      string     `json:"key_type" db:"key_type"` // 'username', 'ip'
+	KeyValue     string     `json:"key_value" db:"key_value"`
+	FailedCount  int        `json:"failed_count" db:"failed_count"`
+	LockCount    int        `json:"lock_count" db:"lock_count"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty" db:"last_failed_at"`
+}
+
+// ===================== LOCKOUT STATUS RESPONSE ===============
+// GET /users/:id/lockout
+
+type LockoutStatusResponse struct {
+	UserID       string  `json:"user_id"`
+	Username     string  `json:"username"`
+	Locked       bool    `json:"locked"`
+	FailedCount  int     `json:"failed_count"`
+	LockCount    int     `json:"lock_count"`
+	LockedUntil  *string `json:"locked_until,omitempty"`
+	LastFailedAt *string `json:"last_failed_at,omitempty"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// ===================== ACHIEVEMENT COMMENT ========================
+// Tabel: achievement_comments
+// Diskusi mahasiswa <-> dosen wali / verifikator pada satu achievement.
+// Terikat ke achievement_references.id (bukan ke status), jadi tetap terlihat
+// setelah achievement dibuka kembali dan di-submit ulang. Komentar bisa
+// membalas komentar lain (ParentID) dan bisa di-anchor ke field atau
+// attachment tertentu.
+
+const (
+	CommentAnchorField      = "field"
+	CommentAnchorAttachment = "attachment"
+)
+
+type AchievementComment struct {
+	ID                     string     `json:"id" db:"id"`
+	AchievementReferenceID string     `json:"achievement_reference_id" db:"achievement_reference_id"`
+	ParentID               *string    `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID               *string    `json:"author_id,omitempty" db:"author_id"`
+	AuthorRole             string     `json:"author_role" db:"author_role"`
+	Body                   string     `json:"body" db:"body"`
+	AnchorType             *string    `json:"anchor_type,omitempty" db:"anchor_type"`     // field / attachment
+	AnchorRef              *string    `json:"anchor_ref,omitempty" db:"anchor_ref"`       // nama field atau file_url attachment
+	AchievementStatus      string     `json:"achievement_status" db:"achievement_status"` // status saat komentar dibuat
+	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
+	EditedAt               *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt              *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// IsAuthor - Komentar ditulis oleh userID
+func (c *AchievementComment) IsAuthor(userID string) bool {
+	return c.AuthorID != nil && *c.AuthorID == userID
+}
+
+// ===================== COMMENT REQUEST ========================
+
+type CommentCreateRequest struct {
+	Body       string  `json:"body" validate:"required,max=2000"`
+	ParentID   *string `json:"parent_id"`
+	AnchorType *string `json:"anchor_type" validate:"omitempty,oneof=field attachment"`
+	AnchorRef  *string `json:"anchor_ref" validate:"required_with=AnchorType"`
+}
+
+type CommentUpdateRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
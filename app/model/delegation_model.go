@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ===================== VERIFICATION DELEGATION ========================
+// Tabel: verification_delegations
+// Dosen wali (LecturerID) mendelegasikan hak verifikasi mahasiswa bimbingannya
+// ke dosen lain (DelegateID) selama [StartsAt, EndsAt). Delegasi berakhir
+// otomatis setelah EndsAt, atau lebih awal jika dicabut (RevokedAt).
+
+type VerificationDelegation struct {
+	ID         string     `json:"id" db:"id"`
+	LecturerID string     `json:"lecturer_id" db:"lecturer_id"`
+	DelegateID string     `json:"delegate_id" db:"delegate_id"`
+	StartsAt   time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt     time.Time  `json:"ends_at" db:"ends_at"`
+	CreatedBy  string     `json:"created_by" db:"created_by"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive - Delegasi berlaku pada waktu at
+func (d *VerificationDelegation) IsActive(at time.Time) bool {
+	return d.RevokedAt == nil && !at.Before(d.StartsAt) && at.Before(d.EndsAt)
+}
+
+// ===================== CREATE DELEGATION REQUEST ========================
+
+type DelegationCreateRequest struct {
+	DelegateID string    `json:"delegate_id" validate:"required"`
+	StartsAt   time.Time `json:"starts_at" validate:"required"`
+	EndsAt     time.Time `json:"ends_at" validate:"required"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// ===================== DUPLICATE FLAG ========================
+// Tabel: duplicate_flags
+// Hasil pengecekan duplikat saat submit: achievement (MongoDB) yang mirip
+// dengan achievement lain. Dihitung ulang setiap kali achievement disubmit.
+
+type DuplicateFlag struct {
+	ID                        string    `json:"id" db:"id"`
+	MongoAchievementID        string    `json:"mongo_achievement_id" db:"mongo_achievement_id"`                 // achievement yang disubmit
+	MatchedMongoAchievementID string    `json:"matched_mongo_achievement_id" db:"matched_mongo_achievement_id"` // achievement yang mirip
+	Reasons                   []string  `json:"reasons" db:"reasons"`                                           // lihat konstanta DuplicateReason*
+	TitleSimilarity           float64   `json:"title_similarity" db:"title_similarity"`                         // 0..1
+	CreatedAt                 time.Time `json:"created_at" db:"created_at"`
+}
+
+// Alasan achievement dianggap mungkin duplikat
+const (
+	DuplicateReasonAttachmentHash = "attachment_hash" // file lampiran identik (SHA-256)
+	DuplicateReasonSameEvent      = "same_event"      // nama & tanggal kegiatan di details sama
+	DuplicateReasonSimilarTitle   = "similar_title"   // judul (dinormalisasi) sangat mirip
+)
+
+// ===================== DUPLICATE MATCH RESPONSE ========================
+// Achievement yang cocok beserta reference-nya (link ke record terkait)
+
+type DuplicateMatch struct {
+	MongoAchievementID string                 `json:"mongo_achievement_id"`
+	Title              string                 `json:"title"`
+	AchievementType    string                 `json:"achievement_type"`
+	StudentID          string                 `json:"student_id"` // pembuat
+	Reasons            []string               `json:"reasons"`
+	TitleSimilarity    float64                `json:"title_similarity"`
+	References         []DuplicateMatchRecord `json:"references"`
+	FlaggedAt          time.Time              `json:"flagged_at"`
+}
+
+type DuplicateMatchRecord struct {
+	ID        string `json:"id"`
+	StudentID string `json:"student_id"`
+	Status    string `json:"status"`
+	Link      string `json:"link"` // /api/v1/achievements/:id
+}
+
+// ===================== DUPLICATE CLUSTER (ADMIN REPORT) ========================
+// Kelompok achievement yang saling terhubung lewat duplicate flag
+
+type DuplicateCluster struct {
+	Achievements []DuplicateMatch `json:"achievements"`
+	Flags        []DuplicateFlag  `json:"flags"`
+}
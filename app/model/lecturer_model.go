@@ -6,11 +6,12 @@ import "time"
 //  tabel "lecturers" di database
 
 type Lecturer struct {
-	ID         string    `json:"id" db:"id"`
-	UserID     string    `json:"user_id" db:"user_id"`
-	LecturerID string    `json:"lecturer_id" db:"lecturer_id"`
-	Department string    `json:"department" db:"department"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	LecturerID string     `json:"lecturer_id" db:"lecturer_id"`
+	Department string     `json:"department" db:"department"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty" db:"retired_at"` // diisi saat role Dosen Wali dicabut
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 }
 
 // ===================== LECTURER PROFILE DTO ===================
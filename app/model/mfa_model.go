@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ===================== USER MFA ==============================
+// Tabel: user_mfa (satu baris per user, Enabled=false selama enrollment belum dikonfirmasi)
+
+type UserMFA struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"`
+	Enabled      bool       `json:"enabled"`
+	LastUsedStep int64      `json:"-"` // mencegah kode TOTP yang sama dipakai dua kali
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ===================== JWT MFA PENDING CLAIMS ================
+// Token sementara setelah password benar, ditukar dengan token asli di /auth/mfa/verify
+
+type MFAPendingClaims struct {
+	UserID             string `json:"user_id"`
+	Username           string `json:"username"`
+	TokenType          string `json:"typ"`
+	EnrollmentRequired bool   `json:"enr,omitempty"`
+	Role               string `json:"role,omitempty"` // role yang dipilih saat login
+	jwt.RegisteredClaims
+}
+
+// ===================== MFA LOGIN CHALLENGE ===================
+// Response login jika user wajib / sudah mengaktifkan MFA
+
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfa_required"`
+	MFAToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+// ===================== MFA REQUESTS ==========================
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"` // kode TOTP atau recovery code
+}
+
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type MFAPolicyRequest struct {
+	MFARequired *bool `json:"mfa_required" validate:"required"`
+}
+
+// ===================== MFA RESPONSES =========================
+
+type MFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth://, di-render sebagai QR code
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool    `json:"enabled"`
+	Required               bool    `json:"required"`
+	EnabledAt              *string `json:"enabled_at,omitempty"`
+	RemainingRecoveryCodes int     `json:"remaining_recovery_codes"`
+}
+
+type MFAPolicyResponse struct {
+	RoleID      string `json:"role_id"`
+	RoleName    string `json:"role_name"`
+	MFARequired bool   `json:"mfa_required"`
+}
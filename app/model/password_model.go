@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// ===================== CHANGE PASSWORD REQUEST ===============
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ===================== FORGOT PASSWORD REQUEST ===============
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ===================== RESET PASSWORD REQUEST ================
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// ===================== PASSWORD RESET TOKEN ==================
+// Token asli hanya dikirim lewat email, yang disimpan hanya hash SHA-256
+
+type PasswordResetToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ===================== VERIFICATION PIPELINE ========================
+// Tabel: verification_pipelines, verification_pipeline_stages
+// Urutan stage verifikasi untuk jenis prestasi dan/atau tingkat kompetisi
+// tertentu (nil = semua). Achievement baru berstatus verified setelah stage
+// terakhir menyetujui.
+
+type VerificationPipeline struct {
+	ID               string          `json:"id" db:"id"`
+	Name             string          `json:"name" db:"name"`
+	AchievementType  *string         `json:"achievement_type,omitempty" db:"achievement_type"`
+	CompetitionLevel *string         `json:"competition_level,omitempty" db:"competition_level"` // details.competitionLevel
+	Stages           []PipelineStage `json:"stages"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// PipelineStage - Satu stage, dipegang role (mis. "Dosen Wali") atau admin
+// yang di-assign ke program studi tertentu (Role "Admin" + ProgramStudy)
+type PipelineStage struct {
+	ID           string  `json:"id" db:"id"`
+	PipelineID   string  `json:"pipeline_id" db:"pipeline_id"`
+	Position     int     `json:"position" db:"position"` // mulai 1
+	Name         string  `json:"name" db:"name"`
+	Role         string  `json:"role" db:"role"`
+	ProgramStudy *string `json:"program_study,omitempty" db:"program_study"`
+}
+
+// ===================== PIPELINE REQUEST ========================
+
+type PipelineRequest struct {
+	Name             string                 `json:"name" validate:"required"`
+	AchievementType  *string                `json:"achievement_type"`
+	CompetitionLevel *string                `json:"competition_level"`
+	Stages           []PipelineStageRequest `json:"stages" validate:"required,min=1,dive"`
+}
+
+type PipelineStageRequest struct {
+	Name         string  `json:"name" validate:"required"`
+	Role         string  `json:"role" validate:"required"`
+	ProgramStudy *string `json:"program_study"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// ===================== POINT RULE ========================
+// Tabel: point_rules
+// Poin prestasi dihitung dari rule, bukan diisi mahasiswa. Rule berlaku untuk
+// satu jenis prestasi; kondisi lain (tingkat kompetisi, rentang peringkat,
+// rentang jumlah anggota tim) nil = semua. Jika beberapa rule cocok, rule
+// dengan kondisi terbanyak yang dipakai.
+
+type PointRule struct {
+	ID               string    `json:"id" db:"id"`
+	Name             string    `json:"name" db:"name"`
+	AchievementType  string    `json:"achievement_type" db:"achievement_type"`
+	CompetitionLevel *string   `json:"competition_level,omitempty" db:"competition_level"` // details.competitionLevel
+	RankMin          *int      `json:"rank_min,omitempty" db:"rank_min"`                   // details.rank
+	RankMax          *int      `json:"rank_max,omitempty" db:"rank_max"`
+	TeamSizeMin      *int      `json:"team_size_min,omitempty" db:"team_size_min"` // details.teamSize (default 1)
+	TeamSizeMax      *int      `json:"team_size_max,omitempty" db:"team_size_max"`
+	Points           int       `json:"points" db:"points"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ===================== POINT ADJUSTMENT ========================
+// Tabel: point_adjustments
+// Perubahan poin oleh verifikator selama submitted, wajib dengan justifikasi
+
+type PointAdjustment struct {
+	ID                     string    `json:"id" db:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id" db:"achievement_reference_id"`
+	FromPoints             int       `json:"from_points" db:"from_points"`
+	ToPoints               int       `json:"to_points" db:"to_points"`
+	Justification          string    `json:"justification" db:"justification"`
+	AdjustedBy             string    `json:"adjusted_by" db:"adjusted_by"`
+	OnBehalfOf             *string   `json:"on_behalf_of,omitempty" db:"on_behalf_of"` // dosen wali yang diwakili (delegasi)
+	CreatedAt              time.Time `json:"created_at" db:"created_at"`
+}
+
+// ===================== POINT RULE REQUEST ========================
+
+type PointRuleRequest struct {
+	Name             string  `json:"name" validate:"required"`
+	AchievementType  string  `json:"achievement_type" validate:"required"`
+	CompetitionLevel *string `json:"competition_level"`
+	RankMin          *int    `json:"rank_min" validate:"omitempty,min=1"`
+	RankMax          *int    `json:"rank_max" validate:"omitempty,min=1"`
+	TeamSizeMin      *int    `json:"team_size_min" validate:"omitempty,min=1"`
+	TeamSizeMax      *int    `json:"team_size_max" validate:"omitempty,min=1"`
+	Points           int     `json:"points" validate:"min=0"`
+}
+
+// ===================== ADJUST POINTS REQUEST ========================
+
+type AdjustPointsRequest struct {
+	Points        *int   `json:"points" validate:"required,min=0"`
+	Justification string `json:"justification" validate:"required,min=10"`
+}
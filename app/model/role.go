@@ -6,11 +6,46 @@ type Role struct {
 	ID          string    `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"` 
+	MFARequired bool      `json:"mfa_required" db:"mfa_required"`
+	IsBuiltin   bool      `json:"is_builtin" db:"is_builtin"` // role bawaan (dipakai di kode), tidak bisa dihapus / di-rename
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// Hanya terisi dari user_roles (UserRepository.GetRoles): assignment dibatasi
+	// program studi / departemen ini. Kosong = tidak dibatasi.
+	ScopeProgramStudy string `json:"scope_program_study,omitempty" db:"-"`
 }
 
 type RoleResponse struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"` // ⭐ TAMBAHKAN INI JUGA (opsional)
-}
\ No newline at end of file
+}
+
+// ===================== ROLE DETAIL RESPONSE ==================
+
+type RoleDetailResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	MFARequired bool     `json:"mfa_required"`
+	IsBuiltin   bool     `json:"is_builtin"`
+	Permissions []string `json:"permissions"`
+	MemberCount int      `json:"member_count"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// ===================== ROLE REQUESTS =========================
+
+type RoleCreateRequest struct {
+	Name        string `json:"name" validate:"required,max=50"`
+	Description string `json:"description"`
+}
+
+type RoleUpdateRequest struct {
+	Name        string  `json:"name,omitempty" validate:"omitempty,max=50"`
+	Description *string `json:"description,omitempty"`
+}
+
+type RolePermissionsRequest struct {
+	Permissions []string `json:"permissions" validate:"required,min=1"` // nama permission, mis. "achievement:read"
+}
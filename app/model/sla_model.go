@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ===================== VERIFICATION SLA ========================
+// Tabel: verification_sla_notifications
+// Achievement yang terlalu lama berstatus submitted: dosen wali diingatkan
+// setelah batas SLA, admin program studi menerima eskalasi setelah batas
+// kedua. Satu notifikasi per level per submit (submitted_at), jadi submit
+// ulang memulai hitungan baru.
+
+const (
+	SLALevelReminder   = "reminder"
+	SLALevelEscalation = "escalation"
+)
+
+// OverdueAchievement - Reference submitted yang melewati SLA beserta data
+// mahasiswa dan dosen walinya
+type OverdueAchievement struct {
+	ReferenceID  string    `json:"id"`
+	StudentID    string    `json:"student_id"`
+	StudentName  string    `json:"student_name"`
+	ProgramStudy string    `json:"program_study"`
+	AdvisorID    *string   `json:"advisor_id,omitempty"`
+	AdvisorName  string    `json:"advisor_name,omitempty"`
+	AdvisorEmail string    `json:"-"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	PipelineID   *string   `json:"pipeline_id,omitempty"`
+	CurrentStage *int      `json:"current_stage,omitempty"`
+}
+
+type SLANotification struct {
+	ID                     string    `json:"id" db:"id"`
+	AchievementReferenceID string    `json:"achievement_reference_id" db:"achievement_reference_id"`
+	Level                  string    `json:"level" db:"level"`               // reminder / escalation
+	SubmittedAt            time.Time `json:"submitted_at" db:"submitted_at"` // submit yang diingatkan
+	Recipients             string    `json:"recipients" db:"recipients"`     // email, dipisah koma
+	SentAt                 time.Time `json:"sent_at" db:"sent_at"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// ===================== TAG ========================
+// Tabel: tags, tag_synonyms
+// Kosakata tag yang dikurasi admin. Setiap tag punya satu nama kanonik dan
+// sinonim ("AI" ← "ai", "Artificial Intelligence"). Nama dan sinonim unik
+// setelah dinormalisasi (lihat NormalizeTag) di seluruh kosakata.
+
+type Tag struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"` // nama kanonik yang disimpan di achievement
+	Synonyms  []string  `json:"synonyms"`       // tabel tag_synonyms
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizeTag - Bentuk pembanding tag: huruf kecil, spasi berlebih dibuang
+func NormalizeTag(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+// Variants - Nama dan sinonim yang sudah dinormalisasi
+func (t *Tag) Variants() []string {
+	variants := []string{NormalizeTag(t.Name)}
+	for _, synonym := range t.Synonyms {
+		variants = append(variants, NormalizeTag(synonym))
+	}
+	return variants
+}
+
+// TagUsage - Tag beserta jumlah achievement yang memakainya (nama atau
+// sinonimnya), dihitung dari MongoDB
+type TagUsage struct {
+	Tag
+	UsageCount int `json:"usage_count"`
+}
+
+// UnmanagedTag - Tag teks bebas di achievement yang belum ada di kosakata
+// (kandidat untuk dibuat atau di-merge admin)
+type UnmanagedTag struct {
+	Name       string   `json:"name"`     // bentuk yang paling sering dipakai
+	Variants   []string `json:"variants"` // semua penulisan dengan bentuk normal yang sama
+	UsageCount int      `json:"usage_count"`
+}
+
+// ===================== TAG REQUEST ========================
+
+type TagRequest struct {
+	Name     string   `json:"name" validate:"required,max=50"`
+	Synonyms []string `json:"synonyms" validate:"omitempty,dive,required,max=50"`
+}
+
+// TagMergeRequest - Sumber berupa nama / sinonim tag lain (tag itu ikut
+// di-merge) atau teks bebas yang dipakai achievement (jadi sinonim)
+type TagMergeRequest struct {
+	Sources []string `json:"sources" validate:"required,min=1,dive,required,max=50"`
+}
@@ -9,10 +9,8 @@ type User struct {
 	ID           string    `json:"id"`
 	Username     string    `json:"username"`
 	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` 
+	PasswordHash string    `json:"-"`
 	FullName     string    `json:"full_name"`
-	RoleID       string    `json:"role_id"`
-	Role         string    `json:"role"`
 	IsActive     bool      `json:"is_active"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
@@ -22,13 +20,14 @@ type User struct {
 //  POST /api/v1/users (Admin only)
 
 type UserCreateRequest struct {
-	Username       string                  `json:"username" validate:"required"`
-	Email          string                  `json:"email" validate:"required,email"`
-	Password       string                  `json:"password" validate:"required,min=8"`
-	FullName       string                  `json:"full_name" validate:"required"`
-	RoleName       string                  `json:"role_name" validate:"required"` // "Admin", "Mahasiswa", "Dosen Wali"
-	StudentProfile *StudentProfileRequest  `json:"student_profile,omitempty"`     
-	LecturerProfile *LecturerProfileRequest `json:"lecturer_profile,omitempty"`   
+	Username          string                  `json:"username" validate:"required"`
+	Email             string                  `json:"email" validate:"required,email"`
+	Password          string                  `json:"password" validate:"required,min=8"`
+	FullName          string                  `json:"full_name" validate:"required"`
+	RoleName          string                  `json:"role_name" validate:"required"` // "Admin", "Mahasiswa", "Dosen Wali"
+	ScopeProgramStudy string                  `json:"scope_program_study,omitempty"` // batasi role ke program studi / departemen (mis. admin prodi)
+	StudentProfile    *StudentProfileRequest  `json:"student_profile,omitempty"`
+	LecturerProfile   *LecturerProfileRequest `json:"lecturer_profile,omitempty"`
 }
 
 // ===================== USER UPDATE DTO =====================
@@ -40,27 +39,32 @@ type UserUpdateRequest struct {
 	IsActive *bool  `json:"is_active,omitempty"` // untuk activate/deactivate user
 }
 
-// ===================== ASSIGN ROLE REQUEST ===================
-// PUT /users/:id/role
+// ===================== ADD ROLE REQUEST ======================
+// POST /users/:id/roles
+// Profile diisi jika role baru butuh profile dan user belum punya (atau sudah di-retire)
 
-type AssignRoleRequest struct {
-	RoleName string `json:"role_name" validate:"required"`
+type AddRoleRequest struct {
+	RoleName          string                  `json:"role_name" validate:"required"`
+	ScopeProgramStudy string                  `json:"scope_program_study,omitempty"` // batasi role ke program studi / departemen
+	StudentProfile    *StudentProfileRequest  `json:"student_profile,omitempty"`
+	LecturerProfile   *LecturerProfileRequest `json:"lecturer_profile,omitempty"`
 }
 
 // ===================== USER RESPONSE =======================
 // Tanpa password dan lebih ringan
 
 type UserResponse struct {
-	ID              string            `json:"id"`
-	Username        string            `json:"username"`
-	Email           string            `json:"email"`
-	FullName        string            `json:"full_name"`
-	Role            string            `json:"role"`
-	IsActive        bool              `json:"is_active"`
-	CreatedAt       string            `json:"created_at"`
-	Permissions     []string          `json:"permissions,omitempty"` 
-	StudentProfile  *StudentResponse  `json:"student_profile,omitempty"`  // jika role = Mahasiswa
-	LecturerProfile *LecturerResponse `json:"lecturer_profile,omitempty"` // jika role = Dosen Wali
+	ID              string              `json:"id"`
+	Username        string              `json:"username"`
+	Email           string              `json:"email"`
+	FullName        string              `json:"full_name"`
+	Roles           []string            `json:"roles"`
+	RoleScopes      map[string][]string `json:"role_scopes,omitempty"` // role -> program studi, hanya untuk role yang dibatasi
+	IsActive        bool                `json:"is_active"`
+	CreatedAt       string              `json:"created_at"`
+	Permissions     []string            `json:"permissions,omitempty"`
+	StudentProfile  *StudentResponse    `json:"student_profile,omitempty"`  // jika role = Mahasiswa
+	LecturerProfile *LecturerResponse   `json:"lecturer_profile,omitempty"` // jika role = Dosen Wali
 }
 
 // ===================== USER LIST RESPONSE ====================
@@ -72,4 +76,4 @@ type UserListResponse struct {
 	Page       int            `json:"page"`
 	PageSize   int            `json:"page_size"`
 	TotalPages int            `json:"total_pages"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type AcademicCalendarRepository interface {
+	GetAll() ([]model.AcademicSemester, error)
+	FindByID(id string) (*model.AcademicSemester, error)
+	Create(semester *model.AcademicSemester) error
+	Update(semester *model.AcademicSemester) error
+	Delete(id string) error
+}
+
+type academicCalendarRepository struct {
+	db *sql.DB
+}
+
+func NewAcademicCalendarRepository(db *sql.DB) AcademicCalendarRepository {
+	return &academicCalendarRepository{db}
+}
+
+const academicSemesterSelect = `
+	SELECT id, name, academic_year, term, start_date, end_date, created_at, updated_at
+	FROM academic_semesters
+`
+
+// GetAll - Semua semester, urut tanggal mulai (tabel kecil; pemetaan
+// tanggal → semester dilakukan di service)
+func (r *academicCalendarRepository) GetAll() ([]model.AcademicSemester, error) {
+	rows, err := r.db.Query(academicSemesterSelect + ` ORDER BY start_date ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var semesters []model.AcademicSemester
+	for rows.Next() {
+		semester, err := scanAcademicSemester(rows)
+		if err != nil {
+			return nil, err
+		}
+		semesters = append(semesters, *semester)
+	}
+	return semesters, rows.Err()
+}
+
+// FindByID - Get semester by ID
+func (r *academicCalendarRepository) FindByID(id string) (*model.AcademicSemester, error) {
+	return scanAcademicSemester(r.db.QueryRow(academicSemesterSelect+` WHERE id = $1`, id))
+}
+
+// Create - Simpan semester baru
+func (r *academicCalendarRepository) Create(semester *model.AcademicSemester) error {
+	semester.CreatedAt = time.Now()
+	semester.UpdatedAt = semester.CreatedAt
+
+	query := `
+		INSERT INTO academic_semesters (name, academic_year, term, start_date, end_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		semester.Name,
+		semester.AcademicYear,
+		semester.Term,
+		semester.StartDate,
+		semester.EndDate,
+		semester.CreatedAt,
+		semester.UpdatedAt,
+	).Scan(&semester.ID)
+}
+
+// Update - Update seluruh kolom semester
+func (r *academicCalendarRepository) Update(semester *model.AcademicSemester) error {
+	semester.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE academic_semesters
+		SET name = $1, academic_year = $2, term = $3, start_date = $4, end_date = $5, updated_at = $6
+		WHERE id = $7
+	`
+	_, err := r.db.Exec(query,
+		semester.Name,
+		semester.AcademicYear,
+		semester.Term,
+		semester.StartDate,
+		semester.EndDate,
+		semester.UpdatedAt,
+		semester.ID,
+	)
+	return err
+}
+
+// Delete - Hapus semester (achievement tidak menyimpan semester, hanya tanggal kegiatan)
+func (r *academicCalendarRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM academic_semesters WHERE id = $1`, id)
+	return err
+}
+
+// Helper: scanAcademicSemester
+func scanAcademicSemester(row interface{ Scan(...interface{}) error }) (*model.AcademicSemester, error) {
+	var semester model.AcademicSemester
+	err := row.Scan(
+		&semester.ID,
+		&semester.Name,
+		&semester.AcademicYear,
+		&semester.Term,
+		&semester.StartDate,
+		&semester.EndDate,
+		&semester.CreatedAt,
+		&semester.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &semester, nil
+}
@@ -15,23 +15,42 @@ import (
 
 type AchievementRepository interface {
 	// PostgreSQL - Achievement References
-	CreateReference(ref *model.AchievementReference) error
-	UpdateReference(ref *model.AchievementReference) error
+	CreateReference(ref *model.AchievementReference, history *model.AchievementStatusHistory) error
+	CreateReferences(refs []*model.AchievementReference, history *model.AchievementStatusHistory) error
+	UpdateReference(ref *model.AchievementReference, from model.ReferenceState, history *model.AchievementStatusHistory) error
+	UpdateReferences(refs []*model.AchievementReference, status string, history *model.AchievementStatusHistory) error
 	GetReferenceByID(id string) (*model.AchievementReference, error)
 	GetReferenceByMongoID(mongoID string) (*model.AchievementReference, error)
+	GetReferencesByMongoID(mongoID string) ([]model.AchievementReference, error)
 	GetReferencesByStudentID(studentID string, status string, limit, offset int) ([]model.AchievementReference, error)
 	CountReferencesByStudentID(studentID string, status string) (int, error)
-	GetReferencesByAdvisorID(advisorID string, status string, limit, offset int) ([]model.AchievementReference, error)
-	CountReferencesByAdvisorID(advisorID string, status string) (int, error)
+	GetReferencesInScope(scope ReferenceScope, status string, limit, offset int) ([]model.AchievementReference, error)
+	CountReferencesInScope(scope ReferenceScope, status string) (int, error)
 	GetAllReferences(status string, limit, offset int) ([]model.AchievementReference, error)
 	CountAllReferences(status string) (int, error)
 
-	// MongoDB - Achievements
-	CreateAchievement(achievement *model.Achievement) (string, error)
-	UpdateAchievement(id string, achievement *model.Achievement) error
+	// PostgreSQL - Trash (reference berstatus deleted)
+	GetDeletedReferences(studentID string) ([]model.AchievementReference, error)
+	GetDeletedReferencesByMongoID(mongoID string) ([]model.AchievementReference, error)
+	GetReferencesDeletedBefore(cutoff time.Time) ([]model.AchievementReference, error)
+	PurgeReferences(mongoID string, referenceIDs []string) error
+
+	// PostgreSQL - Status History
+	GetStatusHistory(referenceID string) ([]model.AchievementStatusHistory, error)
+
+	// MongoDB - Achievements (setiap perubahan isi disimpan sebagai versi baru)
+	CreateAchievement(achievement *model.Achievement, version *model.AchievementVersion) (string, error)
+	UpdateAchievement(id string, achievement *model.Achievement, version *model.AchievementVersion) error
 	GetAchievementByID(id string) (*model.Achievement, error)
+	GetAchievementIDsByEventDate(from, to *time.Time) (map[string]bool, error)
+	SoftDeleteAchievement(id string, at time.Time) error
+	RestoreAchievement(id string) error
 	DeleteAchievement(id string) error
-	AddAttachment(achievementID string, attachment model.Attachment) error
+	AddAttachment(achievementID string, attachment model.Attachment, version *model.AchievementVersion) error
+
+	// MongoDB - Achievement Versions
+	GetVersions(achievementID string) ([]model.AchievementVersion, error)
+	GetVersion(achievementID string, version int) (*model.AchievementVersion, error)
 }
 
 type achievementRepository struct {
@@ -50,18 +69,53 @@ func NewAchievementRepository(pgDB *sql.DB, mongoDB *mongo.Database) Achievement
 // ==================== POSTGRESQL METHODS (REFERENCES) ======================
 //
 
-// CreateReference - Insert reference baru
-func (r *achievementRepository) CreateReference(ref *model.AchievementReference) error {
+// CreateReference - Insert reference baru beserta history status awal (satu transaksi)
+func (r *achievementRepository) CreateReference(ref *model.AchievementReference, history *model.AchievementStatusHistory) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.insertReference(tx, ref, history); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateReferences - Insert reference seluruh anggota prestasi tim dalam satu
+// transaksi. history dipakai sebagai template (disalin per reference).
+func (r *achievementRepository) CreateReferences(refs []*model.AchievementReference, history *model.AchievementStatusHistory) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, ref := range refs {
+		var entry *model.AchievementStatusHistory
+		if history != nil {
+			copied := *history
+			entry = &copied
+		}
+		if err := r.insertReference(tx, ref, entry); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *achievementRepository) insertReference(tx *sql.Tx, ref *model.AchievementReference, history *model.AchievementStatusHistory) error {
 	ref.ID = uuid.New().String()
 	ref.CreatedAt = time.Now()
 	ref.UpdatedAt = time.Now()
 
 	query := `
 		INSERT INTO achievement_references 
-		(id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		(id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
-	_, err := r.pgDB.Exec(query,
+	_, err := tx.Exec(query,
 		ref.ID,
 		ref.StudentID,
 		ref.MongoAchievementID,
@@ -69,39 +123,203 @@ func (r *achievementRepository) CreateReference(ref *model.AchievementReference)
 		ref.SubmittedAt,
 		ref.VerifiedAt,
 		ref.VerifiedBy,
+		ref.OnBehalfOf,
 		ref.RejectionNote,
+		ref.PipelineID,
+		ref.CurrentStage,
+		ref.Points,
+		ref.PointRuleID,
+		ref.PointsFrozenAt,
 		ref.CreatedAt,
 		ref.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if history != nil {
+		history.FromStatus = nil
+		return r.insertStatusHistory(tx, ref, history)
+	}
+	return nil
 }
 
-// UpdateReference - Update reference
-func (r *achievementRepository) UpdateReference(ref *model.AchievementReference) error {
+// UpdateReference - Update reference. Status & current_stage lama dikunci
+// (SELECT ... FOR UPDATE) dan harus sama dengan from (state saat transisi
+// diperiksa service); jika sudah diubah request lain, misalnya stage yang sama
+// disetujui dua kali, return sql.ErrNoRows tanpa menulis apa pun. Poin hanya
+// ditulis saat submit; transisi lain membiarkan kolom poin apa adanya
+// (penyesuaian verifikator tidak tertimpa dan ikut dibekukan saat verified)
+// dan nilainya dibaca ulang lewat RETURNING. Jika history diisi, transisi
+// dicatat dalam transaksi yang sama.
+func (r *achievementRepository) UpdateReference(ref *model.AchievementReference, from model.ReferenceState, history *model.AchievementStatusHistory) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.updateReference(tx, ref, from, history); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateReferences - Pindahkan beberapa reference (mis. semua anggota tim saat
+// hapus / restore) ke status dalam satu transaksi. State awal tiap reference
+// diperiksa seperti UpdateReference; jika salah satu gagal, tidak ada yang
+// berubah. History disalin untuk setiap reference.
+func (r *achievementRepository) UpdateReferences(refs []*model.AchievementReference, status string, history *model.AchievementStatusHistory) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, ref := range refs {
+		from := ref.State()
+		ref.Status = status
+
+		var entry *model.AchievementStatusHistory
+		if history != nil {
+			copied := *history
+			entry = &copied
+		}
+		if err := r.updateReference(tx, ref, from, entry); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *achievementRepository) updateReference(tx *sql.Tx, ref *model.AchievementReference, from model.ReferenceState, history *model.AchievementStatusHistory) error {
 	ref.UpdatedAt = time.Now()
 
+	var lockedStatus string
+	var lockedStage *int
+	err := tx.QueryRow(`SELECT status, current_stage FROM achievement_references WHERE id = $1 FOR UPDATE`, ref.ID).
+		Scan(&lockedStatus, &lockedStage)
+	if err != nil {
+		return err
+	}
+	if lockedStatus != from.Status || !sameStage(lockedStage, from.CurrentStage) {
+		return sql.ErrNoRows
+	}
+
+	submitted := ref.Status == model.AchievementStatusSubmitted && from.Status != model.AchievementStatusSubmitted
+
+	// deleted_at mengikuti status: diisi saat masuk trash, dikosongkan saat restore
 	query := `
 		UPDATE achievement_references
-		SET status = $1, submitted_at = $2, verified_at = $3, verified_by = $4, rejection_note = $5, updated_at = $6
-		WHERE id = $7
+		SET status = $1, submitted_at = $2, verified_at = $3, verified_by = $4, on_behalf_of = $5, rejection_note = $6,
+			pipeline_id = $7, current_stage = $8, points_frozen_at = $9, updated_at = $10,
+			points = CASE WHEN $11 THEN $12 ELSE points END,
+			point_rule_id = CASE WHEN $11 THEN $13 ELSE point_rule_id END,
+			deleted_at = CASE WHEN $1 = 'deleted' THEN COALESCE(deleted_at, $10) ELSE NULL END
+		WHERE id = $14
+		RETURNING points, point_rule_id
 	`
-	_, err := r.pgDB.Exec(query,
+	err = tx.QueryRow(query,
 		ref.Status,
 		ref.SubmittedAt,
 		ref.VerifiedAt,
 		ref.VerifiedBy,
+		ref.OnBehalfOf,
 		ref.RejectionNote,
+		ref.PipelineID,
+		ref.CurrentStage,
+		ref.PointsFrozenAt,
 		ref.UpdatedAt,
+		submitted,
+		ref.Points,
+		ref.PointRuleID,
 		ref.ID,
-	)
-	return err
+	).Scan(&ref.Points, &ref.PointRuleID)
+	if err != nil {
+		return err
+	}
+
+	if history != nil {
+		history.FromStatus = &lockedStatus
+		return r.insertStatusHistory(tx, ref, history)
+	}
+	return nil
+}
+
+// sameStage - Bandingkan current_stage (nil = belum/tidak di pipeline)
+func sameStage(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// insertStatusHistory - Tulis satu baris history (to_status = status reference saat ini)
+func (r *achievementRepository) insertStatusHistory(tx *sql.Tx, ref *model.AchievementReference, history *model.AchievementStatusHistory) error {
+	history.AchievementReferenceID = ref.ID
+	history.ToStatus = ref.Status
+	history.CreatedAt = ref.UpdatedAt
+
+	query := `
+		INSERT INTO achievement_status_history
+		(achievement_reference_id, from_status, to_status, actor_id, actor_role, on_behalf_of, note, stage, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	return tx.QueryRow(query,
+		history.AchievementReferenceID,
+		history.FromStatus,
+		history.ToStatus,
+		history.ActorID,
+		history.ActorRole,
+		history.OnBehalfOf,
+		history.Note,
+		history.Stage,
+		history.CreatedAt,
+	).Scan(&history.ID)
+}
+
+// GetStatusHistory - Semua transisi status satu reference (terlama dulu)
+func (r *achievementRepository) GetStatusHistory(referenceID string) ([]model.AchievementStatusHistory, error) {
+	query := `
+		SELECT id, achievement_reference_id, from_status, to_status, actor_id, COALESCE(actor_role, ''), on_behalf_of, note, stage, created_at
+		FROM achievement_status_history
+		WHERE achievement_reference_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := r.pgDB.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.AchievementStatusHistory
+	for rows.Next() {
+		var h model.AchievementStatusHistory
+		if err := rows.Scan(
+			&h.ID,
+			&h.AchievementReferenceID,
+			&h.FromStatus,
+			&h.ToStatus,
+			&h.ActorID,
+			&h.ActorRole,
+			&h.OnBehalfOf,
+			&h.Note,
+			&h.Stage,
+			&h.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, h)
+	}
+	return entries, rows.Err()
 }
 
 // GetReferenceByID - Get reference by ID
 func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementReference, error) {
 	ref := &model.AchievementReference{}
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 		FROM achievement_references
 		WHERE id = $1
 	`
@@ -113,7 +331,13 @@ func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementR
 		&ref.SubmittedAt,
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
+		&ref.OnBehalfOf,
 		&ref.RejectionNote,
+		&ref.PipelineID,
+		&ref.CurrentStage,
+		&ref.Points,
+		&ref.PointRuleID,
+		&ref.PointsFrozenAt,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -127,7 +351,7 @@ func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementR
 func (r *achievementRepository) GetReferenceByMongoID(mongoID string) (*model.AchievementReference, error) {
 	ref := &model.AchievementReference{}
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = $1
 	`
@@ -139,7 +363,13 @@ func (r *achievementRepository) GetReferenceByMongoID(mongoID string) (*model.Ac
 		&ref.SubmittedAt,
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
+		&ref.OnBehalfOf,
 		&ref.RejectionNote,
+		&ref.PipelineID,
+		&ref.CurrentStage,
+		&ref.Points,
+		&ref.PointRuleID,
+		&ref.PointsFrozenAt,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -149,6 +379,23 @@ func (r *achievementRepository) GetReferenceByMongoID(mongoID string) (*model.Ac
 	return ref, nil
 }
 
+// GetReferencesByMongoID - Reference semua anggota satu achievement (prestasi
+// tim), tanpa yang sudah dihapus, urut dari yang dibuat pertama
+func (r *achievementRepository) GetReferencesByMongoID(mongoID string) ([]model.AchievementReference, error) {
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1 AND status <> 'deleted'
+		ORDER BY created_at ASC
+	`
+	rows, err := r.pgDB.Query(query, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanReferences(rows)
+}
+
 // GetReferencesByStudentID - Get references by student_id dengan filter status
 func (r *achievementRepository) GetReferencesByStudentID(studentID string, status string, limit, offset int) ([]model.AchievementReference, error) {
 	var query string
@@ -157,7 +404,7 @@ func (r *achievementRepository) GetReferencesByStudentID(studentID string, statu
 
 	if status != "" {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 			FROM achievement_references
 			WHERE student_id = $1 AND status = $2 AND status != 'deleted'
 			ORDER BY created_at DESC
@@ -166,7 +413,7 @@ func (r *achievementRepository) GetReferencesByStudentID(studentID string, statu
 		rows, err = r.pgDB.Query(query, studentID, status, limit, offset)
 	} else {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 			FROM achievement_references
 			WHERE student_id = $1 AND status != 'deleted'
 			ORDER BY created_at DESC
@@ -199,32 +446,54 @@ func (r *achievementRepository) CountReferencesByStudentID(studentID string, sta
 	return count, err
 }
 
-// GetReferencesByAdvisorID - Get achievements dari mahasiswa bimbingan (untuk Dosen Wali)
-func (r *achievementRepository) GetReferencesByAdvisorID(advisorID string, status string, limit, offset int) ([]model.AchievementReference, error) {
+// ReferenceScope - Batas akses list reference. Reference termasuk jika cocok
+// dengan salah satu kondisi (OR); semua kosong = tidak ada reference.
+type ReferenceScope struct {
+	StudentIDs     []string // mahasiswa pemilik
+	AdvisorIDs     []string // dosen wali mahasiswa (termasuk yang mendelegasikan)
+	ProgramStudies []string // program studi mahasiswa
+}
+
+// args - Parameter ANY() untuk scope (slice kosong, bukan NULL)
+func (s ReferenceScope) args() []interface{} {
+	args := []interface{}{}
+	for _, values := range [][]string{s.StudentIDs, s.AdvisorIDs, s.ProgramStudies} {
+		if values == nil {
+			values = []string{}
+		}
+		args = append(args, values)
+	}
+	return args
+}
+
+const referenceScopeFrom = `
+	FROM achievement_references ar
+	JOIN students s ON ar.student_id = s.id
+	WHERE (ar.student_id = ANY($1) OR s.advisor_id = ANY($2) OR s.program_study = ANY($3))
+		AND ar.status != 'deleted'
+`
+
+// GetReferencesInScope - Get references dalam scope akses user (gabungan semua role)
+func (r *achievementRepository) GetReferencesInScope(scope ReferenceScope, status string, limit, offset int) ([]model.AchievementReference, error) {
 	var query string
 	var rows *sql.Rows
 	var err error
 
+	selectColumns := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.on_behalf_of, ar.rejection_note, ar.pipeline_id, ar.current_stage, ar.points, ar.point_rule_id, ar.points_frozen_at, ar.created_at, ar.updated_at
+	`
 	if status != "" {
-		query = `
-			SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status = $2 AND ar.status != 'deleted'
+		query = selectColumns + referenceScopeFrom + ` AND ar.status = $4
 			ORDER BY ar.created_at DESC
-			LIMIT $3 OFFSET $4
+			LIMIT $5 OFFSET $6
 		`
-		rows, err = r.pgDB.Query(query, advisorID, status, limit, offset)
+		rows, err = r.pgDB.Query(query, append(scope.args(), status, limit, offset)...)
 	} else {
-		query = `
-			SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status != 'deleted'
+		query = selectColumns + referenceScopeFrom + `
 			ORDER BY ar.created_at DESC
-			LIMIT $2 OFFSET $3
+			LIMIT $4 OFFSET $5
 		`
-		rows, err = r.pgDB.Query(query, advisorID, limit, offset)
+		rows, err = r.pgDB.Query(query, append(scope.args(), limit, offset)...)
 	}
 
 	if err != nil {
@@ -235,29 +504,18 @@ func (r *achievementRepository) GetReferencesByAdvisorID(advisorID string, statu
 	return r.scanReferences(rows)
 }
 
-// CountReferencesByAdvisorID - Count achievements dari mahasiswa bimbingan
-func (r *achievementRepository) CountReferencesByAdvisorID(advisorID string, status string) (int, error) {
+// CountReferencesInScope - Count references dalam scope akses user
+func (r *achievementRepository) CountReferencesInScope(scope ReferenceScope, status string) (int, error) {
 	var count int
-	var query string
 
 	if status != "" {
-		query = `
-			SELECT COUNT(*)
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status = $2 AND ar.status != 'deleted'
-		`
-		err := r.pgDB.QueryRow(query, advisorID, status).Scan(&count)
+		query := `SELECT COUNT(*)` + referenceScopeFrom + ` AND ar.status = $4`
+		err := r.pgDB.QueryRow(query, append(scope.args(), status)...).Scan(&count)
 		return count, err
 	}
 
-	query = `
-		SELECT COUNT(*)
-		FROM achievement_references ar
-		JOIN students s ON ar.student_id = s.id
-		WHERE s.advisor_id = $1 AND ar.status != 'deleted'
-	`
-	err := r.pgDB.QueryRow(query, advisorID).Scan(&count)
+	query := `SELECT COUNT(*)` + referenceScopeFrom
+	err := r.pgDB.QueryRow(query, scope.args()...).Scan(&count)
 	return count, err
 }
 
@@ -269,7 +527,7 @@ func (r *achievementRepository) GetAllReferences(status string, limit, offset in
 
 	if status != "" {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 			FROM achievement_references
 			WHERE status = $1 AND status != 'deleted'
 			ORDER BY created_at DESC
@@ -278,7 +536,7 @@ func (r *achievementRepository) GetAllReferences(status string, limit, offset in
 		rows, err = r.pgDB.Query(query, status, limit, offset)
 	} else {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, created_at, updated_at
 			FROM achievement_references
 			WHERE status != 'deleted'
 			ORDER BY created_at DESC
@@ -312,6 +570,118 @@ func (r *achievementRepository) CountAllReferences(status string) (int, error) {
 }
 
 // Helper: scanReferences
+//
+// ==================== POSTGRESQL METHODS (TRASH) ======================
+//
+
+const deletedReferenceSelect = `
+	SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, on_behalf_of, rejection_note, pipeline_id, current_stage, points, point_rule_id, points_frozen_at, deleted_at, created_at, updated_at
+	FROM achievement_references
+`
+
+// GetDeletedReferences - Isi trash mahasiswa, terbaru dihapus dulu
+func (r *achievementRepository) GetDeletedReferences(studentID string) ([]model.AchievementReference, error) {
+	rows, err := r.pgDB.Query(deletedReferenceSelect+`
+		WHERE student_id = $1 AND status = 'deleted'
+		ORDER BY deleted_at DESC NULLS LAST
+	`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDeletedReferences(rows)
+}
+
+// GetDeletedReferencesByMongoID - Reference terhapus satu achievement, termasuk
+// anggota tim yang dikeluarkan sebelumnya (terbaru dihapus dulu)
+func (r *achievementRepository) GetDeletedReferencesByMongoID(mongoID string) ([]model.AchievementReference, error) {
+	rows, err := r.pgDB.Query(deletedReferenceSelect+`
+		WHERE mongo_achievement_id = $1 AND status = 'deleted'
+		ORDER BY COALESCE(deleted_at, updated_at) DESC
+	`, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDeletedReferences(rows)
+}
+
+// GetReferencesDeletedBefore - Reference di trash yang masa retensinya habis.
+// Data lama tanpa deleted_at memakai updated_at (saat status diubah ke deleted).
+func (r *achievementRepository) GetReferencesDeletedBefore(cutoff time.Time) ([]model.AchievementReference, error) {
+	rows, err := r.pgDB.Query(deletedReferenceSelect+`
+		WHERE status = 'deleted' AND COALESCE(deleted_at, updated_at) < $1
+		ORDER BY mongo_achievement_id ASC
+	`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDeletedReferences(rows)
+}
+
+// PurgeReferences - Hapus permanen reference terhapus milik satu achievement
+// (history, komentar, dll ikut terhapus lewat ON DELETE CASCADE). Jika
+// achievement tidak punya reference lagi, flag duplikatnya ikut dihapus
+// (satu transaksi).
+func (r *achievementRepository) PurgeReferences(mongoID string, referenceIDs []string) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM achievement_references
+		WHERE mongo_achievement_id = $1 AND id = ANY($2) AND status = 'deleted'
+	`
+	if _, err := tx.Exec(query, mongoID, referenceIDs); err != nil {
+		return err
+	}
+
+	query = `
+		DELETE FROM duplicate_flags
+		WHERE (mongo_achievement_id = $1 OR matched_mongo_achievement_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM achievement_references WHERE mongo_achievement_id = $1)
+	`
+	if _, err := tx.Exec(query, mongoID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Helper: scanDeletedReferences
+func scanDeletedReferences(rows *sql.Rows) ([]model.AchievementReference, error) {
+	var refs []model.AchievementReference
+	for rows.Next() {
+		var ref model.AchievementReference
+		err := rows.Scan(
+			&ref.ID,
+			&ref.StudentID,
+			&ref.MongoAchievementID,
+			&ref.Status,
+			&ref.SubmittedAt,
+			&ref.VerifiedAt,
+			&ref.VerifiedBy,
+			&ref.OnBehalfOf,
+			&ref.RejectionNote,
+			&ref.PipelineID,
+			&ref.CurrentStage,
+			&ref.Points,
+			&ref.PointRuleID,
+			&ref.PointsFrozenAt,
+			&ref.DeletedAt,
+			&ref.CreatedAt,
+			&ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
 func (r *achievementRepository) scanReferences(rows *sql.Rows) ([]model.AchievementReference, error) {
 	var refs []model.AchievementReference
 	for rows.Next() {
@@ -324,7 +694,13 @@ func (r *achievementRepository) scanReferences(rows *sql.Rows) ([]model.Achievem
 			&ref.SubmittedAt,
 			&ref.VerifiedAt,
 			&ref.VerifiedBy,
+			&ref.OnBehalfOf,
 			&ref.RejectionNote,
+			&ref.PipelineID,
+			&ref.CurrentStage,
+			&ref.Points,
+			&ref.PointRuleID,
+			&ref.PointsFrozenAt,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
@@ -340,8 +716,8 @@ func (r *achievementRepository) scanReferences(rows *sql.Rows) ([]model.Achievem
 // ==================== MONGODB METHODS (ACHIEVEMENTS) ======================
 //
 
-// CreateAchievement - Insert achievement ke MongoDB
-func (r *achievementRepository) CreateAchievement(achievement *model.Achievement) (string, error) {
+// CreateAchievement - Insert achievement ke MongoDB beserta versi pertama
+func (r *achievementRepository) CreateAchievement(achievement *model.Achievement, version *model.AchievementVersion) (string, error) {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -356,11 +732,69 @@ func (r *achievementRepository) CreateAchievement(achievement *model.Achievement
 
 	// Return ObjectID as string
 	objectID := result.InsertedID.(primitive.ObjectID)
+	achievement.ID = objectID
+
+	if err := r.insertVersion(ctx, objectID.Hex(), achievement, version); err != nil {
+		return "", err
+	}
 	return objectID.Hex(), nil
 }
 
-// UpdateAchievement - Update achievement di MongoDB
-func (r *achievementRepository) UpdateAchievement(id string, achievement *model.Achievement) error {
+// GetAchievementIDsByEventDate - ID achievement yang tanggal kegiatannya
+// (eventDate) berada di [from, to], keduanya inklusif; nil = tanpa batas.
+// Data lama tanpa eventDate memakai createdAt, sama seperti laporan
+// (service.eventDateOf).
+func (r *achievementRepository) GetAchievementIDsByEventDate(from, to *time.Time) (map[string]bool, error) {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dateRange := bson.M{}
+	if from != nil {
+		dateRange["$gte"] = *from
+	}
+	if to != nil {
+		dateRange["$lt"] = to.AddDate(0, 0, 1)
+	}
+
+	// eventDate kosong: field tidak ada, null, atau zero time (dokumen lama
+	// yang disimpan ulang lewat UpdateAchievement)
+	noEventDate := bson.A{nil, time.Time{}}
+
+	withEventDate := bson.M{"$nin": noEventDate}
+	for op, value := range dateRange {
+		withEventDate[op] = value
+	}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"eventDate": withEventDate},
+		bson.M{"eventDate": bson.M{"$in": noEventDate}, "createdAt": dateRange},
+	}}
+	if len(dateRange) == 0 {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids[doc.ID.Hex()] = true
+	}
+	return ids, cursor.Err()
+}
+
+// UpdateAchievement - Update achievement di MongoDB dan simpan snapshot sebagai versi baru
+func (r *achievementRepository) UpdateAchievement(id string, achievement *model.Achievement, version *model.AchievementVersion) error {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -375,8 +809,22 @@ func (r *achievementRepository) UpdateAchievement(id string, achievement *model.
 	filter := bson.M{"_id": objectID}
 	update := bson.M{"$set": achievement}
 
-	_, err = collection.UpdateOne(ctx, filter, update)
-	return err
+	// Field omitempty yang dikosongkan tidak ikut $set, jadi dihapus eksplisit
+	unset := bson.M{}
+	if len(achievement.Team) == 0 {
+		unset["team"] = ""
+	}
+	if achievement.EventEndDate == nil {
+		unset["eventEndDate"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if _, err = collection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+	return r.insertVersion(ctx, id, achievement, version)
 }
 
 // GetAchievementByID - Get achievement dari MongoDB
@@ -400,8 +848,8 @@ func (r *achievementRepository) GetAchievementByID(id string) (*model.Achievemen
 	return &achievement, nil
 }
 
-// DeleteAchievement - Soft delete (optional, bisa juga hard delete)
-func (r *achievementRepository) DeleteAchievement(id string) error {
+// SoftDeleteAchievement - Tandai achievement masuk trash (dokumen & versi tetap ada)
+func (r *achievementRepository) SoftDeleteAchievement(id string, at time.Time) error {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -412,12 +860,46 @@ func (r *achievementRepository) DeleteAchievement(id string) error {
 	}
 
 	filter := bson.M{"_id": objectID}
-	_, err = collection.DeleteOne(ctx, filter)
+	_, err = collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deletedAt": at}})
 	return err
 }
 
-// AddAttachment - Tambah attachment ke achievement
-func (r *achievementRepository) AddAttachment(achievementID string, attachment model.Attachment) error {
+// RestoreAchievement - Keluarkan achievement dari trash
+func (r *achievementRepository) RestoreAchievement(id string) error {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objectID}
+	_, err = collection.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"deletedAt": ""}})
+	return err
+}
+
+// DeleteAchievement - Hard delete dokumen beserta seluruh versinya (rollback
+// create yang gagal, purge trash). Dokumen yang sudah tidak ada bukan error.
+func (r *achievementRepository) DeleteAchievement(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.mongoDB.Collection("achievement_versions").DeleteMany(ctx, bson.M{"achievementId": id}); err != nil {
+		return err
+	}
+	_, err = r.mongoDB.Collection("achievements").DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// AddAttachment - Tambah attachment ke achievement dan simpan snapshot sebagai versi baru
+func (r *achievementRepository) AddAttachment(achievementID string, attachment model.Attachment, version *model.AchievementVersion) error {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -435,6 +917,104 @@ func (r *achievementRepository) AddAttachment(achievementID string, attachment m
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
 
-	_, err = collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(false))
-	return err
+	// Ambil dokumen setelah update untuk snapshot
+	var achievement model.Achievement
+	opts := options.FindOneAndUpdate().SetUpsert(false).SetReturnDocument(options.After)
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&achievement); err != nil {
+		return err
+	}
+	return r.insertVersion(ctx, achievementID, &achievement, version)
+}
+
+//
+// ==================== MONGODB METHODS (VERSIONS) ======================
+//
+
+// versionInsertAttempts - Batas percobaan ulang insertVersion saat nomor versi
+// bentrok dengan penyimpanan lain yang bersamaan
+const versionInsertAttempts = 5
+
+// insertVersion - Simpan snapshot achievement dengan nomor versi berikutnya.
+// Nomor versi dijaga index unik (achievementId, version); jika dua penyimpanan
+// bersamaan mengambil nomor yang sama, yang kalah membaca ulang versi terakhir
+// dan mencoba lagi. Status dan transisi terakhir diambil dari PostgreSQL
+// (kosong saat achievement baru dibuat karena reference belum ada).
+func (r *achievementRepository) insertVersion(ctx context.Context, achievementID string, achievement *model.Achievement, version *model.AchievementVersion) error {
+	collection := r.mongoDB.Collection("achievement_versions")
+
+	version.AchievementID = achievementID
+	version.Snapshot = *achievement
+	version.CreatedAt = time.Now()
+
+	var historyID string
+	err := r.pgDB.QueryRow(`
+		SELECT ar.status, h.id
+		FROM achievement_references ar
+		JOIN achievement_status_history h ON h.achievement_reference_id = ar.id
+		WHERE ar.mongo_achievement_id = $1
+		ORDER BY h.created_at DESC
+		LIMIT 1
+	`, achievementID).Scan(&version.Status, &historyID)
+	switch {
+	case err == sql.ErrNoRows:
+		version.Status = model.AchievementStatusDraft
+	case err != nil:
+		return err
+	default:
+		version.StatusHistoryID = &historyID
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	for attempt := 1; ; attempt++ {
+		var latest model.AchievementVersion
+		err := collection.FindOne(ctx, bson.M{"achievementId": achievementID}, opts).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+		version.Version = latest.Version + 1
+
+		result, err := collection.InsertOne(ctx, version)
+		if mongo.IsDuplicateKeyError(err) && attempt < versionInsertAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		version.ID = result.InsertedID.(primitive.ObjectID)
+		return nil
+	}
+}
+
+// GetVersions - Semua versi achievement, urut dari yang paling lama
+func (r *achievementRepository) GetVersions(achievementID string) ([]model.AchievementVersion, error) {
+	collection := r.mongoDB.Collection("achievement_versions")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"achievementId": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []model.AchievementVersion{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetVersion - Satu versi achievement
+func (r *achievementRepository) GetVersion(achievementID string, version int) (*model.AchievementVersion, error) {
+	collection := r.mongoDB.Collection("achievement_versions")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var result model.AchievementVersion
+	filter := bson.M{"achievementId": achievementID, "version": version}
+	if err := collection.FindOne(ctx, filter).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
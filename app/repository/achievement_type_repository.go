@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type AchievementTypeRepository interface {
+	GetAll() ([]model.AchievementType, error)
+	FindByCode(code string) (*model.AchievementType, error)
+	Create(achievementType *model.AchievementType) error
+	Update(achievementType *model.AchievementType) error
+}
+
+type achievementTypeRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementTypeRepository(db *sql.DB) AchievementTypeRepository {
+	return &achievementTypeRepository{db}
+}
+
+// GetAll - Semua jenis prestasi (termasuk yang tidak aktif)
+func (r *achievementTypeRepository) GetAll() ([]model.AchievementType, error) {
+	query := `
+		SELECT code, name, description, details_schema, is_active, created_at, updated_at
+		FROM achievement_types
+		ORDER BY name ASC
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var types []model.AchievementType
+	for rows.Next() {
+		achievementType, err := scanAchievementType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, *achievementType)
+	}
+	return types, rows.Err()
+}
+
+// FindByCode - sql.ErrNoRows jika jenis tidak terdaftar
+func (r *achievementTypeRepository) FindByCode(code string) (*model.AchievementType, error) {
+	query := `
+		SELECT code, name, description, details_schema, is_active, created_at, updated_at
+		FROM achievement_types
+		WHERE code = $1
+	`
+	return scanAchievementType(r.db.QueryRow(query, code))
+}
+
+// Create - Daftarkan jenis prestasi baru
+func (r *achievementTypeRepository) Create(achievementType *model.AchievementType) error {
+	achievementType.CreatedAt = time.Now()
+	achievementType.UpdatedAt = achievementType.CreatedAt
+
+	query := `
+		INSERT INTO achievement_types (code, name, description, details_schema, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query,
+		achievementType.Code,
+		achievementType.Name,
+		achievementType.Description,
+		string(achievementType.DetailsSchema),
+		achievementType.IsActive,
+		achievementType.CreatedAt,
+		achievementType.UpdatedAt,
+	)
+	return err
+}
+
+// Update - Update nama, deskripsi, schema dan status aktif
+func (r *achievementTypeRepository) Update(achievementType *model.AchievementType) error {
+	achievementType.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE achievement_types
+		SET name = $1, description = $2, details_schema = $3, is_active = $4, updated_at = $5
+		WHERE code = $6
+	`
+	_, err := r.db.Exec(query,
+		achievementType.Name,
+		achievementType.Description,
+		string(achievementType.DetailsSchema),
+		achievementType.IsActive,
+		achievementType.UpdatedAt,
+		achievementType.Code,
+	)
+	return err
+}
+
+// Helper: scanAchievementType
+func scanAchievementType(row interface{ Scan(...interface{}) error }) (*model.AchievementType, error) {
+	var achievementType model.AchievementType
+	var schema []byte
+	err := row.Scan(
+		&achievementType.Code,
+		&achievementType.Name,
+		&achievementType.Description,
+		&schema,
+		&achievementType.IsActive,
+		&achievementType.CreatedAt,
+		&achievementType.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	achievementType.DetailsSchema = schema
+	return &achievementType, nil
+}
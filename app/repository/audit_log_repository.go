@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type AuditLogRepository interface {
+	Create(entry *model.AuditLog) error
+	GetByTarget(targetType, targetID string, limit int) ([]model.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	db *sql.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) AuditLogRepository {
+	return &auditLogRepository{db}
+}
+
+// Create - Tulis satu entri audit log
+func (r *auditLogRepository) Create(entry *model.AuditLog) error {
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO audit_logs (actor_id, action, target_type, target_id, details, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		entry.ActorID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.Details,
+		entry.IPAddress,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+// GetByTarget - Riwayat audit untuk satu target (terbaru dulu)
+func (r *auditLogRepository) GetByTarget(targetType, targetID string, limit int) ([]model.AuditLog, error) {
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, COALESCE(details, ''), COALESCE(ip_address, ''), created_at
+		FROM audit_logs
+		WHERE target_type = $1 AND target_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(query, targetType, targetID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.AuditLog
+	for rows.Next() {
+		var e model.AuditLog
+		if err := rows.Scan(
+			&e.ID,
+			&e.ActorID,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&e.Details,
+			&e.IPAddress,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type CommentRepository interface {
+	Create(comment *model.AchievementComment) error
+	FindByID(id string) (*model.AchievementComment, error)
+	GetByReferenceID(referenceID string) ([]model.AchievementComment, error)
+	UpdateBody(id, body string) error
+	SoftDelete(id string) error
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db}
+}
+
+// Create - Simpan komentar baru
+func (r *commentRepository) Create(comment *model.AchievementComment) error {
+	comment.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO achievement_comments
+			(achievement_reference_id, parent_id, author_id, author_role, body, anchor_type, anchor_ref, achievement_status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		comment.AchievementReferenceID,
+		comment.ParentID,
+		comment.AuthorID,
+		comment.AuthorRole,
+		comment.Body,
+		comment.AnchorType,
+		comment.AnchorRef,
+		comment.AchievementStatus,
+		comment.CreatedAt,
+	).Scan(&comment.ID)
+}
+
+// FindByID - Cari komentar berdasarkan ID (termasuk yang sudah dihapus)
+func (r *commentRepository) FindByID(id string) (*model.AchievementComment, error) {
+	query := `
+		SELECT id, achievement_reference_id, parent_id, author_id, author_role, body,
+			anchor_type, anchor_ref, achievement_status, created_at, edited_at, deleted_at
+		FROM achievement_comments
+		WHERE id = $1
+	`
+	return r.scanComment(r.db.QueryRow(query, id))
+}
+
+// GetByReferenceID - Semua komentar satu achievement (terlama dulu).
+// Komentar yang dihapus tetap dikembalikan supaya balasannya tidak yatim.
+func (r *commentRepository) GetByReferenceID(referenceID string) ([]model.AchievementComment, error) {
+	query := `
+		SELECT id, achievement_reference_id, parent_id, author_id, author_role, body,
+			anchor_type, anchor_ref, achievement_status, created_at, edited_at, deleted_at
+		FROM achievement_comments
+		WHERE achievement_reference_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.AchievementComment
+	for rows.Next() {
+		comment, err := r.scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *comment)
+	}
+	return comments, rows.Err()
+}
+
+// UpdateBody - Ubah isi komentar dan catat waktu edit
+func (r *commentRepository) UpdateBody(id, body string) error {
+	query := `
+		UPDATE achievement_comments
+		SET body = $1, edited_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	_, err := r.db.Exec(query, body, time.Now(), id)
+	return err
+}
+
+// SoftDelete - Tandai komentar terhapus (baris tetap ada untuk thread)
+func (r *commentRepository) SoftDelete(id string) error {
+	query := `
+		UPDATE achievement_comments
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
+
+// Helper: scanComment (dipakai untuk *sql.Row dan *sql.Rows)
+func (r *commentRepository) scanComment(row interface{ Scan(...interface{}) error }) (*model.AchievementComment, error) {
+	comment := &model.AchievementComment{}
+	err := row.Scan(
+		&comment.ID,
+		&comment.AchievementReferenceID,
+		&comment.ParentID,
+		&comment.AuthorID,
+		&comment.AuthorRole,
+		&comment.Body,
+		&comment.AnchorType,
+		&comment.AnchorRef,
+		&comment.AchievementStatus,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+		&comment.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
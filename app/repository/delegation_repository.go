@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type DelegationRepository interface {
+	Create(delegation *model.VerificationDelegation) error
+	FindByID(id string) (*model.VerificationDelegation, error)
+	GetByLecturerID(lecturerID string) ([]model.VerificationDelegation, error)
+	FindActive(lecturerID, delegateID string, at time.Time) (*model.VerificationDelegation, error)
+	GetActiveDelegatorIDs(delegateID string, at time.Time) ([]string, error)
+	Revoke(id string) (bool, error)
+}
+
+type delegationRepository struct {
+	db *sql.DB
+}
+
+func NewDelegationRepository(db *sql.DB) DelegationRepository {
+	return &delegationRepository{db}
+}
+
+// Create - Simpan delegasi verifikasi baru
+func (r *delegationRepository) Create(delegation *model.VerificationDelegation) error {
+	delegation.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO verification_delegations (lecturer_id, delegate_id, starts_at, ends_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		delegation.LecturerID,
+		delegation.DelegateID,
+		delegation.StartsAt,
+		delegation.EndsAt,
+		delegation.CreatedBy,
+		delegation.CreatedAt,
+	).Scan(&delegation.ID)
+}
+
+// FindByID - Cari delegasi berdasarkan ID
+func (r *delegationRepository) FindByID(id string) (*model.VerificationDelegation, error) {
+	query := `
+		SELECT id, lecturer_id, delegate_id, starts_at, ends_at, created_by, revoked_at, created_at
+		FROM verification_delegations
+		WHERE id = $1
+	`
+	return r.scanDelegation(r.db.QueryRow(query, id))
+}
+
+// GetByLecturerID - Semua delegasi dari seorang dosen wali (terbaru dulu)
+func (r *delegationRepository) GetByLecturerID(lecturerID string) ([]model.VerificationDelegation, error) {
+	query := `
+		SELECT id, lecturer_id, delegate_id, starts_at, ends_at, created_by, revoked_at, created_at
+		FROM verification_delegations
+		WHERE lecturer_id = $1
+		ORDER BY starts_at DESC
+	`
+	rows, err := r.db.Query(query, lecturerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var delegations []model.VerificationDelegation
+	for rows.Next() {
+		delegation, err := r.scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, *delegation)
+	}
+	return delegations, rows.Err()
+}
+
+// FindActive - Delegasi lecturerID -> delegateID yang berlaku pada waktu at.
+// Delegasi yang sudah lewat ends_at atau dicabut tidak ikut (expired otomatis).
+func (r *delegationRepository) FindActive(lecturerID, delegateID string, at time.Time) (*model.VerificationDelegation, error) {
+	query := `
+		SELECT id, lecturer_id, delegate_id, starts_at, ends_at, created_by, revoked_at, created_at
+		FROM verification_delegations
+		WHERE lecturer_id = $1 AND delegate_id = $2
+			AND revoked_at IS NULL AND starts_at <= $3 AND ends_at > $3
+		ORDER BY starts_at DESC
+		LIMIT 1
+	`
+	return r.scanDelegation(r.db.QueryRow(query, lecturerID, delegateID, at))
+}
+
+// GetActiveDelegatorIDs - Dosen wali yang sedang mendelegasikan verifikasi ke delegateID
+func (r *delegationRepository) GetActiveDelegatorIDs(delegateID string, at time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT lecturer_id
+		FROM verification_delegations
+		WHERE delegate_id = $1 AND revoked_at IS NULL AND starts_at <= $2 AND ends_at > $2
+	`
+	rows, err := r.db.Query(query, delegateID, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lecturerIDs []string
+	for rows.Next() {
+		var lecturerID string
+		if err := rows.Scan(&lecturerID); err != nil {
+			return nil, err
+		}
+		lecturerIDs = append(lecturerIDs, lecturerID)
+	}
+	return lecturerIDs, rows.Err()
+}
+
+// Revoke - Cabut delegasi sebelum ends_at. Return false jika sudah dicabut.
+func (r *delegationRepository) Revoke(id string) (bool, error) {
+	query := `
+		UPDATE verification_delegations
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Helper: scanDelegation (dipakai untuk *sql.Row dan *sql.Rows)
+func (r *delegationRepository) scanDelegation(row interface{ Scan(...interface{}) error }) (*model.VerificationDelegation, error) {
+	delegation := &model.VerificationDelegation{}
+	err := row.Scan(
+		&delegation.ID,
+		&delegation.LecturerID,
+		&delegation.DelegateID,
+		&delegation.StartsAt,
+		&delegation.EndsAt,
+		&delegation.CreatedBy,
+		&delegation.RevokedAt,
+		&delegation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"project_uas/app/model"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DuplicateRepository interface {
+	// MongoDB - Kandidat pembanding
+	FindCandidates(achievement *model.Achievement, limit int) ([]model.Achievement, error)
+
+	// PostgreSQL - Duplicate flags
+	ReplaceFlags(mongoID string, flags []model.DuplicateFlag) error
+	GetFlags(mongoID string) ([]model.DuplicateFlag, error)
+	FlaggedIDs(mongoIDs []string) (map[string]bool, error)
+	GetActiveFlags() ([]model.DuplicateFlag, error)
+}
+
+type duplicateRepository struct {
+	db      *sql.DB
+	mongoDB *mongo.Database
+}
+
+func NewDuplicateRepository(db *sql.DB, mongoDB *mongo.Database) DuplicateRepository {
+	return &duplicateRepository{
+		db:      db,
+		mongoDB: mongoDB,
+	}
+}
+
+// FindCandidates - Achievement lain (di luar trash) yang perlu dibandingkan saat
+// cek duplikat: sejenis, atau punya lampiran dengan hash yang sama (jenis apa pun).
+// Dibatasi limit dokumen terbaru.
+func (r *duplicateRepository) FindCandidates(achievement *model.Achievement, limit int) ([]model.Achievement, error) {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conditions := bson.A{bson.M{"achievementType": achievement.AchievementType}}
+	hashes := bson.A{}
+	for _, attachment := range achievement.Attachments {
+		if attachment.SHA256 != "" {
+			hashes = append(hashes, attachment.SHA256)
+		}
+	}
+	if len(hashes) > 0 {
+		conditions = append(conditions, bson.M{"attachments.sha256": bson.M{"$in": hashes}})
+	}
+
+	filter := bson.M{
+		"_id":       bson.M{"$ne": achievement.ID},
+		"deletedAt": bson.M{"$exists": false},
+		"$or":       conditions,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	candidates := []model.Achievement{}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// ReplaceFlags - Ganti hasil cek duplikat satu achievement (satu transaksi)
+func (r *duplicateRepository) ReplaceFlags(mongoID string, flags []model.DuplicateFlag) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM duplicate_flags WHERE mongo_achievement_id = $1`, mongoID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO duplicate_flags (mongo_achievement_id, matched_mongo_achievement_id, reasons, title_similarity, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	now := time.Now()
+	for i := range flags {
+		flag := &flags[i]
+		flag.MongoAchievementID = mongoID
+		flag.CreatedAt = now
+		err := tx.QueryRow(query,
+			flag.MongoAchievementID,
+			flag.MatchedMongoAchievementID,
+			strings.Join(flag.Reasons, ","),
+			flag.TitleSimilarity,
+			flag.CreatedAt,
+		).Scan(&flag.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetFlags - Flag yang melibatkan achievement ini (sebagai yang disubmit
+// maupun sebagai yang cocok), terbaru dulu
+func (r *duplicateRepository) GetFlags(mongoID string) ([]model.DuplicateFlag, error) {
+	query := `
+		SELECT id, mongo_achievement_id, matched_mongo_achievement_id, reasons, title_similarity, created_at
+		FROM duplicate_flags
+		WHERE mongo_achievement_id = $1 OR matched_mongo_achievement_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDuplicateFlags(rows)
+}
+
+// FlaggedIDs - Achievement (dari mongoIDs) yang terlibat di minimal satu flag
+func (r *duplicateRepository) FlaggedIDs(mongoIDs []string) (map[string]bool, error) {
+	flagged := make(map[string]bool)
+	if len(mongoIDs) == 0 {
+		return flagged, nil
+	}
+
+	query := `
+		SELECT mongo_achievement_id, matched_mongo_achievement_id
+		FROM duplicate_flags
+		WHERE mongo_achievement_id = ANY($1) OR matched_mongo_achievement_id = ANY($1)
+	`
+	rows, err := r.db.Query(query, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source, matched string
+		if err := rows.Scan(&source, &matched); err != nil {
+			return nil, err
+		}
+		flagged[source] = true
+		flagged[matched] = true
+	}
+	return flagged, rows.Err()
+}
+
+// GetActiveFlags - Semua flag yang kedua achievement-nya belum dihapus
+func (r *duplicateRepository) GetActiveFlags() ([]model.DuplicateFlag, error) {
+	query := `
+		SELECT f.id, f.mongo_achievement_id, f.matched_mongo_achievement_id, f.reasons, f.title_similarity, f.created_at
+		FROM duplicate_flags f
+		WHERE EXISTS (SELECT 1 FROM achievement_references ar WHERE ar.mongo_achievement_id = f.mongo_achievement_id AND ar.status <> 'deleted')
+			AND EXISTS (SELECT 1 FROM achievement_references ar WHERE ar.mongo_achievement_id = f.matched_mongo_achievement_id AND ar.status <> 'deleted')
+		ORDER BY f.created_at DESC
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDuplicateFlags(rows)
+}
+
+// Helper: scanDuplicateFlags
+func scanDuplicateFlags(rows *sql.Rows) ([]model.DuplicateFlag, error) {
+	var flags []model.DuplicateFlag
+	for rows.Next() {
+		var flag model.DuplicateFlag
+		var reasons string
+		if err := rows.Scan(&flag.ID, &flag.MongoAchievementID, &flag.MatchedMongoAchievementID, &reasons, &flag.TitleSimilarity, &flag.CreatedAt); err != nil {
+			return nil, err
+		}
+		flag.Reasons = strings.Split(reasons, ",")
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
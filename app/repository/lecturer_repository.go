@@ -15,6 +15,9 @@ type LecturerRepository interface {
 	Delete(id string) error
 	GetAll(limit, offset int) ([]model.Lecturer, error)
 	CountAll() (int, error)
+	GetAllByDepartments(departments []string, limit, offset int) ([]model.Lecturer, error)
+	CountAllByDepartments(departments []string) (int, error)
+	SetRetired(id string, retired bool) error
 }
 
 type lecturerRepository struct {
@@ -51,7 +54,7 @@ func (r *lecturerRepository) FindByUserID(userID string) (*model.Lecturer, error
 	lecturer := &model.Lecturer{}
 	// Karena ID = UserID, kita query berdasarkan ID saja
 	query := `
-		SELECT id, lecturer_id, department, created_at
+		SELECT id, lecturer_id, department, retired_at, created_at
 		FROM lecturers
 		WHERE id = $1
 	`
@@ -59,6 +62,7 @@ func (r *lecturerRepository) FindByUserID(userID string) (*model.Lecturer, error
 		&lecturer.ID,
 		&lecturer.LecturerID,
 		&lecturer.Department,
+		&lecturer.RetiredAt,
 		&lecturer.CreatedAt,
 	)
 	if err != nil {
@@ -79,7 +83,7 @@ func (r *lecturerRepository) FindByID(id string) (*model.Lecturer, error) {
 func (r *lecturerRepository) FindByLecturerID(lecturerID string) (*model.Lecturer, error) {
 	lecturer := &model.Lecturer{}
 	query := `
-		SELECT id, lecturer_id, department, created_at
+		SELECT id, lecturer_id, department, retired_at, created_at
 		FROM lecturers
 		WHERE lecturer_id = $1
 	`
@@ -87,6 +91,7 @@ func (r *lecturerRepository) FindByLecturerID(lecturerID string) (*model.Lecture
 		&lecturer.ID,
 		&lecturer.LecturerID,
 		&lecturer.Department,
+		&lecturer.RetiredAt,
 		&lecturer.CreatedAt,
 	)
 	if err != nil {
@@ -117,8 +122,9 @@ func (r *lecturerRepository) Delete(id string) error {
 // GetAll - Ambil semua lecturers dengan pagination
 func (r *lecturerRepository) GetAll(limit, offset int) ([]model.Lecturer, error) {
 	query := `
-		SELECT id, lecturer_id, department, created_at
+		SELECT id, lecturer_id, department, retired_at, created_at
 		FROM lecturers
+		WHERE retired_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
@@ -128,6 +134,44 @@ func (r *lecturerRepository) GetAll(limit, offset int) ([]model.Lecturer, error)
 	}
 	defer rows.Close()
 
+	return scanLecturers(rows), nil
+}
+
+// CountAll - Hitung total lecturers
+func (r *lecturerRepository) CountAll() (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM lecturers WHERE retired_at IS NULL`
+	err := r.db.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// GetAllByDepartments - Lecturers aktif di departemen tertentu (admin fakultas / prodi)
+func (r *lecturerRepository) GetAllByDepartments(departments []string, limit, offset int) ([]model.Lecturer, error) {
+	query := `
+		SELECT id, lecturer_id, department, retired_at, created_at
+		FROM lecturers
+		WHERE retired_at IS NULL AND department = ANY($1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, departments, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLecturers(rows), nil
+}
+
+// CountAllByDepartments - Hitung lecturers aktif di departemen tertentu
+func (r *lecturerRepository) CountAllByDepartments(departments []string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM lecturers WHERE retired_at IS NULL AND department = ANY($1)`
+	err := r.db.QueryRow(query, departments).Scan(&count)
+	return count, err
+}
+
+func scanLecturers(rows *sql.Rows) []model.Lecturer {
 	var lecturers []model.Lecturer
 	for rows.Next() {
 		var l model.Lecturer
@@ -135,6 +179,7 @@ func (r *lecturerRepository) GetAll(limit, offset int) ([]model.Lecturer, error)
 			&l.ID,
 			&l.LecturerID,
 			&l.Department,
+			&l.RetiredAt,
 			&l.CreatedAt,
 		)
 		if err != nil {
@@ -143,13 +188,15 @@ func (r *lecturerRepository) GetAll(limit, offset int) ([]model.Lecturer, error)
 		l.UserID = l.ID // Map ID ke UserID
 		lecturers = append(lecturers, l)
 	}
-	return lecturers, nil
+	return lecturers
 }
 
-// CountAll - Hitung total lecturers
-func (r *lecturerRepository) CountAll() (int, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM lecturers`
-	err := r.db.QueryRow(query).Scan(&count)
-	return count, err
+// SetRetired - Retire / aktifkan lagi profile lecturer
+func (r *lecturerRepository) SetRetired(id string, retired bool) error {
+	query := `UPDATE lecturers SET retired_at = NULL WHERE id = $1`
+	if retired {
+		query = `UPDATE lecturers SET retired_at = NOW() WHERE id = $1`
+	}
+	_, err := r.db.Exec(query, id)
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type LoginLockoutRepository interface {
+	Get(keyType, keyValue string) (*model.LoginLockout, error)
+	RecordFailure(keyType, keyValue string, now, windowStart, decayStart time.Time) (failedCount int, lockCount int, err error)
+	Lock(keyType, keyValue string, lockCount int, lockedUntil time.Time) (bool, error)
+	RecordFailedIP(username, ip string) error
+	ResetFailures(keyType, keyValue string) error
+	ClearUsername(username string) (clearedIPs []string, err error)
+}
+
+type loginLockoutRepository struct {
+	db *sql.DB
+}
+
+func NewLoginLockoutRepository(db *sql.DB) LoginLockoutRepository {
+	return &loginLockoutRepository{db}
+}
+
+// Get - Ambil counter lockout untuk username / IP
+func (r *loginLockoutRepository) Get(keyType, keyValue string) (*model.LoginLockout, error) {
+	lockout := &model.LoginLockout{}
+	query := `
+		SELECT key_type, key_value, failed_count, lock_count, locked_until, last_failed_at
+		FROM login_lockouts
+		WHERE key_type = $1 AND key_value = $2
+	`
+	err := r.db.QueryRow(query, keyType, keyValue).Scan(
+		&lockout.KeyType,
+		&lockout.KeyValue,
+		&lockout.FailedCount,
+		&lockout.LockCount,
+		&lockout.LockedUntil,
+		&lockout.LastFailedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return lockout, nil
+}
+
+// RecordFailure - Tambah counter gagal login secara atomik (aman untuk request
+// paralel). Counter mulai dari 1 lagi jika gagal login terakhir sebelum
+// windowStart; jumlah lock (tingkat backoff) dan IP yang tercatat kembali
+// kosong jika sebelum decayStart.
+// Return counter setelah ditambah dan jumlah lock sejauh ini.
+func (r *loginLockoutRepository) RecordFailure(keyType, keyValue string, now, windowStart, decayStart time.Time) (int, int, error) {
+	query := `
+		INSERT INTO login_lockouts (key_type, key_value, failed_count, last_failed_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (key_type, key_value) DO UPDATE
+		SET failed_count = CASE
+				WHEN login_lockouts.last_failed_at IS NULL OR login_lockouts.last_failed_at < $4 THEN 1
+				ELSE login_lockouts.failed_count + 1
+			END,
+			lock_count = CASE
+				WHEN login_lockouts.last_failed_at IS NULL OR login_lockouts.last_failed_at < $5 THEN 0
+				ELSE login_lockouts.lock_count
+			END,
+			failed_ips = CASE
+				WHEN login_lockouts.last_failed_at IS NULL OR login_lockouts.last_failed_at < $5 THEN '{}'
+				ELSE login_lockouts.failed_ips
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failed_count, lock_count
+	`
+	var failedCount, lockCount int
+	err := r.db.QueryRow(query, keyType, keyValue, now, windowStart, decayStart).Scan(&failedCount, &lockCount)
+	return failedCount, lockCount, err
+}
+
+// Lock - Kunci key sampai lockedUntil sebagai lock ke-lockCount dan reset
+// counter. Return false jika request lain sudah mengunci lebih dulu
+// (lock_count sudah berubah).
+func (r *loginLockoutRepository) Lock(keyType, keyValue string, lockCount int, lockedUntil time.Time) (bool, error) {
+	query := `
+		UPDATE login_lockouts
+		SET lock_count = $3, locked_until = $4, failed_count = 0
+		WHERE key_type = $1 AND key_value = $2 AND lock_count = $3 - 1
+	`
+	result, err := r.db.Exec(query, keyType, keyValue, lockCount, lockedUntil)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// RecordFailedIP - Catat IP asal gagal login ke username (sekali per IP)
+func (r *loginLockoutRepository) RecordFailedIP(username, ip string) error {
+	query := `
+		UPDATE login_lockouts
+		SET failed_ips = array_append(failed_ips, $2)
+		WHERE key_type = 'username' AND key_value = $1 AND NOT ($2 = ANY(failed_ips))
+	`
+	_, err := r.db.Exec(query, username, ip)
+	return err
+}
+
+// ResetFailures - Reset counter gagal login (login sukses). lock_count tetap
+// supaya lock berikutnya tetap berlipat sampai decay.
+func (r *loginLockoutRepository) ResetFailures(keyType, keyValue string) error {
+	_, err := r.db.Exec(`UPDATE login_lockouts SET failed_count = 0 WHERE key_type = $1 AND key_value = $2`, keyType, keyValue)
+	return err
+}
+
+// ClearUsername - Hapus lockout username beserta lockout IP yang tercatat
+// pernah gagal login ke username tsb (dibuka admin). Return IP yang dibuka.
+func (r *loginLockoutRepository) ClearUsername(username string) ([]string, error) {
+	query := `
+		WITH account AS (
+			DELETE FROM login_lockouts
+			WHERE key_type = 'username' AND key_value = $1
+			RETURNING failed_ips
+		)
+		DELETE FROM login_lockouts
+		WHERE key_type = 'ip' AND key_value IN (SELECT unnest(failed_ips) FROM account)
+		RETURNING key_value
+	`
+	rows, err := r.db.Query(query, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ips := []string{}
+	for rows.Next() {
+		var ip string
+		if err := rows.Scan(&ip); err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, rows.Err()
+}
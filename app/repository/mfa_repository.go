@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type MFARepository interface {
+	FindByUserID(userID string) (*model.UserMFA, error)
+	SaveSecret(userID, secret string) error
+	Enable(userID string, step int64) error
+	MarkStepUsed(userID string, step int64) (bool, error)
+	Delete(userID string) error
+
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	UseRecoveryCode(userID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID string) (int, error)
+}
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &mfaRepository{db}
+}
+
+// FindByUserID - Ambil konfigurasi MFA user
+func (r *mfaRepository) FindByUserID(userID string) (*model.UserMFA, error) {
+	mfa := &model.UserMFA{}
+	query := `
+		SELECT user_id, secret, enabled, last_used_step, enabled_at, created_at, updated_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+	err := r.db.QueryRow(query, userID).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&mfa.Enabled,
+		&mfa.LastUsedStep,
+		&mfa.EnabledAt,
+		&mfa.CreatedAt,
+		&mfa.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// SaveSecret - Simpan secret baru untuk enrollment (belum aktif).
+// Tidak menimpa MFA yang sudah aktif.
+func (r *mfaRepository) SaveSecret(userID, secret string) error {
+	now := time.Now()
+	query := `
+		INSERT INTO user_mfa (user_id, secret, enabled, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, FALSE, 0, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE user_mfa.enabled = FALSE
+	`
+	_, err := r.db.Exec(query, userID, secret, now)
+	return err
+}
+
+// Enable - Aktifkan MFA setelah kode pertama dikonfirmasi
+func (r *mfaRepository) Enable(userID string, step int64) error {
+	now := time.Now()
+	query := `
+		UPDATE user_mfa
+		SET enabled = TRUE, enabled_at = $1, last_used_step = $2, updated_at = $1
+		WHERE user_id = $3
+	`
+	_, err := r.db.Exec(query, now, step, userID)
+	return err
+}
+
+// MarkStepUsed - Simpan step TOTP terakhir yang dipakai (atomic).
+// Return false jika step ini (atau yang lebih baru) sudah pernah dipakai.
+func (r *mfaRepository) MarkStepUsed(userID string, step int64) (bool, error) {
+	query := `
+		UPDATE user_mfa
+		SET last_used_step = $1, updated_at = $2
+		WHERE user_id = $3 AND last_used_step < $1
+	`
+	result, err := r.db.Exec(query, step, time.Now(), userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// Delete - Nonaktifkan MFA (recovery code ikut terhapus)
+func (r *mfaRepository) Delete(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM user_mfa WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes - Ganti semua recovery code user (hanya hash yang disimpan)
+func (r *mfaRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, hash := range codeHashes {
+		_, err := tx.Exec(`
+			INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at)
+			VALUES ($1, $2, $3)
+		`, userID, hash, now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode - Pakai recovery code (atomic, single-use)
+func (r *mfaRepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CountRecoveryCodes - Jumlah recovery code yang belum dipakai
+func (r *mfaRepository) CountRecoveryCodes(userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
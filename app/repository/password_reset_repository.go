@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	Create(token *model.PasswordResetToken) error
+	FindByHash(tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(id string) (bool, error)
+	InvalidateByUserID(userID string) error
+}
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+// Create - Simpan token reset baru (hanya hash)
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+}
+
+// FindByHash - Cari token reset berdasarkan hash
+func (r *passwordResetRepository) FindByHash(tokenHash string) (*model.PasswordResetToken, error) {
+	token := &model.PasswordResetToken{}
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed - Tandai token sudah dipakai (atomic, single-use).
+// Return false jika token sudah dipakai atau expired.
+func (r *passwordResetRepository) MarkUsed(id string) (bool, error) {
+	now := time.Now()
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL AND expires_at > $1
+	`
+	result, err := r.db.Exec(query, now, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// InvalidateByUserID - Batalkan semua token reset user yang belum dipakai
+func (r *passwordResetRepository) InvalidateByUserID(userID string) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE user_id = $2 AND used_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"project_uas/app/model"
 )
 
 type PermissionRepository interface {
 	GetPermissionsByRoleID(roleID string) ([]string, error)
+
+	GetAll() ([]model.Permission, error)
+	FindByName(name string) (*model.Permission, error)
+	FindByID(id string) (*model.Permission, error)
+	Grant(roleID, permissionID string) (bool, error)
+	Revoke(roleID, permissionID string) (bool, error)
 }
 
 type permissionRepository struct {
@@ -40,4 +47,92 @@ func (r *permissionRepository) GetPermissionsByRoleID(roleID string) ([]string,
 	}
 	
 	return permissions, nil
-}
\ No newline at end of file
+}
+
+// GetAll - Ambil semua permission
+func (r *permissionRepository) GetAll() ([]model.Permission, error) {
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		ORDER BY name
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []model.Permission
+	for rows.Next() {
+		var p model.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+// FindByName - Cari permission berdasarkan nama (mis. "achievement:read")
+func (r *permissionRepository) FindByName(name string) (*model.Permission, error) {
+	p := &model.Permission{}
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		WHERE name = $1
+	`
+	err := r.db.QueryRow(query, name).Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FindByID - Cari permission berdasarkan ID
+func (r *permissionRepository) FindByID(id string) (*model.Permission, error) {
+	p := &model.Permission{}
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Grant - Tambah permission ke role. Return false jika sudah ada.
+func (r *permissionRepository) Grant(roleID, permissionID string) (bool, error) {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := r.db.Exec(query, roleID, permissionID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// Revoke - Hapus permission dari role. Return false jika memang tidak ada.
+func (r *permissionRepository) Revoke(roleID, permissionID string) (bool, error) {
+	query := `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`
+	result, err := r.db.Exec(query, roleID, permissionID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type PipelineRepository interface {
+	GetAll() ([]model.VerificationPipeline, error)
+	FindByID(id string) (*model.VerificationPipeline, error)
+	FindForAchievement(achievementType, competitionLevel string) (*model.VerificationPipeline, error)
+	Create(pipeline *model.VerificationPipeline) error
+	Update(pipeline *model.VerificationPipeline) error
+	Delete(id string) error
+	CountInFlight(id string) (int, error)
+}
+
+type pipelineRepository struct {
+	db *sql.DB
+}
+
+func NewPipelineRepository(db *sql.DB) PipelineRepository {
+	return &pipelineRepository{db}
+}
+
+// GetAll - Semua pipeline beserta stage-nya
+func (r *pipelineRepository) GetAll() ([]model.VerificationPipeline, error) {
+	query := `
+		SELECT id, name, achievement_type, competition_level, created_at, updated_at
+		FROM verification_pipelines
+		ORDER BY name ASC
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pipelines []model.VerificationPipeline
+	for rows.Next() {
+		var p model.VerificationPipeline
+		if err := rows.Scan(&p.ID, &p.Name, &p.AchievementType, &p.CompetitionLevel, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range pipelines {
+		if pipelines[i].Stages, err = r.getStages(pipelines[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return pipelines, nil
+}
+
+// FindByID - Pipeline beserta stage-nya
+func (r *pipelineRepository) FindByID(id string) (*model.VerificationPipeline, error) {
+	query := `
+		SELECT id, name, achievement_type, competition_level, created_at, updated_at
+		FROM verification_pipelines
+		WHERE id = $1
+	`
+	return r.scanPipeline(r.db.QueryRow(query, id))
+}
+
+// FindForAchievement - Pipeline paling spesifik untuk jenis prestasi & tingkat
+// kompetisi: cocok keduanya > tingkat kompetisi saja > jenis saja > catch-all.
+// sql.ErrNoRows jika tidak ada yang cocok.
+func (r *pipelineRepository) FindForAchievement(achievementType, competitionLevel string) (*model.VerificationPipeline, error) {
+	query := `
+		SELECT id, name, achievement_type, competition_level, created_at, updated_at
+		FROM verification_pipelines
+		WHERE (achievement_type IS NULL OR achievement_type = $1)
+			AND (competition_level IS NULL OR competition_level = $2)
+		ORDER BY (competition_level IS NOT NULL) DESC, (achievement_type IS NOT NULL) DESC
+		LIMIT 1
+	`
+	return r.scanPipeline(r.db.QueryRow(query, achievementType, competitionLevel))
+}
+
+// Create - Simpan pipeline dan stage-nya (satu transaksi)
+func (r *pipelineRepository) Create(pipeline *model.VerificationPipeline) error {
+	pipeline.CreatedAt = time.Now()
+	pipeline.UpdatedAt = pipeline.CreatedAt
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO verification_pipelines (name, achievement_type, competition_level, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	err = tx.QueryRow(query,
+		pipeline.Name,
+		pipeline.AchievementType,
+		pipeline.CompetitionLevel,
+		pipeline.CreatedAt,
+		pipeline.UpdatedAt,
+	).Scan(&pipeline.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.insertStages(tx, pipeline); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update - Update pipeline dan ganti seluruh stage-nya (satu transaksi)
+func (r *pipelineRepository) Update(pipeline *model.VerificationPipeline) error {
+	pipeline.UpdatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE verification_pipelines
+		SET name = $1, achievement_type = $2, competition_level = $3, updated_at = $4
+		WHERE id = $5
+	`
+	_, err = tx.Exec(query,
+		pipeline.Name,
+		pipeline.AchievementType,
+		pipeline.CompetitionLevel,
+		pipeline.UpdatedAt,
+		pipeline.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM verification_pipeline_stages WHERE pipeline_id = $1`, pipeline.ID); err != nil {
+		return err
+	}
+	if err := r.insertStages(tx, pipeline); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete - Hapus pipeline (stage ikut terhapus)
+func (r *pipelineRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM verification_pipelines WHERE id = $1`, id)
+	return err
+}
+
+// CountInFlight - Jumlah achievement yang sedang diverifikasi lewat pipeline ini
+func (r *pipelineRepository) CountInFlight(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM achievement_references
+		WHERE pipeline_id = $1 AND status = 'submitted'
+	`, id).Scan(&count)
+	return count, err
+}
+
+func (r *pipelineRepository) insertStages(tx *sql.Tx, pipeline *model.VerificationPipeline) error {
+	query := `
+		INSERT INTO verification_pipeline_stages (pipeline_id, position, name, role, program_study)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	for i := range pipeline.Stages {
+		stage := &pipeline.Stages[i]
+		stage.PipelineID = pipeline.ID
+		stage.Position = i + 1
+		if err := tx.QueryRow(query, stage.PipelineID, stage.Position, stage.Name, stage.Role, stage.ProgramStudy).Scan(&stage.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *pipelineRepository) getStages(pipelineID string) ([]model.PipelineStage, error) {
+	query := `
+		SELECT id, pipeline_id, position, name, role, program_study
+		FROM verification_pipeline_stages
+		WHERE pipeline_id = $1
+		ORDER BY position ASC
+	`
+	rows, err := r.db.Query(query, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []model.PipelineStage{}
+	for rows.Next() {
+		var stage model.PipelineStage
+		if err := rows.Scan(&stage.ID, &stage.PipelineID, &stage.Position, &stage.Name, &stage.Role, &stage.ProgramStudy); err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	return stages, rows.Err()
+}
+
+func (r *pipelineRepository) scanPipeline(row *sql.Row) (*model.VerificationPipeline, error) {
+	pipeline := &model.VerificationPipeline{}
+	err := row.Scan(
+		&pipeline.ID,
+		&pipeline.Name,
+		&pipeline.AchievementType,
+		&pipeline.CompetitionLevel,
+		&pipeline.CreatedAt,
+		&pipeline.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if pipeline.Stages, err = r.getStages(pipeline.ID); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type PointRuleRepository interface {
+	GetAll() ([]model.PointRule, error)
+	GetByType(achievementType string) ([]model.PointRule, error)
+	FindByID(id string) (*model.PointRule, error)
+	Create(rule *model.PointRule) error
+	Update(rule *model.PointRule) error
+	Delete(id string) error
+	AdjustPoints(ref *model.AchievementReference, adjustment *model.PointAdjustment) error
+	GetAdjustments(referenceID string) ([]model.PointAdjustment, error)
+}
+
+type pointRuleRepository struct {
+	db *sql.DB
+}
+
+func NewPointRuleRepository(db *sql.DB) PointRuleRepository {
+	return &pointRuleRepository{db}
+}
+
+const pointRuleSelect = `
+	SELECT id, name, achievement_type, competition_level, rank_min, rank_max, team_size_min, team_size_max, points, created_at, updated_at
+	FROM point_rules
+`
+
+// GetAll - Semua rule, dikelompokkan per jenis prestasi
+func (r *pointRuleRepository) GetAll() ([]model.PointRule, error) {
+	rows, err := r.db.Query(pointRuleSelect + ` ORDER BY achievement_type ASC, points DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPointRules(rows)
+}
+
+// GetByType - Rule untuk satu jenis prestasi (pemilihan rule di service)
+func (r *pointRuleRepository) GetByType(achievementType string) ([]model.PointRule, error) {
+	rows, err := r.db.Query(pointRuleSelect+` WHERE achievement_type = $1 ORDER BY points DESC`, achievementType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPointRules(rows)
+}
+
+// FindByID - Get rule by ID
+func (r *pointRuleRepository) FindByID(id string) (*model.PointRule, error) {
+	return scanPointRule(r.db.QueryRow(pointRuleSelect+` WHERE id = $1`, id))
+}
+
+// Create - Simpan rule baru
+func (r *pointRuleRepository) Create(rule *model.PointRule) error {
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	query := `
+		INSERT INTO point_rules (name, achievement_type, competition_level, rank_min, rank_max, team_size_min, team_size_max, points, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		rule.Name,
+		rule.AchievementType,
+		rule.CompetitionLevel,
+		rule.RankMin,
+		rule.RankMax,
+		rule.TeamSizeMin,
+		rule.TeamSizeMax,
+		rule.Points,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	).Scan(&rule.ID)
+}
+
+// Update - Update seluruh kolom rule
+func (r *pointRuleRepository) Update(rule *model.PointRule) error {
+	rule.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE point_rules
+		SET name = $1, achievement_type = $2, competition_level = $3, rank_min = $4, rank_max = $5,
+			team_size_min = $6, team_size_max = $7, points = $8, updated_at = $9
+		WHERE id = $10
+	`
+	_, err := r.db.Exec(query,
+		rule.Name,
+		rule.AchievementType,
+		rule.CompetitionLevel,
+		rule.RankMin,
+		rule.RankMax,
+		rule.TeamSizeMin,
+		rule.TeamSizeMax,
+		rule.Points,
+		rule.UpdatedAt,
+		rule.ID,
+	)
+	return err
+}
+
+// Delete - Hapus rule (reference yang memakainya tetap menyimpan poinnya)
+func (r *pointRuleRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM point_rules WHERE id = $1`, id)
+	return err
+}
+
+// AdjustPoints - Ganti poin reference dan catat penyesuaiannya (satu transaksi).
+// Reference yang sudah tidak submitted (mis. baru saja diverifikasi) atau sudah
+// pindah stage sejak dibaca service (ref.CurrentStage) tidak diubah
+// (sql.ErrNoRows). FromPoints diisi dari poin lama yang dikunci di transaksi.
+func (r *pointRuleRepository) AdjustPoints(ref *model.AchievementReference, adjustment *model.PointAdjustment) error {
+	adjustment.AchievementReferenceID = ref.ID
+	adjustment.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		WITH old AS (
+			SELECT id, points FROM achievement_references
+			WHERE id = $3 AND status = 'submitted' AND points_frozen_at IS NULL
+				AND current_stage IS NOT DISTINCT FROM $4
+			FOR UPDATE
+		)
+		UPDATE achievement_references ar
+		SET points = $1, point_rule_id = NULL, updated_at = $2
+		FROM old
+		WHERE ar.id = old.id
+		RETURNING COALESCE(old.points, 0)
+	`, adjustment.ToPoints, adjustment.CreatedAt, ref.ID, ref.CurrentStage).Scan(&adjustment.FromPoints)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO point_adjustments (achievement_reference_id, from_points, to_points, justification, adjusted_by, on_behalf_of, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	err = tx.QueryRow(query,
+		adjustment.AchievementReferenceID,
+		adjustment.FromPoints,
+		adjustment.ToPoints,
+		adjustment.Justification,
+		adjustment.AdjustedBy,
+		adjustment.OnBehalfOf,
+		adjustment.CreatedAt,
+	).Scan(&adjustment.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	ref.Points = &adjustment.ToPoints
+	ref.PointRuleID = nil
+	ref.UpdatedAt = adjustment.CreatedAt
+	return nil
+}
+
+// GetAdjustments - Riwayat penyesuaian poin (terlama dulu)
+func (r *pointRuleRepository) GetAdjustments(referenceID string) ([]model.PointAdjustment, error) {
+	query := `
+		SELECT id, achievement_reference_id, from_points, to_points, justification, COALESCE(adjusted_by::text, ''), on_behalf_of, created_at
+		FROM point_adjustments
+		WHERE achievement_reference_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []model.PointAdjustment
+	for rows.Next() {
+		var a model.PointAdjustment
+		if err := rows.Scan(&a.ID, &a.AchievementReferenceID, &a.FromPoints, &a.ToPoints, &a.Justification, &a.AdjustedBy, &a.OnBehalfOf, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, a)
+	}
+	return adjustments, rows.Err()
+}
+
+// Helper: scanPointRule
+func scanPointRule(row interface{ Scan(...interface{}) error }) (*model.PointRule, error) {
+	var rule model.PointRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.AchievementType,
+		&rule.CompetitionLevel,
+		&rule.RankMin,
+		&rule.RankMax,
+		&rule.TeamSizeMin,
+		&rule.TeamSizeMax,
+		&rule.Points,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Helper: scanPointRules
+func scanPointRules(rows *sql.Rows) ([]model.PointRule, error) {
+	var rules []model.PointRule
+	for rows.Next() {
+		rule, err := scanPointRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
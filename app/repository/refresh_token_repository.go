@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	FindByID(id string) (*model.RefreshToken, error)
+	MarkUsed(id string, replacedBy string) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeByUserID(userID string) error
+}
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+// Create - Simpan refresh token baru (ID = jti)
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// FindByID - Cari refresh token berdasarkan jti
+func (r *refreshTokenRepository) FindByID(id string) (*model.RefreshToken, error) {
+	token := &model.RefreshToken{}
+	query := `
+		SELECT id, user_id, family_id, expires_at, used_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed - Tandai token sudah dipakai (atomic).
+// Return false jika token sudah pernah dipakai / direvoke sebelumnya.
+func (r *refreshTokenRepository) MarkUsed(id string, replacedBy string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = $1, replaced_by = $2
+		WHERE id = $3 AND used_at IS NULL AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), replacedBy, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// RevokeFamily - Revoke semua token dalam satu sesi (family)
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), familyID)
+	return err
+}
+
+// RevokeByUserID - Revoke semua sesi milik user
+func (r *refreshTokenRepository) RevokeByUserID(userID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
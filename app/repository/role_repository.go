@@ -8,6 +8,14 @@ import (
 type RoleRepository interface {
 	GetRoleByID(id string) (*model.Role, error)
 	GetRoleByName(name string) (*model.Role, error) // ⭐ TAMBAHKAN METHOD INI
+	GetAll() ([]model.Role, error)
+	SetMFARequired(id string, required bool) error
+
+	Create(role *model.Role) error
+	Update(role *model.Role) error
+	Delete(id string) error
+	CountMembers(id string) (int, error)
+	GetMemberIDs(id string) ([]string, error)
 }
 
 type roleRepository struct {
@@ -18,24 +26,116 @@ func NewRoleRepository(db *sql.DB) RoleRepository {
 	return &roleRepository{db}
 }
 
-// GetRoleByID - Cari role berdasarkan ID (EXISTING)
-func (r *roleRepository) GetRoleByID(id string) (*model.Role, error) {
+const roleColumns = `id, name, COALESCE(description, ''), mfa_required, is_builtin, created_at`
+
+func scanRole(row interface{ Scan(...interface{}) error }) (*model.Role, error) {
 	role := &model.Role{}
-	query := `SELECT id, name, description, created_at FROM roles WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	err := row.Scan(&role.ID, &role.Name, &role.Description, &role.MFARequired, &role.IsBuiltin, &role.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return role, nil
 }
 
+// GetRoleByID - Cari role berdasarkan ID (EXISTING)
+func (r *roleRepository) GetRoleByID(id string) (*model.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE id = $1`
+	return scanRole(r.db.QueryRow(query, id))
+}
+
 // GetRoleByName - Cari role berdasarkan name (NEW)
 func (r *roleRepository) GetRoleByName(name string) (*model.Role, error) {
-	role := &model.Role{}
-	query := `SELECT id, name, description, created_at FROM roles WHERE name = $1`
-	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE name = $1`
+	return scanRole(r.db.QueryRow(query, name))
+}
+
+// GetAll - Ambil semua role
+func (r *roleRepository) GetAll() ([]model.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles ORDER BY name`
+	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
-	return role, nil
-}
\ No newline at end of file
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	return roles, rows.Err()
+}
+
+// SetMFARequired - Atur kebijakan MFA wajib untuk role
+func (r *roleRepository) SetMFARequired(id string, required bool) error {
+	query := `UPDATE roles SET mfa_required = $1 WHERE id = $2`
+	return r.execOne(query, required, id)
+}
+
+// Create - Buat role baru (selalu bukan built-in)
+func (r *roleRepository) Create(role *model.Role) error {
+	query := `
+		INSERT INTO roles (name, description, mfa_required, is_builtin)
+		VALUES ($1, $2, $3, FALSE)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(query, role.Name, role.Description, role.MFARequired).Scan(&role.ID, &role.CreatedAt)
+}
+
+// Update - Update nama dan deskripsi role
+func (r *roleRepository) Update(role *model.Role) error {
+	query := `UPDATE roles SET name = $1, description = $2 WHERE id = $3`
+	return r.execOne(query, role.Name, role.Description, role.ID)
+}
+
+// Delete - Hapus role non built-in (role_permissions ikut terhapus via CASCADE)
+func (r *roleRepository) Delete(id string) error {
+	query := `DELETE FROM roles WHERE id = $1 AND is_builtin = FALSE`
+	return r.execOne(query, id)
+}
+
+// CountMembers - Jumlah user dengan role ini
+func (r *roleRepository) CountMembers(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(DISTINCT user_id) FROM user_roles WHERE role_id = $1`, id).Scan(&count)
+	return count, err
+}
+
+// GetMemberIDs - ID semua user dengan role ini (untuk revoke token saat permission berubah)
+func (r *roleRepository) GetMemberIDs(id string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT user_id FROM user_roles WHERE role_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, userID)
+	}
+	return ids, rows.Err()
+}
+
+// execOne - Exec dan return sql.ErrNoRows jika tidak ada baris yang berubah
+func (r *roleRepository) execOne(query string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type SLARepository interface {
+	GetOverdue(submittedBefore time.Time, filter OverdueFilter) ([]model.OverdueAchievement, error)
+	GetRoleHolders(role, programStudy string) ([]model.User, error)
+	GetActiveDelegates(lecturerID string, at time.Time) ([]model.User, error)
+	HasNotified(referenceID, level string, submittedAt time.Time) (bool, error)
+	RecordNotification(notification *model.SLANotification) error
+}
+
+type slaRepository struct {
+	db *sql.DB
+}
+
+func NewSLARepository(db *sql.DB) SLARepository {
+	return &slaRepository{db}
+}
+
+const overdueSelect = `
+	SELECT ar.id, s.id, COALESCE(su.full_name, ''), COALESCE(s.program_study, ''),
+		s.advisor_id, COALESCE(lu.full_name, ''), COALESCE(lu.email, ''),
+		ar.submitted_at, ar.pipeline_id, ar.current_stage
+	FROM achievement_references ar
+	JOIN students s ON s.id = ar.student_id
+	LEFT JOIN users su ON su.id = s.id
+	LEFT JOIN users lu ON lu.id = s.advisor_id
+	WHERE ar.status = 'submitted' AND ar.submitted_at < $1
+`
+
+// OverdueFilter - Batas GetOverdue untuk satu user: mahasiswa bimbingannya,
+// bimbingan dosen yang sedang mendelegasikan kepadanya, atau achievement yang
+// stage pipeline aktifnya dipegang role user tsb. UserID kosong = semua
+// (scheduler). Pemegang stage yang pasti tetap ditentukan service.
+type OverdueFilter struct {
+	UserID string
+	At     time.Time // waktu cek delegasi aktif
+}
+
+const overdueForUserCondition = `
+	AND (
+		s.advisor_id = $2
+		OR s.advisor_id IN (
+			SELECT vd.lecturer_id FROM verification_delegations vd
+			WHERE vd.delegate_id = $2 AND vd.revoked_at IS NULL AND vd.starts_at <= $3 AND vd.ends_at > $3
+		)
+		OR EXISTS (
+			SELECT 1
+			FROM verification_pipeline_stages ps
+			JOIN roles ro ON ro.name = ps.role
+			JOIN user_roles ur ON ur.role_id = ro.id AND ur.user_id = $2
+			WHERE ps.pipeline_id = ar.pipeline_id
+				AND ps.position = LEAST(GREATEST(COALESCE(ar.current_stage, 1), 1),
+					(SELECT MAX(position) FROM verification_pipeline_stages WHERE pipeline_id = ar.pipeline_id))
+		)
+	)
+`
+
+// GetOverdue - Achievement submitted sebelum submittedBefore (terlama dulu)
+func (r *slaRepository) GetOverdue(submittedBefore time.Time, filter OverdueFilter) ([]model.OverdueAchievement, error) {
+	query := overdueSelect
+	args := []interface{}{submittedBefore}
+	if filter.UserID != "" {
+		query += overdueForUserCondition
+		args = append(args, filter.UserID, filter.At)
+	}
+
+	rows, err := r.db.Query(query+` ORDER BY ar.submitted_at ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanOverdue(rows)
+}
+
+// GetRoleHolders - User aktif dengan role yang di-assign ke programStudy
+// ("" = assignment tanpa batas program studi)
+func (r *slaRepository) GetRoleHolders(role, programStudy string) ([]model.User, error) {
+	query := `
+		SELECT DISTINCT u.id, u.username, u.email, u.password_hash, u.full_name, u.is_active, u.created_at, u.updated_at
+		FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN roles r ON r.id = ur.role_id
+		WHERE r.name = $1 AND u.is_active = true AND ur.program_study = $2
+	`
+	rows, err := r.db.Query(query, role, programStudy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUsers(rows), nil
+}
+
+// GetActiveDelegates - User dosen yang sedang menerima delegasi verifikasi dari lecturerID
+func (r *slaRepository) GetActiveDelegates(lecturerID string, at time.Time) ([]model.User, error) {
+	query := `
+		SELECT DISTINCT u.id, u.username, u.email, u.password_hash, u.full_name, u.is_active, u.created_at, u.updated_at
+		FROM verification_delegations vd
+		JOIN lecturers l ON l.id = vd.delegate_id
+		JOIN users u ON u.id = l.id
+		WHERE vd.lecturer_id = $1 AND vd.revoked_at IS NULL AND vd.starts_at <= $2 AND vd.ends_at > $2
+			AND u.is_active = true
+	`
+	rows, err := r.db.Query(query, lecturerID, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUsers(rows), nil
+}
+
+// HasNotified - Notifikasi level ini sudah dikirim untuk submit tsb
+func (r *slaRepository) HasNotified(referenceID, level string, submittedAt time.Time) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM verification_sla_notifications
+			WHERE achievement_reference_id = $1 AND level = $2 AND submitted_at = $3
+		)
+	`
+	err := r.db.QueryRow(query, referenceID, level, submittedAt).Scan(&exists)
+	return exists, err
+}
+
+// RecordNotification - Catat notifikasi yang sudah dikirim
+func (r *slaRepository) RecordNotification(notification *model.SLANotification) error {
+	notification.SentAt = time.Now()
+
+	query := `
+		INSERT INTO verification_sla_notifications (achievement_reference_id, level, submitted_at, recipients, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (achievement_reference_id, level, submitted_at) DO NOTHING
+		RETURNING id
+	`
+	err := r.db.QueryRow(query,
+		notification.AchievementReferenceID,
+		notification.Level,
+		notification.SubmittedAt,
+		notification.Recipients,
+		notification.SentAt,
+	).Scan(&notification.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// Helper: scanOverdue
+func scanOverdue(rows *sql.Rows) ([]model.OverdueAchievement, error) {
+	var items []model.OverdueAchievement
+	for rows.Next() {
+		var item model.OverdueAchievement
+		err := rows.Scan(
+			&item.ReferenceID,
+			&item.StudentID,
+			&item.StudentName,
+			&item.ProgramStudy,
+			&item.AdvisorID,
+			&item.AdvisorName,
+			&item.AdvisorEmail,
+			&item.SubmittedAt,
+			&item.PipelineID,
+			&item.CurrentStage,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -17,6 +17,9 @@ type StudentRepository interface {
 	SetAdvisor(studentID string, advisorID string) error
 	GetAll(limit, offset int) ([]model.Student, error)
 	CountAll() (int, error)
+	GetAllByProgramStudies(programStudies []string, limit, offset int) ([]model.Student, error)
+	CountAllByProgramStudies(programStudies []string) (int, error)
+	SetRetired(id string, retired bool) error
 }
 
 type studentRepository struct {
@@ -61,7 +64,7 @@ func (r *studentRepository) FindByUserID(userID string) (*model.Student, error)
 	var academicYear int
 	
 	query := `
-		SELECT id, student_id, program_study, academic_year, advisor_id, created_at
+		SELECT id, student_id, program_study, academic_year, advisor_id, retired_at, created_at
 		FROM students
 		WHERE id = $1
 	`
@@ -72,6 +75,7 @@ func (r *studentRepository) FindByUserID(userID string) (*model.Student, error)
 		&student.ProgramStudy,
 		&academicYear,
 		&student.AdvisorID,
+		&student.RetiredAt,
 		&student.CreatedAt,
 	)
 	if err != nil {
@@ -89,7 +93,7 @@ func (r *studentRepository) FindByID(id string) (*model.Student, error) {
 	var academicYear int
 	
 	query := `
-		SELECT id, student_id, program_study, academic_year, advisor_id, created_at
+		SELECT id, student_id, program_study, academic_year, advisor_id, retired_at, created_at
 		FROM students
 		WHERE id = $1
 	`
@@ -100,6 +104,7 @@ func (r *studentRepository) FindByID(id string) (*model.Student, error) {
 		&student.ProgramStudy,
 		&academicYear,
 		&student.AdvisorID,
+		&student.RetiredAt,
 		&student.CreatedAt,
 	)
 	if err != nil {
@@ -117,7 +122,7 @@ func (r *studentRepository) FindByStudentID(studentID string) (*model.Student, e
 	var academicYear int
 	
 	query := `
-		SELECT id, student_id, program_study, academic_year, advisor_id, created_at
+		SELECT id, student_id, program_study, academic_year, advisor_id, retired_at, created_at
 		FROM students
 		WHERE student_id = $1
 	`
@@ -128,6 +133,7 @@ func (r *studentRepository) FindByStudentID(studentID string) (*model.Student, e
 		&student.ProgramStudy,
 		&academicYear,
 		&student.AdvisorID,
+		&student.RetiredAt,
 		&student.CreatedAt,
 	)
 	if err != nil {
@@ -179,8 +185,9 @@ func (r *studentRepository) SetAdvisor(studentID string, advisorID string) error
 // GetAll - Ambil semua students dengan pagination
 func (r *studentRepository) GetAll(limit, offset int) ([]model.Student, error) {
 	query := `
-		SELECT id, student_id, program_study, academic_year, advisor_id, created_at
+		SELECT id, student_id, program_study, academic_year, advisor_id, retired_at, created_at
 		FROM students
+		WHERE retired_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
@@ -190,6 +197,44 @@ func (r *studentRepository) GetAll(limit, offset int) ([]model.Student, error) {
 	}
 	defer rows.Close()
 
+	return scanStudents(rows), nil
+}
+
+// CountAll - Hitung total students
+func (r *studentRepository) CountAll() (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM students WHERE retired_at IS NULL`
+	err := r.db.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// GetAllByProgramStudies - Students aktif di program studi tertentu (admin fakultas / prodi)
+func (r *studentRepository) GetAllByProgramStudies(programStudies []string, limit, offset int) ([]model.Student, error) {
+	query := `
+		SELECT id, student_id, program_study, academic_year, advisor_id, retired_at, created_at
+		FROM students
+		WHERE retired_at IS NULL AND program_study = ANY($1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, programStudies, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStudents(rows), nil
+}
+
+// CountAllByProgramStudies - Hitung students aktif di program studi tertentu
+func (r *studentRepository) CountAllByProgramStudies(programStudies []string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM students WHERE retired_at IS NULL AND program_study = ANY($1)`
+	err := r.db.QueryRow(query, programStudies).Scan(&count)
+	return count, err
+}
+
+func scanStudents(rows *sql.Rows) []model.Student {
 	var students []model.Student
 	for rows.Next() {
 		var s model.Student
@@ -201,6 +246,7 @@ func (r *studentRepository) GetAll(limit, offset int) ([]model.Student, error) {
 			&s.ProgramStudy,
 			&academicYear,
 			&s.AdvisorID,
+			&s.RetiredAt,
 			&s.CreatedAt,
 		)
 		if err != nil {
@@ -211,13 +257,15 @@ func (r *studentRepository) GetAll(limit, offset int) ([]model.Student, error) {
 		s.AcademicYear = fmt.Sprintf("%d", academicYear)
 		students = append(students, s)
 	}
-	return students, nil
+	return students
 }
 
-// CountAll - Hitung total students
-func (r *studentRepository) CountAll() (int, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM students`
-	err := r.db.QueryRow(query).Scan(&count)
-	return count, err
+// SetRetired - Retire / aktifkan lagi profile student (data prestasi tetap disimpan)
+func (r *studentRepository) SetRetired(id string, retired bool) error {
+	query := `UPDATE students SET retired_at = NULL WHERE id = $1`
+	if retired {
+		query = `UPDATE students SET retired_at = NOW() WHERE id = $1`
+	}
+	_, err := r.db.Exec(query, id)
+	return err
 }
\ No newline at end of file
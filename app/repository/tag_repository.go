@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"project_uas/app/model"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TagRepository interface {
+	// PostgreSQL - Kosakata tag
+	GetAll() ([]model.Tag, error)
+	FindByID(id string) (*model.Tag, error)
+	Create(tag *model.Tag) error
+	Update(tag *model.Tag) error
+	Delete(id string) error
+	Merge(target *model.Tag, sourceIDs []string) error
+
+	// MongoDB - Tag yang dipakai achievement
+	CountUsage() (map[string]int, error)
+	RewriteAchievementTags(variants []string, canonical string) (int, error)
+}
+
+type tagRepository struct {
+	db      *sql.DB
+	mongoDB *mongo.Database
+}
+
+func NewTagRepository(db *sql.DB, mongoDB *mongo.Database) TagRepository {
+	return &tagRepository{
+		db:      db,
+		mongoDB: mongoDB,
+	}
+}
+
+// GetAll - Semua tag beserta sinonimnya, urut nama (kosakata kecil; pencarian
+// & pemetaan sinonim dilakukan di service)
+func (r *tagRepository) GetAll() ([]model.Tag, error) {
+	rows, err := r.db.Query(`SELECT id, name, created_at, updated_at FROM tags ORDER BY normalized ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []model.Tag
+	index := make(map[string]int)
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tag.Synonyms = []string{}
+		index[tag.ID] = len(tags)
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	synonyms, err := r.db.Query(`SELECT tag_id, synonym FROM tag_synonyms ORDER BY normalized ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer synonyms.Close()
+
+	for synonyms.Next() {
+		var tagID, synonym string
+		if err := synonyms.Scan(&tagID, &synonym); err != nil {
+			return nil, err
+		}
+		if i, ok := index[tagID]; ok {
+			tags[i].Synonyms = append(tags[i].Synonyms, synonym)
+		}
+	}
+	return tags, synonyms.Err()
+}
+
+// FindByID - Get tag by ID beserta sinonimnya
+func (r *tagRepository) FindByID(id string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.QueryRow(`SELECT id, name, created_at, updated_at FROM tags WHERE id = $1`, id).
+		Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT synonym FROM tag_synonyms WHERE tag_id = $1 ORDER BY normalized ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tag.Synonyms = []string{}
+	for rows.Next() {
+		var synonym string
+		if err := rows.Scan(&synonym); err != nil {
+			return nil, err
+		}
+		tag.Synonyms = append(tag.Synonyms, synonym)
+	}
+	return &tag, rows.Err()
+}
+
+// Create - Simpan tag baru beserta sinonimnya (satu transaksi)
+func (r *tagRepository) Create(tag *model.Tag) error {
+	tag.CreatedAt = time.Now()
+	tag.UpdatedAt = tag.CreatedAt
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO tags (name, normalized, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	if err := tx.QueryRow(query, tag.Name, model.NormalizeTag(tag.Name), tag.CreatedAt, tag.UpdatedAt).Scan(&tag.ID); err != nil {
+		return err
+	}
+	if err := replaceSynonyms(tx, tag); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update - Ganti nama & seluruh sinonim tag (satu transaksi)
+func (r *tagRepository) Update(tag *model.Tag) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateTag(tx, tag); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete - Hapus tag (sinonim ikut terhapus; achievement tetap menyimpan teksnya)
+func (r *tagRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM tags WHERE id = $1`, id)
+	return err
+}
+
+// Merge - Hapus tag sumber lalu simpan target dengan sinonim gabungan
+// (satu transaksi). Sumber dihapus dulu agar sinonimnya bisa dipindah.
+func (r *tagRepository) Merge(target *model.Tag, sourceIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(sourceIDs) > 0 {
+		if _, err := tx.Exec(`DELETE FROM tags WHERE id = ANY($1)`, sourceIDs); err != nil {
+			return err
+		}
+	}
+	if err := updateTag(tx, target); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CountUsage - Jumlah achievement (di luar trash) per teks tag persis seperti tersimpan
+func (r *tagRepository) CountUsage() (map[string]int, error) {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deletedAt": bson.M{"$exists": false}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Tag   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]int)
+	for _, result := range results {
+		usage[result.Tag] = result.Count
+	}
+	return usage, nil
+}
+
+// RewriteAchievementTags - Ganti tag achievement yang bentuk normalnya ada di
+// variants menjadi canonical (tag ganda dibuang, urutan dipertahankan).
+// updatedAt tidak diubah karena isi prestasi tidak berubah. Return jumlah
+// achievement yang ditulis ulang.
+func (r *tagRepository) RewriteAchievementTags(variants []string, canonical string) (int, error) {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	wanted := make(map[string]bool)
+	for _, variant := range variants {
+		wanted[model.NormalizeTag(variant)] = true
+	}
+
+	// Teks tag yang tersimpan bisa berbeda huruf besar / spasi
+	values, err := collection.Distinct(ctx, "tags", bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	matched := bson.A{}
+	for _, value := range values {
+		if text, ok := value.(string); ok && wanted[model.NormalizeTag(text)] {
+			matched = append(matched, text)
+		}
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"tags": 1})
+	cursor, err := collection.Find(ctx, bson.M{"tags": bson.M{"$in": matched}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []model.Achievement
+	if err := cursor.All(ctx, &achievements); err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, achievement := range achievements {
+		tags := make([]string, 0, len(achievement.Tags))
+		seen := make(map[string]bool)
+		for _, tag := range achievement.Tags {
+			if wanted[model.NormalizeTag(tag)] {
+				tag = canonical
+			}
+			if seen[model.NormalizeTag(tag)] {
+				continue
+			}
+			seen[model.NormalizeTag(tag)] = true
+			tags = append(tags, tag)
+		}
+		if slices.Equal(tags, achievement.Tags) {
+			continue
+		}
+
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": achievement.ID}, bson.M{"$set": bson.M{"tags": tags}})
+		if err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
+
+// Helper: updateTag - Update nama tag lalu ganti seluruh sinonimnya
+func updateTag(tx *sql.Tx, tag *model.Tag) error {
+	tag.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE tags
+		SET name = $1, normalized = $2, updated_at = $3
+		WHERE id = $4
+	`
+	result, err := tx.Exec(query, tag.Name, model.NormalizeTag(tag.Name), tag.UpdatedAt, tag.ID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+	return replaceSynonyms(tx, tag)
+}
+
+// Helper: replaceSynonyms
+func replaceSynonyms(tx *sql.Tx, tag *model.Tag) error {
+	if _, err := tx.Exec(`DELETE FROM tag_synonyms WHERE tag_id = $1`, tag.ID); err != nil {
+		return err
+	}
+	for _, synonym := range tag.Synonyms {
+		_, err := tx.Exec(
+			`INSERT INTO tag_synonyms (tag_id, synonym, normalized) VALUES ($1, $2, $3)`,
+			tag.ID, synonym, model.NormalizeTag(synonym),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"project_uas/app/model"
+	"time"
+)
+
+type TokenRevocationRepository interface {
+	Create(revocation *model.TokenRevocation) error
+	GetActive() ([]model.TokenRevocation, error)
+	DeleteExpired() error
+}
+
+type tokenRevocationRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRevocationRepository(db *sql.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db}
+}
+
+// Create - Simpan revocation baru (per jti atau per user)
+func (r *tokenRevocationRepository) Create(revocation *model.TokenRevocation) error {
+	revocation.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO token_revocations (kind, jti, user_id, revoked_before, reason, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	return r.db.QueryRow(query,
+		revocation.Kind,
+		revocation.JTI,
+		revocation.UserID,
+		revocation.RevokedBefore,
+		revocation.Reason,
+		revocation.ExpiresAt,
+		revocation.CreatedAt,
+	).Scan(&revocation.ID)
+}
+
+// GetActive - Ambil semua revocation yang belum expired (untuk mengisi cache)
+func (r *tokenRevocationRepository) GetActive() ([]model.TokenRevocation, error) {
+	query := `
+		SELECT id, kind, jti, user_id, revoked_before, reason, expires_at, created_at
+		FROM token_revocations
+		WHERE expires_at > $1
+	`
+	rows, err := r.db.Query(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revocations []model.TokenRevocation
+	for rows.Next() {
+		var rev model.TokenRevocation
+		var reason sql.NullString
+		if err := rows.Scan(
+			&rev.ID,
+			&rev.Kind,
+			&rev.JTI,
+			&rev.UserID,
+			&rev.RevokedBefore,
+			&reason,
+			&rev.ExpiresAt,
+			&rev.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rev.Reason = reason.String
+		revocations = append(revocations, rev)
+	}
+	return revocations, rows.Err()
+}
+
+// DeleteExpired - Hapus revocation yang token-nya pasti sudah expired
+func (r *tokenRevocationRepository) DeleteExpired() error {
+	_, err := r.db.Exec(`DELETE FROM token_revocations WHERE expires_at <= $1`, time.Now())
+	return err
+}
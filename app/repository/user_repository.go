@@ -12,7 +12,6 @@ type UserRepository interface {
 	// Auth methods (sudah ada)
 	FindByUsername(username string) (*model.User, error)
 	FindByID(id string) (*model.User, error)
-	GetRoleName(roleID string) (string, error)
 	GetPermissions(role string) ([]string, error)
 
 	// User management methods (BARU)
@@ -22,7 +21,14 @@ type UserRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	GetAll(limit, offset int, roleName string) ([]model.User, error)
 	CountAll(roleName string) (int, error)
-	UpdateRole(userID string, roleID string) error
+	GetAllByProgramStudies(programStudies []string, limit, offset int, roleName string) ([]model.User, error)
+	CountAllByProgramStudies(programStudies []string, roleName string) (int, error)
+	UpdatePassword(userID string, passwordHash string) error
+
+	// User roles (tabel user_roles)
+	GetRoles(userID string) ([]model.Role, error)
+	AddRole(userID string, roleID string, programStudy string) (bool, error)
+	RemoveRole(userID string, roleID string) (bool, error)
 }
 
 type userRepository struct {
@@ -40,7 +46,7 @@ func NewUserRepository(db *sql.DB) UserRepository {
 func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 	user := model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, is_active, created_at, updated_at
 		FROM users
 		WHERE username = $1 LIMIT 1
 	`
@@ -50,7 +56,6 @@ func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
-		&user.RoleID,
 		&user.IsActive,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -64,7 +69,7 @@ func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 func (r *userRepository) FindByID(id string) (*model.User, error) {
 	user := model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, is_active, created_at, updated_at
 		FROM users
 		WHERE id = $1 LIMIT 1
 	`
@@ -74,7 +79,6 @@ func (r *userRepository) FindByID(id string) (*model.User, error) {
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
-		&user.RoleID,
 		&user.IsActive,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -85,15 +89,6 @@ func (r *userRepository) FindByID(id string) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) GetRoleName(roleID string) (string, error) {
-	var role string
-	err := r.db.QueryRow("SELECT name FROM roles WHERE id=$1", roleID).Scan(&role)
-	if err != nil {
-		return "", err
-	}
-	return role, nil
-}
-
 func (r *userRepository) GetPermissions(role string) ([]string, error) {
 	rows, err := r.db.Query(`SELECT permission FROM role_permissions WHERE role=$1`, role)
 	if err != nil {
@@ -121,8 +116,8 @@ func (r *userRepository) Create(user *model.User) error {
 	user.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO users (id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO users (id, username, email, password_hash, full_name, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	_, err := r.db.Exec(query,
 		user.ID,
@@ -130,7 +125,6 @@ func (r *userRepository) Create(user *model.User) error {
 		user.Email,
 		user.PasswordHash,
 		user.FullName,
-		user.RoleID,
 		user.IsActive,
 		user.CreatedAt,
 		user.UpdatedAt,
@@ -168,7 +162,7 @@ func (r *userRepository) Delete(id string) error {
 func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	user := &model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, is_active, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -178,7 +172,6 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
-		&user.RoleID,
 		&user.IsActive,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -197,17 +190,16 @@ func (r *userRepository) GetAll(limit, offset int, roleName string) ([]model.Use
 
 	if roleName != "" {
 		query = `
-			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, u.is_active, u.created_at, u.updated_at
+			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.is_active, u.created_at, u.updated_at
 			FROM users u
-			JOIN roles r ON u.role_id = r.id
-			WHERE r.name = $1
+			WHERE `+hasRoleCondition("$1")+`
 			ORDER BY u.created_at DESC
 			LIMIT $2 OFFSET $3
 		`
 		rows, err = r.db.Query(query, roleName, limit, offset)
 	} else {
 		query = `
-			SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+			SELECT id, username, email, password_hash, full_name, is_active, created_at, updated_at
 			FROM users
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
@@ -220,26 +212,7 @@ func (r *userRepository) GetAll(limit, offset int, roleName string) ([]model.Use
 	}
 	defer rows.Close()
 
-	var users []model.User
-	for rows.Next() {
-		var u model.User
-		err := rows.Scan(
-			&u.ID,
-			&u.Username,
-			&u.Email,
-			&u.PasswordHash,
-			&u.FullName,
-			&u.RoleID,
-			&u.IsActive,
-			&u.CreatedAt,
-			&u.UpdatedAt,
-		)
-		if err != nil {
-			continue
-		}
-		users = append(users, u)
-	}
-	return users, nil
+	return scanUsers(rows), nil
 }
 
 // CountAll - Hitung total users dengan filter role
@@ -251,8 +224,7 @@ func (r *userRepository) CountAll(roleName string) (int, error) {
 		query = `
 			SELECT COUNT(*)
 			FROM users u
-			JOIN roles r ON u.role_id = r.id
-			WHERE r.name = $1
+			WHERE `+hasRoleCondition("$1")+`
 		`
 		err := r.db.QueryRow(query, roleName).Scan(&count)
 		return count, err
@@ -263,13 +235,155 @@ func (r *userRepository) CountAll(roleName string) (int, error) {
 	return count, err
 }
 
-// UpdateRole - Update role user
-func (r *userRepository) UpdateRole(userID string, roleID string) error {
+// GetAllByProgramStudies - Users dengan profil mahasiswa / dosen di program studi
+// (atau departemen) tertentu. Dipakai admin fakultas / prodi.
+func (r *userRepository) GetAllByProgramStudies(programStudies []string, limit, offset int, roleName string) ([]model.User, error) {
+	var query string
+	var rows *sql.Rows
+	var err error
+
+	if roleName != "" {
+		query = `
+			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.is_active, u.created_at, u.updated_at
+			FROM users u
+			WHERE ` + inProgramStudiesCondition + ` AND ` + hasRoleCondition("$2") + `
+			ORDER BY u.created_at DESC
+			LIMIT $3 OFFSET $4
+		`
+		rows, err = r.db.Query(query, programStudies, roleName, limit, offset)
+	} else {
+		query = `
+			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.is_active, u.created_at, u.updated_at
+			FROM users u
+			WHERE ` + inProgramStudiesCondition + `
+			ORDER BY u.created_at DESC
+			LIMIT $2 OFFSET $3
+		`
+		rows, err = r.db.Query(query, programStudies, limit, offset)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanUsers(rows), nil
+}
+
+// CountAllByProgramStudies - Hitung users di program studi / departemen tertentu
+func (r *userRepository) CountAllByProgramStudies(programStudies []string, roleName string) (int, error) {
+	var count int
+
+	if roleName != "" {
+		query := `SELECT COUNT(*) FROM users u WHERE ` + inProgramStudiesCondition + ` AND ` + hasRoleCondition("$2")
+		err := r.db.QueryRow(query, programStudies, roleName).Scan(&count)
+		return count, err
+	}
+
+	query := `SELECT COUNT(*) FROM users u WHERE ` + inProgramStudiesCondition
+	err := r.db.QueryRow(query, programStudies).Scan(&count)
+	return count, err
+}
+
+// Kondisi filter users (alias u): user punya profil di salah satu program studi $1
+const inProgramStudiesCondition = `(
+		EXISTS (SELECT 1 FROM students s WHERE s.id = u.id AND s.program_study = ANY($1))
+		OR EXISTS (SELECT 1 FROM lecturers l WHERE l.id = u.id AND l.department = ANY($1))
+	)`
+
+// hasRoleCondition - Kondisi filter users (alias u) yang punya role bernama placeholder
+func hasRoleCondition(placeholder string) string {
+	return `u.id IN (
+		SELECT ur.user_id FROM user_roles ur JOIN roles r ON ur.role_id = r.id WHERE r.name = ` + placeholder + `
+	)`
+}
+
+func scanUsers(rows *sql.Rows) []model.User {
+	var users []model.User
+	for rows.Next() {
+		var u model.User
+		err := rows.Scan(
+			&u.ID,
+			&u.Username,
+			&u.Email,
+			&u.PasswordHash,
+			&u.FullName,
+			&u.IsActive,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+		)
+		if err != nil {
+			continue
+		}
+		users = append(users, u)
+	}
+	return users
+}
+
+// UpdatePassword - Update password hash user
+func (r *userRepository) UpdatePassword(userID string, passwordHash string) error {
 	query := `
 		UPDATE users
-		SET role_id = $1, updated_at = $2
+		SET password_hash = $1, updated_at = $2
 		WHERE id = $3
 	`
-	_, err := r.db.Exec(query, roleID, time.Now(), userID)
+	_, err := r.db.Exec(query, passwordHash, time.Now(), userID)
 	return err
-}
\ No newline at end of file
+}
+
+
+//
+// ==================== USER ROLES ======================
+//
+
+// GetRoles - Semua role assignment milik user. Role yang di-assign ke beberapa
+// program studi muncul sekali per program studi.
+func (r *userRepository) GetRoles(userID string) ([]model.Role, error) {
+	rows, err := r.db.Query(`
+		SELECT r.id, r.name, COALESCE(r.description, ''), r.mfa_required, r.is_builtin, r.created_at, ur.program_study
+		FROM user_roles ur
+		JOIN roles r ON ur.role_id = r.id
+		WHERE ur.user_id = $1
+		ORDER BY r.name, ur.program_study
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		var role model.Role
+		err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.MFARequired, &role.IsBuiltin, &role.CreatedAt, &role.ScopeProgramStudy)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// AddRole - Tambah role ke user, opsional dibatasi program studi ('' = semua).
+// false jika assignment yang sama sudah ada.
+func (r *userRepository) AddRole(userID string, roleID string, programStudy string) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO user_roles (user_id, role_id, program_study)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, userID, roleID, programStudy)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// RemoveRole - Cabut role dari user (semua program studi). false jika user tidak punya role tsb.
+func (r *userRepository) RemoveRole(userID string, roleID string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`, userID, roleID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== ACADEMIC CALENDAR ======================
+// Admin mengatur rentang tanggal tiap semester ("2025/2026 Ganjil").
+// Achievement dipetakan ke semester lewat tanggal kegiatannya (event_date),
+// dan laporan / daftar achievement bisa difilter per semester.
+//
+
+type AcademicCalendarService struct {
+	calendarRepo repository.AcademicCalendarRepository
+	audit        *AuditService
+	validate     *validator.Validate
+}
+
+func NewAcademicCalendarService(
+	calendarRepo repository.AcademicCalendarRepository,
+	audit *AuditService,
+) *AcademicCalendarService {
+	return &AcademicCalendarService{
+		calendarRepo: calendarRepo,
+		audit:        audit,
+		validate:     validator.New(),
+	}
+}
+
+const dateLayout = "2006-01-02"
+
+var academicYearPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
+//
+// ==================== GET SEMESTERS (GET /academic-calendar) ======================
+// ?date=YYYY-MM-DD → hanya semester yang memuat tanggal tersebut
+//
+
+func (s *AcademicCalendarService) GetSemesters(c *fiber.Ctx) error {
+	semesters, err := s.calendarRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch academic calendar",
+		})
+	}
+	if semesters == nil {
+		semesters = []model.AcademicSemester{}
+	}
+
+	if value := c.Query("date"); value != "" {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "date must be in YYYY-MM-DD format",
+			})
+		}
+		matched := []model.AcademicSemester{}
+		if semester := semesterFor(semesters, date); semester != nil {
+			matched = append(matched, *semester)
+		}
+		semesters = matched
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"semesters": semesters,
+			"total":     len(semesters),
+		},
+	})
+}
+
+//
+// ==================== CREATE SEMESTER (POST /academic-calendar) ======================
+//
+
+func (s *AcademicCalendarService) CreateSemester(c *fiber.Ctx) error {
+	semester, err := s.parseSemester(c, "")
+	if semester == nil {
+		return err
+	}
+
+	if err := s.calendarRepo.Create(semester); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create semester",
+		})
+	}
+
+	s.audit.Record(c, "academic_semester.create", "academic_semester", semester.ID, semesterAuditDetails(semester))
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "semester created successfully",
+		Data:    semester,
+	})
+}
+
+//
+// ==================== UPDATE SEMESTER (PUT /academic-calendar/:id) ======================
+//
+
+func (s *AcademicCalendarService) UpdateSemester(c *fiber.Ctx) error {
+	existing, err := s.calendarRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "semester not found",
+		})
+	}
+
+	semester, err := s.parseSemester(c, existing.ID)
+	if semester == nil {
+		return err
+	}
+	semester.ID = existing.ID
+	semester.CreatedAt = existing.CreatedAt
+
+	if err := s.calendarRepo.Update(semester); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update semester",
+		})
+	}
+
+	s.audit.Record(c, "academic_semester.update", "academic_semester", semester.ID, semesterAuditDetails(semester))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "semester updated successfully",
+		Data:    semester,
+	})
+}
+
+//
+// ==================== DELETE SEMESTER (DELETE /academic-calendar/:id) ======================
+// Achievement hanya menyimpan tanggal kegiatan, jadi tidak ada yang ikut terhapus
+//
+
+func (s *AcademicCalendarService) DeleteSemester(c *fiber.Ctx) error {
+	semester, err := s.calendarRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "semester not found",
+		})
+	}
+
+	if err := s.calendarRepo.Delete(semester.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete semester",
+		})
+	}
+
+	s.audit.Record(c, "academic_semester.delete", "academic_semester", semester.ID, semesterAuditDetails(semester))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "semester deleted successfully",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// parseSemester - Parse & validasi request; semester lain (selain excludeID)
+// tidak boleh bernama sama atau rentangnya tumpang tindih. Return nil semester
+// jika response error sudah dikirim.
+func (s *AcademicCalendarService) parseSemester(c *fiber.Ctx, excludeID string) (*model.AcademicSemester, error) {
+	req := new(model.AcademicSemesterRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	invalid := func(message string) (*model.AcademicSemester, error) {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  message,
+		})
+	}
+
+	years := academicYearPattern.FindStringSubmatch(req.AcademicYear)
+	if years == nil {
+		return invalid("academic_year must look like 2025/2026")
+	}
+	first, _ := strconv.Atoi(years[1])
+	second, _ := strconv.Atoi(years[2])
+	if second != first+1 {
+		return invalid("academic_year must span two consecutive years")
+	}
+
+	startDate, _ := time.Parse(dateLayout, req.StartDate)
+	endDate, _ := time.Parse(dateLayout, req.EndDate)
+	if endDate.Before(startDate) {
+		return invalid("end_date must not be before start_date")
+	}
+
+	semester := &model.AcademicSemester{
+		Name:         req.AcademicYear + " " + req.Term,
+		AcademicYear: req.AcademicYear,
+		Term:         req.Term,
+		StartDate:    startDate,
+		EndDate:      endDate,
+	}
+
+	others, err := s.calendarRepo.GetAll()
+	if err != nil {
+		return nil, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch academic calendar",
+		})
+	}
+	for _, other := range others {
+		if other.ID == excludeID {
+			continue
+		}
+		if other.Name == semester.Name {
+			return nil, c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("semester '%s' already exists", semester.Name),
+			})
+		}
+		if other.Contains(semester.StartDate) || other.Contains(semester.EndDate) || semester.Contains(other.StartDate) {
+			return nil, c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("dates overlap with semester '%s'", other.Name),
+			})
+		}
+	}
+
+	return semester, nil
+}
+
+func semesterAuditDetails(semester *model.AcademicSemester) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       semester.Name,
+		"start_date": semester.StartDate.Format(dateLayout),
+		"end_date":   semester.EndDate.Format(dateLayout),
+	}
+}
+
+// semesterFor - Semester yang memuat tanggal (nil jika tidak ada / tanggal kosong)
+func semesterFor(semesters []model.AcademicSemester, date time.Time) *model.AcademicSemester {
+	if date.IsZero() {
+		return nil
+	}
+	for i := range semesters {
+		if semesters[i].Contains(date) {
+			return &semesters[i]
+		}
+	}
+	return nil
+}
+
+// semesterName - Nama semester yang memuat tanggal (nil jika tidak ada)
+func semesterName(semesters []model.AcademicSemester, date time.Time) *string {
+	if semester := semesterFor(semesters, date); semester != nil {
+		return &semester.Name
+	}
+	return nil
+}
+
+// loadSemesters - Kalender akademik untuk pemetaan semester di response;
+// gagal dibaca = tanpa semester (tidak menggagalkan request)
+func loadSemesters(calendarRepo repository.AcademicCalendarRepository) []model.AcademicSemester {
+	semesters, err := calendarRepo.GetAll()
+	if err != nil {
+		return nil
+	}
+	return semesters
+}
+
+//
+// ==================== EVENT DATE ======================
+//
+
+// eventDateOf - Tanggal kegiatan achievement. Data lama tanpa event_date
+// memakai tanggal input (CreatedAt).
+func eventDateOf(achievement *model.Achievement) time.Time {
+	if achievement.EventDate.IsZero() {
+		return achievement.CreatedAt
+	}
+	return achievement.EventDate
+}
+
+// checkEventDates - Tanggal selesai tidak boleh sebelum tanggal mulai.
+// sent = true jika response error sudah dikirim.
+func checkEventDates(c *fiber.Ctx, achievement *model.Achievement) (bool, error) {
+	if achievement.EventEndDate != nil && achievement.EventEndDate.Before(achievement.EventDate) {
+		return true, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "event_end_date must not be before event_date",
+		})
+	}
+	return false, nil
+}
+
+// EventFilter - Filter tanggal kegiatan dari query (?semester=, ?event_from=,
+// ?event_to=); batas inklusif, nil = tanpa batas
+type EventFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Semester *model.AcademicSemester
+}
+
+// Active - Ada batas tanggal yang harus diterapkan
+func (f *EventFilter) Active() bool {
+	return f.From != nil || f.To != nil
+}
+
+// eventFilterFromQuery - Semester dipersempit dengan event_from / event_to jika
+// keduanya diisi. Return nil jika response error sudah dikirim.
+func eventFilterFromQuery(c *fiber.Ctx, calendarRepo repository.AcademicCalendarRepository) (*EventFilter, error) {
+	filter := &EventFilter{}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{{"event_from", &filter.From}, {"event_to", &filter.To}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("%s must be in YYYY-MM-DD format", param.name),
+			})
+		}
+		*param.target = &date
+	}
+
+	if id := c.Query("semester"); id != "" {
+		semester, err := calendarRepo.FindByID(id)
+		if err != nil {
+			return nil, c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "semester not found",
+			})
+		}
+		filter.Semester = semester
+		if filter.From == nil || filter.From.Before(semester.StartDate) {
+			filter.From = &semester.StartDate
+		}
+		if filter.To == nil || filter.To.After(semester.EndDate) {
+			filter.To = &semester.EndDate
+		}
+	}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "event_to must not be before event_from",
+		})
+	}
+	return filter, nil
+}
+
+// filterByEventDate - Reference yang tanggal kegiatan achievement-nya masuk filter
+func filterByEventDate(repo repository.AchievementRepository, filter *EventFilter, references []model.AchievementReference) ([]model.AchievementReference, error) {
+	if !filter.Active() {
+		return references, nil
+	}
+	ids, err := repo.GetAchievementIDsByEventDate(filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []model.AchievementReference{}
+	for _, ref := range references {
+		if ids[ref.MongoAchievementID] {
+			filtered = append(filtered, ref)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupAcademicCalendarTest() (*AcademicCalendarService, *mocks.MockAcademicCalendarRepository) {
+	mockCalendarRepo := new(mocks.MockAcademicCalendarRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	service := NewAcademicCalendarService(mockCalendarRepo, NewAuditService(mockAuditRepo))
+
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+	return service, mockCalendarRepo
+}
+
+func day(value string) time.Time {
+	parsed, _ := time.Parse(dateLayout, value)
+	return parsed
+}
+
+// ganjil2025 - Semester "2025/2026 Ganjil" (Agustus 2025 - Januari 2026)
+func ganjil2025() model.AcademicSemester {
+	return model.AcademicSemester{
+		ID:           "semester-1",
+		Name:         "2025/2026 Ganjil",
+		AcademicYear: "2025/2026",
+		Term:         model.SemesterTermGanjil,
+		StartDate:    day("2025-08-01"),
+		EndDate:      day("2026-01-31"),
+	}
+}
+
+// ==================== SEMESTER MAPPING ====================
+
+func TestSemesterFor(t *testing.T) {
+	semesters := []model.AcademicSemester{ganjil2025()}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"hari pertama", day("2025-08-01"), true},
+		{"hari terakhir (dengan jam)", day("2026-01-31").Add(23 * time.Hour), true},
+		{"sebelum semester", day("2025-07-31"), false},
+		{"tanggal kosong", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, semesterFor(semesters, tt.date) != nil)
+		})
+	}
+}
+
+// ==================== CREATE SEMESTER ====================
+
+func TestCreateSemester(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			"genap setelah ganjil",
+			`{"academic_year": "2025/2026", "term": "Genap", "start_date": "2026-02-01", "end_date": "2026-07-31"}`,
+			201,
+		},
+		{
+			"tumpang tindih",
+			`{"academic_year": "2025/2026", "term": "Genap", "start_date": "2026-01-15", "end_date": "2026-07-31"}`,
+			409,
+		},
+		{
+			"sudah ada",
+			`{"academic_year": "2025/2026", "term": "Ganjil", "start_date": "2030-08-01", "end_date": "2031-01-31"}`,
+			409,
+		},
+		{
+			"tahun akademik tidak berurutan",
+			`{"academic_year": "2025/2027", "term": "Genap", "start_date": "2026-02-01", "end_date": "2026-07-31"}`,
+			422,
+		},
+		{
+			"tanggal selesai sebelum mulai",
+			`{"academic_year": "2025/2026", "term": "Genap", "start_date": "2026-07-31", "end_date": "2026-02-01"}`,
+			422,
+		},
+		{
+			"term tidak dikenal",
+			`{"academic_year": "2025/2026", "term": "Pendek", "start_date": "2026-07-01", "end_date": "2026-07-31"}`,
+			422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockCalendarRepo := setupAcademicCalendarTest()
+			mockCalendarRepo.On("GetAll").Return([]model.AcademicSemester{ganjil2025()}, nil).Maybe()
+			mockCalendarRepo.On("Create", mock.AnythingOfType("*model.AcademicSemester")).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/academic-calendar", service.CreateSemester)
+
+			req := httptest.NewRequest("POST", "/academic-calendar", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				mockCalendarRepo.AssertCalled(t, "Create", mock.MatchedBy(func(semester *model.AcademicSemester) bool {
+					return semester.Name == "2025/2026 Genap" && semester.StartDate.Equal(day("2026-02-01"))
+				}))
+			} else {
+				mockCalendarRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateSemester_KeepsOwnRange(t *testing.T) {
+	service, mockCalendarRepo := setupAcademicCalendarTest()
+	existing := ganjil2025()
+	mockCalendarRepo.On("FindByID", "semester-1").Return(&existing, nil)
+	mockCalendarRepo.On("GetAll").Return([]model.AcademicSemester{existing}, nil)
+	mockCalendarRepo.On("Update", mock.AnythingOfType("*model.AcademicSemester")).Return(nil)
+
+	app := fiber.New()
+	app.Put("/academic-calendar/:id", service.UpdateSemester)
+
+	body := `{"academic_year": "2025/2026", "term": "Ganjil", "start_date": "2025-09-01", "end_date": "2026-01-31"}`
+	req := httptest.NewRequest("PUT", "/academic-calendar/semester-1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockCalendarRepo.AssertCalled(t, "Update", mock.MatchedBy(func(semester *model.AcademicSemester) bool {
+		return semester.ID == "semester-1" && semester.StartDate.Equal(day("2025-09-01"))
+	}))
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project_uas/app/model"
+)
+
+//
+// ==================== ACHIEVEMENT VERSION DIFF ======================
+// Perbandingan field-level dua snapshot achievement. Details dibandingkan per
+// key (nested map jadi "details.a.b"), anggota tim per student_id, tags dan
+// attachments sebagai himpunan (attachment dikenali dari file_url).
+//
+
+func diffAchievements(from, to *model.AchievementVersion) model.AchievementDiff {
+	diff := model.AchievementDiff{
+		FromVersion:        from.Version,
+		ToVersion:          to.Version,
+		Fields:             []model.FieldChange{},
+		TagsAdded:          []string{},
+		TagsRemoved:        []string{},
+		AttachmentsAdded:   []model.Attachment{},
+		AttachmentsRemoved: []model.Attachment{},
+	}
+	old, cur := from.Snapshot, to.Snapshot
+
+	addChange := func(field string, oldValue, newValue interface{}) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diff.Fields = append(diff.Fields, model.FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	addChange("achievement_type", old.AchievementType, cur.AchievementType)
+	addChange("title", old.Title, cur.Title)
+	addChange("description", old.Description, cur.Description)
+	addChange("points", old.Points, cur.Points)
+
+	// Details
+	oldDetails, newDetails := map[string]interface{}{}, map[string]interface{}{}
+	flattenDetails("details", old.Details, oldDetails)
+	flattenDetails("details", cur.Details, newDetails)
+
+	keys := []string{}
+	for key := range oldDetails {
+		keys = append(keys, key)
+	}
+	for key := range newDetails {
+		if _, ok := oldDetails[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		addChange(key, oldDetails[key], newDetails[key])
+	}
+
+	// Team (per anggota: "team.<student_id>" = peran, nil = masuk / keluar tim)
+	oldRoles, newRoles := map[string]interface{}{}, map[string]interface{}{}
+	for _, member := range old.Team {
+		oldRoles["team."+member.StudentID] = member.Role
+	}
+	for _, member := range cur.Team {
+		newRoles["team."+member.StudentID] = member.Role
+	}
+	members := []string{}
+	for key := range oldRoles {
+		members = append(members, key)
+	}
+	for key := range newRoles {
+		if _, ok := oldRoles[key]; !ok {
+			members = append(members, key)
+		}
+	}
+	sort.Strings(members)
+	for _, key := range members {
+		addChange(key, oldRoles[key], newRoles[key])
+	}
+
+	// Tags
+	for _, tag := range cur.Tags {
+		if !contains(old.Tags, tag) {
+			diff.TagsAdded = append(diff.TagsAdded, tag)
+		}
+	}
+	for _, tag := range old.Tags {
+		if !contains(cur.Tags, tag) {
+			diff.TagsRemoved = append(diff.TagsRemoved, tag)
+		}
+	}
+
+	// Attachments
+	for _, attachment := range cur.Attachments {
+		if !hasAttachment(old.Attachments, attachment.FileURL) {
+			diff.AttachmentsAdded = append(diff.AttachmentsAdded, attachment)
+		}
+	}
+	for _, attachment := range old.Attachments {
+		if !hasAttachment(cur.Attachments, attachment.FileURL) {
+			diff.AttachmentsRemoved = append(diff.AttachmentsRemoved, attachment)
+		}
+	}
+
+	return diff
+}
+
+// flattenDetails - Nested document jadi key bertitik. Dokumen dari MongoDB bisa
+// berupa primitive.M / primitive.D, bukan map[string]interface{}.
+func flattenDetails(prefix string, value interface{}, out map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenDetails(prefix+"."+key, child, out)
+		}
+	case primitive.M:
+		flattenDetails(prefix, map[string]interface{}(v), out)
+	case primitive.D:
+		for _, element := range v {
+			flattenDetails(prefix+"."+element.Key, element.Value, out)
+		}
+	default:
+		if prefix != "details" {
+			out[prefix] = value
+		}
+	}
+}
+
+func hasAttachment(attachments []model.Attachment, fileURL string) bool {
+	for _, attachment := range attachments {
+		if attachment.FileURL == fileURL {
+			return true
+		}
+	}
+	return false
+}
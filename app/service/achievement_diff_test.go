@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project_uas/app/model"
+)
+
+// ==================== VERSION DIFF ====================
+
+func TestDiffAchievements(t *testing.T) {
+	from := &model.AchievementVersion{
+		Version: 1,
+		Snapshot: model.Achievement{
+			AchievementType: "competition",
+			Title:           "Lomba Coding",
+			Points:          10,
+			Details: map[string]interface{}{
+				// Nested document hasil decode MongoDB
+				"competition": primitive.D{{Key: "level", Value: "regional"}, {Key: "rank", Value: int32(2)}},
+				"organizer":   "Kampus A",
+			},
+			Tags: []string{"coding", "tim"},
+			Attachments: []model.Attachment{
+				{FileName: "sertifikat.pdf", FileURL: "/uploads/a.pdf"},
+			},
+		},
+	}
+	to := &model.AchievementVersion{
+		Version: 3,
+		Snapshot: model.Achievement{
+			AchievementType: "competition",
+			Title:           "Lomba Coding",
+			Points:          25,
+			Details: map[string]interface{}{
+				"competition": primitive.M{"level": "national", "rank": int32(2)},
+				"location":    "Jakarta",
+			},
+			Tags: []string{"coding", "nasional"},
+			Attachments: []model.Attachment{
+				{FileName: "sertifikat.pdf", FileURL: "/uploads/a.pdf"},
+				{FileName: "foto.jpg", FileURL: "/uploads/b.jpg"},
+			},
+		},
+	}
+
+	diff := diffAchievements(from, to)
+
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 3, diff.ToVersion)
+	assert.Equal(t, []model.FieldChange{
+		{Field: "points", Old: 10, New: 25},
+		{Field: "details.competition.level", Old: "regional", New: "national"},
+		{Field: "details.location", Old: nil, New: "Jakarta"},
+		{Field: "details.organizer", Old: "Kampus A", New: nil},
+	}, diff.Fields)
+	assert.Equal(t, []string{"nasional"}, diff.TagsAdded)
+	assert.Equal(t, []string{"tim"}, diff.TagsRemoved)
+	assert.Len(t, diff.AttachmentsAdded, 1)
+	assert.Equal(t, "/uploads/b.jpg", diff.AttachmentsAdded[0].FileURL)
+	assert.Empty(t, diff.AttachmentsRemoved)
+}
+
+func TestDiffAchievements_NoChanges(t *testing.T) {
+	version := &model.AchievementVersion{Version: 1, Snapshot: model.Achievement{Title: "Lomba", Tags: []string{"coding"}}}
+
+	diff := diffAchievements(version, version)
+
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.TagsAdded)
+	assert.Empty(t, diff.TagsRemoved)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+)
+
+//
+// ==================== ACHIEVEMENT POINTS ======================
+// Poin achievement = poin rule yang cocok (lihat matchPointRule), dihitung
+// ulang setiap submit. Verifikator stage aktif boleh mengubahnya dengan
+// justifikasi selama submitted; saat verified poin dikunci (points_frozen_at).
+//
+
+// calculatePoints - Poin & rule yang dipakai (rule nil = tidak ada yang cocok, poin 0)
+func (s *AchievementService) calculatePoints(achievement *model.Achievement) (int, *model.PointRule, error) {
+	rules, err := s.pointRuleRepo.GetByType(achievement.AchievementType)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rule := matchPointRule(rules, achievement)
+	if rule == nil {
+		return 0, nil, nil
+	}
+	return rule.Points, rule, nil
+}
+
+//
+// ==================== GET POINTS (GET /achievements/:id/points) ======================
+// Poin, rule yang dipakai, dan riwayat penyesuaian. Draft mendapat perkiraan
+// dari rule saat ini (estimated = true).
+//
+
+func (s *AchievementService) GetPoints(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	points := reference.Points
+	ruleID := reference.PointRuleID
+	estimated := false
+	if points == nil {
+		achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+		if err != nil {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "achievement detail not found",
+			})
+		}
+		value, rule, err := s.calculatePoints(achievement)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to calculate points",
+			})
+		}
+		points, estimated = &value, true
+		if rule != nil {
+			ruleID = &rule.ID
+		}
+	}
+
+	var rule *model.PointRule
+	if ruleID != nil {
+		rule, _ = s.pointRuleRepo.FindByID(*ruleID) // rule bisa sudah dihapus
+	}
+
+	adjustments, err := s.pointRuleRepo.GetAdjustments(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch point adjustments",
+		})
+	}
+	if adjustments == nil {
+		adjustments = []model.PointAdjustment{}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_id":   reference.ID,
+			"points":           *points,
+			"estimated":        estimated,
+			"rule":             rule,
+			"frozen":           reference.PointsFrozenAt != nil,
+			"points_frozen_at": reference.PointsFrozenAt,
+			"adjustments":      adjustments,
+		},
+	})
+}
+
+//
+// ==================== ADJUST POINTS (PUT /achievements/:id/points) ======================
+// Hanya pemegang stage aktif (atau delegasinya), hanya selama submitted
+//
+
+func (s *AchievementService) AdjustPoints(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization: pemegang stage aktif (termasuk delegasi dosen wali)
+	decision, err := s.authorizeStage(c, claims, reference)
+	if decision == nil {
+		return err
+	}
+
+	if reference.Status != model.AchievementStatusSubmitted || reference.PointsFrozenAt != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "points can only be adjusted while the achievement is awaiting verification",
+		})
+	}
+
+	req := new(model.AdjustPointsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	req.Justification = strings.TrimSpace(req.Justification)
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	adjustment := &model.PointAdjustment{
+		ToPoints:      *req.Points,
+		Justification: req.Justification,
+		AdjustedBy:    claims.UserID,
+		OnBehalfOf:    optionalString(decision.onBehalfOf),
+	}
+	if err := s.pointRuleRepo.AdjustPoints(reference, adjustment); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "achievement is no longer awaiting verification",
+			})
+		}
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to adjust points",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "points adjusted successfully",
+		Data:    adjustment,
+	})
+}
@@ -1,19 +1,27 @@
 package service
 
 import (
+	"database/sql"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
 	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"project_uas/app/model"
 	"project_uas/app/repository"
+	"project_uas/utils"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AchievementService struct {
@@ -21,6 +29,14 @@ type AchievementService struct {
 	studentRepo     repository.StudentRepository
 	lecturerRepo    repository.LecturerRepository
 	userRepo        repository.UserRepository
+	pipelineRepo    repository.PipelineRepository
+	typeRepo        repository.AchievementTypeRepository
+	pointRuleRepo   repository.PointRuleRepository
+	duplicateRepo   repository.DuplicateRepository
+	calendarRepo    repository.AcademicCalendarRepository
+	tagRepo         repository.TagRepository
+	authz           *Authorizer
+	tagPolicy       TagPolicy
 	validate        *validator.Validate
 }
 
@@ -29,12 +45,28 @@ func NewAchievementService(
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
 	userRepo repository.UserRepository,
+	pipelineRepo repository.PipelineRepository,
+	typeRepo repository.AchievementTypeRepository,
+	pointRuleRepo repository.PointRuleRepository,
+	duplicateRepo repository.DuplicateRepository,
+	calendarRepo repository.AcademicCalendarRepository,
+	tagRepo repository.TagRepository,
+	authz *Authorizer,
+	tagPolicy TagPolicy,
 ) *AchievementService {
 	return &AchievementService{
 		achievementRepo: achievementRepo,
 		studentRepo:     studentRepo,
 		lecturerRepo:    lecturerRepo,
 		userRepo:        userRepo,
+		pipelineRepo:    pipelineRepo,
+		typeRepo:        typeRepo,
+		pointRuleRepo:   pointRuleRepo,
+		duplicateRepo:   duplicateRepo,
+		calendarRepo:    calendarRepo,
+		tagRepo:         tagRepo,
+		authz:           authz,
+		tagPolicy:       tagPolicy,
 		validate:        validator.New(),
 	}
 }
@@ -80,19 +112,42 @@ func (s *AchievementService) CreateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validasi details sesuai schema jenis prestasi
+	if sent, err := s.checkDetails(c, req.AchievementType, req.Details, true); sent {
+		return err
+	}
+
+	// Prestasi tim: anggota harus terdaftar dan memuat pembuat
+	if sent, err := s.checkTeam(c, student.ID, req.Team); sent {
+		return err
+	}
+
 	// Create achievement di MongoDB
 	achievement := &model.Achievement{
 		StudentID:       student.ID,
+		Team:            req.Team,
 		AchievementType: req.AchievementType,
 		Title:           req.Title,
 		Description:     req.Description,
 		Details:         req.Details,
 		Tags:            req.Tags,
-		Points:          req.Points,
 		Attachments:     []model.Attachment{}, // empty initially
 	}
+	achievement.EventDate, _ = time.Parse(dateLayout, req.EventDate)
+	if req.EventEndDate != "" {
+		eventEndDate, _ := time.Parse(dateLayout, req.EventEndDate)
+		achievement.EventEndDate = &eventEndDate
+	}
+	if sent, err := checkEventDates(c, achievement); sent {
+		return err
+	}
+
+	// Tag dalam nama kanonik (dan hanya dari kosakata jika diwajibkan)
+	if sent, err := s.checkTags(c, &achievement.Tags); sent {
+		return err
+	}
 
-	mongoID, err := s.achievementRepo.CreateAchievement(achievement)
+	mongoID, err := s.achievementRepo.CreateAchievement(achievement, contentEdit(claims))
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -104,10 +159,27 @@ func (s *AchievementService) CreateAchievement(c *fiber.Ctx) error {
 	reference := &model.AchievementReference{
 		StudentID:          student.ID,
 		MongoAchievementID: mongoID,
-		Status:             "draft", // Status awal: draft
+		Status:             model.AchievementStatusDraft, // Status awal: draft
 	}
 
-	if err := s.achievementRepo.CreateReference(reference); err != nil {
+	// Prestasi tim: satu reference per anggota (reference pembuat yang dikembalikan)
+	if len(achievement.Team) > 0 {
+		references := []*model.AchievementReference{reference}
+		for _, member := range achievement.Team {
+			if member.StudentID == student.ID {
+				continue
+			}
+			references = append(references, &model.AchievementReference{
+				StudentID:          member.StudentID,
+				MongoAchievementID: mongoID,
+				Status:             model.AchievementStatusDraft,
+			})
+		}
+		err = s.achievementRepo.CreateReferences(references, statusChange(claims, nil, ""))
+	} else {
+		err = s.achievementRepo.CreateReference(reference, statusChange(claims, nil, ""))
+	}
+	if err != nil {
 		// Rollback: hapus achievement di MongoDB
 		s.achievementRepo.DeleteAchievement(mongoID)
 		return c.Status(500).JSON(model.APIResponse{
@@ -118,6 +190,7 @@ func (s *AchievementService) CreateAchievement(c *fiber.Ctx) error {
 
 	// Build response
 	response := s.buildAchievementResponse(achievement, reference, mongoID)
+	response.Semester = semesterName(loadSemesters(s.calendarRepo), achievement.EventDate)
 
 	return c.Status(201).JSON(model.APIResponse{
 		Status:  "success",
@@ -145,7 +218,7 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 
 	// Get reference dari PostgreSQL
 	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
-	if err != nil {
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
 		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "achievement not found",
@@ -153,28 +226,22 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 	}
 
 	// Check authorization
-	if claims.Role == "Mahasiswa" {
-		student, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if student == nil || student.ID != reference.StudentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		student, _ := s.studentRepo.FindByID(reference.StudentID)
-		if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Build history
-	history := s.buildAchievementHistory(reference)
+	rows, err := s.achievementRepo.GetStatusHistory(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievement history",
+		})
+	}
+	history := s.buildAchievementHistory(rows)
 
 	return c.JSON(model.APIResponse{
 		Status: "success",
@@ -188,99 +255,248 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 
 //
 // ==================== HELPER: BUILD ACHIEVEMENT HISTORY ======================
+// History dibaca dari tabel achievement_status_history (satu baris per transisi)
 //
 
 type HistoryEntry struct {
-	Status    string  `json:"status"`
-	Timestamp string  `json:"timestamp"`
-	Actor     string  `json:"actor,omitempty"`
-	ActorID   *string `json:"actor_id,omitempty"`
-	Action    string  `json:"action"`
-	Notes     *string `json:"notes,omitempty"`
+	Status       string  `json:"status"`
+	FromStatus   *string `json:"from_status,omitempty"`
+	Timestamp    string  `json:"timestamp"`
+	Actor        string  `json:"actor,omitempty"`
+	ActorID      *string `json:"actor_id,omitempty"`
+	ActorRole    string  `json:"actor_role,omitempty"`
+	OnBehalfOf   string  `json:"on_behalf_of,omitempty"` // dosen wali yang diwakili (delegasi)
+	OnBehalfOfID *string `json:"on_behalf_of_id,omitempty"`
+	Action       string  `json:"action"`
+	Notes        *string `json:"notes,omitempty"`
+	Stage        *int    `json:"stage,omitempty"` // stage pipeline (verify / reject / revision)
 }
 
-func (s *AchievementService) buildAchievementHistory(reference *model.AchievementReference) []HistoryEntry {
-	var history []HistoryEntry
+// Deskripsi aksi per status tujuan
+var historyActions = map[string]string{
+	model.AchievementStatusDraft:             "Achievement created",
+	model.AchievementStatusSubmitted:         "Submitted for verification",
+	model.AchievementStatusVerified:          "Achievement verified",
+	model.AchievementStatusRejected:          "Achievement rejected",
+	model.AchievementStatusRevisionRequested: "Revision requested",
+	model.AchievementStatusDeleted:           "Achievement deleted",
+}
 
-	// 1. Draft/Created
-	history = append(history, HistoryEntry{
-		Status:    "draft",
-		Timestamp: reference.CreatedAt.Format("2006-01-02 15:04:05"),
-		Action:    "Achievement created",
-		Notes:     nil,
-	})
+func (s *AchievementService) buildAchievementHistory(rows []model.AchievementStatusHistory) []HistoryEntry {
+	history := []HistoryEntry{}
+	names := map[string]string{} // cache nama user per ID
+
+	for _, row := range rows {
+		entry := HistoryEntry{
+			Status:     row.ToStatus,
+			FromStatus: row.FromStatus,
+			Timestamp:  row.CreatedAt.Format("2006-01-02 15:04:05"),
+			ActorID:    row.ActorID,
+			ActorRole:  row.ActorRole,
+			Action:     historyActions[row.ToStatus],
+			Notes:      row.Note,
+			Stage:      row.Stage,
+		}
+		if entry.Action == "" {
+			entry.Action = "Status changed to " + row.ToStatus
+		}
 
-	// 2. Submitted (jika ada)
-	if reference.SubmittedAt != nil {
-		history = append(history, HistoryEntry{
-			Status:    "submitted",
-			Timestamp: reference.SubmittedAt.Format("2006-01-02 15:04:05"),
-			Action:    "Submitted for verification",
-			Notes:     nil,
-		})
-	}
+		// Persetujuan stage pipeline yang bukan stage terakhir
+		if row.ToStatus == model.AchievementStatusSubmitted && row.FromStatus != nil && *row.FromStatus == model.AchievementStatusSubmitted && row.Stage != nil {
+			entry.Action = fmt.Sprintf("Stage %d approved", *row.Stage)
+		}
+
+		// Kembali ke draft: withdraw (dari submitted) atau reopen (setelah ditolak / diminta revisi)
+		if row.ToStatus == model.AchievementStatusDraft && row.FromStatus != nil {
+			if *row.FromStatus == model.AchievementStatusSubmitted {
+				entry.Action = "Withdrawn from verification"
+			} else {
+				entry.Action = "Reopened for editing"
+			}
+		}
 
-	// 3. Verified (jika ada)
-	if reference.Status == "verified" && reference.VerifiedAt != nil {
-		var actorName string
-		var actorID *string
+		if row.ActorID != nil {
+			entry.Actor = s.userName(names, *row.ActorID)
+			if entry.Actor != "" && row.ActorRole != "" {
+				entry.Actor += " (" + row.ActorRole + ")"
+			}
+		}
 
-		if reference.VerifiedBy != nil {
-			// Get verifier info
-			user, err := s.userRepo.FindByID(*reference.VerifiedBy)
-			if err == nil {
-				actorName = user.FullName + " (Dosen Wali)"
-				actorID = reference.VerifiedBy
+		// Verifikasi / penolakan yang dilakukan lewat delegasi
+		if row.OnBehalfOf != nil {
+			entry.OnBehalfOfID = row.OnBehalfOf
+			entry.Action += " on behalf of advisor"
+			if name := s.userName(names, *row.OnBehalfOf); name != "" {
+				entry.OnBehalfOf = name + " (Dosen Wali)"
 			}
 		}
 
-		history = append(history, HistoryEntry{
-			Status:    "verified",
-			Timestamp: reference.VerifiedAt.Format("2006-01-02 15:04:05"),
-			Actor:     actorName,
-			ActorID:   actorID,
-			Action:    "Achievement verified",
-			Notes:     nil,
+		history = append(history, entry)
+	}
+
+	return history
+}
+
+func (s *AchievementService) userName(cache map[string]string, userID string) string {
+	if name, ok := cache[userID]; ok {
+		return name
+	}
+	name := ""
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		name = user.FullName
+	}
+	cache[userID] = name
+	return name
+}
+
+// statusChange - Baris history untuk transisi yang dilakukan user di sesi ini.
+// from/to status diisi repository di dalam transaksi update.
+func statusChange(claims *model.JWTClaims, note *string, onBehalfOf string) *model.AchievementStatusHistory {
+	actorID := claims.UserID
+	return &model.AchievementStatusHistory{
+		ActorID:    &actorID,
+		ActorRole:  strings.Join(claims.Roles, ", "),
+		OnBehalfOf: optionalString(onBehalfOf),
+		Note:       note,
+	}
+}
+
+// statusUpdateFailed - Response untuk UpdateReference yang gagal: 409 jika
+// status sudah diubah request lain setelah transisi diperiksa (sql.ErrNoRows),
+// selain itu 500 dengan message.
+func statusUpdateFailed(c *fiber.Ctx, err error, message string) error {
+	if err == sql.ErrNoRows {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  errStatusChanged,
 		})
 	}
+	return c.Status(500).JSON(model.APIResponse{
+		Status: "error",
+		Error:  message,
+	})
+}
 
-	// 4. Rejected (jika ada)
-	if reference.Status == "rejected" {
-		var actorName string
-		var actorID *string
+// errStatusChanged - Pesan 409 untuk transisi yang kalah balapan
+const errStatusChanged = "achievement status was changed by another request"
 
-		if reference.VerifiedBy != nil {
-			// Note: VerifiedBy juga dipakai untuk rejection
-			user, err := s.userRepo.FindByID(*reference.VerifiedBy)
-			if err == nil {
-				actorName = user.FullName + " (Dosen Wali)"
-				actorID = reference.VerifiedBy
-			}
-		}
+// contentEdit - Versi baru untuk perubahan isi oleh user di sesi ini.
+// Nomor versi, snapshot, dan status diisi repository.
+func contentEdit(claims *model.JWTClaims) *model.AchievementVersion {
+	return &model.AchievementVersion{EditedBy: claims.UserID}
+}
 
-		history = append(history, HistoryEntry{
-			Status:    "rejected",
-			Timestamp: reference.UpdatedAt.Format("2006-01-02 15:04:05"),
-			Actor:     actorName,
-			ActorID:   actorID,
-			Action:    "Achievement rejected",
-			Notes:     reference.RejectionNote,
+//
+// ==================== GET ACHIEVEMENT VERSIONS (GET /achievements/:id/versions) ======================
+// Snapshot isi achievement setiap kali disimpan, urut dari versi pertama
+//
+
+func (s *AchievementService) GetAchievementVersions(c *fiber.Ctx) error {
+	reference, err := s.authorizeVersionRead(c)
+	if reference == nil {
+		return err
+	}
+
+	versions, err := s.achievementRepo.GetVersions(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievement versions",
 		})
 	}
 
-	// 5. Deleted (jika ada)
-	if reference.Status == "deleted" {
-		history = append(history, HistoryEntry{
-			Status:    "deleted",
-			Timestamp: reference.UpdatedAt.Format("2006-01-02 15:04:05"),
-			Action:    "Achievement deleted",
-			Notes:     nil,
+	names := map[string]string{}
+	responses := []fiber.Map{}
+	for _, version := range versions {
+		responses = append(responses, fiber.Map{
+			"version":           version.Version,
+			"status":            version.Status,
+			"status_history_id": version.StatusHistoryID,
+			"edited_by":         version.EditedBy,
+			"edited_by_name":    s.userName(names, version.EditedBy),
+			"created_at":        version.CreatedAt.Format("2006-01-02 15:04:05"),
+			"snapshot":          version.Snapshot,
 		})
 	}
 
-	return history
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_id": reference.ID,
+			"versions":       responses,
+			"total":          len(responses),
+		},
+	})
+}
+
+//
+// ==================== DIFF ACHIEVEMENT VERSIONS (GET /achievements/:id/versions/diff?from=1&to=2) ======================
+// Perubahan field-level antara dua versi (details, tags, attachments, dll)
+//
+
+func (s *AchievementService) DiffAchievementVersions(c *fiber.Ctx) error {
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "query parameters 'from' and 'to' must be version numbers",
+		})
+	}
+
+	reference, err := s.authorizeVersionRead(c)
+	if reference == nil {
+		return err
+	}
+
+	fromVersion, err := s.achievementRepo.GetVersion(reference.MongoAchievementID, from)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("version %d not found", from),
+		})
+	}
+	toVersion, err := s.achievementRepo.GetVersion(reference.MongoAchievementID, to)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("version %d not found", to),
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   diffAchievements(fromVersion, toVersion),
+	})
 }
 
+// authorizeVersionRead - Load reference dari :id dan cek akses baca.
+// Return nil reference jika response error sudah dikirim.
+func (s *AchievementService) authorizeVersionRead(c *fiber.Ctx) (*model.AchievementReference, error) {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return nil, c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
+		return nil, c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return nil, c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+	return reference, nil
+}
 
 //
 // ==================== GET ACHIEVEMENTS (GET /achievements) ======================
@@ -314,66 +530,36 @@ func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
 
 	offset := (page - 1) * pageSize
 
-	var references []model.AchievementReference
-	var total int
-	var err error
-
-	// Filter berdasarkan role
-	if claims.Role == "Mahasiswa" {
-		// Get student profile
-		student, err := s.studentRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "student profile not found",
-			})
-		}
-
-		references, err = s.achievementRepo.GetReferencesByStudentID(student.ID, status, pageSize, offset)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
+	// Filter semester / tanggal kegiatan
+	eventFilter, err := eventFilterFromQuery(c, s.calendarRepo)
+	if eventFilter == nil {
+		return err
+	}
 
-		total, err = s.achievementRepo.CountReferencesByStudentID(student.ID, status)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to count achievements",
-			})
-		}
+	// Filter sesuai scope role (own / advisee / program_study / all)
+	filter, err := s.authz.Filter(claims, ActionAchievementRead)
+	if err != nil {
+		return authzError(c, err)
+	}
 
-	} else if claims.Role == "Dosen Wali" {
-		// Get lecturer profile
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer profile not found",
-			})
+	var references []model.AchievementReference
+	var total int
+	if eventFilter.Active() {
+		// Tanggal kegiatan ada di MongoDB: filter & paginasi dilakukan di sini
+		references, err = referencesInScope(s.achievementRepo, filter, status, 10000, 0)
+		if err == nil {
+			references, err = filterByEventDate(s.achievementRepo, eventFilter, references)
 		}
-
-		references, err = s.achievementRepo.GetReferencesByAdvisorID(lecturer.ID, status, pageSize, offset)
 		if err != nil {
 			return c.Status(500).JSON(model.APIResponse{
 				Status: "error",
 				Error:  "failed to fetch achievements",
 			})
 		}
-
-		total, err = s.achievementRepo.CountReferencesByAdvisorID(lecturer.ID, status)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to count achievements",
-			})
-		}
-
-	} else if claims.Role == "Admin" {
-		// Admin dapat melihat semua
-		references, err = s.achievementRepo.GetAllReferences(status, pageSize, offset)
+		total = len(references)
+		references = references[min(offset, total):min(offset+pageSize, total)]
+	} else {
+		references, err = referencesInScope(s.achievementRepo, filter, status, pageSize, offset)
 		if err != nil {
 			return c.Status(500).JSON(model.APIResponse{
 				Status: "error",
@@ -381,22 +567,19 @@ func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
 			})
 		}
 
-		total, err = s.achievementRepo.CountAllReferences(status)
+		total, err = countReferencesInScope(s.achievementRepo, filter, status)
 		if err != nil {
 			return c.Status(500).JSON(model.APIResponse{
 				Status: "error",
 				Error:  "failed to count achievements",
 			})
 		}
-	} else {
-		return c.Status(403).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "forbidden",
-		})
 	}
 
 	// Fetch details dari MongoDB
-	var achievements []model.AchievementResponse
+	var responses []*model.AchievementResponse
+	var mongoIDs []string
+	semesters := loadSemesters(s.calendarRepo)
 	for _, ref := range references {
 		achievement, err := s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
 		if err != nil {
@@ -404,6 +587,14 @@ func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
 		}
 
 		response := s.buildAchievementResponse(achievement, &ref, ref.MongoAchievementID)
+		response.Semester = semesterName(semesters, achievement.EventDate)
+		responses = append(responses, response)
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	}
+	s.markDuplicates(claims, responses, mongoIDs)
+
+	var achievements []model.AchievementResponse
+	for _, response := range responses {
 		achievements = append(achievements, *response)
 	}
 
@@ -439,9 +630,9 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get reference dari PostgreSQL
+	// Get reference dari PostgreSQL (achievement di trash hanya terlihat lewat /trash)
 	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
-	if err != nil {
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
 		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "achievement not found",
@@ -449,25 +640,12 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 	}
 
 	// Check authorization
-	if claims.Role == "Mahasiswa" {
-		student, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if student == nil || student.ID != reference.StudentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		student, _ := s.studentRepo.FindByID(reference.StudentID)
-		if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Get detail dari MongoDB
 	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
@@ -479,6 +657,8 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 	}
 
 	response := s.buildAchievementResponse(achievement, reference, reference.MongoAchievementID)
+	response.Semester = semesterName(loadSemesters(s.calendarRepo), achievement.EventDate)
+	s.markDuplicates(claims, []*model.AchievementResponse{response}, []string{reference.MongoAchievementID})
 
 	return c.JSON(model.APIResponse{
 		Status: "success",
@@ -512,9 +692,8 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
+	// Check authorization
+	if !s.authz.Can(claims, ActionAchievementUpdate, Target{StudentID: reference.StudentID}) {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "forbidden",
@@ -522,7 +701,7 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 	}
 
 	// Hanya bisa update jika status = draft
-	if reference.Status != "draft" {
+	if reference.Status != model.AchievementStatusDraft {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "can only update achievement with status 'draft'",
@@ -538,6 +717,13 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
 	// Get existing achievement dari MongoDB
 	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
 	if err != nil {
@@ -547,7 +733,27 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	// Prestasi tim: hanya pembuat, dan hanya jika semua anggota masih draft
+	if sent, err := s.checkTeamContent(c, achievement, reference, model.AchievementStatusDraft); sent {
+		return err
+	}
+
+	// Daftar anggota baru (team: [] = jadikan prestasi individu)
+	var currentRefs []model.AchievementReference
+	if req.Team != nil {
+		if sent, err := s.checkTeam(c, achievement.StudentID, req.Team); sent {
+			return err
+		}
+		if currentRefs, err = s.teamReferences(achievement, reference); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to fetch team members",
+			})
+		}
+	}
+
 	// Update fields (hanya yang diisi)
+	previousType := achievement.AchievementType
 	if req.AchievementType != "" {
 		achievement.AchievementType = req.AchievementType
 	}
@@ -561,21 +767,59 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		achievement.Details = req.Details
 	}
 	if req.Tags != nil {
+		if sent, err := s.checkTags(c, &req.Tags); sent {
+			return err
+		}
 		achievement.Tags = req.Tags
 	}
-	if req.Points > 0 {
-		achievement.Points = req.Points
+	if req.Team != nil {
+		achievement.Team = req.Team
+	}
+	if req.EventDate != "" {
+		achievement.EventDate, _ = time.Parse(dateLayout, req.EventDate)
+	}
+	if req.EventEndDate != nil {
+		achievement.EventEndDate = nil
+		if *req.EventEndDate != "" {
+			eventEndDate, err := time.Parse(dateLayout, *req.EventEndDate)
+			if err != nil {
+				return c.Status(422).JSON(model.APIResponse{
+					Status: "error",
+					Error:  "event_end_date must be in YYYY-MM-DD format",
+				})
+			}
+			achievement.EventEndDate = &eventEndDate
+		}
+	}
+	if sent, err := checkEventDates(c, achievement); sent {
+		return err
+	}
+
+	// Details dicek ulang jika jenis atau details berubah. Jenis yang sudah
+	// dinonaktifkan tetap boleh dipakai achievement lama.
+	if req.AchievementType != "" || req.Details != nil {
+		typeChanged := req.AchievementType != "" && req.AchievementType != previousType
+		if sent, err := s.checkDetails(c, achievement.AchievementType, achievement.Details, typeChanged); sent {
+			return err
+		}
 	}
 
 	// Update di MongoDB
-	if err := s.achievementRepo.UpdateAchievement(reference.MongoAchievementID, achievement); err != nil {
+	if err := s.achievementRepo.UpdateAchievement(reference.MongoAchievementID, achievement, contentEdit(claims)); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "failed to update achievement",
 		})
 	}
 
+	if req.Team != nil {
+		if err := s.syncTeamReferences(claims, reference.MongoAchievementID, achievement, currentRefs); err != nil {
+			return statusUpdateFailed(c, err, "failed to update team members")
+		}
+	}
+
 	response := s.buildAchievementResponse(achievement, reference, reference.MongoAchievementID)
+	response.Semester = semesterName(loadSemesters(s.calendarRepo), achievement.EventDate)
 
 	return c.JSON(model.APIResponse{
 		Status:  "success",
@@ -591,6 +835,8 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 // 1. Soft delete data di MongoDB
 // 2. Update reference di PostgreSQL
 // 3. Return success message
+// Achievement masuk trash: bisa di-restore pemiliknya sampai dihapus permanen
+// oleh job retensi (lihat TrashService)
 //
 
 func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
@@ -614,9 +860,8 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
+	// Check authorization
+	if !s.authz.Can(claims, ActionAchievementDelete, Target{StudentID: reference.StudentID}) {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "forbidden",
@@ -624,31 +869,55 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 	}
 
 	// Precondition: Hanya bisa delete jika status = draft
-	if reference.Status != "draft" {
+	status, err := nextStatus(reference.Status, transitionDelete)
+	if err != nil {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "can only delete achievement with status 'draft'",
+			Error:  err.Error(),
 		})
 	}
 
-	// FR-005: Soft delete sesuai SRS
-	// 1. Soft delete data di MongoDB
-	if err := s.achievementRepo.DeleteAchievement(reference.MongoAchievementID); err != nil {
+	// Prestasi tim dihapus pembuatnya untuk semua anggota (semua masih draft)
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+	if sent, err := s.checkTeamContent(c, achievement, reference, model.AchievementStatusDraft); sent {
+		return err
+	}
+	references, err := s.teamReferences(achievement, reference)
+	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to delete achievement from MongoDB",
+			Error:  "failed to fetch team members",
 		})
 	}
 
-	// 2. Update reference di PostgreSQL dengan status 'deleted'
-	reference.Status = "deleted"
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
+	// FR-005: Soft delete sesuai SRS
+	// 1. Soft delete data di MongoDB
+	if err := s.achievementRepo.SoftDeleteAchievement(reference.MongoAchievementID, time.Now()); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to update reference status",
+			Error:  "failed to delete achievement from MongoDB",
 		})
 	}
 
+	// 2. Update reference (semua anggota) di PostgreSQL dengan status 'deleted'
+	// dalam satu transaksi; jika gagal, tanda di MongoDB dibatalkan
+	teamRefs := make([]*model.AchievementReference, len(references))
+	for i := range references {
+		teamRefs[i] = &references[i]
+	}
+	if err := s.achievementRepo.UpdateReferences(teamRefs, status, statusChange(claims, nil, "")); err != nil {
+		if undoErr := s.achievementRepo.RestoreAchievement(reference.MongoAchievementID); undoErr != nil {
+			log.Printf("[DELETE] Failed to undo soft delete of %s: %v", reference.MongoAchievementID, undoErr)
+		}
+		return statusUpdateFailed(c, err, "failed to update reference status")
+	}
+
 	// 3. Return success message
 	return c.JSON(model.APIResponse{
 		Status:  "success",
@@ -683,8 +952,7 @@ func (s *AchievementService) SubmitForVerification(c *fiber.Ctx) error {
 	}
 
 	// Check authorization
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
+	if !s.authz.Can(claims, ActionAchievementUpdate, Target{StudentID: reference.StudentID}) {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "forbidden",
@@ -692,35 +960,115 @@ func (s *AchievementService) SubmitForVerification(c *fiber.Ctx) error {
 	}
 
 	// Hanya bisa submit jika status = draft
-	if reference.Status != "draft" {
+	status, err := nextStatus(reference.Status, transitionSubmit)
+	if err != nil {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "achievement already submitted or processed",
+			Error:  err.Error(),
 		})
 	}
 
-	// Update status menjadi 'submitted'
-	now := time.Now()
-	reference.Status = "submitted"
-	reference.SubmittedAt = &now
+	// Pipeline verifikasi dipilih dari jenis prestasi & tingkat kompetisi
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+	pipeline, err := s.pipelineFor(achievement)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to resolve verification pipeline",
+		})
+	}
 
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
+	// Poin dihitung dari point rules (penyesuaian putaran sebelumnya tidak dibawa)
+	points, rule, err := s.calculatePoints(achievement)
+	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to submit achievement",
+			Error:  "failed to calculate points",
 		})
 	}
 
+	// Update status menjadi 'submitted'
+	now := time.Now()
+	from := reference.State()
+	reference.Status = status
+	applySubmission(reference, now, pipeline, points, rule)
+
+	if err := s.achievementRepo.UpdateReference(reference, from, statusChange(claims, nil, "")); err != nil {
+		return statusUpdateFailed(c, err, "failed to submit achievement")
+	}
+
+	// Prestasi tim: submit pembuat ikut mengajukan reference anggota yang masih
+	// draft, masing-masing ke dosen walinya. Anggota lain hanya submit miliknya.
+	teamSubmitted := []string{}
+	if len(achievement.Team) > 0 && reference.StudentID == achievement.StudentID {
+		members, err := s.achievementRepo.GetReferencesByMongoID(reference.MongoAchievementID)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to fetch team members",
+			})
+		}
+		for i := range members {
+			member := &members[i]
+			if member.ID == reference.ID || member.Status != model.AchievementStatusDraft {
+				continue
+			}
+			from := member.State()
+			member.Status = status
+			applySubmission(member, now, pipeline, points, rule)
+			if err := s.achievementRepo.UpdateReference(member, from, statusChange(claims, nil, "")); err != nil {
+				continue // anggota bisa submit sendiri
+			}
+			teamSubmitted = append(teamSubmitted, member.StudentID)
+		}
+	}
+
+	// Cek kemungkinan duplikat untuk verifikator; tidak menggagalkan submit
+	duplicates, err := s.detectDuplicates(achievement, reference.MongoAchievementID)
+	if err != nil {
+		log.Printf("[DUPLICATE] Failed to check %s: %v", reference.MongoAchievementID, err)
+	}
+
 	return c.JSON(model.APIResponse{
 		Status:  "success",
 		Message: "achievement submitted for verification",
 		Data: fiber.Map{
-			"status":       reference.Status,
-			"submitted_at": reference.SubmittedAt.Format("2006-01-02 15:04:05"),
+			"status":         reference.Status,
+			"submitted_at":   reference.SubmittedAt.Format("2006-01-02 15:04:05"),
+			"pipeline_id":    reference.PipelineID,
+			"current_stage":  reference.CurrentStage,
+			"points":         points,
+			"team_submitted": teamSubmitted,
+			"duplicates":     len(duplicates),
 		},
 	})
 }
 
+// applySubmission - Kolom reference saat diajukan (catatan dosen dari putaran
+// sebelumnya dihapus, poin dihitung ulang)
+func applySubmission(reference *model.AchievementReference, now time.Time, pipeline *model.VerificationPipeline, points int, rule *model.PointRule) {
+	firstStage := 1
+	reference.SubmittedAt = &now
+	reference.RejectionNote = nil
+	reference.PipelineID = nil
+	reference.CurrentStage = &firstStage
+	if pipeline != nil {
+		reference.PipelineID = &pipeline.ID
+	}
+	reference.Points = &points
+	reference.PointRuleID = nil
+	reference.PointsFrozenAt = nil
+	if rule != nil {
+		reference.PointRuleID = &rule.ID
+	}
+}
+
 //
 // ==================== VERIFY ACHIEVEMENT (POST /achievements/:id/verify) ======================
 // FR-007: Dosen wali memverifikasi prestasi mahasiswa
@@ -747,55 +1095,231 @@ func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya dosen wali dari mahasiswa tersebut)
-	lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-	student, _ := s.studentRepo.FindByID(reference.StudentID)
+	// Check authorization: pemegang stage aktif (dosen wali sendiri / penerima
+	// delegasi aktif untuk stage dosen wali). Stage terakhir: verified, stage
+	// lain: lanjut ke stage berikutnya
+	decision, failure := s.approveStage(claims, reference, nil)
+	if failure != nil {
+		return c.Status(failure.code).JSON(model.APIResponse{
+			Status: "error",
+			Error:  failure.message,
+		})
+	}
 
-	if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return c.Status(403).JSON(model.APIResponse{
+	if !decision.final {
+		return c.JSON(model.APIResponse{
+			Status:  "success",
+			Message: fmt.Sprintf("stage '%s' approved", decision.stage.Name),
+			Data: fiber.Map{
+				"status":         reference.Status,
+				"approved_stage": decision.stage.Position,
+				"current_stage":  reference.CurrentStage,
+				"on_behalf_of":   optionalString(decision.onBehalfOf),
+			},
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "achievement verified successfully",
+		Data: fiber.Map{
+			"status":       reference.Status,
+			"verified_at":  reference.VerifiedAt.Format("2006-01-02 15:04:05"),
+			"verified_by":  reference.VerifiedBy,
+			"on_behalf_of": reference.OnBehalfOf,
+		},
+	})
+}
+
+//
+// ==================== REJECT ACHIEVEMENT (POST /achievements/:id/reject) ======================
+// FR-008: Dosen wali menolak prestasi dengan catatan
+//
+
+func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden: you are not the advisor of this student",
+			Error:  "unauthorized",
 		})
 	}
 
-	// Hanya bisa verify jika status = submitted
-	if reference.Status != "submitted" {
+	// Parse request
+	req := new(model.RejectAchievementRequest)
+	if err := c.BodyParser(req); err != nil {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "achievement must be in 'submitted' status",
+			Error:  "invalid request body",
 		})
 	}
 
-	// Update status menjadi 'verified'
-	now := time.Now()
-	reference.Status = "verified"
-	reference.VerifiedAt = &now
-	reference.VerifiedBy = &claims.UserID
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
 
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
-		return c.Status(500).JSON(model.APIResponse{
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization: pemegang stage aktif, status harus submitted
+	decision, failure := s.rejectStage(claims, reference, req.RejectionNote)
+	if failure != nil {
+		return c.Status(failure.code).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to verify achievement",
+			Error:  failure.message,
 		})
 	}
 
 	return c.JSON(model.APIResponse{
 		Status:  "success",
-		Message: "achievement verified successfully",
+		Message: "achievement rejected",
 		Data: fiber.Map{
-			"status":      reference.Status,
-			"verified_at": reference.VerifiedAt.Format("2006-01-02 15:04:05"),
-			"verified_by": reference.VerifiedBy,
+			"status":         reference.Status,
+			"rejection_note": reference.RejectionNote,
+			"on_behalf_of":   optionalString(decision.onBehalfOf),
 		},
 	})
 }
 
 //
-// ==================== REJECT ACHIEVEMENT (POST /achievements/:id/reject) ======================
-// FR-008: Dosen wali menolak prestasi dengan catatan
+// ==================== BULK VERIFY / REJECT (POST /achievements/bulk/verify, /bulk/reject) ======================
+// Setiap item dicek sendiri seperti endpoint per item (pemegang stage aktif,
+// status submitted) dan ditulis dalam transaksi sendiri bersama baris
+// history-nya. Item yang gagal tidak membatalkan item lain.
 //
 
-func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
+type BulkItemResult struct {
+	ID           string  `json:"id"`
+	Success      bool    `json:"success"`
+	Status       string  `json:"status,omitempty"`
+	CurrentStage *int    `json:"current_stage,omitempty"` // masih di pipeline (stage berikutnya)
+	OnBehalfOf   *string `json:"on_behalf_of,omitempty"`
+	Code         int     `json:"code,omitempty"` // status HTTP yang akan didapat endpoint per item
+	Error        string  `json:"error,omitempty"`
+}
+
+func (s *AchievementService) BulkVerifyAchievements(c *fiber.Ctx) error {
+	return s.bulkDecide(c, transitionVerify)
+}
+
+func (s *AchievementService) BulkRejectAchievements(c *fiber.Ctx) error {
+	return s.bulkDecide(c, transitionReject)
+}
+
+func (s *AchievementService) bulkDecide(c *fiber.Ctx, action string) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.BulkDecisionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	results := []BulkItemResult{}
+	succeeded := 0
+	seen := map[string]bool{}
+	for _, id := range req.IDs {
+		// ID ganda hanya diproses sekali (verify dua kali bisa melompati stage)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := s.decideBulkItem(claims, id, req.NoteFor(id), action)
+		if result.Success {
+			succeeded++
+		}
+		results = append(results, result)
+	}
+
+	verb := "verified"
+	if action == transitionReject {
+		verb = "rejected"
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("%d of %d achievements %s", succeeded, len(results), verb),
+		Data: fiber.Map{
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	})
+}
+
+// decideBulkItem - Verify / reject satu item bulk
+func (s *AchievementService) decideBulkItem(claims *model.JWTClaims, id, note, action string) BulkItemResult {
+	result := BulkItemResult{ID: id}
+
+	reference, err := s.achievementRepo.GetReferenceByID(id)
+	if err != nil {
+		result.Code, result.Error = 404, "achievement not found"
+		return result
+	}
+
+	var decision *stageDecision
+	var failure *decisionError
+	if action == transitionReject {
+		if note == "" {
+			result.Code, result.Error = 422, "rejection note is required"
+			return result
+		}
+		decision, failure = s.rejectStage(claims, reference, note)
+	} else {
+		decision, failure = s.approveStage(claims, reference, optionalString(note))
+	}
+	if failure != nil {
+		result.Code, result.Error = failure.code, failure.message
+		return result
+	}
+
+	result.Success = true
+	result.Status = reference.Status
+	result.OnBehalfOf = optionalString(decision.onBehalfOf)
+	if reference.Status == model.AchievementStatusSubmitted {
+		result.CurrentStage = reference.CurrentStage
+	}
+	return result
+}
+
+//
+// ==================== REQUEST REVISION (POST /achievements/:id/request-revision) ======================
+// Dosen wali meminta mahasiswa memperbaiki prestasi (bukan penolakan final)
+//
+
+func (s *AchievementService) RequestRevision(c *fiber.Ctx) error {
 	achievementID := c.Params("id")
 
 	// Get user dari context
@@ -808,7 +1332,7 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 	}
 
 	// Parse request
-	req := new(model.RejectAchievementRequest)
+	req := new(model.RequestRevisionRequest)
 	if err := c.BodyParser(req); err != nil {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
@@ -833,39 +1357,115 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization
-	lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-	student, _ := s.studentRepo.FindByID(reference.StudentID)
+	// Check authorization: pemegang stage aktif
+	decision, err := s.authorizeStage(c, claims, reference)
+	if decision == nil {
+		return err
+	}
+	onBehalfOf := decision.onBehalfOf
 
-	if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return c.Status(403).JSON(model.APIResponse{
+	// Hanya bisa diminta revisi jika status = submitted
+	status, err := nextStatus(reference.Status, transitionRequestRevision)
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden: you are not the advisor of this student",
+			Error:  err.Error(),
 		})
 	}
 
-	// Hanya bisa reject jika status = submitted
-	if reference.Status != "submitted" {
-		return c.Status(400).JSON(model.APIResponse{
+	// Catatan dosen disimpan di kolom yang sama dengan catatan penolakan
+	from := reference.State()
+	reference.Status = status
+	reference.RejectionNote = &req.Note
+
+	history := statusChange(claims, &req.Note, onBehalfOf)
+	history.Stage = &decision.stage.Position
+
+	if err := s.achievementRepo.UpdateReference(reference, from, history); err != nil {
+		return statusUpdateFailed(c, err, "failed to request revision")
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "revision requested",
+		Data: fiber.Map{
+			"status":       reference.Status,
+			"note":         reference.RejectionNote,
+			"on_behalf_of": optionalString(onBehalfOf),
+		},
+	})
+}
+
+//
+// ==================== WITHDRAW (POST /achievements/:id/withdraw) ======================
+// Mahasiswa menarik kembali prestasi yang sudah disubmit (kembali ke draft)
+//
+
+func (s *AchievementService) WithdrawAchievement(c *fiber.Ctx) error {
+	return s.returnToDraft(c, transitionWithdraw, "achievement withdrawn to draft")
+}
+
+//
+// ==================== REOPEN (POST /achievements/:id/reopen) ======================
+// Mahasiswa membuka kembali prestasi yang ditolak / diminta revisi untuk
+// diperbaiki lalu diajukan ulang (atau dihapus)
+//
+
+func (s *AchievementService) ReopenAchievement(c *fiber.Ctx) error {
+	return s.returnToDraft(c, transitionReopen, "achievement reopened as draft")
+}
+
+func (s *AchievementService) returnToDraft(c *fiber.Ctx, action, message string) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "achievement must be in 'submitted' status",
+			Error:  "unauthorized",
 		})
 	}
 
-	// Update status menjadi 'rejected'
-	reference.Status = "rejected"
-	reference.RejectionNote = &req.RejectionNote
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
 
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
-		return c.Status(500).JSON(model.APIResponse{
+	// Check authorization
+	if !s.authz.Can(claims, ActionAchievementUpdate, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to reject achievement",
+			Error:  "forbidden",
 		})
 	}
 
+	status, err := nextStatus(reference.Status, action)
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	// Catatan dosen tetap disimpan sampai diajukan ulang; pipeline dipilih ulang saat submit
+	from := reference.State()
+	reference.Status = status
+	reference.SubmittedAt = nil
+	reference.PipelineID = nil
+	reference.CurrentStage = nil
+
+	if err := s.achievementRepo.UpdateReference(reference, from, statusChange(claims, nil, "")); err != nil {
+		return statusUpdateFailed(c, err, "failed to update achievement status")
+	}
+
 	return c.JSON(model.APIResponse{
 		Status:  "success",
-		Message: "achievement rejected",
+		Message: message,
 		Data: fiber.Map{
 			"status":         reference.Status,
 			"rejection_note": reference.RejectionNote,
@@ -901,9 +1501,8 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
+	// Check authorization
+	if !s.authz.Can(claims, ActionAchievementUpdate, Target{StudentID: reference.StudentID}) {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "forbidden",
@@ -911,13 +1510,25 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 	}
 
 	// Hanya bisa upload jika status = draft atau submitted
-	if reference.Status != "draft" && reference.Status != "submitted" {
+	if reference.Status != model.AchievementStatusDraft && reference.Status != model.AchievementStatusSubmitted {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "can only upload attachments for draft or submitted achievements",
 		})
 	}
 
+	// Prestasi tim: hanya pembuat, dan hanya selama belum ada anggota yang selesai diverifikasi
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+	if sent, err := s.checkTeamContent(c, achievement, reference, model.AchievementStatusDraft, model.AchievementStatusSubmitted); sent {
+		return err
+	}
+
 	// Parse multipart file
 	file, err := c.FormFile("file")
 	if err != nil {
@@ -973,6 +1584,18 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		})
 	}
 
+	// SHA-256 isi file (untuk cek lampiran duplikat)
+	hash := sha256.New()
+	if _, err = fileHeader.Seek(0, io.SeekStart); err == nil {
+		_, err = io.Copy(hash, fileHeader)
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to read file content",
+		})
+	}
+
 	// Generate unique filename
 	timestamp := time.Now().Unix()
 	randomString := uuid.New().String()[:8]
@@ -1004,11 +1627,12 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		FileName:   file.Filename, // Original filename
 		FileURL:    fmt.Sprintf("/uploads/%s", newFilename), // Relative path
 		FileType:   contentType,
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
 		UploadedAt: time.Now(),
 	}
 
 	// Add attachment ke MongoDB
-	if err := s.achievementRepo.AddAttachment(reference.MongoAchievementID, attachment); err != nil {
+	if err := s.achievementRepo.AddAttachment(reference.MongoAchievementID, attachment, contentEdit(claims)); err != nil {
 		// Rollback: hapus file yang sudah diupload
 		os.Remove(filePath)
 		return c.Status(500).JSON(model.APIResponse{
@@ -1024,6 +1648,81 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 	})
 }
 
+//
+// ==================== HELPER: VALIDATE DETAILS ======================
+// Details dicek terhadap JSON Schema jenis prestasi (achievement_types).
+// Pelanggaran dikirim per field: Data.fields = [{field, message}].
+//
+
+// checkDetails - sent = true jika response error sudah dikirim.
+// requireActive: jenis yang dinonaktifkan ditolak (achievement baru / ganti jenis).
+func (s *AchievementService) checkDetails(c *fiber.Ctx, typeCode string, details map[string]interface{}, requireActive bool) (bool, error) {
+	invalid := func(errs []utils.SchemaError) (bool, error) {
+		return true, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "details do not match achievement type schema",
+			Data:   fiber.Map{"fields": errs},
+		})
+	}
+
+	achievementType, err := s.typeRepo.FindByCode(typeCode)
+	if err == sql.ErrNoRows {
+		return invalid([]utils.SchemaError{{Field: "achievement_type", Message: "unknown achievement type"}})
+	}
+	if err != nil {
+		return true, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievement type",
+		})
+	}
+	if requireActive && !achievementType.IsActive {
+		return invalid([]utils.SchemaError{{Field: "achievement_type", Message: "achievement type is inactive"}})
+	}
+
+	schema, err := utils.ParseJSONSchema(achievementType.DetailsSchema)
+	if err != nil {
+		return true, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid details schema for achievement type",
+		})
+	}
+
+	if errs := schema.Validate("details", plainDocument(details)); len(errs) > 0 {
+		return invalid(errs)
+	}
+	return false, nil
+}
+
+// plainDocument - Ubah primitive.D / primitive.M hasil decode MongoDB jadi
+// map biasa supaya bisa divalidasi seperti request JSON
+func plainDocument(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			out[key] = plainDocument(child)
+		}
+		return out
+	case primitive.M:
+		return plainDocument(map[string]interface{}(v))
+	case primitive.D:
+		out := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			out[element.Key] = plainDocument(element.Value)
+		}
+		return out
+	case primitive.A:
+		return plainDocument([]interface{}(v))
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = plainDocument(child)
+		}
+		return out
+	}
+	return value
+}
+
 //
 // ==================== HELPER: BUILD ACHIEVEMENT RESPONSE ======================
 //
@@ -1035,18 +1734,29 @@ func (s *AchievementService) buildAchievementResponse(
 ) *model.AchievementResponse {
 	response := &model.AchievementResponse{
 		ID:              reference.ID,
-		StudentID:       achievement.StudentID,
+		StudentID:       reference.StudentID,
 		AchievementType: achievement.AchievementType,
 		Title:           achievement.Title,
 		Description:     achievement.Description,
 		Details:         achievement.Details,
 		Attachments:     achievement.Attachments,
 		Tags:            achievement.Tags,
-		Points:          achievement.Points,
+		Team:            achievement.Team,
+		Points:          reference.AwardedPoints(),
 		Status:          reference.Status,
 		CreatedAt:       achievement.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:       achievement.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
+	if len(achievement.Team) > 0 {
+		response.OwnerID = &achievement.StudentID
+	}
+	if !achievement.EventDate.IsZero() {
+		response.EventDate = achievement.EventDate.Format(dateLayout)
+	}
+	if achievement.EventEndDate != nil {
+		eventEndDate := achievement.EventEndDate.Format(dateLayout)
+		response.EventEndDate = &eventEndDate
+	}
 
 	if reference.SubmittedAt != nil {
 		submittedAt := reference.SubmittedAt.Format("2006-01-02 15:04:05")
@@ -1059,7 +1769,20 @@ func (s *AchievementService) buildAchievementResponse(
 	}
 
 	response.VerifiedBy = reference.VerifiedBy
+	response.OnBehalfOf = reference.OnBehalfOf
 	response.RejectionNote = reference.RejectionNote
+	response.PipelineID = reference.PipelineID
+	if reference.Status == model.AchievementStatusSubmitted {
+		response.CurrentStage = reference.CurrentStage
+	}
 
 	return response
-}
\ No newline at end of file
+}
+
+// optionalString - "" jadi nil untuk kolom nullable
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -10,8 +13,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"project_uas/app/model"
+	"project_uas/app/repository"
 	"project_uas/test/mocks"
 )
 
@@ -28,11 +33,82 @@ func setupAchievementTest() (*AchievementService, *mocks.MockAchievementReposito
 		mockStudentRepo,
 		mockLecturerRepo,
 		mockUserRepo,
+		noPipelines(),
+		achievementTypes(),
+		noPointRules(),
+		noDuplicates(),
+		noSemesters(),
+		noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy),
+		TagPolicy{},
 	)
 
 	return service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, mockUserRepo
 }
 
+// noPipelines - Pipeline repository tanpa pipeline (verifikasi dosen wali saja)
+func noPipelines() *mocks.MockPipelineRepository {
+	repo := new(mocks.MockPipelineRepository)
+	repo.On("FindForAchievement", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+	return repo
+}
+
+// noPointRules - Point rule repository tanpa rule (poin 0)
+func noPointRules() *mocks.MockPointRuleRepository {
+	repo := new(mocks.MockPointRuleRepository)
+	repo.On("GetByType", mock.Anything).Return([]model.PointRule{}, nil).Maybe()
+	return repo
+}
+
+// noDuplicates - Duplicate repository tanpa achievement pembanding
+func noDuplicates() *mocks.MockDuplicateRepository {
+	repo := new(mocks.MockDuplicateRepository)
+	repo.On("FindCandidates", mock.Anything, mock.Anything).Return([]model.Achievement{}, nil).Maybe()
+	repo.On("ReplaceFlags", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("GetFlags", mock.Anything).Return([]model.DuplicateFlag{}, nil).Maybe()
+	repo.On("FlaggedIDs", mock.Anything).Return(map[string]bool{}, nil).Maybe()
+	return repo
+}
+
+// noSemesters - Kalender akademik kosong (achievement tanpa semester)
+func noSemesters() *mocks.MockAcademicCalendarRepository {
+	repo := new(mocks.MockAcademicCalendarRepository)
+	repo.On("GetAll").Return([]model.AcademicSemester{}, nil).Maybe()
+	return repo
+}
+
+// noTags - Kosakata tag kosong (tag teks bebas disimpan apa adanya)
+func noTags() *mocks.MockTagRepository {
+	repo := new(mocks.MockTagRepository)
+	repo.On("GetAll").Return([]model.Tag{}, nil).Maybe()
+	return repo
+}
+
+// competitionSchema - Schema details jenis 'competition' untuk test
+const competitionSchema = `{
+	"type": "object",
+	"required": ["competitionName", "competitionLevel"],
+	"properties": {
+		"competitionName": {"type": "string", "minLength": 1},
+		"competitionLevel": {"type": "string", "enum": ["international", "national", "regional", "local"]},
+		"rank": {"type": "integer", "minimum": 1},
+		"eventDate": {"type": "string", "format": "date"}
+	}
+}`
+
+// achievementTypes - Registry berisi 'competition' (aktif) dan 'seminar' (nonaktif)
+func achievementTypes() *mocks.MockAchievementTypeRepository {
+	repo := new(mocks.MockAchievementTypeRepository)
+	repo.On("FindByCode", "competition").Return(&model.AchievementType{
+		Code: "competition", Name: "Kompetisi", DetailsSchema: json.RawMessage(competitionSchema), IsActive: true,
+	}, nil).Maybe()
+	repo.On("FindByCode", "seminar").Return(&model.AchievementType{
+		Code: "seminar", Name: "Seminar", DetailsSchema: json.RawMessage(`{"type": "object"}`), IsActive: false,
+	}, nil).Maybe()
+	repo.On("FindByCode", mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+	return repo
+}
+
 // ==================== FR-003: CREATE ACHIEVEMENT ====================
 
 func TestCreateAchievement_Success(t *testing.T) {
@@ -48,7 +124,7 @@ func TestCreateAchievement_Success(t *testing.T) {
 	app.Post("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.CreateAchievement(c)
 	})
@@ -59,15 +135,16 @@ func TestCreateAchievement_Success(t *testing.T) {
 		UserID: userID,
 	}, nil)
 
-	mockAchievementRepo.On("CreateAchievement", mock.AnythingOfType("*model.Achievement")).Return(mongoID, nil)
-	mockAchievementRepo.On("CreateReference", mock.AnythingOfType("*model.AchievementReference")).Return(nil)
+	mockAchievementRepo.On("CreateAchievement", mock.AnythingOfType("*model.Achievement"), mock.AnythingOfType("*model.AchievementVersion")).Return(mongoID, nil)
+	mockAchievementRepo.On("CreateReference", mock.AnythingOfType("*model.AchievementReference"), mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
 
 	// Request body
 	body := `{
 		"achievement_type": "competition",
 		"title": "Juara 1 Hackathon",
 		"description": "Memenangkan hackathon nasional",
-		"points": 100
+		"event_date": "2025-05-10",
+		"details": {"competitionName": "Hackathon Nasional", "competitionLevel": "national", "rank": 1}
 	}`
 
 	req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
@@ -80,6 +157,104 @@ func TestCreateAchievement_Success(t *testing.T) {
 	mockAchievementRepo.AssertExpectations(t)
 }
 
+func TestCreateAchievement_EventDates(t *testing.T) {
+	tests := []struct {
+		name  string
+		dates string
+		want  int
+	}{
+		{"satu hari", `"event_date": "2025-05-10"`, 201},
+		{"beberapa hari", `"event_date": "2025-05-10", "event_end_date": "2025-05-12"`, 201},
+		{"tanpa tanggal kegiatan", `"event_end_date": "2025-05-12"`, 422},
+		{"format salah", `"event_date": "10/05/2025"`, 422},
+		{"selesai sebelum mulai", `"event_date": "2025-05-10", "event_end_date": "2025-05-09"`, 422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+			mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123", UserID: "user-123"}, nil).Maybe()
+			mockAchievementRepo.On("CreateAchievement", mock.AnythingOfType("*model.Achievement"), mock.Anything).Return("mongo-1", nil).Maybe()
+			mockAchievementRepo.On("CreateReference", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+				return service.CreateAchievement(c)
+			})
+
+			body := `{"achievement_type": "competition", "title": "Lomba", "description": "Lomba",
+				"details": {"competitionName": "Gemastik", "competitionLevel": "national"}, ` + tt.dates + `}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 201 {
+				mockAchievementRepo.AssertNotCalled(t, "CreateAchievement", mock.Anything, mock.Anything)
+				return
+			}
+			mockAchievementRepo.AssertCalled(t, "CreateAchievement", mock.MatchedBy(func(achievement *model.Achievement) bool {
+				return achievement.EventDate.Equal(day("2025-05-10")) &&
+					(achievement.EventEndDate == nil) == !strings.Contains(tt.dates, "event_end_date")
+			}), mock.Anything)
+		})
+	}
+}
+
+func TestCreateAchievement_CanonicalTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		canonicalOnly bool
+		tags          string
+		want          int
+		wantTags      []string
+	}{
+		{"sinonim jadi nama kanonik", false, `["ai", "Artificial  Intelligence", "IoT"]`, 201, []string{"AI", "IoT"}},
+		{"teks bebas tetap boleh", false, `["AI", "robotik"]`, 201, []string{"AI", "robotik"}},
+		{"hanya kosakata", true, `["artificial intelligence"]`, 201, []string{"AI"}},
+		{"tag di luar kosakata ditolak", true, `["AI", "robotik"]`, 422, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+			mockTagRepo := new(mocks.MockTagRepository)
+			mockTagRepo.On("GetAll").Return([]model.Tag{
+				{ID: "tag-1", Name: "AI", Synonyms: []string{"Artificial Intelligence"}},
+				{ID: "tag-2", Name: "IoT", Synonyms: []string{}},
+			}, nil)
+			service.tagRepo = mockTagRepo
+			service.tagPolicy = TagPolicy{CanonicalOnly: tt.canonicalOnly}
+
+			mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123", UserID: "user-123"}, nil)
+			mockAchievementRepo.On("CreateAchievement", mock.AnythingOfType("*model.Achievement"), mock.Anything).Return("mongo-1", nil).Maybe()
+			mockAchievementRepo.On("CreateReference", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+				return service.CreateAchievement(c)
+			})
+
+			body := `{"achievement_type": "competition", "title": "Lomba", "description": "Lomba", "event_date": "2025-05-10",
+				"details": {"competitionName": "Gemastik", "competitionLevel": "national"}, "tags": ` + tt.tags + `}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 201 {
+				mockAchievementRepo.AssertNotCalled(t, "CreateAchievement", mock.Anything, mock.Anything)
+				return
+			}
+			mockAchievementRepo.AssertCalled(t, "CreateAchievement", mock.MatchedBy(func(achievement *model.Achievement) bool {
+				return assert.ObjectsAreEqual(tt.wantTags, achievement.Tags)
+			}), mock.Anything)
+		})
+	}
+}
+
 func TestCreateAchievement_Unauthorized(t *testing.T) {
 	service, _, _, _, _ := setupAchievementTest()
 
@@ -102,14 +277,14 @@ func TestCreateAchievement_StudentNotFound(t *testing.T) {
 	app.Post("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "user-123",
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.CreateAchievement(c)
 	})
 
 	mockStudentRepo.On("FindByUserID", "user-123").Return(nil, errors.New("not found"))
 
-	body := `{"achievement_type": "competition", "title": "Test", "description": "Test"}`
+	body := `{"achievement_type": "competition", "title": "Test", "description": "Test", "event_date": "2025-05-10"}`
 	req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
@@ -119,6 +294,75 @@ func TestCreateAchievement_StudentNotFound(t *testing.T) {
 	mockStudentRepo.AssertExpectations(t)
 }
 
+func TestCreateAchievement_DetailsSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{
+			"field wajib tidak diisi",
+			`{"achievement_type": "competition", "title": "Lomba", "description": "Lomba", "event_date": "2025-05-10", "details": {"rank": 1}}`,
+			[]string{"details.competitionLevel", "details.competitionName"},
+		},
+		{
+			"nilai di luar enum dan format salah",
+			`{"achievement_type": "competition", "title": "Lomba", "description": "Lomba", "event_date": "2025-05-10",
+			  "details": {"competitionName": "Gemastik", "competitionLevel": "galaxy", "rank": 0, "eventDate": "12/05/2025"}}`,
+			[]string{"details.competitionLevel", "details.eventDate", "details.rank"},
+		},
+		{
+			"tanpa details",
+			`{"achievement_type": "competition", "title": "Lomba", "description": "Lomba", "event_date": "2025-05-10"}`,
+			[]string{"details.competitionLevel", "details.competitionName"},
+		},
+		{
+			"jenis tidak terdaftar",
+			`{"achievement_type": "hobby", "title": "Lomba", "description": "Lomba", "event_date": "2025-05-10"}`,
+			[]string{"achievement_type"},
+		},
+		{
+			"jenis nonaktif",
+			`{"achievement_type": "seminar", "title": "Seminar", "description": "Seminar", "event_date": "2025-05-10"}`,
+			[]string{"achievement_type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+			mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "user-123", UserID: "user-123"}, nil)
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+				return service.CreateAchievement(c)
+			})
+
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, 422, resp.StatusCode)
+
+			var result struct {
+				Data struct {
+					Fields []struct {
+						Field string `json:"field"`
+					} `json:"fields"`
+				} `json:"data"`
+			}
+			json.NewDecoder(resp.Body).Decode(&result)
+			fields := []string{}
+			for _, f := range result.Data.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.ElementsMatch(t, tt.fields, fields)
+			mockAchievementRepo.AssertNotCalled(t, "CreateAchievement", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 // ==================== FR-004: SUBMIT FOR VERIFICATION ====================
 
 func TestSubmitForVerification_Success(t *testing.T) {
@@ -133,7 +377,7 @@ func TestSubmitForVerification_Success(t *testing.T) {
 	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.SubmitForVerification(c)
 	})
@@ -151,7 +395,12 @@ func TestSubmitForVerification_Success(t *testing.T) {
 		UserID: userID,
 	}, nil)
 
-	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference")).Return(nil)
+	mockAchievementRepo.On("GetAchievementByID", "").Return(&model.Achievement{
+		StudentID:       studentID,
+		AchievementType: "competition",
+	}, nil)
+
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
 
 	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/submit", nil)
 	resp, _ := app.Test(req)
@@ -172,7 +421,7 @@ func TestSubmitForVerification_NotDraft(t *testing.T) {
 	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.SubmitForVerification(c)
 	})
@@ -209,7 +458,7 @@ func TestDeleteAchievement_Success(t *testing.T) {
 	app.Delete("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.DeleteAchievement(c)
 	})
@@ -226,8 +475,11 @@ func TestDeleteAchievement_Success(t *testing.T) {
 		UserID: userID,
 	}, nil)
 
-	mockAchievementRepo.On("DeleteAchievement", mongoID).Return(nil)
-	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference")).Return(nil)
+	mockAchievementRepo.On("GetAchievementByID", mongoID).Return(&model.Achievement{StudentID: studentID}, nil)
+	mockAchievementRepo.On("SoftDeleteAchievement", mongoID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockAchievementRepo.On("UpdateReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+		return len(refs) == 1 && refs[0].ID == achievementID
+	}), "deleted", mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/achievements/"+achievementID, nil)
 	resp, _ := app.Test(req)
@@ -248,7 +500,7 @@ func TestDeleteAchievement_NotDraft(t *testing.T) {
 	app.Delete("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.DeleteAchievement(c)
 	})
@@ -279,7 +531,7 @@ func TestDeleteAchievement_Forbidden(t *testing.T) {
 	app.Delete("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "other-user",
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.DeleteAchievement(c)
 	})
@@ -316,7 +568,7 @@ func TestVerifyAchievement_Success(t *testing.T) {
 	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.VerifyAchievement(c)
 	})
@@ -337,7 +589,7 @@ func TestVerifyAchievement_Success(t *testing.T) {
 		AdvisorID: &lecturerID,
 	}, nil)
 
-	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference")).Return(nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
 
 	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/verify", nil)
 	resp, _ := app.Test(req)
@@ -348,6 +600,51 @@ func TestVerifyAchievement_Success(t *testing.T) {
 	mockStudentRepo.AssertExpectations(t)
 }
 
+func TestVerifyAchievement_OnBehalfOfAdvisor(t *testing.T) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockDelegationRepo := new(mocks.MockDelegationRepository)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), noPipelines(), achievementTypes(), noPointRules(), noDuplicates(), noSemesters(), noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, mockDelegationRepo, DefaultPolicy), TagPolicy{})
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	studentID := "student-123"
+	advisorID := "lecturer-123"
+	delegateID := "lecturer-456"
+	userID := "user-delegate"
+
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{
+			UserID: userID,
+			Roles:  []string{"Dosen Wali"},
+		})
+		return service.VerifyAchievement(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:        achievementID,
+		StudentID: studentID,
+		Status:    "submitted",
+	}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: delegateID, UserID: userID}, nil)
+	mockStudentRepo.On("FindByID", studentID).Return(&model.Student{ID: studentID, AdvisorID: &advisorID}, nil)
+	mockDelegationRepo.On("FindActive", advisorID, delegateID, mock.AnythingOfType("time.Time")).Return(&model.VerificationDelegation{ID: "delegation-1"}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.Status == "verified" && *ref.VerifiedBy == userID && ref.OnBehalfOf != nil && *ref.OnBehalfOf == advisorID
+	}), mock.Anything, mock.MatchedBy(func(history *model.AchievementStatusHistory) bool {
+		return *history.ActorID == userID && history.ActorRole == "Dosen Wali" && history.OnBehalfOf != nil && *history.OnBehalfOf == advisorID
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/verify", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertExpectations(t)
+	mockDelegationRepo.AssertExpectations(t)
+}
+
 func TestVerifyAchievement_NotSubmitted(t *testing.T) {
 	service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, _ := setupAchievementTest()
 
@@ -360,7 +657,7 @@ func TestVerifyAchievement_NotSubmitted(t *testing.T) {
 	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.VerifyAchievement(c)
 	})
@@ -400,7 +697,7 @@ func TestVerifyAchievement_NotAdvisor(t *testing.T) {
 	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.VerifyAchievement(c)
 	})
@@ -427,6 +724,540 @@ func TestVerifyAchievement_NotAdvisor(t *testing.T) {
 	assert.Equal(t, 403, resp.StatusCode)
 }
 
+// ==================== VERIFICATION PIPELINE ====================
+
+// Kompetisi internasional: dosen wali lalu admin kemahasiswaan fakultas
+var internationalPipeline = &model.VerificationPipeline{
+	ID:   "pipeline-1",
+	Name: "Kompetisi Internasional",
+	Stages: []model.PipelineStage{
+		{Position: 1, Name: "Dosen Wali", Role: "Dosen Wali"},
+		{Position: 2, Name: "Kemahasiswaan", Role: "Admin", ProgramStudy: strPtr("Fakultas Teknik")},
+	},
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func TestVerifyAchievement_MultiStagePipeline(t *testing.T) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockPipelineRepo := new(mocks.MockPipelineRepository)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), mockPipelineRepo, achievementTypes(), noPointRules(), noDuplicates(), noSemesters(), noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy), TagPolicy{})
+
+	achievementID := "achievement-123"
+	studentID := "student-123"
+	advisorID := "lecturer-123"
+	pipelineID := internationalPipeline.ID
+
+	mockPipelineRepo.On("FindByID", pipelineID).Return(internationalPipeline, nil)
+	mockStudentRepo.On("FindByID", studentID).Return(&model.Student{ID: studentID, AdvisorID: &advisorID}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-lecturer").Return(&model.Lecturer{ID: advisorID}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+	verify := func(stage int, claims *model.JWTClaims) *http.Response {
+		reference := &model.AchievementReference{ID: achievementID, StudentID: studentID, Status: "submitted", PipelineID: &pipelineID, CurrentStage: &stage}
+		mockAchievementRepo.On("GetReferenceByID", achievementID).Return(reference, nil).Once()
+
+		app := fiber.New()
+		app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+			c.Locals("user", claims)
+			return service.VerifyAchievement(c)
+		})
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/"+achievementID+"/verify", nil))
+		return resp
+	}
+
+	lecturer := &model.JWTClaims{UserID: "user-lecturer", Roles: []string{"Dosen Wali"}}
+	globalAdmin := &model.JWTClaims{UserID: "user-admin", Roles: []string{"Admin"}}
+	facultyAdmin := &model.JWTClaims{UserID: "user-ft", Roles: []string{"Admin"}, RoleScopes: map[string][]string{"Admin": {"Fakultas Teknik"}}}
+
+	// Stage 1: admin belum boleh, dosen wali memajukan ke stage 2 (belum verified)
+	assert.Equal(t, 403, verify(1, facultyAdmin).StatusCode)
+	assert.Equal(t, 200, verify(1, lecturer).StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference",
+		mock.MatchedBy(func(ref *model.AchievementReference) bool {
+			return ref.Status == "submitted" && *ref.CurrentStage == 2 && ref.VerifiedAt == nil
+		}), mock.Anything,
+		mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+			return h.Stage != nil && *h.Stage == 1
+		}),
+	)
+
+	// Stage 2: hanya admin yang di-assign ke Fakultas Teknik
+	assert.Equal(t, 403, verify(2, lecturer).StatusCode)
+	assert.Equal(t, 403, verify(2, globalAdmin).StatusCode)
+	assert.Equal(t, 200, verify(2, facultyAdmin).StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference",
+		mock.MatchedBy(func(ref *model.AchievementReference) bool {
+			return ref.Status == "verified" && ref.VerifiedBy != nil && *ref.VerifiedBy == "user-ft"
+		}), mock.Anything,
+		mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+			return h.Stage != nil && *h.Stage == 2
+		}),
+	)
+	mockAchievementRepo.AssertNumberOfCalls(t, "UpdateReference", 2)
+}
+
+// Dua approval stage yang sama bersamaan: status tetap submitted, jadi yang
+// kalah ditolak karena current_stage di bawah lock sudah maju
+func TestVerifyAchievement_StageApprovedConcurrently(t *testing.T) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockPipelineRepo := new(mocks.MockPipelineRepository)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), mockPipelineRepo, achievementTypes(), noPointRules(), noDuplicates(), noSemesters(), noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy), TagPolicy{})
+
+	advisorID := "lecturer-123"
+	pipelineID := internationalPipeline.ID
+	stage := 1
+
+	mockPipelineRepo.On("FindByID", pipelineID).Return(internationalPipeline, nil)
+	mockStudentRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-lecturer").Return(&model.Lecturer{ID: advisorID}, nil)
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted", PipelineID: &pipelineID, CurrentStage: &stage}, nil)
+	mockAchievementRepo.On("UpdateReference",
+		mock.MatchedBy(func(ref *model.AchievementReference) bool {
+			return *ref.CurrentStage == 2
+		}),
+		model.ReferenceState{Status: "submitted", CurrentStage: &stage},
+		mock.Anything,
+	).Return(sql.ErrNoRows)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-lecturer", Roles: []string{"Dosen Wali"}})
+		return service.VerifyAchievement(c)
+	})
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+	mockAchievementRepo.AssertExpectations(t)
+}
+
+func TestSubmitForVerification_SelectsPipeline(t *testing.T) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockPipelineRepo := new(mocks.MockPipelineRepository)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), mockPipelineRepo, achievementTypes(), noPointRules(), noDuplicates(), noSemesters(), noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy), TagPolicy{})
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	mongoID := "507f1f77bcf86cd799439011"
+	studentID := "student-123"
+	userID := "user-123"
+
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Mahasiswa"}})
+		return service.SubmitForVerification(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:                 achievementID,
+		StudentID:          studentID,
+		MongoAchievementID: mongoID,
+		Status:             "draft",
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+	mockAchievementRepo.On("GetAchievementByID", mongoID).Return(&model.Achievement{
+		AchievementType: "competition",
+		Details:         map[string]interface{}{"competitionLevel": "international"},
+	}, nil)
+	mockPipelineRepo.On("FindForAchievement", "competition", "international").Return(internationalPipeline, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/"+achievementID+"/submit", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.Status == "submitted" && ref.PipelineID != nil && *ref.PipelineID == "pipeline-1" && *ref.CurrentStage == 1
+	}), mock.Anything, mock.Anything)
+}
+
+// ==================== POINTS ====================
+
+func setupPointsTest() (*AchievementService, *mocks.MockAchievementRepository, *mocks.MockPointRuleRepository) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockPointRuleRepo := new(mocks.MockPointRuleRepository)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), noPipelines(), achievementTypes(), mockPointRuleRepo, noDuplicates(), noSemesters(), noTags(),
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy), TagPolicy{})
+
+	// student-1 dibimbing lecturer-1
+	advisorID := "lecturer-1"
+	mockStudentRepo.On("FindByUserID", "student-1").Return(&model.Student{ID: "student-1", UserID: "student-1"}, nil).Maybe()
+	mockStudentRepo.On("FindByID", "student-1").Return(&model.Student{ID: "student-1", UserID: "student-1", AdvisorID: &advisorID}, nil).Maybe()
+	mockLecturerRepo.On("FindByUserID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", UserID: "lecturer-1"}, nil).Maybe()
+	mockLecturerRepo.On("FindByUserID", "lecturer-2").Return(&model.Lecturer{ID: "lecturer-2", UserID: "lecturer-2"}, nil).Maybe()
+
+	return service, mockAchievementRepo, mockPointRuleRepo
+}
+
+func TestSubmitForVerification_CalculatesPoints(t *testing.T) {
+	service, mockAchievementRepo, mockPointRuleRepo := setupPointsTest()
+
+	national := "national"
+	one := 1
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: model.AchievementStatusDraft,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{
+		AchievementType: "competition",
+		Details:         map[string]interface{}{"competitionLevel": "national", "rank": float64(1)},
+		Points:          1000, // nilai lama dari mahasiswa diabaikan
+	}, nil)
+	mockPointRuleRepo.On("GetByType", "competition").Return([]model.PointRule{
+		{ID: "rule-any", AchievementType: "competition", Points: 10},
+		{ID: "rule-national-winner", AchievementType: "competition", CompetitionLevel: &national, RankMin: &one, RankMax: &one, Points: 80},
+	}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+		return service.SubmitForVerification(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.AwardedPoints() == 80 && *ref.PointRuleID == "rule-national-winner" && ref.PointsFrozenAt == nil
+	}), mock.Anything, mock.Anything)
+}
+
+func TestVerifyAchievement_FreezesPoints(t *testing.T) {
+	service, mockAchievementRepo, _ := setupPointsTest()
+
+	points := 80
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", Status: model.AchievementStatusSubmitted, Points: &points,
+	}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", withLecturerClaims("lecturer-1", "Dosen Wali", service.VerifyAchievement))
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.Status == model.AchievementStatusVerified && ref.PointsFrozenAt != nil && ref.AwardedPoints() == 80
+	}), mock.Anything, mock.Anything)
+}
+
+func TestAdjustPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		lecturer string
+		status   string
+		body     string
+		want     int
+	}{
+		{"dosen wali dengan justifikasi", "lecturer-1", model.AchievementStatusSubmitted, `{"points": 60, "justification": "Peserta hanya 12 tim, bukan skala nasional penuh"}`, 200},
+		{"tanpa justifikasi", "lecturer-1", model.AchievementStatusSubmitted, `{"points": 60, "justification": "   "}`, 422},
+		{"tanpa poin", "lecturer-1", model.AchievementStatusSubmitted, `{"justification": "Peserta hanya 12 tim"}`, 422},
+		{"sudah verified (poin dikunci)", "lecturer-1", model.AchievementStatusVerified, `{"points": 60, "justification": "Peserta hanya 12 tim"}`, 400},
+		{"bukan dosen wali mahasiswa", "lecturer-2", model.AchievementStatusSubmitted, `{"points": 60, "justification": "Peserta hanya 12 tim"}`, 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockPointRuleRepo := setupPointsTest()
+
+			points := 80
+			mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+				ID: "ref-1", StudentID: "student-1", Status: tt.status, Points: &points,
+			}, nil)
+			mockPointRuleRepo.On("AdjustPoints", mock.AnythingOfType("*model.AchievementReference"), mock.AnythingOfType("*model.PointAdjustment")).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Put("/achievements/:id/points", withLecturerClaims(tt.lecturer, "Dosen Wali", service.AdjustPoints))
+
+			req := httptest.NewRequest("PUT", "/achievements/ref-1/points", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 200 {
+				mockPointRuleRepo.AssertCalled(t, "AdjustPoints", mock.Anything, mock.MatchedBy(func(a *model.PointAdjustment) bool {
+					return a.ToPoints == 60 && a.AdjustedBy == "lecturer-1" && a.Justification != ""
+				}))
+			} else {
+				mockPointRuleRepo.AssertNotCalled(t, "AdjustPoints", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// ==================== TEAM ACHIEVEMENTS ====================
+
+// setupTeamTest - student-1 (pembuat, dosen wali lecturer-1) dan student-2
+// (dosen wali lecturer-2); student-9 tidak terdaftar
+func setupTeamTest() (*AchievementService, *mocks.MockAchievementRepository, *mocks.MockPointRuleRepository) {
+	service, mockAchievementRepo, mockPointRuleRepo := setupPointsTest()
+	mockStudentRepo := service.studentRepo.(*mocks.MockStudentRepository)
+
+	advisorID := "lecturer-2"
+	mockStudentRepo.On("FindByUserID", "student-2").Return(&model.Student{ID: "student-2", UserID: "student-2"}, nil).Maybe()
+	mockStudentRepo.On("FindByID", "student-2").Return(&model.Student{ID: "student-2", UserID: "student-2", AdvisorID: &advisorID}, nil).Maybe()
+	mockStudentRepo.On("FindByID", "student-3").Return(&model.Student{ID: "student-3", UserID: "student-3"}, nil).Maybe()
+	mockStudentRepo.On("FindByID", "student-9").Return(nil, sql.ErrNoRows).Maybe()
+	return service, mockAchievementRepo, mockPointRuleRepo
+}
+
+// teamAchievement - Prestasi tim mongo-team milik student-1
+func teamAchievement(members ...string) *model.Achievement {
+	achievement := &model.Achievement{
+		StudentID:       "student-1",
+		AchievementType: "competition",
+		Title:           "Juara Hackathon",
+		Details:         map[string]interface{}{"competitionName": "Hackathon", "competitionLevel": "national"},
+	}
+	for i, member := range members {
+		role := "anggota"
+		if i == 0 {
+			role = "ketua"
+		}
+		achievement.Team = append(achievement.Team, model.TeamMember{StudentID: member, Role: role})
+	}
+	return achievement
+}
+
+func TestCreateAchievement_Team(t *testing.T) {
+	tests := []struct {
+		name string
+		team string
+		want int
+	}{
+		{"tim valid", `[{"student_id": "student-1", "role": "ketua"}, {"student_id": "student-2", "role": "anggota"}]`, 201},
+		{"pembuat tidak termasuk", `[{"student_id": "student-2", "role": "ketua"}, {"student_id": "student-3", "role": "anggota"}]`, 422},
+		{"anggota tidak terdaftar", `[{"student_id": "student-1", "role": "ketua"}, {"student_id": "student-9", "role": "anggota"}]`, 422},
+		{"anggota ganda", `[{"student_id": "student-1", "role": "ketua"}, {"student_id": "student-1", "role": "anggota"}]`, 422},
+		{"peran kosong", `[{"student_id": "student-1", "role": "ketua"}, {"student_id": "student-2", "role": " "}]`, 422},
+		{"hanya satu anggota", `[{"student_id": "student-1", "role": "ketua"}]`, 422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, _ := setupTeamTest()
+			mockAchievementRepo.On("CreateAchievement", mock.AnythingOfType("*model.Achievement"), mock.Anything).Return("mongo-team", nil).Maybe()
+			mockAchievementRepo.On("CreateReferences", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+				return service.CreateAchievement(c)
+			})
+
+			body := `{"achievement_type": "competition", "title": "Juara Hackathon", "description": "Tim 2 orang", "event_date": "2025-05-10",
+				"details": {"competitionName": "Hackathon", "competitionLevel": "national"}, "team": ` + tt.team + `}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 201 {
+				mockAchievementRepo.AssertNotCalled(t, "CreateAchievement", mock.Anything, mock.Anything)
+				return
+			}
+			mockAchievementRepo.AssertCalled(t, "CreateReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+				return len(refs) == 2 && refs[0].StudentID == "student-1" && refs[1].StudentID == "student-2" &&
+					refs[1].MongoAchievementID == "mongo-team" && refs[1].Status == model.AchievementStatusDraft
+			}), mock.Anything)
+			mockAchievementRepo.AssertNotCalled(t, "CreateReference", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// Reference semua anggota diubah dalam satu transaksi; jika gagal (mis. anggota
+// baru saja diubah), tanda hapus di MongoDB dibatalkan
+func TestDeleteAchievement_TeamRollsBack(t *testing.T) {
+	service, mockAchievementRepo, _ := setupTeamTest()
+
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(teamAchievement("student-1", "student-2"), nil)
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-team").Return([]model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft},
+		{ID: "ref-2", StudentID: "student-2", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft},
+	}, nil)
+	mockAchievementRepo.On("SoftDeleteAchievement", "mongo-team", mock.AnythingOfType("time.Time")).Return(nil)
+	mockAchievementRepo.On("UpdateReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+		return len(refs) == 2
+	}), model.AchievementStatusDeleted, mock.Anything).Return(sql.ErrNoRows)
+	mockAchievementRepo.On("RestoreAchievement", "mongo-team").Return(nil)
+
+	app := fiber.New()
+	app.Delete("/achievements/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+		return service.DeleteAchievement(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievements/ref-1", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "RestoreAchievement", "mongo-team")
+	mockAchievementRepo.AssertNotCalled(t, "UpdateReference", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmitForVerification_TeamOwnerSubmitsMembers(t *testing.T) {
+	service, mockAchievementRepo, mockPointRuleRepo := setupTeamTest()
+
+	three := 3
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(teamAchievement("student-1", "student-2", "student-3"), nil)
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-team").Return([]model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft},
+		{ID: "ref-2", StudentID: "student-2", MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft},
+		{ID: "ref-3", StudentID: "student-3", MongoAchievementID: "mongo-team", Status: model.AchievementStatusRejected},
+	}, nil)
+	mockPointRuleRepo.On("GetByType", "competition").Return([]model.PointRule{
+		{ID: "rule-individual", AchievementType: "competition", Points: 60},
+		{ID: "rule-team", AchievementType: "competition", TeamSizeMin: &three, Points: 45},
+	}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+		return service.SubmitForVerification(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	var result struct {
+		Data struct {
+			TeamSubmitted []string `json:"team_submitted"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"student-2"}, result.Data.TeamSubmitted)
+
+	// Jumlah anggota (3) diambil dari daftar tim, bukan details.teamSize
+	for _, id := range []string{"ref-1", "ref-2"} {
+		mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+			return ref.ID == id && ref.Status == model.AchievementStatusSubmitted && ref.AwardedPoints() == 45
+		}), mock.Anything, mock.Anything)
+	}
+	mockAchievementRepo.AssertNotCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.ID == "ref-3"
+	}), mock.Anything, mock.Anything)
+}
+
+func TestVerifyAchievement_TeamMemberByOwnAdvisor(t *testing.T) {
+	tests := []struct {
+		name     string
+		lecturer string
+		want     int
+	}{
+		{"dosen wali anggota", "lecturer-2", 200},
+		{"dosen wali pembuat", "lecturer-1", 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, _ := setupTeamTest()
+			mockAchievementRepo.On("GetReferenceByID", "ref-2").Return(&model.AchievementReference{
+				ID: "ref-2", StudentID: "student-2", MongoAchievementID: "mongo-team", Status: model.AchievementStatusSubmitted,
+			}, nil)
+			mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/achievements/:id/verify", withLecturerClaims(tt.lecturer, "Dosen Wali", service.VerifyAchievement))
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-2/verify", nil))
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
+
+func TestUpdateAchievement_Team(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		refID   string
+		members []model.AchievementReference
+		body    string
+		want    int
+	}{
+		{
+			"anggota bukan pembuat",
+			"student-2", "ref-2",
+			nil,
+			`{"title": "Judul baru"}`,
+			403,
+		},
+		{
+			"anggota lain sudah submit",
+			"student-1", "ref-1",
+			[]model.AchievementReference{
+				{ID: "ref-1", StudentID: "student-1", Status: model.AchievementStatusDraft},
+				{ID: "ref-2", StudentID: "student-2", Status: model.AchievementStatusSubmitted},
+			},
+			`{"title": "Judul baru"}`,
+			400,
+		},
+		{
+			"ganti anggota",
+			"student-1", "ref-1",
+			[]model.AchievementReference{
+				{ID: "ref-1", StudentID: "student-1", Status: model.AchievementStatusDraft},
+				{ID: "ref-2", StudentID: "student-2", Status: model.AchievementStatusDraft},
+			},
+			`{"team": [{"student_id": "student-1", "role": "ketua"}, {"student_id": "student-3", "role": "anggota"}]}`,
+			200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, _ := setupTeamTest()
+			mockAchievementRepo.On("GetReferenceByID", tt.refID).Return(&model.AchievementReference{
+				ID: tt.refID, StudentID: tt.user, MongoAchievementID: "mongo-team", Status: model.AchievementStatusDraft,
+			}, nil)
+			mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(teamAchievement("student-1", "student-2"), nil)
+			mockAchievementRepo.On("GetReferencesByMongoID", "mongo-team").Return(tt.members, nil).Maybe()
+			mockAchievementRepo.On("UpdateAchievement", "mongo-team", mock.AnythingOfType("*model.Achievement"), mock.Anything).Return(nil).Maybe()
+			mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockAchievementRepo.On("CreateReferences", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Put("/achievements/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: tt.user, Roles: []string{"Mahasiswa"}})
+				return service.UpdateAchievement(c)
+			})
+
+			req := httptest.NewRequest("PUT", "/achievements/"+tt.refID, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 200 {
+				mockAchievementRepo.AssertNotCalled(t, "UpdateAchievement", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			// student-2 dikeluarkan, student-3 mendapat reference baru
+			mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+				return ref.ID == "ref-2" && ref.Status == model.AchievementStatusDeleted
+			}), mock.Anything, mock.Anything)
+			mockAchievementRepo.AssertCalled(t, "CreateReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+				return len(refs) == 1 && refs[0].StudentID == "student-3"
+			}), mock.Anything)
+		})
+	}
+}
+
 // ==================== FR-008: REJECT ACHIEVEMENT ====================
 
 func TestRejectAchievement_Success(t *testing.T) {
@@ -442,7 +1273,7 @@ func TestRejectAchievement_Success(t *testing.T) {
 	app.Post("/achievements/:id/reject", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.RejectAchievement(c)
 	})
@@ -463,7 +1294,7 @@ func TestRejectAchievement_Success(t *testing.T) {
 		AdvisorID: &lecturerID,
 	}, nil)
 
-	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference")).Return(nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
 
 	body := `{"rejection_note": "Data tidak lengkap"}`
 	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/reject", strings.NewReader(body))
@@ -475,6 +1306,309 @@ func TestRejectAchievement_Success(t *testing.T) {
 	mockAchievementRepo.AssertExpectations(t)
 }
 
+// ==================== BULK VERIFY / REJECT ====================
+
+// setupBulkTest - Dosen wali lecturer-123 dengan ref-1..ref-3 milik mahasiswa
+// bimbingannya (ref-3 masih draft) dan ref-other milik mahasiswa dosen lain
+func setupBulkTest(handler func(*AchievementService) fiber.Handler, path string) (*fiber.App, *mocks.MockAchievementRepository) {
+	service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, _ := setupAchievementTest()
+
+	advisorID := "lecturer-123"
+	otherAdvisorID := "lecturer-999"
+	mockLecturerRepo.On("FindByUserID", "user-lecturer").Return(&model.Lecturer{ID: advisorID, UserID: "user-lecturer"}, nil)
+	mockStudentRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	mockStudentRepo.On("FindByID", "student-999").Return(&model.Student{ID: "student-999", AdvisorID: &otherAdvisorID}, nil)
+
+	for _, ref := range []model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-123", Status: "submitted"},
+		{ID: "ref-2", StudentID: "student-123", Status: "submitted"},
+		{ID: "ref-3", StudentID: "student-123", Status: "draft"},
+		{ID: "ref-other", StudentID: "student-999", Status: "submitted"},
+	} {
+		reference := ref
+		mockAchievementRepo.On("GetReferenceByID", reference.ID).Return(&reference, nil)
+	}
+	mockAchievementRepo.On("GetReferenceByID", "ref-missing").Return(nil, errors.New("not found"))
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+	app := fiber.New()
+	app.Post(path, func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-lecturer", Roles: []string{"Dosen Wali"}})
+		return handler(service)(c)
+	})
+	return app, mockAchievementRepo
+}
+
+type bulkResponse struct {
+	Data struct {
+		Results   []BulkItemResult `json:"results"`
+		Succeeded int              `json:"succeeded"`
+		Failed    int              `json:"failed"`
+	} `json:"data"`
+}
+
+func TestBulkVerifyAchievements(t *testing.T) {
+	app, mockAchievementRepo := setupBulkTest(func(s *AchievementService) fiber.Handler { return s.BulkVerifyAchievements }, "/achievements/bulk/verify")
+
+	body := `{"ids": ["ref-1", "ref-2", "ref-1", "ref-3", "ref-other", "ref-missing"], "note": "Sesuai sertifikat"}`
+	req := httptest.NewRequest("POST", "/achievements/bulk/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result bulkResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, 2, result.Data.Succeeded)
+	assert.Equal(t, 3, result.Data.Failed)
+
+	codes := map[string]int{}
+	for _, item := range result.Data.Results {
+		codes[item.ID] = item.Code
+	}
+	assert.Equal(t, map[string]int{"ref-1": 0, "ref-2": 0, "ref-3": 400, "ref-other": 403, "ref-missing": 404}, codes)
+	assert.Equal(t, "verified", result.Data.Results[0].Status)
+
+	// Satu transaksi (reference + history) per item yang berhasil; ID ganda sekali saja
+	mockAchievementRepo.AssertNumberOfCalls(t, "UpdateReference", 2)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.Anything, mock.Anything, mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return h.Note != nil && *h.Note == "Sesuai sertifikat"
+	}))
+}
+
+func TestBulkRejectAchievements(t *testing.T) {
+	app, mockAchievementRepo := setupBulkTest(func(s *AchievementService) fiber.Handler { return s.BulkRejectAchievements }, "/achievements/bulk/reject")
+
+	// Tanpa catatan bersama: item tanpa catatan per item gagal
+	body := `{"ids": ["ref-1", "ref-2"], "notes": {"ref-1": "Sertifikat tidak terbaca"}}`
+	req := httptest.NewRequest("POST", "/achievements/bulk/reject", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result bulkResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, 1, result.Data.Succeeded)
+	assert.Equal(t, "rejected", result.Data.Results[0].Status)
+	assert.Equal(t, 422, result.Data.Results[1].Code)
+
+	mockAchievementRepo.AssertNumberOfCalls(t, "UpdateReference", 1)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-1" && *r.RejectionNote == "Sertifikat tidak terbaca"
+	}), mock.Anything, mock.Anything)
+
+	// Daftar kosong
+	req = httptest.NewRequest("POST", "/achievements/bulk/reject", strings.NewReader(`{"ids": [], "note": "x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, 422, resp.StatusCode)
+}
+
+// Item yang statusnya diubah request lain di antara pengecekan dan update
+// (UpdateReference → sql.ErrNoRows) dilaporkan 409
+func TestBulkVerifyAchievements_ConcurrentChange(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, _ := setupAchievementTest()
+
+	advisorID := "lecturer-123"
+	mockLecturerRepo.On("FindByUserID", "user-lecturer").Return(&model.Lecturer{ID: advisorID, UserID: "user-lecturer"}, nil)
+	mockStudentRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+	mockAchievementRepo.On("GetReferenceByID", "ref-2").Return(&model.AchievementReference{ID: "ref-2", StudentID: "student-123", Status: "submitted"}, nil)
+
+	// ref-2 sudah ditarik mahasiswa sebelum baris dikunci
+	mockAchievementRepo.On("UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+		return ref.ID == "ref-2"
+	}), model.ReferenceState{Status: "submitted"}, mock.Anything).Return(sql.ErrNoRows)
+	mockAchievementRepo.On("UpdateReference", mock.Anything, model.ReferenceState{Status: "submitted"}, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/achievements/bulk/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-lecturer", Roles: []string{"Dosen Wali"}})
+		return service.BulkVerifyAchievements(c)
+	})
+
+	req := httptest.NewRequest("POST", "/achievements/bulk/verify", strings.NewReader(`{"ids": ["ref-1", "ref-2"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result bulkResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, 1, result.Data.Succeeded)
+	if assert.Len(t, result.Data.Results, 2) {
+		assert.Equal(t, 0, result.Data.Results[0].Code)
+		assert.Equal(t, 409, result.Data.Results[1].Code)
+		assert.Equal(t, errStatusChanged, result.Data.Results[1].Error)
+	}
+}
+
+// ==================== REQUEST REVISION ====================
+
+func TestRequestRevision_Success(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, _ := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	studentID := "student-123"
+	lecturerID := "lecturer-123"
+	userID := "user-lecturer"
+
+	app.Post("/achievements/:id/request-revision", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{
+			UserID: userID,
+			Roles:  []string{"Dosen Wali"},
+		})
+		return service.RequestRevision(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:        achievementID,
+		StudentID: studentID,
+		Status:    "submitted",
+	}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: lecturerID, UserID: userID}, nil)
+	mockStudentRepo.On("FindByID", studentID).Return(&model.Student{ID: studentID, AdvisorID: &lecturerID}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+	// Catatan wajib diisi
+	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/request-revision", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 422, resp.StatusCode)
+
+	req = httptest.NewRequest("POST", "/achievements/"+achievementID+"/request-revision", strings.NewReader(`{"note": "Lengkapi sertifikat"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReference",
+		mock.MatchedBy(func(ref *model.AchievementReference) bool {
+			return ref.Status == "revision_requested" && ref.RejectionNote != nil && *ref.RejectionNote == "Lengkapi sertifikat"
+		}), mock.Anything,
+		mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+			return h.Note != nil && *h.Note == "Lengkapi sertifikat"
+		}),
+	)
+}
+
+// ==================== WITHDRAW / REOPEN ====================
+
+func TestReturnToDraft(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		status string
+		want   int
+	}{
+		{"withdraw submitted", "withdraw", "submitted", 200},
+		{"withdraw draft", "withdraw", "draft", 400},
+		{"withdraw verified", "withdraw", "verified", 400},
+		{"reopen rejected", "reopen", "rejected", 200},
+		{"reopen revision requested", "reopen", "revision_requested", 200},
+		{"reopen verified", "reopen", "verified", 400},
+		{"reopen submitted", "reopen", "submitted", 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+
+			app := fiber.New()
+			achievementID := "achievement-123"
+			studentID := "student-123"
+			userID := "user-123"
+			submittedAt := time.Now()
+			note := "Sertifikat kurang jelas"
+
+			withStudent := func(handler fiber.Handler) fiber.Handler {
+				return func(c *fiber.Ctx) error {
+					c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Mahasiswa"}})
+					return handler(c)
+				}
+			}
+			app.Post("/achievements/:id/withdraw", withStudent(service.WithdrawAchievement))
+			app.Post("/achievements/:id/reopen", withStudent(service.ReopenAchievement))
+
+			mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+				ID:            achievementID,
+				StudentID:     studentID,
+				Status:        tt.status,
+				SubmittedAt:   &submittedAt,
+				RejectionNote: &note,
+			}, nil)
+			mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+			mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+			req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/"+tt.path, nil)
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 200 {
+				// Catatan dosen tetap ada supaya mahasiswa tahu apa yang harus diperbaiki
+				mockAchievementRepo.AssertCalled(t, "UpdateReference", mock.MatchedBy(func(ref *model.AchievementReference) bool {
+					return ref.Status == "draft" && ref.SubmittedAt == nil && ref.RejectionNote != nil
+				}), mock.Anything, mock.Anything)
+			} else {
+				mockAchievementRepo.AssertNotCalled(t, "UpdateReference", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// Withdraw kalah balapan dengan verifikasi dosen: status di database sudah
+// bukan submitted saat baris dikunci
+func TestWithdrawAchievement_ConcurrentVerify(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	userID := "user-123"
+
+	app.Post("/achievements/:id/withdraw", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Mahasiswa"}})
+		return service.WithdrawAchievement(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:        achievementID,
+		StudentID: "student-123",
+		Status:    "submitted",
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: "student-123", UserID: userID}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), model.ReferenceState{Status: "submitted"}, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(sql.ErrNoRows)
+
+	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/withdraw", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	mockAchievementRepo.AssertExpectations(t)
+}
+
+func TestReopenAchievement_Forbidden(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	userID := "user-123"
+
+	app.Post("/achievements/:id/reopen", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Mahasiswa"}})
+		return service.ReopenAchievement(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:        achievementID,
+		StudentID: "other-student",
+		Status:    "rejected",
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: "student-123", UserID: userID}, nil)
+
+	req := httptest.NewRequest("POST", "/achievements/"+achievementID+"/reopen", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+	mockAchievementRepo.AssertNotCalled(t, "UpdateReference", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // ==================== UPDATE ACHIEVEMENT ====================
 
 func TestUpdateAchievement_Success(t *testing.T) {
@@ -490,7 +1624,7 @@ func TestUpdateAchievement_Success(t *testing.T) {
 	app.Put("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.UpdateAchievement(c)
 	})
@@ -516,7 +1650,7 @@ func TestUpdateAchievement_Success(t *testing.T) {
 		UpdatedAt:       time.Now(),
 	}, nil)
 
-	mockAchievementRepo.On("UpdateAchievement", mongoID, mock.AnythingOfType("*model.Achievement")).Return(nil)
+	mockAchievementRepo.On("UpdateAchievement", mongoID, mock.AnythingOfType("*model.Achievement"), mock.AnythingOfType("*model.AchievementVersion")).Return(nil)
 
 	body := `{"title": "New Title", "description": "New Description"}`
 	req := httptest.NewRequest("PUT", "/achievements/"+achievementID, strings.NewReader(body))
@@ -526,7 +1660,165 @@ func TestUpdateAchievement_Success(t *testing.T) {
 
 	assert.Equal(t, 200, resp.StatusCode)
 	mockAchievementRepo.AssertExpectations(t)
+	mockAchievementRepo.AssertCalled(t, "UpdateAchievement", mongoID, mock.Anything, mock.MatchedBy(func(v *model.AchievementVersion) bool {
+		return v.EditedBy == userID
+	}))
 }
+
+func TestUpdateAchievement_DetailsSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[string]interface{}
+		body    string
+		want    int
+	}{
+		{
+			"details baru tidak sesuai schema",
+			map[string]interface{}{"competitionName": "Gemastik", "competitionLevel": "national"},
+			`{"details": {"competitionName": "Gemastik", "competitionLevel": "planet"}}`,
+			422,
+		},
+		{
+			// details lama dari MongoDB (primitive.D) dicek terhadap schema jenis baru
+			"ganti jenis, details lama sesuai",
+			map[string]interface{}{"competitionName": "Gemastik", "competitionLevel": "national", "venue": primitive.D{{Key: "city", Value: "Malang"}}},
+			`{"achievement_type": "competition"}`,
+			200,
+		},
+		{
+			"ganti jenis ke jenis nonaktif",
+			map[string]interface{}{},
+			`{"achievement_type": "seminar"}`,
+			422,
+		},
+		{
+			"hanya judul berubah, details tidak dicek",
+			map[string]interface{}{"legacy": true},
+			`{"title": "Judul Baru"}`,
+			200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+			mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "user-123", UserID: "user-123"}, nil)
+			mockAchievementRepo.On("GetReferenceByID", "achievement-123").Return(&model.AchievementReference{
+				ID: "achievement-123", StudentID: "user-123", MongoAchievementID: "mongo-123", Status: model.AchievementStatusDraft,
+			}, nil)
+			mockAchievementRepo.On("GetAchievementByID", "mongo-123").Return(&model.Achievement{
+				StudentID: "user-123", AchievementType: "other", Title: "Lama", Details: tt.details,
+			}, nil)
+			mockAchievementRepo.On("UpdateAchievement", "mongo-123", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Put("/achievements/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+				return service.UpdateAchievement(c)
+			})
+
+			req := httptest.NewRequest("PUT", "/achievements/achievement-123", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 422 {
+				mockAchievementRepo.AssertNotCalled(t, "UpdateAchievement", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// ==================== ACHIEVEMENT VERSIONS ====================
+
+func TestGetAchievementVersions_Success(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, mockUserRepo := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	mongoID := "507f1f77bcf86cd799439011"
+	studentID := "student-123"
+	lecturerID := "lecturer-123"
+	userID := "user-lecturer"
+
+	app.Get("/achievements/:id/versions", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Dosen Wali"}})
+		return service.GetAchievementVersions(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:                 achievementID,
+		StudentID:          studentID,
+		MongoAchievementID: mongoID,
+		Status:             "submitted",
+	}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: lecturerID, UserID: userID}, nil)
+	mockStudentRepo.On("FindByID", studentID).Return(&model.Student{ID: studentID, AdvisorID: &lecturerID}, nil)
+
+	historyID := "history-2"
+	mockAchievementRepo.On("GetVersions", mongoID).Return([]model.AchievementVersion{
+		{AchievementID: mongoID, Version: 1, Status: "draft", EditedBy: studentID, Snapshot: model.Achievement{Title: "Lomba"}},
+		{AchievementID: mongoID, Version: 2, Status: "revision_requested", StatusHistoryID: &historyID, EditedBy: studentID, Snapshot: model.Achievement{Title: "Lomba Nasional"}},
+	}, nil)
+	mockUserRepo.On("FindByID", studentID).Return(&model.User{ID: studentID, FullName: "Mahasiswa A"}, nil).Once()
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/"+achievementID+"/versions", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Versions []map[string]interface{} `json:"versions"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(t, result.Data.Versions, 2)
+	assert.Equal(t, "history-2", result.Data.Versions[1]["status_history_id"])
+	assert.Equal(t, "Mahasiswa A", result.Data.Versions[1]["edited_by_name"])
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestDiffAchievementVersions(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	mongoID := "507f1f77bcf86cd799439011"
+	studentID := "student-123"
+	userID := "user-123"
+
+	app.Get("/achievements/:id/versions/diff", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{"Mahasiswa"}})
+		return service.DiffAchievementVersions(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:                 achievementID,
+		StudentID:          studentID,
+		MongoAchievementID: mongoID,
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+	mockAchievementRepo.On("GetVersion", mongoID, 1).Return(&model.AchievementVersion{Version: 1, Snapshot: model.Achievement{Title: "Lomba", Tags: []string{"coding"}}}, nil)
+	mockAchievementRepo.On("GetVersion", mongoID, 2).Return(&model.AchievementVersion{Version: 2, Snapshot: model.Achievement{Title: "Lomba Nasional", Tags: []string{"coding", "nasional"}}}, nil)
+	mockAchievementRepo.On("GetVersion", mongoID, 9).Return(nil, errors.New("mongo: no documents in result"))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/"+achievementID+"/versions/diff?from=1", nil))
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/achievements/"+achievementID+"/versions/diff?from=1&to=9", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/achievements/"+achievementID+"/versions/diff?from=1&to=2", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.AchievementDiff `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []model.FieldChange{{Field: "title", Old: "Lomba", New: "Lomba Nasional"}}, result.Data.Fields)
+	assert.Equal(t, []string{"nasional"}, result.Data.TagsAdded)
+	assert.Empty(t, result.Data.TagsRemoved)
+}
+
 // ==================== TAMBAHAN TEST - PASTE DI AKHIR achievement_service_test.go ====================
 
 // ==================== GET ACHIEVEMENT BY ID ====================
@@ -544,7 +1836,7 @@ func TestGetAchievementByID_Success(t *testing.T) {
 	app.Get("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievementByID(c)
 	})
@@ -601,7 +1893,7 @@ func TestGetAchievementByID_NotFound(t *testing.T) {
 	app.Get("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "user-123",
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievementByID(c)
 	})
@@ -614,6 +1906,32 @@ func TestGetAchievementByID_NotFound(t *testing.T) {
 	assert.Equal(t, 404, resp.StatusCode)
 }
 
+// Achievement di trash tidak terlihat lewat endpoint per-ID (hanya /trash)
+func TestReadEndpoints_DeletedAchievement(t *testing.T) {
+	service, mockAchievementRepo, _, _, _ := setupAchievementTest()
+	mockAchievementRepo.On("GetReferenceByID", "ref-deleted").Return(&model.AchievementReference{
+		ID: "ref-deleted", StudentID: "student-123", MongoAchievementID: "mongo-deleted", Status: model.AchievementStatusDeleted,
+	}, nil)
+
+	handlers := map[string]fiber.Handler{
+		"":          service.GetAchievementByID,
+		"/history":  service.GetAchievementHistory,
+		"/versions": service.GetAchievementVersions,
+		"/points":   service.GetPoints,
+		"/team":     service.GetAchievementTeam,
+	}
+	for path, handler := range handlers {
+		t.Run("GET /achievements/:id"+path, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/achievements/:id"+path, withLecturerClaims("student-123", "Mahasiswa", handler))
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-deleted"+path, nil))
+			assert.Equal(t, 404, resp.StatusCode)
+		})
+	}
+	mockAchievementRepo.AssertNotCalled(t, "GetAchievementByID", mock.Anything)
+}
+
 func TestGetAchievementByID_Forbidden(t *testing.T) {
 	service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
 
@@ -626,7 +1944,7 @@ func TestGetAchievementByID_Forbidden(t *testing.T) {
 	app.Get("/achievements/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: otherUserID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievementByID(c)
 	})
@@ -662,7 +1980,7 @@ func TestGetAchievements_Success_Mahasiswa(t *testing.T) {
 	app.Get("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievements(c)
 	})
@@ -692,8 +2010,8 @@ func TestGetAchievements_Success_Mahasiswa(t *testing.T) {
 	}
 
 	mockStudentRepo.On("FindByUserID", userID).Return(student, nil)
-	mockAchievementRepo.On("GetReferencesByStudentID", studentID, "", 10, 0).Return(references, nil)
-	mockAchievementRepo.On("CountReferencesByStudentID", studentID, "").Return(1, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "", 10, 0).Return(references, nil)
+	mockAchievementRepo.On("CountReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "").Return(1, nil)
 	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(achievement, nil)
 	mockStudentRepo.On("FindByID", studentID).Return(student, nil)
 	mockUserRepo.On("FindByID", userID).Return(&model.User{
@@ -720,7 +2038,7 @@ func TestGetAchievements_Success_DosenWali(t *testing.T) {
 	app.Get("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetAchievements(c)
 	})
@@ -755,8 +2073,8 @@ func TestGetAchievements_Success_DosenWali(t *testing.T) {
 	}
 
 	mockLecturerRepo.On("FindByUserID", userID).Return(lecturer, nil)
-	mockAchievementRepo.On("GetReferencesByAdvisorID", lecturerID, "", 10, 0).Return(references, nil)
-	mockAchievementRepo.On("CountReferencesByAdvisorID", lecturerID, "").Return(1, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{AdvisorIDs: []string{lecturerID}}, "", 10, 0).Return(references, nil)
+	mockAchievementRepo.On("CountReferencesInScope", repository.ReferenceScope{AdvisorIDs: []string{lecturerID}}, "").Return(1, nil)
 	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(achievement, nil)
 	mockStudentRepo.On("FindByID", studentID).Return(student, nil)
 	mockUserRepo.On("FindByID", "user-student").Return(&model.User{
@@ -781,7 +2099,7 @@ func TestGetAchievements_Success_Admin(t *testing.T) {
 	app.Get("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "admin-user",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetAchievements(c)
 	})
@@ -837,7 +2155,7 @@ func TestGetAchievements_WithPagination(t *testing.T) {
 	app.Get("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievements(c)
 	})
@@ -846,8 +2164,8 @@ func TestGetAchievements_WithPagination(t *testing.T) {
 		ID:     studentID,
 		UserID: userID,
 	}, nil)
-	mockAchievementRepo.On("GetReferencesByStudentID", studentID, "", 20, 20).Return([]model.AchievementReference{}, nil)
-	mockAchievementRepo.On("CountReferencesByStudentID", studentID, "").Return(50, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "", 20, 20).Return([]model.AchievementReference{}, nil)
+	mockAchievementRepo.On("CountReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "").Return(50, nil)
 
 	req := httptest.NewRequest("GET", "/achievements?page=2&page_size=20", nil)
 	resp, _ := app.Test(req)
@@ -866,7 +2184,7 @@ func TestGetAchievements_WithStatusFilter(t *testing.T) {
 	app.Get("/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetAchievements(c)
 	})
@@ -875,8 +2193,8 @@ func TestGetAchievements_WithStatusFilter(t *testing.T) {
 		ID:     studentID,
 		UserID: userID,
 	}, nil)
-	mockAchievementRepo.On("GetReferencesByStudentID", studentID, "verified", 10, 0).Return([]model.AchievementReference{}, nil)
-	mockAchievementRepo.On("CountReferencesByStudentID", studentID, "verified").Return(0, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "verified", 10, 0).Return([]model.AchievementReference{}, nil)
+	mockAchievementRepo.On("CountReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "verified").Return(0, nil)
 
 	req := httptest.NewRequest("GET", "/achievements?status=verified", nil)
 	resp, _ := app.Test(req)
@@ -884,6 +2202,85 @@ func TestGetAchievements_WithStatusFilter(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestGetAchievements_SemesterFilter(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, _ := setupAchievementTest()
+	mockCalendarRepo := new(mocks.MockAcademicCalendarRepository)
+	service.calendarRepo = mockCalendarRepo
+
+	semester := ganjil2025()
+	mockCalendarRepo.On("FindByID", "semester-1").Return(&semester, nil)
+	mockCalendarRepo.On("GetAll").Return([]model.AcademicSemester{semester}, nil)
+	mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123", UserID: "user-123"}, nil)
+
+	// Tiga achievement, dua di antaranya berlangsung pada semester tersebut
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{"student-123"}}, "", 10000, 0).Return([]model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "draft"},
+		{ID: "ref-2", StudentID: "student-123", MongoAchievementID: "mongo-2", Status: "draft"},
+		{ID: "ref-3", StudentID: "student-123", MongoAchievementID: "mongo-3", Status: "draft"},
+	}, nil)
+	mockAchievementRepo.On("GetAchievementIDsByEventDate", mock.MatchedBy(func(from *time.Time) bool {
+		return from.Equal(day("2025-08-01"))
+	}), mock.MatchedBy(func(to *time.Time) bool {
+		return to.Equal(day("2026-01-31"))
+	})).Return(map[string]bool{"mongo-1": true, "mongo-3": true}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-3").Return(&model.Achievement{
+		StudentID: "student-123", Title: "Lomba", EventDate: day("2025-11-20"),
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/achievements", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+		return service.GetAchievements(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements?semester=semester-1&page=2&page_size=1", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.AchievementListResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, 2, result.Data.Total)
+	if assert.Len(t, result.Data.Achievements, 1) {
+		achievement := result.Data.Achievements[0]
+		assert.Equal(t, "ref-3", achievement.ID)
+		assert.Equal(t, "2025-11-20", achievement.EventDate)
+		assert.Equal(t, "2025/2026 Ganjil", *achievement.Semester)
+	}
+	mockAchievementRepo.AssertNotCalled(t, "CountReferencesInScope", mock.Anything, mock.Anything)
+}
+
+func TestGetAchievements_InvalidEventFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"format tanggal salah", "event_from=10-05-2025", 400},
+		{"rentang terbalik", "event_from=2025-06-01&event_to=2025-05-01", 400},
+		{"semester tidak ada", "semester=missing", 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _, _, _ := setupAchievementTest()
+			mockCalendarRepo := new(mocks.MockAcademicCalendarRepository)
+			mockCalendarRepo.On("FindByID", "missing").Return(nil, sql.ErrNoRows).Maybe()
+			service.calendarRepo = mockCalendarRepo
+
+			app := fiber.New()
+			app.Get("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+				return service.GetAchievements(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/achievements?"+tt.query, nil))
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
+
 func TestGetAchievements_Unauthorized(t *testing.T) {
 	service, _, _, _, _ := setupAchievementTest()
 
@@ -912,6 +2309,68 @@ func TestGetAchievementHistory_EmptyResult(t *testing.T) {
 	t.Skip("Skipped: Complex routing - requires integration test")
 }
 
+func TestGetAchievementHistory_FromStatusHistory(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, mockUserRepo := setupAchievementTest()
+
+	app := fiber.New()
+	achievementID := "achievement-123"
+	studentID := "student-123"
+	studentUserID := "user-student"
+	delegateID := "user-delegate"
+	advisorID := "lecturer-123"
+
+	app.Get("/achievements/:id/history", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: studentUserID, Roles: []string{"Mahasiswa"}})
+		return service.GetAchievementHistory(c)
+	})
+
+	mockAchievementRepo.On("GetReferenceByID", achievementID).Return(&model.AchievementReference{
+		ID:        achievementID,
+		StudentID: studentID,
+		Status:    "verified",
+	}, nil)
+	mockStudentRepo.On("FindByUserID", studentUserID).Return(&model.Student{ID: studentID}, nil)
+
+	// Ditolak, dibuka kembali, lalu diajukan ulang: semua transisi tetap ada
+	draft, submitted, rejected := "draft", "submitted", "rejected"
+	note := "Sertifikat kurang jelas"
+	now := time.Now()
+	mockAchievementRepo.On("GetStatusHistory", achievementID).Return([]model.AchievementStatusHistory{
+		{ToStatus: "draft", ActorID: &studentUserID, ActorRole: "Mahasiswa", CreatedAt: now},
+		{FromStatus: &draft, ToStatus: "submitted", ActorID: &studentUserID, ActorRole: "Mahasiswa", CreatedAt: now},
+		{FromStatus: &submitted, ToStatus: "rejected", ActorID: &delegateID, ActorRole: "Dosen Wali", Note: &note, CreatedAt: now},
+		{FromStatus: &rejected, ToStatus: "draft", ActorID: &studentUserID, ActorRole: "Mahasiswa", CreatedAt: now},
+		{FromStatus: &draft, ToStatus: "submitted", ActorID: &studentUserID, ActorRole: "Mahasiswa", CreatedAt: now},
+		{FromStatus: &submitted, ToStatus: "verified", ActorID: &delegateID, ActorRole: "Dosen Wali", OnBehalfOf: &advisorID, CreatedAt: now},
+	}, nil)
+	mockUserRepo.On("FindByID", studentUserID).Return(&model.User{ID: studentUserID, FullName: "Mahasiswa A"}, nil).Once()
+	mockUserRepo.On("FindByID", delegateID).Return(&model.User{ID: delegateID, FullName: "Dosen Pengganti"}, nil).Once()
+	mockUserRepo.On("FindByID", advisorID).Return(&model.User{ID: advisorID, FullName: "Dosen Wali Asli"}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/achievements/"+achievementID+"/history", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			History []HistoryEntry `json:"history"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	history := result.Data.History
+	assert.Len(t, history, 6)
+	assert.Equal(t, "rejected", history[2].Status)
+	assert.Equal(t, "Dosen Pengganti (Dosen Wali)", history[2].Actor)
+	assert.Equal(t, &note, history[2].Notes)
+	assert.Equal(t, "rejected", *history[3].FromStatus)
+	assert.Equal(t, "Reopened for editing", history[3].Action)
+	assert.Equal(t, "Dosen Wali Asli (Dosen Wali)", history[5].OnBehalfOf)
+	assert.Equal(t, "Achievement verified on behalf of advisor", history[5].Action)
+	mockUserRepo.AssertExpectations(t)
+}
+
 // ==================== UPLOAD ATTACHMENT ====================
 
 func TestUploadAttachment_AchievementNotFound(t *testing.T) {
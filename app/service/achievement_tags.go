@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+)
+
+//
+// ==================== ACHIEVEMENT TAGS ======================
+// Tag yang cocok dengan nama / sinonim di kosakata (lihat TagService) disimpan
+// dalam nama kanoniknya. Tag di luar kosakata tetap boleh, kecuali
+// TagPolicy.CanonicalOnly aktif.
+//
+
+// canonicalTags - Tag dalam nama kanonik, tanpa duplikat (urutan dipertahankan).
+// unknown = tag yang tidak ada di kosakata.
+func canonicalTags(index map[string]*model.Tag, tags []string) (resolved []string, unknown []string) {
+	resolved = []string{}
+	seen := make(map[string]bool)
+	for _, value := range tags {
+		value = strings.Join(strings.Fields(value), " ")
+		if value == "" {
+			continue
+		}
+		if tag, ok := index[model.NormalizeTag(value)]; ok {
+			value = tag.Name
+		} else {
+			unknown = append(unknown, value)
+		}
+		if seen[model.NormalizeTag(value)] {
+			continue
+		}
+		seen[model.NormalizeTag(value)] = true
+		resolved = append(resolved, value)
+	}
+	return resolved, unknown
+}
+
+// checkTags - Ganti tags dengan bentuk kanoniknya. Kosakata yang gagal dibaca
+// hanya menggagalkan request jika CanonicalOnly aktif.
+// sent = true jika response error sudah dikirim.
+func (s *AchievementService) checkTags(c *fiber.Ctx, tags *[]string) (bool, error) {
+	vocabulary, err := s.tagRepo.GetAll()
+	if err != nil {
+		if !s.tagPolicy.CanonicalOnly {
+			return false, nil
+		}
+		return true, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch tags",
+		})
+	}
+
+	resolved, unknown := canonicalTags(tagIndex(vocabulary), *tags)
+	if len(unknown) > 0 && s.tagPolicy.CanonicalOnly {
+		return true, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("unknown tags: %s (use tags from GET /api/v1/tags)", strings.Join(unknown, ", ")),
+		})
+	}
+	*tags = resolved
+	return false, nil
+}
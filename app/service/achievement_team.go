@@ -0,0 +1,233 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+)
+
+//
+// ==================== TEAM ACHIEVEMENTS ======================
+// Prestasi tim = satu dokumen MongoDB (Team: anggota + peran) dan satu
+// achievement_references per anggota. Isi dokumen hanya diubah pembuatnya
+// (achievement.StudentID) selama semua reference anggota masih draft; setiap
+// reference diverifikasi dosen wali anggota masing-masing.
+//
+
+// checkTeam - Validasi daftar anggota (kosong = prestasi individu). Peran
+// di-trim di tempat. sent = true jika response error sudah dikirim.
+func (s *AchievementService) checkTeam(c *fiber.Ctx, ownerID string, members []model.TeamMember) (bool, error) {
+	if len(members) == 0 {
+		return false, nil
+	}
+
+	invalid := func(message string) (bool, error) {
+		return true, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  message,
+		})
+	}
+
+	if len(members) < 2 {
+		return invalid("a team achievement needs at least two members")
+	}
+
+	seen := make(map[string]bool)
+	for i := range members {
+		member := &members[i]
+		member.Role = strings.TrimSpace(member.Role)
+		if member.Role == "" {
+			return invalid(fmt.Sprintf("role is required for team member '%s'", member.StudentID))
+		}
+		if seen[member.StudentID] {
+			return invalid(fmt.Sprintf("student '%s' is listed more than once", member.StudentID))
+		}
+		seen[member.StudentID] = true
+
+		if member.StudentID == ownerID {
+			continue
+		}
+		if _, err := s.studentRepo.FindByID(member.StudentID); err != nil {
+			return invalid(fmt.Sprintf("team member '%s' not found", member.StudentID))
+		}
+	}
+
+	if !seen[ownerID] {
+		return invalid("team must include the submitting student")
+	}
+	return false, nil
+}
+
+// teamReferences - Reference semua anggota (prestasi individu: reference itu sendiri)
+func (s *AchievementService) teamReferences(achievement *model.Achievement, reference *model.AchievementReference) ([]model.AchievementReference, error) {
+	if len(achievement.Team) == 0 {
+		return []model.AchievementReference{*reference}, nil
+	}
+	return s.achievementRepo.GetReferencesByMongoID(reference.MongoAchievementID)
+}
+
+// checkTeamContent - Isi prestasi tim hanya boleh diubah pembuatnya dan hanya
+// jika reference semua anggota berstatus salah satu dari allowed.
+// sent = true jika response error sudah dikirim.
+func (s *AchievementService) checkTeamContent(c *fiber.Ctx, achievement *model.Achievement, reference *model.AchievementReference, allowed ...string) (bool, error) {
+	if len(achievement.Team) == 0 {
+		return false, nil
+	}
+
+	if reference.StudentID != achievement.StudentID {
+		return true, c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "only the team owner can change a team achievement",
+		})
+	}
+
+	refs, err := s.achievementRepo.GetReferencesByMongoID(reference.MongoAchievementID)
+	if err != nil {
+		return true, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch team members",
+		})
+	}
+	for _, ref := range refs {
+		if !contains(allowed, ref.Status) {
+			return true, c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("team member entries must have status %s", strings.Join(allowed, " or ")),
+				Data: fiber.Map{
+					"student_id": ref.StudentID,
+					"status":     ref.Status,
+				},
+			})
+		}
+	}
+	return false, nil
+}
+
+// syncTeamReferences - Sesuaikan reference dengan daftar anggota baru: anggota
+// baru mendapat reference draft, anggota yang dikeluarkan ditandai deleted.
+// Dipanggil hanya jika semua reference masih draft (checkTeamContent).
+func (s *AchievementService) syncTeamReferences(claims *model.JWTClaims, mongoID string, achievement *model.Achievement, current []model.AchievementReference) error {
+	members := map[string]bool{achievement.StudentID: true}
+	for _, member := range achievement.Team {
+		members[member.StudentID] = true
+	}
+
+	existing := make(map[string]bool)
+	for i := range current {
+		ref := &current[i]
+		existing[ref.StudentID] = true
+		if members[ref.StudentID] {
+			continue
+		}
+
+		status, err := nextStatus(ref.Status, transitionDelete)
+		if err != nil {
+			return err
+		}
+		from := ref.State()
+		ref.Status = status
+		if err := s.achievementRepo.UpdateReference(ref, from, statusChange(claims, optionalString("removed from team"), "")); err != nil {
+			return err
+		}
+	}
+
+	var added []*model.AchievementReference
+	for _, member := range achievement.Team {
+		if existing[member.StudentID] {
+			continue
+		}
+		added = append(added, &model.AchievementReference{
+			StudentID:          member.StudentID,
+			MongoAchievementID: mongoID,
+			Status:             model.AchievementStatusDraft,
+		})
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	return s.achievementRepo.CreateReferences(added, statusChange(claims, nil, ""))
+}
+
+//
+// ==================== GET TEAM (GET /achievements/:id/team) ======================
+// Anggota prestasi tim beserta status verifikasi masing-masing
+//
+
+func (s *AchievementService) GetAchievementTeam(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+
+	refs, err := s.teamReferences(achievement, reference)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch team members",
+		})
+	}
+	byStudent := make(map[string]model.AchievementReference)
+	for _, ref := range refs {
+		byStudent[ref.StudentID] = ref
+	}
+
+	team := achievement.Team
+	if len(team) == 0 {
+		team = []model.TeamMember{{StudentID: achievement.StudentID}}
+	}
+
+	members := []fiber.Map{}
+	for _, member := range team {
+		entry := fiber.Map{
+			"student_id": member.StudentID,
+			"role":       member.Role,
+			"is_owner":   member.StudentID == achievement.StudentID,
+		}
+		if ref, ok := byStudent[member.StudentID]; ok {
+			entry["achievement_id"] = ref.ID
+			entry["status"] = ref.Status
+			entry["points"] = ref.AwardedPoints()
+			entry["verified_at"] = ref.VerifiedAt
+		}
+		members = append(members, entry)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"mongo_achievement_id": reference.MongoAchievementID,
+			"owner_id":             achievement.StudentID,
+			"members":              members,
+			"total":                len(members),
+		},
+	})
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/utils"
+)
+
+//
+// ==================== ACHIEVEMENT TYPE REGISTRY ======================
+// Admin mengelola jenis prestasi beserta JSON Schema untuk details-nya.
+// Schema dicek saat achievement dibuat / diubah; perubahan schema tidak
+// memvalidasi ulang achievement yang sudah ada.
+//
+
+var achievementTypeCode = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type AchievementTypeService struct {
+	typeRepo repository.AchievementTypeRepository
+	audit    *AuditService
+	validate *validator.Validate
+}
+
+func NewAchievementTypeService(
+	typeRepo repository.AchievementTypeRepository,
+	audit *AuditService,
+) *AchievementTypeService {
+	return &AchievementTypeService{
+		typeRepo: typeRepo,
+		audit:    audit,
+		validate: validator.New(),
+	}
+}
+
+//
+// ==================== GET ACHIEVEMENT TYPES (GET /achievement-types) ======================
+// Dipakai frontend untuk merender form details. Default hanya jenis aktif;
+// ?include_inactive=true untuk semua.
+//
+
+func (s *AchievementTypeService) GetAchievementTypes(c *fiber.Ctx) error {
+	types, err := s.typeRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievement types",
+		})
+	}
+
+	includeInactive := c.QueryBool("include_inactive", false)
+	result := []model.AchievementType{}
+	for _, achievementType := range types {
+		if achievementType.IsActive || includeInactive {
+			result = append(result, achievementType)
+		}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_types": result,
+			"total":             len(result),
+		},
+	})
+}
+
+//
+// ==================== GET ACHIEVEMENT TYPE (GET /achievement-types/:code) ======================
+//
+
+func (s *AchievementTypeService) GetAchievementType(c *fiber.Ctx) error {
+	achievementType, err := s.typeRepo.FindByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement type not found",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   achievementType,
+	})
+}
+
+//
+// ==================== CREATE ACHIEVEMENT TYPE (POST /achievement-types) ======================
+//
+
+func (s *AchievementTypeService) CreateAchievementType(c *fiber.Ctx) error {
+	req := new(model.AchievementTypeCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	if !achievementTypeCode.MatchString(req.Code) {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "code must start with a lowercase letter and contain only lowercase letters, digits or '_'",
+		})
+	}
+	if sent, err := s.checkSchema(c, req.DetailsSchema); sent {
+		return err
+	}
+
+	if existing, _ := s.typeRepo.FindByCode(req.Code); existing != nil {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement type code already exists",
+		})
+	}
+
+	achievementType := &model.AchievementType{
+		Code:          req.Code,
+		Name:          req.Name,
+		Description:   req.Description,
+		DetailsSchema: req.DetailsSchema,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.typeRepo.Create(achievementType); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create achievement type",
+		})
+	}
+
+	s.audit.Record(c, "achievement_type.create", "achievement_type", achievementType.Code, map[string]interface{}{
+		"name":      achievementType.Name,
+		"is_active": achievementType.IsActive,
+	})
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "achievement type created successfully",
+		Data:    achievementType,
+	})
+}
+
+//
+// ==================== UPDATE ACHIEVEMENT TYPE (PUT /achievement-types/:code) ======================
+// Code tidak bisa diubah (dipakai achievement yang sudah ada); nonaktifkan
+// dengan is_active = false
+//
+
+func (s *AchievementTypeService) UpdateAchievementType(c *fiber.Ctx) error {
+	existing, err := s.typeRepo.FindByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement type not found",
+		})
+	}
+
+	req := new(model.AchievementTypeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	if sent, err := s.checkSchema(c, req.DetailsSchema); sent {
+		return err
+	}
+
+	existing.Name = req.Name
+	existing.Description = req.Description
+	existing.DetailsSchema = req.DetailsSchema
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	if err := s.typeRepo.Update(existing); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update achievement type",
+		})
+	}
+
+	s.audit.Record(c, "achievement_type.update", "achievement_type", existing.Code, map[string]interface{}{
+		"name":      existing.Name,
+		"is_active": existing.IsActive,
+	})
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "achievement type updated successfully",
+		Data:    existing,
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// checkSchema - 422 jika details_schema bukan schema yang didukung.
+// sent = true jika response sudah dikirim.
+func (s *AchievementTypeService) checkSchema(c *fiber.Ctx, raw []byte) (bool, error) {
+	if _, err := utils.ParseJSONSchema(raw); err != nil {
+		return true, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	return false, nil
+}
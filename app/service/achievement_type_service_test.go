@@ -0,0 +1,158 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupAchievementTypeTest() (*AchievementTypeService, *mocks.MockAchievementTypeRepository, *mocks.MockAuditLogRepository) {
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	service := NewAchievementTypeService(mockTypeRepo, NewAuditService(mockAuditRepo))
+
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+	return service, mockTypeRepo, mockAuditRepo
+}
+
+// ==================== GET ACHIEVEMENT TYPES ====================
+
+func TestGetAchievementTypes(t *testing.T) {
+	service, mockTypeRepo, _ := setupAchievementTypeTest()
+	mockTypeRepo.On("GetAll").Return([]model.AchievementType{
+		{Code: "competition", Name: "Kompetisi", DetailsSchema: json.RawMessage(competitionSchema), IsActive: true},
+		{Code: "seminar", Name: "Seminar", DetailsSchema: json.RawMessage(`{"type": "object"}`), IsActive: false},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/achievement-types", service.GetAchievementTypes)
+
+	tests := []struct {
+		url   string
+		codes []string
+	}{
+		{"/achievement-types", []string{"competition"}},
+		{"/achievement-types?include_inactive=true", []string{"competition", "seminar"}},
+	}
+
+	for _, tt := range tests {
+		resp, _ := app.Test(httptest.NewRequest("GET", tt.url, nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data struct {
+				Types []struct {
+					Code          string                 `json:"code"`
+					DetailsSchema map[string]interface{} `json:"details_schema"`
+				} `json:"achievement_types"`
+			} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		codes := []string{}
+		for _, achievementType := range result.Data.Types {
+			codes = append(codes, achievementType.Code)
+			assert.Equal(t, "object", achievementType.DetailsSchema["type"])
+		}
+		assert.Equal(t, tt.codes, codes, tt.url)
+	}
+}
+
+// ==================== CREATE ACHIEVEMENT TYPE ====================
+
+func TestCreateAchievementType(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			"schema valid",
+			`{"code": "hackathon", "name": "Hackathon", "details_schema": {"type": "object", "required": ["teamName"], "properties": {"teamName": {"type": "string"}}}}`,
+			201,
+		},
+		{
+			"keyword schema tidak didukung",
+			`{"code": "hackathon", "name": "Hackathon", "details_schema": {"type": "object", "oneOf": []}}`,
+			422,
+		},
+		{
+			"root bukan object",
+			`{"code": "hackathon", "name": "Hackathon", "details_schema": {"type": "string"}}`,
+			422,
+		},
+		{
+			"code tidak valid",
+			`{"code": "Hack Athon", "name": "Hackathon", "details_schema": {"type": "object"}}`,
+			422,
+		},
+		{
+			"code sudah dipakai",
+			`{"code": "competition", "name": "Kompetisi", "details_schema": {"type": "object"}}`,
+			409,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockTypeRepo, mockAuditRepo := setupAchievementTypeTest()
+			mockTypeRepo.On("FindByCode", "competition").Return(&model.AchievementType{Code: "competition"}, nil).Maybe()
+			mockTypeRepo.On("FindByCode", mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+			mockTypeRepo.On("Create", mock.AnythingOfType("*model.AchievementType")).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/achievement-types", service.CreateAchievementType)
+
+			req := httptest.NewRequest("POST", "/achievement-types", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				mockTypeRepo.AssertCalled(t, "Create", mock.MatchedBy(func(at *model.AchievementType) bool {
+					return at.Code == "hackathon" && at.IsActive
+				}))
+				mockAuditRepo.AssertCalled(t, "Create", mock.MatchedBy(func(log *model.AuditLog) bool {
+					return log.Action == "achievement_type.create"
+				}))
+			} else {
+				mockTypeRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+// ==================== UPDATE ACHIEVEMENT TYPE ====================
+
+func TestUpdateAchievementType_Deactivate(t *testing.T) {
+	service, mockTypeRepo, _ := setupAchievementTypeTest()
+	mockTypeRepo.On("FindByCode", "competition").Return(&model.AchievementType{
+		Code: "competition", Name: "Kompetisi", DetailsSchema: json.RawMessage(competitionSchema), IsActive: true,
+	}, nil)
+	mockTypeRepo.On("Update", mock.AnythingOfType("*model.AchievementType")).Return(nil)
+
+	app := fiber.New()
+	app.Put("/achievement-types/:code", service.UpdateAchievementType)
+
+	body := `{"name": "Lomba", "details_schema": {"type": "object"}, "is_active": false}`
+	req := httptest.NewRequest("PUT", "/achievement-types/competition", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockTypeRepo.AssertCalled(t, "Update", mock.MatchedBy(func(at *model.AchievementType) bool {
+		return at.Code == "competition" && at.Name == "Lomba" && !at.IsActive && string(at.DetailsSchema) == `{"type": "object"}`
+	}))
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+
+	"project_uas/app/model"
+)
+
+//
+// ==================== ACHIEVEMENT STATE MACHINE ======================
+// Satu-satunya sumber transisi status achievement: status asal -> aksi ->
+// status tujuan. Semua handler yang mengubah status harus lewat nextStatus;
+// siapa yang boleh melakukan aksi tetap dicek lewat Authorizer.
+//
+//   draft              -> submit: submitted, delete: deleted
+//   submitted          -> approve_stage: submitted (stage pipeline berikutnya),
+//                         verify: verified (stage terakhir), reject: rejected,
+//                         request_revision: revision_requested, withdraw: draft
+//   rejected           -> reopen: draft
+//   revision_requested -> reopen: draft
+//   deleted            -> restore: draft (selama belum dihapus permanen)
+//
+
+const (
+	transitionSubmit          = "submit"
+	transitionDelete          = "delete"
+	transitionApproveStage    = "approve_stage"
+	transitionVerify          = "verify"
+	transitionReject          = "reject"
+	transitionRequestRevision = "request_revision"
+	transitionWithdraw        = "withdraw"
+	transitionReopen          = "reopen"
+	transitionRestore         = "restore"
+)
+
+var achievementTransitions = map[string]map[string]string{
+	model.AchievementStatusDraft: {
+		transitionSubmit: model.AchievementStatusSubmitted,
+		transitionDelete: model.AchievementStatusDeleted,
+	},
+	model.AchievementStatusSubmitted: {
+		transitionApproveStage:    model.AchievementStatusSubmitted,
+		transitionVerify:          model.AchievementStatusVerified,
+		transitionReject:          model.AchievementStatusRejected,
+		transitionRequestRevision: model.AchievementStatusRevisionRequested,
+		transitionWithdraw:        model.AchievementStatusDraft,
+	},
+	model.AchievementStatusRejected: {
+		transitionReopen: model.AchievementStatusDraft,
+	},
+	model.AchievementStatusRevisionRequested: {
+		transitionReopen: model.AchievementStatusDraft,
+	},
+	model.AchievementStatusDeleted: {
+		transitionRestore: model.AchievementStatusDraft,
+	},
+}
+
+// transitionError - Aksi yang tidak sah untuk status saat ini
+type transitionError struct {
+	from   string
+	action string
+}
+
+func (e *transitionError) Error() string {
+	return fmt.Sprintf("cannot %s achievement with status '%s'", e.action, e.from)
+}
+
+// nextStatus - Status tujuan aksi dari status from, atau transitionError
+func nextStatus(from, action string) (string, error) {
+	if to, ok := achievementTransitions[from][action]; ok {
+		return to, nil
+	}
+	return "", &transitionError{from: from, action: action}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"project_uas/app/model"
+)
+
+// ==================== STATE MACHINE ====================
+
+func TestNextStatus_AllTransitions(t *testing.T) {
+	statuses := []string{
+		model.AchievementStatusDraft,
+		model.AchievementStatusSubmitted,
+		model.AchievementStatusVerified,
+		model.AchievementStatusRejected,
+		model.AchievementStatusRevisionRequested,
+		model.AchievementStatusDeleted,
+	}
+	actions := []string{
+		transitionSubmit,
+		transitionDelete,
+		transitionApproveStage,
+		transitionVerify,
+		transitionReject,
+		transitionRequestRevision,
+		transitionWithdraw,
+		transitionReopen,
+		transitionRestore,
+	}
+
+	// Satu-satunya transisi yang sah; kombinasi lain harus ditolak
+	legal := map[[2]string]string{
+		{model.AchievementStatusDraft, transitionSubmit}:              model.AchievementStatusSubmitted,
+		{model.AchievementStatusDraft, transitionDelete}:              model.AchievementStatusDeleted,
+		{model.AchievementStatusSubmitted, transitionApproveStage}:    model.AchievementStatusSubmitted,
+		{model.AchievementStatusSubmitted, transitionVerify}:          model.AchievementStatusVerified,
+		{model.AchievementStatusSubmitted, transitionReject}:          model.AchievementStatusRejected,
+		{model.AchievementStatusSubmitted, transitionRequestRevision}: model.AchievementStatusRevisionRequested,
+		{model.AchievementStatusSubmitted, transitionWithdraw}:        model.AchievementStatusDraft,
+		{model.AchievementStatusRejected, transitionReopen}:           model.AchievementStatusDraft,
+		{model.AchievementStatusRevisionRequested, transitionReopen}:  model.AchievementStatusDraft,
+		{model.AchievementStatusDeleted, transitionRestore}:           model.AchievementStatusDraft,
+	}
+
+	for _, from := range statuses {
+		for _, action := range actions {
+			to, err := nextStatus(from, action)
+
+			if want, ok := legal[[2]string{from, action}]; ok {
+				assert.NoError(t, err, "%s --%s-->", from, action)
+				assert.Equal(t, want, to, "%s --%s-->", from, action)
+				continue
+			}
+
+			assert.Error(t, err, "%s --%s--> should be illegal", from, action)
+			assert.Empty(t, to)
+			assert.IsType(t, &transitionError{}, err)
+		}
+	}
+}
+
+func TestNextStatus_UnknownStatus(t *testing.T) {
+	_, err := nextStatus("archived", transitionSubmit)
+
+	assert.EqualError(t, err, "cannot submit achievement with status 'archived'")
+}
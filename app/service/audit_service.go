@@ -0,0 +1,53 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== AUDIT LOG ======================
+// Dipakai service lain untuk mencatat aksi penting ke tabel audit_logs
+//
+
+type AuditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record - Actor diambil dari JWT claims (jika ada), IP dari request
+func (s *AuditService) Record(c *fiber.Ctx, action, targetType, targetID string, details map[string]interface{}) error {
+	entry := &model.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  c.IP(),
+	}
+
+	if claims, ok := c.Locals("user").(*model.JWTClaims); ok {
+		actorID := claims.UserID
+		entry.ActorID = &actorID
+	}
+
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		entry.Details = string(raw)
+	}
+
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("[AUDIT] Failed to write %s on %s/%s: %v", action, targetType, targetID, err)
+		return err
+	}
+	return nil
+}
@@ -1,32 +1,50 @@
 package service
 
 import (
+	"errors"
 	"log"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"project_uas/app/model"
 	"project_uas/app/repository"
 	"project_uas/utils"
 )
 
+var errRoleNotAssigned = errors.New("role not assigned to user")
+
 type AuthService struct {
-	userRepo repository.UserRepository
-	roleRepo repository.RoleRepository
-	permRepo repository.PermissionRepository
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	permRepo         repository.PermissionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	revocations      *TokenRevocationService
+	lockout          *LockoutService
+	mfa              *MFAService
 }
 
 func NewAuthService(
 	user repository.UserRepository,
 	role repository.RoleRepository,
 	perm repository.PermissionRepository,
+	refreshToken repository.RefreshTokenRepository,
+	revocations *TokenRevocationService,
+	lockout *LockoutService,
+	mfa *MFAService,
 ) *AuthService {
 	return &AuthService{
-		userRepo: user,
-		roleRepo: role,
-		permRepo: perm,
+		userRepo:         user,
+		roleRepo:         role,
+		permRepo:         perm,
+		refreshTokenRepo: refreshToken,
+		revocations:      revocations,
+		lockout:          lockout,
+		mfa:              mfa,
 	}
 }
 
@@ -45,9 +63,15 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 		})
 	}
 
+	// cek lockout (username / IP)
+	if lockedUntil := s.lockout.LockedUntil(req.Username, c.IP()); lockedUntil != nil {
+		return s.tooManyAttempts(c, *lockedUntil)
+	}
+
 	// cek username
 	user, err := s.userRepo.FindByUsername(req.Username)
 	if err != nil {
+		s.lockout.RecordFailure(c, req.Username, c.IP())
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "invalid username or password",
@@ -56,50 +80,106 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 
 	// cek password
 	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		s.lockout.RecordFailure(c, req.Username, c.IP())
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "invalid username or password",
 		})
 	}
 
-	// ambil role
-	role, _ := s.roleRepo.GetRoleByID(user.RoleID)
+	// cek status akun (dicek setelah password supaya status akun tidak bocor)
+	if !user.IsActive {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account is inactive",
+		})
+	}
 
-	// ambil permission by role
-	perms, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+	// ambil role sesi (semua role user, atau hanya role yang dipilih)
+	roles, err := s.sessionRoles(user.ID, req.Role)
+	if err != nil {
+		return s.sessionRoleError(c, err)
+	}
 
-	// response user
-	userRes := model.UserResponse{
-		ID:          user.ID,
-		Username:    user.Username,
-		Email:       user.Email,
-		FullName:    user.FullName,
-		Role:        role.Name,
-		IsActive:    user.IsActive,
-		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
-		Permissions: perms,
+	// MFA: token asli baru diberikan setelah kode dicek di /auth/mfa/verify
+	challenge, err := s.mfa.loginChallenge(user, roles, req.Role)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create session",
+		})
+	}
+	if challenge != nil {
+		return c.JSON(model.APIResponse{
+			Status:  "success",
+			Message: "mfa code required",
+			Data:    challenge,
+		})
 	}
 
-	// generate token
-	access, _ := utils.GenerateJWT(userRes)
-	refresh, _ := utils.GenerateRefreshToken(user.ID)
+	s.lockout.RecordSuccess(req.Username)
 
-	// Log successful login
-	log.Printf("[LOGIN] User: %s (%s) | Role: %s | Time: %s",
-		user.Username,
-		user.ID,
-		role.Name,
-		time.Now().Format("2006-01-02 15:04:05"),
-	)
+	return s.completeLogin(c, user, roles, req.Role, nil)
+}
 
-	return c.JSON(model.APIResponse{
-		Status: "success",
-		Data: model.LoginResponse{
-			Token:        access,
-			RefreshToken: refresh,
-			User:         userRes,
-		},
-	})
+//
+// ==================== VERIFY MFA (LOGIN STEP 2) ======================
+// Tukar token mfa_pending + kode TOTP / recovery code dengan token asli
+//
+
+func (s *AuthService) VerifyMFA(c *fiber.Ctx) error {
+
+	req := new(model.MFAVerifyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	claims, err := utils.ValidateMFAToken(req.MFAToken)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid mfa token",
+		})
+	}
+
+	// kode salah ikut dihitung di lockout (brute force 6 digit)
+	if lockedUntil := s.lockout.LockedUntil(claims.Username, c.IP()); lockedUntil != nil {
+		return s.tooManyAttempts(c, *lockedUntil)
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid mfa token",
+		})
+	}
+
+	if !user.IsActive {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account is inactive",
+		})
+	}
+
+	recoveryCodes, err := s.mfa.completeLogin(c, user.ID, req.Code)
+	if err != nil {
+		if errors.Is(err, errInvalidMFACode) {
+			s.lockout.RecordFailure(c, claims.Username, c.IP())
+		}
+		return s.mfa.mfaError(c, err)
+	}
+
+	s.lockout.RecordSuccess(claims.Username)
+
+	roles, err := s.sessionRoles(user.ID, claims.Role)
+	if err != nil {
+		return s.sessionRoleError(c, err)
+	}
+	return s.completeLogin(c, user, roles, claims.Role, recoveryCodes)
 }
 
 //
@@ -111,20 +191,43 @@ func (s *AuthService) Refresh(c *fiber.Ctx) error {
 	req := new(model.RefreshTokenRequest)
 	_ = c.BodyParser(req)
 
-	claims := &model.RefreshTokenClaims{}
-
-	// validasi refresh token
-	token, err := jwt.ParseWithClaims(req.RefreshToken, claims, func(t *jwt.Token) (interface{}, error) {
-		return utils.JwtKey, nil
-	})
+	// validasi refresh token (signature + expiry)
+	claims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid refresh token",
+		})
+	}
 
-	if err != nil || !token.Valid {
+	// cek token di server-side store
+	stored, err := s.refreshTokenRepo.FindByID(claims.ID)
+	if err != nil || stored.UserID != claims.UserID {
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "invalid refresh token",
 		})
 	}
 
+	if stored.RevokedAt != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "refresh token revoked",
+		})
+	}
+
+	// Token sudah pernah dipakai = kemungkinan dicuri, revoke seluruh family
+	if stored.UsedAt != nil {
+		return s.rejectReusedRefreshToken(c, stored)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "refresh token expired",
+		})
+	}
+
 	// cari user
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
@@ -134,26 +237,48 @@ func (s *AuthService) Refresh(c *fiber.Ctx) error {
 		})
 	}
 
-	// ambil role
-	role, _ := s.roleRepo.GetRoleByID(user.RoleID)
+	if !user.IsActive {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account is inactive",
+		})
+	}
 
-	// ambil permission
-	perms, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+	// role & permission terbaru (pilihan role saat login tetap berlaku)
+	roles, err := s.sessionRoles(user.ID, claims.Role)
+	if err != nil {
+		if errors.Is(err, errRoleNotAssigned) {
+			return c.Status(401).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "selected role is no longer assigned",
+			})
+		}
+		return s.sessionRoleError(c, err)
+	}
 
-	userRes := model.UserResponse{
-		ID:          user.ID,
-		Username:    user.Username,
-		Email:       user.Email,
-		FullName:    user.FullName,
-		Role:        role.Name,
-		IsActive:    user.IsActive,
-		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
-		Permissions: perms,
+	userRes := s.sessionUser(user, roles)
+
+	// rotasi: token lama ditandai used, token baru di family yang sama
+	newTokenID := uuid.New().String()
+	ok, err := s.refreshTokenRepo.MarkUsed(stored.ID, newTokenID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to rotate refresh token",
+		})
+	}
+	if !ok {
+		// Kalah race dengan request lain yang memakai token yang sama
+		return s.rejectReusedRefreshToken(c, stored)
 	}
 
-	// generate new tokens
-	access, _ := utils.GenerateJWT(userRes)
-	refresh, _ := utils.GenerateRefreshToken(user.ID)
+	access, refresh, err := s.issueTokensWithID(userRes, stored.FamilyID, newTokenID, claims.Role)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to rotate refresh token",
+		})
+	}
 
 	// Log token refresh
 	log.Printf("[REFRESH] User: %s (%s) | Time: %s",
@@ -189,13 +314,14 @@ func (s *AuthService) Profile(c *fiber.Ctx) error {
 	}
 
 	res := model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Role:      claims.Role,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		FullName:   user.FullName,
+		Roles:      claims.Roles,
+		RoleScopes: claims.RoleScopes,
+		IsActive:   user.IsActive,
+		CreatedAt:  user.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 
 	return c.JSON(model.APIResponse{
@@ -218,11 +344,30 @@ func (s *AuthService) Logout(c *fiber.Ctx) error {
 		})
 	}
 
+	// Revoke sesi saat ini di server (refresh token tidak bisa dipakai lagi)
+	var err error
+	if claims.SessionID != "" {
+		err = s.refreshTokenRepo.RevokeFamily(claims.SessionID)
+	} else {
+		// Token lama tanpa sid: revoke semua sesi user
+		err = s.refreshTokenRepo.RevokeByUserID(claims.UserID)
+	}
+	if err == nil {
+		// Access token yang sedang dipakai juga langsung tidak berlaku
+		err = s.revocations.RevokeToken(claims, "logout")
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke session",
+		})
+	}
+
 	// Log logout activity (audit trail)
-	log.Printf("[LOGOUT] User: %s (%s) | Role: %s | Time: %s",
+	log.Printf("[LOGOUT] User: %s (%s) | Roles: %s | Time: %s",
 		claims.Username,
 		claims.UserID,
-		claims.Role,
+		strings.Join(claims.Roles, ","),
 		time.Now().Format("2006-01-02 15:04:05"),
 	)
 
@@ -230,4 +375,180 @@ func (s *AuthService) Logout(c *fiber.Ctx) error {
 		Status:  "success",
 		Message: "logout successful",
 	})
-}
\ No newline at end of file
+}
+//
+// ==================== HELPER: COMPLETE LOGIN ======================
+// Buat sesi baru dan kirim response login
+//
+
+func (s *AuthService) completeLogin(c *fiber.Ctx, user *model.User, roles []model.Role, selectedRole string, recoveryCodes []string) error {
+	// response user + gabungan permission semua role sesi
+	userRes := s.sessionUser(user, roles)
+
+	// generate token (sesi baru = family baru)
+	access, refresh, err := s.issueTokens(userRes, uuid.New().String(), selectedRole)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create session",
+		})
+	}
+
+	// Log successful login
+	log.Printf("[LOGIN] User: %s (%s) | Roles: %s | Time: %s",
+		user.Username,
+		user.ID,
+		strings.Join(userRes.Roles, ","),
+		time.Now().Format("2006-01-02 15:04:05"),
+	)
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.LoginResponse{
+			Token:         access,
+			RefreshToken:  refresh,
+			User:          userRes,
+			RecoveryCodes: recoveryCodes,
+		},
+	})
+}
+
+//
+// ==================== HELPER: SESSION ROLES ======================
+//
+
+// sessionRoles - Role aktif di sesi: semua role user, atau hanya role yang dipilih saat login
+func (s *AuthService) sessionRoles(userID, selectedRole string) ([]model.Role, error) {
+	roles, err := s.userRepo.GetRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	if selectedRole == "" {
+		return roles, nil
+	}
+
+	// Role ber-scope bisa punya beberapa assignment (satu per program studi)
+	var selected []model.Role
+	for _, role := range roles {
+		if role.Name == selectedRole {
+			selected = append(selected, role)
+		}
+	}
+	if len(selected) > 0 {
+		return selected, nil
+	}
+	return nil, errRoleNotAssigned
+}
+
+// sessionUser - Response user dengan gabungan permission dari semua role sesi
+func (s *AuthService) sessionUser(user *model.User, roles []model.Role) model.UserResponse {
+	perms := []string{}
+	seen := map[string]bool{}
+	loaded := map[string]bool{}
+
+	for _, role := range roles {
+		if loaded[role.ID] {
+			continue
+		}
+		loaded[role.ID] = true
+
+		rolePerms, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+		for _, perm := range rolePerms {
+			if !seen[perm] {
+				seen[perm] = true
+				perms = append(perms, perm)
+			}
+		}
+	}
+
+	return model.UserResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Roles:       roleNames(roles),
+		RoleScopes:  roleScopes(roles),
+		IsActive:    user.IsActive,
+		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
+		Permissions: perms,
+	}
+}
+
+func (s *AuthService) sessionRoleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errRoleNotAssigned) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	return c.Status(500).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "failed to load user roles",
+	})
+}
+
+// tooManyAttempts - Response 429 + header Retry-After saat username / IP terkunci
+func (s *AuthService) tooManyAttempts(c *fiber.Ctx, lockedUntil time.Time) error {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(429).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "too many failed login attempts, try again later",
+	})
+}
+
+//
+// ==================== HELPER: ISSUE TOKENS ======================
+//
+
+func (s *AuthService) issueTokens(userRes model.UserResponse, familyID, selectedRole string) (string, string, error) {
+	return s.issueTokensWithID(userRes, familyID, uuid.New().String(), selectedRole)
+}
+
+// issueTokensWithID - Simpan refresh token di store lalu generate access + refresh token
+func (s *AuthService) issueTokensWithID(userRes model.UserResponse, familyID, tokenID, selectedRole string) (string, string, error) {
+	expiresAt := time.Now().Add(utils.RefreshTokenTTL)
+
+	stored := &model.RefreshToken{
+		ID:        tokenID,
+		UserID:    userRes.ID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.refreshTokenRepo.Create(stored); err != nil {
+		return "", "", err
+	}
+
+	access, err := utils.GenerateJWT(userRes, familyID, s.revocations.IssuedAt(userRes.ID))
+	if err != nil {
+		return "", "", err
+	}
+
+	refresh, err := utils.GenerateRefreshToken(userRes.ID, tokenID, familyID, selectedRole, expiresAt)
+	if err != nil {
+		return "", "", err
+	}
+
+	return access, refresh, nil
+}
+
+// rejectReusedRefreshToken - Refresh token dipakai ulang: revoke seluruh family
+func (s *AuthService) rejectReusedRefreshToken(c *fiber.Ctx, stored *model.RefreshToken) error {
+	if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+		log.Printf("[REFRESH] Failed to revoke family %s: %v", stored.FamilyID, err)
+	}
+
+	log.Printf("[REFRESH] Reuse detected | User: %s | Family: %s | Time: %s",
+		stored.UserID,
+		stored.FamilyID,
+		time.Now().Format("2006-01-02 15:04:05"),
+	)
+
+	return c.Status(401).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "refresh token reuse detected, session revoked",
+	})
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http/httptest"
 	"strings"
@@ -9,7 +11,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
-	
+	"github.com/stretchr/testify/mock"
 
 	"project_uas/app/model"
 	
@@ -19,20 +21,34 @@ import (
 
 // ==================== HELPER FUNCTIONS ====================
 
-func setupAuthTest() (*AuthService, *mocks.MockUserRepository, *mocks.MockRoleRepository, *mocks.MockPermissionRepository) {
+func setupAuthTest() (*AuthService, *mocks.MockUserRepository, *mocks.MockRoleRepository, *mocks.MockPermissionRepository, *mocks.MockRefreshTokenRepository, *mocks.MockTokenRevocationRepository, *mocks.MockLoginLockoutRepository, *mocks.MockMFARepository) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockRoleRepo := new(mocks.MockRoleRepository)
 	mockPermRepo := new(mocks.MockPermissionRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockRevocationRepo := new(mocks.MockTokenRevocationRepository)
+	mockLockoutRepo := new(mocks.MockLoginLockoutRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+	mockMFARepo := new(mocks.MockMFARepository)
+
+	useTestJWTKeys()
 
-	service := NewAuthService(mockUserRepo, mockRoleRepo, mockPermRepo)
+	// Audit log dicek di lockout_service_test / mfa_service_test
+	mockAuditRepo.On("Create", mock.Anything).Return(nil).Maybe()
 
-	return service, mockUserRepo, mockRoleRepo, mockPermRepo
+	revocations := NewTokenRevocationService(mockRevocationRepo, mockRefreshRepo)
+	audit := NewAuditService(mockAuditRepo)
+	lockout := NewLockoutService(mockLockoutRepo, mockUserRepo, audit, DefaultLockoutPolicy)
+	mfa := NewMFAService(mockMFARepo, mockUserRepo, mockRoleRepo, audit)
+	service := NewAuthService(mockUserRepo, mockRoleRepo, mockPermRepo, mockRefreshRepo, revocations, lockout, mfa)
+
+	return service, mockUserRepo, mockRoleRepo, mockPermRepo, mockRefreshRepo, mockRevocationRepo, mockLockoutRepo, mockMFARepo
 }
 
 // ==================== FR-001: LOGIN ====================
 
 func TestLogin_Success(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, mockPermRepo := setupAuthTest()
+	service, mockUserRepo, mockRoleRepo, mockPermRepo, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/login", service.Login)
@@ -47,7 +63,6 @@ func TestLogin_Success(t *testing.T) {
 		Email:        "mahasiswa@test.com",
 		PasswordHash: hashedPassword,
 		FullName:     "John Doe",
-		RoleID:       roleID,
 		IsActive:     true,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -61,9 +76,15 @@ func TestLogin_Success(t *testing.T) {
 	permissions := []string{"achievement:create", "achievement:read"}
 
 	// Mock expectations
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("ResetFailures", "username", "mahasiswa123").Return(nil)
+	mockMFARepo.On("FindByUserID", userID).Return(nil, sql.ErrNoRows)
 	mockUserRepo.On("FindByUsername", "mahasiswa123").Return(user, nil)
-	mockRoleRepo.On("GetRoleByID", roleID).Return(role, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{*role}, nil)
 	mockPermRepo.On("GetPermissionsByRoleID", roleID).Return(permissions, nil)
+	mockRefreshRepo.On("Create", mock.MatchedBy(func(token *model.RefreshToken) bool {
+		return token.UserID == userID && token.ID != "" && token.FamilyID != ""
+	})).Return(nil)
 
 	body := `{"username": "mahasiswa123", "password": "password123"}`
 	req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
@@ -75,14 +96,19 @@ func TestLogin_Success(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 	mockRoleRepo.AssertExpectations(t)
 	mockPermRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertExpectations(t)
 }
 
 func TestLogin_InvalidUsername(t *testing.T) {
-	service, mockUserRepo, _, _ := setupAuthTest()
+	service, mockUserRepo, _, _, _, _, mockLockoutRepo, _ := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/login", service.Login)
 
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, 0, nil).Twice()
+	mockLockoutRepo.On("RecordFailedIP", mock.Anything, mock.Anything).Return(nil).Once()
 	mockUserRepo.On("FindByUsername", "invaliduser").Return(nil, errors.New("user not found"))
 
 	body := `{"username": "invaliduser", "password": "password123"}`
@@ -93,10 +119,11 @@ func TestLogin_InvalidUsername(t *testing.T) {
 
 	assert.Equal(t, 401, resp.StatusCode)
 	mockUserRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertExpectations(t)
 }
 
 func TestLogin_InvalidPassword(t *testing.T) {
-	service, mockUserRepo, _, _ := setupAuthTest()
+	service, mockUserRepo, _, _, _, _, mockLockoutRepo, _ := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/login", service.Login)
@@ -110,6 +137,9 @@ func TestLogin_InvalidPassword(t *testing.T) {
 		PasswordHash: hashedPassword,
 	}
 
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, 0, nil).Twice()
+	mockLockoutRepo.On("RecordFailedIP", mock.Anything, mock.Anything).Return(nil).Once()
 	mockUserRepo.On("FindByUsername", "mahasiswa123").Return(user, nil)
 
 	body := `{"username": "mahasiswa123", "password": "wrongpassword"}`
@@ -120,10 +150,66 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 	assert.Equal(t, 401, resp.StatusCode)
 	mockUserRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertExpectations(t)
+}
+
+func TestLogin_InactiveAccount(t *testing.T) {
+	service, mockUserRepo, _, _, mockRefreshRepo, _, mockLockoutRepo, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+
+	user := &model.User{
+		ID:           "user-123",
+		Username:     "mahasiswa123",
+		PasswordHash: hashedPassword,
+		IsActive:     false,
+	}
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("FindByUsername", "mahasiswa123").Return(user, nil)
+
+	body := `{"username": "mahasiswa123", "password": "password123"}`
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+	mockUserRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	service, mockUserRepo, _, _, _, _, mockLockoutRepo, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	lockedUntil := time.Now().Add(2 * time.Minute)
+	mockLockoutRepo.On("Get", "username", "mahasiswa123").Return(&model.LoginLockout{
+		KeyType:     "username",
+		KeyValue:    "mahasiswa123",
+		LockCount:   1,
+		LockedUntil: &lockedUntil,
+	}, nil)
+	mockLockoutRepo.On("Get", "ip", mock.Anything).Return(nil, sql.ErrNoRows)
+
+	body := `{"username": "mahasiswa123", "password": "password123"}`
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	mockUserRepo.AssertNotCalled(t, "FindByUsername", mock.Anything)
 }
 
 func TestLogin_InvalidRequestBody(t *testing.T) {
-	service, _, _, _ := setupAuthTest()
+	service, _, _, _, _, _, _, _ := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/login", service.Login)
@@ -137,14 +223,407 @@ func TestLogin_InvalidRequestBody(t *testing.T) {
 	assert.Equal(t, 400, resp.StatusCode)
 }
 
+// ==================== LOGIN: MULTI ROLE ====================
+
+func TestLogin_MultiRoleUnionsPermissions(t *testing.T) {
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	user := &model.User{ID: "user-1", Username: "asdos", PasswordHash: hashedPassword, IsActive: true}
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("ResetFailures", "username", "asdos").Return(nil)
+	mockMFARepo.On("FindByUserID", "user-1").Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("FindByUsername", "asdos").Return(user, nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-dosen", Name: "Dosen Wali"},
+	}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", "role-mhs").Return([]string{"achievement:create", "achievement:read"}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", "role-dosen").Return([]string{"achievement:read", "achievement:verify"}, nil)
+	mockRefreshRepo.On("Create", mock.AnythingOfType("*model.RefreshToken")).Return(nil)
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username": "asdos", "password": "password123"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.LoginResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"Mahasiswa", "Dosen Wali"}, result.Data.User.Roles)
+	assert.Equal(t, []string{"achievement:create", "achievement:read", "achievement:verify"}, result.Data.User.Permissions)
+
+	claims, err := utils.ValidateToken(result.Data.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Mahasiswa", "Dosen Wali"}, claims.Roles)
+}
+
+func TestLogin_SelectedRoleLimitsSession(t *testing.T) {
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	user := &model.User{ID: "user-1", Username: "asdos", PasswordHash: hashedPassword, IsActive: true}
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("ResetFailures", "username", "asdos").Return(nil)
+	mockMFARepo.On("FindByUserID", "user-1").Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("FindByUsername", "asdos").Return(user, nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-dosen", Name: "Dosen Wali"},
+	}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", "role-dosen").Return([]string{"achievement:read", "achievement:verify"}, nil)
+	mockRefreshRepo.On("Create", mock.AnythingOfType("*model.RefreshToken")).Return(nil)
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username": "asdos", "password": "password123", "role": "Dosen Wali"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.LoginResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"Dosen Wali"}, result.Data.User.Roles)
+
+	// Refresh token menyimpan pilihan role supaya sesi berikutnya tetap sama
+	refreshClaims, err := utils.ValidateRefreshToken(result.Data.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "Dosen Wali", refreshClaims.Role)
+	mockPermRepo.AssertNotCalled(t, "GetPermissionsByRoleID", "role-mhs")
+}
+
+func TestLogin_SelectedRoleNotAssigned(t *testing.T) {
+	service, mockUserRepo, _, _, mockRefreshRepo, _, mockLockoutRepo, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	user := &model.User{ID: "user-1", Username: "mhs", PasswordHash: hashedPassword, IsActive: true}
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("FindByUsername", "mhs").Return(user, nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username": "mhs", "password": "password123", "role": "Admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 403, resp.StatusCode)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// ==================== LOGIN + MFA ====================
+
+func TestLogin_MFARequiredReturnsChallenge(t *testing.T) {
+	service, mockUserRepo, _, _, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/login", service.Login)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	user := &model.User{ID: "admin-1", Username: "admin", PasswordHash: hashedPassword, IsActive: true}
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("FindByUsername", "admin").Return(user, nil)
+	mockUserRepo.On("GetRoles", "admin-1").Return([]model.Role{model.Role{ID: "role-admin", Name: "Admin", MFARequired: true}}, nil)
+	mockMFARepo.On("FindByUserID", "admin-1").Return(nil, sql.ErrNoRows)
+
+	body := `{"username": "admin", "password": "password123"}`
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.MFAChallengeResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.True(t, result.Data.MFARequired)
+	assert.True(t, result.Data.EnrollmentRequired)
+
+	// Token mfa_pending tidak bisa dipakai sebagai access token
+	claims, err := utils.ValidateMFAToken(result.Data.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin-1", claims.UserID)
+	_, err = utils.ValidateToken(result.Data.MFAToken)
+	assert.Error(t, err)
+
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockLockoutRepo.AssertNotCalled(t, "ResetFailures", mock.Anything, mock.Anything)
+}
+
+func TestVerifyMFA_Success(t *testing.T) {
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/mfa/verify", service.VerifyMFA)
+
+	secret, _ := utils.GenerateTOTPSecret()
+	step := utils.TOTPStep(time.Now())
+	code, _ := utils.TOTPCode(secret, step)
+	mfaToken, _ := utils.GenerateMFAToken("admin-1", "admin", "", false)
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("ResetFailures", "username", "admin").Return(nil)
+	mockUserRepo.On("FindByID", "admin-1").Return(&model.User{ID: "admin-1", Username: "admin", IsActive: true}, nil)
+	mockMFARepo.On("FindByUserID", "admin-1").Return(&model.UserMFA{UserID: "admin-1", Secret: secret, Enabled: true}, nil)
+	mockMFARepo.On("MarkStepUsed", "admin-1", mock.AnythingOfType("int64")).Return(true, nil)
+	mockUserRepo.On("GetRoles", "admin-1").Return([]model.Role{model.Role{ID: "role-admin", Name: "Admin", MFARequired: true}}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"user:manage"}, nil)
+	mockRefreshRepo.On("Create", mock.AnythingOfType("*model.RefreshToken")).Return(nil)
+
+	body := `{"mfa_token": "` + mfaToken + `", "code": "` + code + `"}`
+	req := httptest.NewRequest("POST", "/mfa/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockMFARepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertExpectations(t)
+}
+
+func TestVerifyMFA_EnrollmentReturnsRecoveryCodes(t *testing.T) {
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/mfa/verify", service.VerifyMFA)
+
+	secret, _ := utils.GenerateTOTPSecret()
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	mfaToken, _ := utils.GenerateMFAToken("admin-1", "admin", "", true)
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("ResetFailures", "username", "admin").Return(nil)
+	mockUserRepo.On("FindByID", "admin-1").Return(&model.User{ID: "admin-1", Username: "admin", IsActive: true}, nil)
+	mockMFARepo.On("FindByUserID", "admin-1").Return(&model.UserMFA{UserID: "admin-1", Secret: secret, Enabled: false}, nil)
+	mockMFARepo.On("Enable", "admin-1", mock.AnythingOfType("int64")).Return(nil)
+	mockMFARepo.On("ReplaceRecoveryCodes", "admin-1", mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+	mockUserRepo.On("GetRoles", "admin-1").Return([]model.Role{model.Role{ID: "role-admin", Name: "Admin", MFARequired: true}}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"user:manage"}, nil)
+	mockRefreshRepo.On("Create", mock.AnythingOfType("*model.RefreshToken")).Return(nil)
+
+	body := `{"mfa_token": "` + mfaToken + `", "code": "` + code + `"}`
+	req := httptest.NewRequest("POST", "/mfa/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.LoginResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.NotEmpty(t, result.Data.Token)
+	assert.Len(t, result.Data.RecoveryCodes, 10)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestVerifyMFA_InvalidCodeCountsFailure(t *testing.T) {
+	service, mockUserRepo, _, _, mockRefreshRepo, _, mockLockoutRepo, mockMFARepo := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/mfa/verify", service.VerifyMFA)
+
+	secret, _ := utils.GenerateTOTPSecret()
+	wrongCode, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now())+10)
+	mfaToken, _ := utils.GenerateMFAToken("admin-1", "admin", "", false)
+
+	mockLockoutRepo.On("Get", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, 0, nil).Twice()
+	mockLockoutRepo.On("RecordFailedIP", mock.Anything, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("FindByID", "admin-1").Return(&model.User{ID: "admin-1", Username: "admin", IsActive: true}, nil)
+	mockMFARepo.On("FindByUserID", "admin-1").Return(&model.UserMFA{UserID: "admin-1", Secret: secret, Enabled: true}, nil)
+	mockMFARepo.On("UseRecoveryCode", "admin-1", mock.AnythingOfType("string")).Return(false, nil)
+
+	body := `{"mfa_token": "` + mfaToken + `", "code": "` + wrongCode + `"}`
+	req := httptest.NewRequest("POST", "/mfa/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockLockoutRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestVerifyMFA_InvalidToken(t *testing.T) {
+	service, _, _, _, _, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/mfa/verify", service.VerifyMFA)
+
+	// Access token biasa tidak bisa dipakai sebagai token mfa_pending
+	accessToken, _ := utils.GenerateJWT(model.UserResponse{ID: "admin-1", Username: "admin"}, "session-1", time.Now())
+
+	body := `{"mfa_token": "` + accessToken + `", "code": "123456"}`
+	req := httptest.NewRequest("POST", "/mfa/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+}
+
 // ==================== REFRESH TOKEN ====================
 
 func TestRefreshToken_Success(t *testing.T) {
-	t.Skip("Skipped: Refresh token validation requires JWT secret from config/environment. This is an integration test that should be run with proper environment setup.")
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/refresh", service.Refresh)
+
+	userID := "user-123"
+	roleID := "role-123"
+	tokenID := "token-1"
+	familyID := "family-1"
+	expiresAt := time.Now().Add(time.Hour)
+
+	refreshToken, _ := utils.GenerateRefreshToken(userID, tokenID, familyID, "", expiresAt)
+
+	mockRefreshRepo.On("FindByID", tokenID).Return(&model.RefreshToken{
+		ID:        tokenID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}, nil)
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, IsActive: true}, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{model.Role{ID: roleID, Name: "Mahasiswa"}}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", roleID).Return([]string{"achievement:read"}, nil)
+	mockRefreshRepo.On("MarkUsed", tokenID, mock.AnythingOfType("string")).Return(true, nil)
+	mockRefreshRepo.On("Create", mock.MatchedBy(func(token *model.RefreshToken) bool {
+		return token.FamilyID == familyID && token.ID != tokenID
+	})).Return(nil)
+
+	body := `{"refreshToken": "` + refreshToken + `"}`
+	req := httptest.NewRequest("POST", "/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	service, _, _, _, mockRefreshRepo, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/refresh", service.Refresh)
+
+	userID := "user-123"
+	tokenID := "token-1"
+	familyID := "family-1"
+	expiresAt := time.Now().Add(time.Hour)
+	usedAt := time.Now().Add(-time.Minute)
+
+	refreshToken, _ := utils.GenerateRefreshToken(userID, tokenID, familyID, "", expiresAt)
+
+	mockRefreshRepo.On("FindByID", tokenID).Return(&model.RefreshToken{
+		ID:        tokenID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+		UsedAt:    &usedAt,
+	}, nil)
+	mockRefreshRepo.On("RevokeFamily", familyID).Return(nil)
+
+	body := `{"refreshToken": "` + refreshToken + `"}`
+	req := httptest.NewRequest("POST", "/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestRefreshToken_ConcurrentUseRevokesFamily(t *testing.T) {
+	service, mockUserRepo, _, mockPermRepo, mockRefreshRepo, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/refresh", service.Refresh)
+
+	userID := "user-123"
+	roleID := "role-123"
+	tokenID := "token-1"
+	familyID := "family-1"
+	expiresAt := time.Now().Add(time.Hour)
+
+	refreshToken, _ := utils.GenerateRefreshToken(userID, tokenID, familyID, "", expiresAt)
+
+	mockRefreshRepo.On("FindByID", tokenID).Return(&model.RefreshToken{
+		ID:        tokenID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}, nil)
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, IsActive: true}, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{model.Role{ID: roleID, Name: "Mahasiswa"}}, nil)
+	mockPermRepo.On("GetPermissionsByRoleID", roleID).Return([]string{"achievement:read"}, nil)
+	mockRefreshRepo.On("MarkUsed", tokenID, mock.AnythingOfType("string")).Return(false, nil)
+	mockRefreshRepo.On("RevokeFamily", familyID).Return(nil)
+
+	body := `{"refreshToken": "` + refreshToken + `"}`
+	req := httptest.NewRequest("POST", "/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestRefreshToken_Revoked(t *testing.T) {
+	service, _, _, _, mockRefreshRepo, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/refresh", service.Refresh)
+
+	tokenID := "token-1"
+	expiresAt := time.Now().Add(time.Hour)
+	revokedAt := time.Now()
+
+	refreshToken, _ := utils.GenerateRefreshToken("user-123", tokenID, "family-1", "", expiresAt)
+
+	mockRefreshRepo.On("FindByID", tokenID).Return(&model.RefreshToken{
+		ID:        tokenID,
+		UserID:    "user-123",
+		FamilyID:  "family-1",
+		ExpiresAt: expiresAt,
+		RevokedAt: &revokedAt,
+	}, nil)
+
+	body := `{"refreshToken": "` + refreshToken + `"}`
+	req := httptest.NewRequest("POST", "/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 func TestRefreshToken_InvalidToken(t *testing.T) {
-	service, _, _, _ := setupAuthTest()
+	service, _, _, _, _, _, _, _ := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/refresh", service.Refresh)
@@ -158,14 +637,30 @@ func TestRefreshToken_InvalidToken(t *testing.T) {
 	assert.Equal(t, 401, resp.StatusCode)
 }
 
-func TestRefreshToken_UserNotFound(t *testing.T) {
-	t.Skip("Skipped: Refresh token validation requires JWT secret from config/environment. This is an integration test that should be run with proper environment setup.")
+func TestRefreshToken_UnknownToken(t *testing.T) {
+	service, _, _, _, mockRefreshRepo, _, _, _ := setupAuthTest()
+
+	app := fiber.New()
+	app.Post("/refresh", service.Refresh)
+
+	refreshToken, _ := utils.GenerateRefreshToken("user-123", "token-x", "family-1", "", time.Now().Add(time.Hour))
+
+	mockRefreshRepo.On("FindByID", "token-x").Return(nil, errors.New("not found"))
+
+	body := `{"refreshToken": "` + refreshToken + `"}`
+	req := httptest.NewRequest("POST", "/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 // ==================== PROFILE ====================
 
 func TestProfile_Success(t *testing.T) {
-	service, mockUserRepo, _, _ := setupAuthTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupAuthTest()
 
 	app := fiber.New()
 	
@@ -175,7 +670,7 @@ func TestProfile_Success(t *testing.T) {
 		c.Locals("user", &model.JWTClaims{
 			UserID:   userID,
 			Username: "mahasiswa123",
-			Role:     "Mahasiswa",
+			Roles:    []string{"Mahasiswa"},
 		})
 		return service.Profile(c)
 	})
@@ -201,27 +696,39 @@ func TestProfile_Success(t *testing.T) {
 // ==================== LOGOUT ====================
 
 func TestLogout_Success(t *testing.T) {
-	service, _, _, _ := setupAuthTest()
+	service, _, _, _, mockRefreshRepo, mockRevocationRepo, _, _ := setupAuthTest()
 
 	app := fiber.New()
 	
+	claims := &model.JWTClaims{
+		UserID:    "user-123",
+		Username:  "mahasiswa123",
+		Roles:     []string{"Mahasiswa"},
+		SessionID: "family-1",
+	}
+	claims.ID = "access-jti-1"
+
 	app.Post("/logout", func(c *fiber.Ctx) error {
-		c.Locals("user", &model.JWTClaims{
-			UserID:   "user-123",
-			Username: "mahasiswa123",
-			Role:     "Mahasiswa",
-		})
+		c.Locals("user", claims)
 		return service.Logout(c)
 	})
 
+	mockRefreshRepo.On("RevokeFamily", "family-1").Return(nil)
+	mockRevocationRepo.On("Create", mock.MatchedBy(func(rev *model.TokenRevocation) bool {
+		return rev.Kind == "token" && rev.JTI != nil && *rev.JTI == "access-jti-1"
+	})).Return(nil)
+
 	req := httptest.NewRequest("POST", "/logout", nil)
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 200, resp.StatusCode)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
+	assert.True(t, service.revocations.IsRevoked(claims))
 }
 
 func TestLogout_Unauthorized(t *testing.T) {
-	service, _, _, _ := setupAuthTest()
+	service, _, _, _, _, _, _, _ := setupAuthTest()
 
 	app := fiber.New()
 	app.Post("/logout", service.Logout)
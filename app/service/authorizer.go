@@ -0,0 +1,450 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== AUTHORIZATION POLICY ======================
+// RequirePermission (middleware) hanya menjawab "boleh melakukan action ini?".
+// Authorizer menjawab "terhadap data milik siapa?" berdasarkan tabel policy
+// role -> action -> scope. Menambah role baru (mis. Kaprodi) cukup dengan
+// menambah entry di policy, tanpa mengubah handler.
+//
+// Role yang di-assign terbatas program studi (user_roles.program_study, mis.
+// admin fakultas / prodi) tidak mendapat scope "all": scope-nya dipersempit
+// menjadi program_study untuk program studi assignment tsb.
+//
+// Scope "delegated" berlaku untuk dosen yang sedang menerima delegasi
+// verifikasi aktif dari dosen wali mahasiswa (verification_delegations).
+//
+// Verifikasi dievaluasi per stage pipeline (ActingForStage): hanya role
+// pemegang stage aktif yang dipakai, bukan gabungan semua role user.
+//
+
+// Scope - Jangkauan data yang boleh diakses sebuah role untuk satu action
+type Scope string
+
+const (
+	ScopeOwn          Scope = "own"           // mahasiswa pemilik data / dosen pemilik profil
+	ScopeAdvisee      Scope = "advisee"       // dosen wali dari mahasiswa pemilik data
+	ScopeDelegated    Scope = "delegated"     // dosen penerima delegasi aktif dari dosen wali mahasiswa
+	ScopeProgramStudy Scope = "program_study" // program studi mahasiswa sama dengan program studi / departemen user
+	ScopeAll          Scope = "all"
+)
+
+// Urutan dari yang paling luas, dipakai untuk AccessFilter.Scope
+var scopePrecedence = []Scope{ScopeAll, ScopeProgramStudy, ScopeAdvisee, ScopeDelegated, ScopeOwn}
+
+// Action yang dievaluasi Authorizer
+const (
+	ActionAchievementRead   = "achievement:read"   // detail, list, history, report prestasi
+	ActionAchievementUpdate = "achievement:update" // update, submit, upload attachment
+	ActionAchievementDelete = "achievement:delete"
+	ActionAchievementVerify = "achievement:verify" // verify & reject
+	ActionAdviseeRead       = "advisee:read"       // daftar mahasiswa bimbingan dosen
+	ActionUserManage        = "user:manage"        // kelola user, profil mahasiswa / dosen, dosen wali
+	ActionDelegationManage  = "delegation:manage"  // kelola delegasi verifikasi milik dosen wali
+)
+
+// Policy - role -> action -> scope yang diizinkan. User dengan beberapa role
+// mendapat gabungan scope semua role di sesinya.
+type Policy map[string]map[string][]Scope
+
+var DefaultPolicy = Policy{
+	"Mahasiswa": {
+		ActionAchievementRead:   {ScopeOwn},
+		ActionAchievementUpdate: {ScopeOwn},
+		ActionAchievementDelete: {ScopeOwn},
+	},
+	"Dosen Wali": {
+		ActionAchievementRead:   {ScopeAdvisee, ScopeDelegated},
+		ActionAchievementVerify: {ScopeAdvisee, ScopeDelegated},
+		ActionAdviseeRead:       {ScopeOwn},
+		ActionDelegationManage:  {ScopeOwn},
+	},
+	"Admin": {
+		ActionAchievementRead:   {ScopeAll},
+		ActionAchievementVerify: {ScopeAll}, // hanya di stage pipeline yang dipegang Admin
+		ActionAdviseeRead:       {ScopeAll},
+		ActionUserManage:        {ScopeAll},
+		ActionDelegationManage:  {ScopeAll},
+	},
+}
+
+var (
+	errForbidden               = errors.New("forbidden")
+	errStudentProfileNotFound  = errors.New("student profile not found")
+	errLecturerProfileNotFound = errors.New("lecturer profile not found")
+)
+
+// Target - Data yang akan diakses. Student / Lecturer boleh diisi jika sudah
+// di-load handler supaya tidak query ulang.
+type Target struct {
+	StudentID  string
+	Student    *model.Student
+	LecturerID string
+	Lecturer   *model.Lecturer
+	UserID     string // akun user (manajemen user)
+}
+
+// AccessFilter - Hasil resolve scope untuk query list. ScopeAll = tanpa batas;
+// selain itu data cocok dengan salah satu field di bawah (gabungan scope semua
+// role, sama seperti Can). Scope = scope terluas yang didapat.
+type AccessFilter struct {
+	Scope          Scope
+	StudentIDs     []string // ScopeOwn
+	AdvisorIDs     []string // ScopeAdvisee / ScopeDelegated (dosen wali sendiri + yang mendelegasikan)
+	ProgramStudies []string // ScopeProgramStudy
+}
+
+// referenceScope - Kondisi query repository untuk filter non-ScopeAll
+func (f *AccessFilter) referenceScope() repository.ReferenceScope {
+	return repository.ReferenceScope{
+		StudentIDs:     f.StudentIDs,
+		AdvisorIDs:     f.AdvisorIDs,
+		ProgramStudies: f.ProgramStudies,
+	}
+}
+
+// grant - Satu scope hasil policy. programStudies terisi jika role di-assign
+// terbatas program studi; kosong = program studi user sendiri.
+type grant struct {
+	scope          Scope
+	programStudies []string
+}
+
+type Authorizer struct {
+	studentRepo    repository.StudentRepository
+	lecturerRepo   repository.LecturerRepository
+	delegationRepo repository.DelegationRepository
+	policy         Policy
+	now            func() time.Time
+}
+
+func NewAuthorizer(
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+	delegationRepo repository.DelegationRepository,
+	policy Policy,
+) *Authorizer {
+	return &Authorizer{
+		studentRepo:    studentRepo,
+		lecturerRepo:   lecturerRepo,
+		delegationRepo: delegationRepo,
+		policy:         policy,
+		now:            time.Now,
+	}
+}
+
+//
+// ==================== CAN (SINGLE RESOURCE) ======================
+//
+
+// Can - true jika salah satu scope role untuk action ini mencakup target
+func (a *Authorizer) Can(claims *model.JWTClaims, action string, target Target) bool {
+	allowed, _ := a.ActingFor(claims, action, target)
+	return allowed
+}
+
+// ActingFor - Seperti Can, plus ID dosen wali yang diwakili jika target hanya
+// tercakup lewat delegasi ("" jika user berwenang atas namanya sendiri).
+func (a *Authorizer) ActingFor(claims *model.JWTClaims, action string, target Target) (bool, string) {
+	grants := a.grants(claims, action)
+	for _, g := range grants {
+		if g.scope != ScopeDelegated && a.covers(claims, g, &target) {
+			return true, ""
+		}
+	}
+	for _, g := range grants {
+		if g.scope == ScopeDelegated && a.covers(claims, g, &target) {
+			return true, *a.targetStudent(&target).AdvisorID
+		}
+	}
+	return false, ""
+}
+
+// ActingForStage - ActingFor(ActionAchievementVerify) untuk stage pipeline,
+// hanya memakai role pemegang stage. Stage yang terikat program studi hanya
+// boleh diputuskan assignment role yang dibatasi ke program studi tsb (mis.
+// admin kemahasiswaan fakultas) dan berlaku untuk semua mahasiswa di pipeline.
+func (a *Authorizer) ActingForStage(claims *model.JWTClaims, stage model.PipelineStage, target Target) (bool, string) {
+	if claims == nil || !contains(claims.Roles, stage.Role) {
+		return false, ""
+	}
+	if stage.ProgramStudy != nil {
+		return contains(claims.RoleScopes[stage.Role], *stage.ProgramStudy) && a.RoleCan(stage.Role, ActionAchievementVerify), ""
+	}
+
+	narrowed := *claims
+	narrowed.Roles = []string{stage.Role}
+	return a.ActingFor(&narrowed, ActionAchievementVerify, target)
+}
+
+// RoleCan - Policy memberi role ini scope untuk action (tanpa melihat data)
+func (a *Authorizer) RoleCan(role, action string) bool {
+	return len(a.policy[role][action]) > 0
+}
+
+func (a *Authorizer) covers(claims *model.JWTClaims, g grant, target *Target) bool {
+	if g.scope == ScopeAll {
+		return true
+	}
+
+	// Akun user: dalam scope jika profil mahasiswa / dosennya ada di program studi scope
+	if target.UserID != "" {
+		switch g.scope {
+		case ScopeOwn:
+			return target.UserID == claims.UserID
+		case ScopeProgramStudy:
+			return a.userInProgramStudies(target.UserID, a.allowedProgramStudies(claims, g))
+		}
+		return false
+	}
+
+	// Resource milik dosen (mis. daftar bimbingan)
+	if target.lecturerID() != "" {
+		switch g.scope {
+		case ScopeOwn:
+			lecturer, err := a.lecturerRepo.FindByUserID(claims.UserID)
+			return err == nil && lecturer != nil && lecturer.ID == target.lecturerID()
+		case ScopeProgramStudy:
+			lecturer := a.targetLecturer(target)
+			return lecturer != nil && contains(a.allowedProgramStudies(claims, g), lecturer.Department)
+		}
+		return false
+	}
+
+	// Resource milik mahasiswa
+	if g.scope == ScopeOwn {
+		current, err := a.studentRepo.FindByUserID(claims.UserID)
+		return err == nil && current != nil && current.ID == target.studentID()
+	}
+
+	student := a.targetStudent(target)
+	if student == nil {
+		return false
+	}
+
+	switch g.scope {
+	case ScopeAdvisee:
+		lecturer, err := a.lecturerRepo.FindByUserID(claims.UserID)
+		return err == nil && lecturer != nil && student.AdvisorID != nil && *student.AdvisorID == lecturer.ID
+	case ScopeDelegated:
+		lecturer, err := a.lecturerRepo.FindByUserID(claims.UserID)
+		if err != nil || lecturer == nil || student.AdvisorID == nil || *student.AdvisorID == lecturer.ID {
+			return false
+		}
+		delegation, err := a.delegationRepo.FindActive(*student.AdvisorID, lecturer.ID, a.now())
+		return err == nil && delegation != nil
+	case ScopeProgramStudy:
+		return contains(a.allowedProgramStudies(claims, g), student.ProgramStudy)
+	}
+	return false
+}
+
+func (t *Target) studentID() string {
+	if t.Student != nil {
+		return t.Student.ID
+	}
+	return t.StudentID
+}
+
+func (t *Target) lecturerID() string {
+	if t.Lecturer != nil {
+		return t.Lecturer.ID
+	}
+	return t.LecturerID
+}
+
+func (a *Authorizer) targetLecturer(target *Target) *model.Lecturer {
+	if target.Lecturer == nil && target.LecturerID != "" {
+		lecturer, err := a.lecturerRepo.FindByID(target.LecturerID)
+		if err != nil {
+			return nil
+		}
+		target.Lecturer = lecturer
+	}
+	return target.Lecturer
+}
+
+func (a *Authorizer) targetStudent(target *Target) *model.Student {
+	if target.Student == nil && target.StudentID != "" {
+		student, err := a.studentRepo.FindByID(target.StudentID)
+		if err != nil {
+			return nil
+		}
+		target.Student = student
+	}
+	return target.Student
+}
+
+//
+// ==================== FILTER (LIST) ======================
+//
+
+// Filter - Gabungan scope semua role untuk action ini, sudah di-resolve ke
+// profil user. Profil yang tidak ada hanya menjadi error jika tidak ada scope
+// lain yang bisa dipakai.
+func (a *Authorizer) Filter(claims *model.JWTClaims, action string) (*AccessFilter, error) {
+	granted := map[Scope]bool{}
+	filter := &AccessFilter{}
+	for _, g := range a.grants(claims, action) {
+		granted[g.scope] = true
+		if g.scope == ScopeProgramStudy {
+			for _, programStudy := range a.allowedProgramStudies(claims, g) {
+				if !contains(filter.ProgramStudies, programStudy) {
+					filter.ProgramStudies = append(filter.ProgramStudies, programStudy)
+				}
+			}
+		}
+	}
+	if granted[ScopeAll] {
+		return &AccessFilter{Scope: ScopeAll}, nil
+	}
+
+	var profileErr error
+	if granted[ScopeAdvisee] || granted[ScopeDelegated] {
+		lecturer, err := a.lecturerRepo.FindByUserID(claims.UserID)
+		if err != nil || lecturer == nil {
+			profileErr = errLecturerProfileNotFound
+		} else {
+			if granted[ScopeAdvisee] {
+				filter.AdvisorIDs = append(filter.AdvisorIDs, lecturer.ID)
+			}
+			// Mahasiswa bimbingan dosen wali yang sedang mendelegasikan verifikasi
+			if granted[ScopeDelegated] {
+				if delegatorIDs, err := a.delegationRepo.GetActiveDelegatorIDs(lecturer.ID, a.now()); err == nil {
+					filter.AdvisorIDs = append(filter.AdvisorIDs, delegatorIDs...)
+				}
+			}
+		}
+	}
+	if granted[ScopeOwn] {
+		student, err := a.studentRepo.FindByUserID(claims.UserID)
+		if err != nil || student == nil {
+			profileErr = errStudentProfileNotFound
+		} else {
+			filter.StudentIDs = []string{student.ID}
+		}
+	}
+
+	resolved := map[Scope]bool{
+		ScopeProgramStudy: len(filter.ProgramStudies) > 0,
+		ScopeAdvisee:      len(filter.AdvisorIDs) > 0,
+		ScopeOwn:          len(filter.StudentIDs) > 0,
+	}
+	for _, scope := range scopePrecedence {
+		if resolved[scope] {
+			filter.Scope = scope
+			return filter, nil
+		}
+	}
+
+	if profileErr != nil {
+		return nil, profileErr
+	}
+	return nil, errForbidden
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// grants - Gabungan scope dari semua role di sesi
+func (a *Authorizer) grants(claims *model.JWTClaims, action string) []grant {
+	if claims == nil {
+		return nil
+	}
+
+	var grants []grant
+	for _, role := range claims.Roles {
+		restricted := claims.RoleScopes[role]
+		for _, scope := range a.policy[role][action] {
+			// Assignment terbatas: "all" / "program_study" jadi program studi assignment
+			if len(restricted) > 0 && (scope == ScopeAll || scope == ScopeProgramStudy) {
+				grants = append(grants, grant{scope: ScopeProgramStudy, programStudies: restricted})
+				continue
+			}
+			grants = append(grants, grant{scope: scope})
+		}
+	}
+	return grants
+}
+
+// allowedProgramStudies - Program studi untuk grant ScopeProgramStudy
+func (a *Authorizer) allowedProgramStudies(claims *model.JWTClaims, g grant) []string {
+	if len(g.programStudies) > 0 {
+		return g.programStudies
+	}
+	if programStudy := a.programStudyOf(claims); programStudy != "" {
+		return []string{programStudy}
+	}
+	return nil
+}
+
+// userInProgramStudies - Profil mahasiswa / dosen user ada di salah satu program studi
+func (a *Authorizer) userInProgramStudies(userID string, programStudies []string) bool {
+	if student, err := a.studentRepo.FindByUserID(userID); err == nil && student != nil && contains(programStudies, student.ProgramStudy) {
+		return true
+	}
+	if lecturer, err := a.lecturerRepo.FindByUserID(userID); err == nil && lecturer != nil && contains(programStudies, lecturer.Department) {
+		return true
+	}
+	return false
+}
+
+// programStudyOf - Program studi user: departemen untuk dosen, program studi untuk mahasiswa
+func (a *Authorizer) programStudyOf(claims *model.JWTClaims) string {
+	if lecturer, err := a.lecturerRepo.FindByUserID(claims.UserID); err == nil && lecturer != nil {
+		return lecturer.Department
+	}
+	if student, err := a.studentRepo.FindByUserID(claims.UserID); err == nil && student != nil {
+		return student.ProgramStudy
+	}
+	return ""
+}
+
+// authzError - Response untuk error dari Authorizer.Filter
+func authzError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errStudentProfileNotFound) || errors.Is(err, errLecturerProfileNotFound) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	return c.Status(403).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "forbidden",
+	})
+}
+
+// referencesInScope - Query achievement reference sesuai AccessFilter
+func referencesInScope(repo repository.AchievementRepository, filter *AccessFilter, status string, limit, offset int) ([]model.AchievementReference, error) {
+	if filter.Scope == ScopeAll {
+		return repo.GetAllReferences(status, limit, offset)
+	}
+	return repo.GetReferencesInScope(filter.referenceScope(), status, limit, offset)
+}
+
+func countReferencesInScope(repo repository.AchievementRepository, filter *AccessFilter, status string) (int, error) {
+	if filter.Scope == ScopeAll {
+		return repo.CountAllReferences(status)
+	}
+	return repo.CountReferencesInScope(filter.referenceScope(), status)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
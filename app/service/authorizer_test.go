@@ -0,0 +1,261 @@
+package service
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupAuthorizerTest(policy Policy) (*Authorizer, *mocks.MockStudentRepository, *mocks.MockLecturerRepository) {
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+
+	return NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), policy), mockStudentRepo, mockLecturerRepo
+}
+
+// noDelegations - Delegation repository tanpa delegasi aktif
+func noDelegations() *mocks.MockDelegationRepository {
+	repo := new(mocks.MockDelegationRepository)
+	repo.On("FindActive", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+	repo.On("GetActiveDelegatorIDs", mock.Anything, mock.Anything).Return([]string{}, nil).Maybe()
+	return repo
+}
+
+// Role baru cukup didaftarkan di policy
+var kaprodiPolicy = Policy{
+	"Kaprodi": {
+		ActionAchievementRead:   {ScopeProgramStudy},
+		ActionAchievementVerify: {ScopeAdvisee, ScopeProgramStudy},
+	},
+}
+
+// ==================== CAN ====================
+
+func TestAuthorizerCan_DefaultPolicy(t *testing.T) {
+	advisorID := "lecturer-1"
+	student := &model.Student{ID: "student-1", UserID: "user-mhs", ProgramStudy: "Informatika", AdvisorID: &advisorID}
+
+	tests := []struct {
+		name   string
+		role   string
+		userID string
+		action string
+		want   bool
+	}{
+		{"mahasiswa pemilik boleh baca", "Mahasiswa", "user-mhs", ActionAchievementRead, true},
+		{"mahasiswa lain tidak boleh baca", "Mahasiswa", "user-other", ActionAchievementRead, false},
+		{"mahasiswa tidak boleh verify", "Mahasiswa", "user-mhs", ActionAchievementVerify, false},
+		{"dosen wali boleh verify bimbingan", "Dosen Wali", "user-dosen", ActionAchievementVerify, true},
+		{"dosen lain tidak boleh verify", "Dosen Wali", "user-dosen2", ActionAchievementVerify, false},
+		{"dosen wali tidak boleh update", "Dosen Wali", "user-dosen", ActionAchievementUpdate, false},
+		{"admin boleh baca semua", "Admin", "user-admin", ActionAchievementRead, true},
+		{"admin boleh verify (stage pipeline)", "Admin", "user-admin", ActionAchievementVerify, true},
+		{"role tanpa policy ditolak", "Tamu", "user-mhs", ActionAchievementRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(DefaultPolicy)
+
+			mockStudentRepo.On("FindByID", "student-1").Return(student, nil)
+			mockStudentRepo.On("FindByUserID", "user-mhs").Return(student, nil)
+			mockStudentRepo.On("FindByUserID", "user-other").Return(&model.Student{ID: "student-2"}, nil)
+			mockLecturerRepo.On("FindByUserID", "user-dosen").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+			mockLecturerRepo.On("FindByUserID", "user-dosen2").Return(&model.Lecturer{ID: "lecturer-2"}, nil)
+
+			claims := &model.JWTClaims{UserID: tt.userID, Roles: []string{tt.role}}
+			assert.Equal(t, tt.want, authz.Can(claims, tt.action, Target{StudentID: "student-1"}))
+		})
+	}
+}
+
+func TestAuthorizerCan_ProgramStudyScope(t *testing.T) {
+	authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(kaprodiPolicy)
+
+	mockLecturerRepo.On("FindByUserID", "user-kaprodi").Return(&model.Lecturer{ID: "lecturer-9", Department: "Informatika"}, nil)
+	mockStudentRepo.On("FindByID", "student-1").Return(&model.Student{ID: "student-1", ProgramStudy: "Informatika"}, nil)
+	mockStudentRepo.On("FindByID", "student-2").Return(&model.Student{ID: "student-2", ProgramStudy: "Sistem Informasi"}, nil)
+
+	claims := &model.JWTClaims{UserID: "user-kaprodi", Roles: []string{"Kaprodi"}}
+	assert.True(t, authz.Can(claims, ActionAchievementRead, Target{StudentID: "student-1"}))
+	assert.False(t, authz.Can(claims, ActionAchievementRead, Target{StudentID: "student-2"}))
+
+	// Bukan dosen wali, tapi program studi sama
+	assert.True(t, authz.Can(claims, ActionAchievementVerify, Target{StudentID: "student-1"}))
+}
+
+func TestAuthorizerCan_LecturerTarget(t *testing.T) {
+	authz, _, mockLecturerRepo := setupAuthorizerTest(DefaultPolicy)
+
+	mockLecturerRepo.On("FindByUserID", "user-dosen").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+
+	dosen := &model.JWTClaims{UserID: "user-dosen", Roles: []string{"Dosen Wali"}}
+	assert.True(t, authz.Can(dosen, ActionAdviseeRead, Target{LecturerID: "lecturer-1"}))
+	assert.False(t, authz.Can(dosen, ActionAdviseeRead, Target{LecturerID: "lecturer-2"}))
+
+	mahasiswa := &model.JWTClaims{UserID: "user-mhs", Roles: []string{"Mahasiswa"}}
+	assert.False(t, authz.Can(mahasiswa, ActionAdviseeRead, Target{LecturerID: "lecturer-1"}))
+}
+
+func TestAuthorizerCan_ScopedAdmin(t *testing.T) {
+	authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(DefaultPolicy)
+
+	mockStudentRepo.On("FindByID", "student-1").Return(&model.Student{ID: "student-1", ProgramStudy: "Informatika"}, nil)
+	mockStudentRepo.On("FindByID", "student-2").Return(&model.Student{ID: "student-2", ProgramStudy: "Sistem Informasi"}, nil)
+	mockStudentRepo.On("FindByUserID", "user-mhs1").Return(&model.Student{ID: "user-mhs1", ProgramStudy: "Informatika"}, nil)
+	mockStudentRepo.On("FindByUserID", "user-mhs2").Return(&model.Student{ID: "user-mhs2", ProgramStudy: "Sistem Informasi"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-mhs2").Return(nil, assert.AnError)
+
+	// Admin prodi Informatika: scope "all" dipersempit ke program studi assignment
+	claims := &model.JWTClaims{
+		UserID:     "user-admin-if",
+		Roles:      []string{"Admin"},
+		RoleScopes: map[string][]string{"Admin": {"Informatika"}},
+	}
+	assert.True(t, authz.Can(claims, ActionAchievementRead, Target{StudentID: "student-1"}))
+	assert.False(t, authz.Can(claims, ActionAchievementRead, Target{StudentID: "student-2"}))
+	assert.True(t, authz.Can(claims, ActionUserManage, Target{UserID: "user-mhs1"}))
+	assert.False(t, authz.Can(claims, ActionUserManage, Target{UserID: "user-mhs2"}))
+	assert.True(t, authz.Can(claims, ActionAdviseeRead, Target{Lecturer: &model.Lecturer{ID: "lecturer-1", Department: "Informatika"}}))
+	assert.False(t, authz.Can(claims, ActionAdviseeRead, Target{Lecturer: &model.Lecturer{ID: "lecturer-2", Department: "Sistem Informasi"}}))
+
+	filter, err := authz.Filter(claims, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeProgramStudy, ProgramStudies: []string{"Informatika"}}, filter)
+}
+
+// ==================== DELEGATION ====================
+
+func TestAuthorizerActingFor_Delegation(t *testing.T) {
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockDelegationRepo := new(mocks.MockDelegationRepository)
+	authz := NewAuthorizer(mockStudentRepo, mockLecturerRepo, mockDelegationRepo, DefaultPolicy)
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	authz.now = func() time.Time { return now }
+
+	advisorID := "lecturer-1"
+	mockStudentRepo.On("FindByID", "student-1").Return(&model.Student{ID: "student-1", AdvisorID: &advisorID}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-dosen").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-dosen2").Return(&model.Lecturer{ID: "lecturer-2"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-dosen3").Return(&model.Lecturer{ID: "lecturer-3"}, nil)
+	mockDelegationRepo.On("FindActive", "lecturer-1", "lecturer-2", now).Return(&model.VerificationDelegation{ID: "delegation-1"}, nil)
+	mockDelegationRepo.On("FindActive", "lecturer-1", "lecturer-3", now).Return(nil, sql.ErrNoRows)
+	mockDelegationRepo.On("GetActiveDelegatorIDs", "lecturer-2", now).Return([]string{"lecturer-1"}, nil)
+
+	target := Target{StudentID: "student-1"}
+
+	// Dosen wali sendiri: bukan atas nama siapa pun
+	allowed, onBehalfOf := authz.ActingFor(&model.JWTClaims{UserID: "user-dosen", Roles: []string{"Dosen Wali"}}, ActionAchievementVerify, target)
+	assert.True(t, allowed)
+	assert.Empty(t, onBehalfOf)
+
+	// Penerima delegasi aktif: atas nama dosen wali
+	allowed, onBehalfOf = authz.ActingFor(&model.JWTClaims{UserID: "user-dosen2", Roles: []string{"Dosen Wali"}}, ActionAchievementVerify, target)
+	assert.True(t, allowed)
+	assert.Equal(t, "lecturer-1", onBehalfOf)
+
+	// Tanpa delegasi (atau sudah expired)
+	allowed, _ = authz.ActingFor(&model.JWTClaims{UserID: "user-dosen3", Roles: []string{"Dosen Wali"}}, ActionAchievementVerify, target)
+	assert.False(t, allowed)
+
+	// List penerima delegasi mencakup mahasiswa bimbingan dosen yang mendelegasikan
+	filter, err := authz.Filter(&model.JWTClaims{UserID: "user-dosen2", Roles: []string{"Dosen Wali"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeAdvisee, AdvisorIDs: []string{"lecturer-2", "lecturer-1"}}, filter)
+}
+
+// ==================== FILTER ====================
+
+func TestAuthorizerFilter(t *testing.T) {
+	authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(DefaultPolicy)
+
+	mockStudentRepo.On("FindByUserID", "user-mhs").Return(&model.Student{ID: "student-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-dosen").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-nodosen").Return(nil, assert.AnError)
+
+	filter, err := authz.Filter(&model.JWTClaims{UserID: "user-mhs", Roles: []string{"Mahasiswa"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeOwn, StudentIDs: []string{"student-1"}}, filter)
+
+	filter, err = authz.Filter(&model.JWTClaims{UserID: "user-dosen", Roles: []string{"Dosen Wali"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeAdvisee, AdvisorIDs: []string{"lecturer-1"}}, filter)
+
+	filter, err = authz.Filter(&model.JWTClaims{UserID: "user-admin", Roles: []string{"Admin"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeAll, filter.Scope)
+
+	_, err = authz.Filter(&model.JWTClaims{UserID: "user-nodosen", Roles: []string{"Dosen Wali"}}, ActionAchievementRead)
+	assert.ErrorIs(t, err, errLecturerProfileNotFound)
+
+	_, err = authz.Filter(&model.JWTClaims{UserID: "user-x", Roles: []string{"Tamu"}}, ActionAchievementRead)
+	assert.ErrorIs(t, err, errForbidden)
+}
+
+// User dengan beberapa role: list memakai gabungan scope, sama seperti Can
+func TestAuthorizerFilter_MultipleRoles(t *testing.T) {
+	authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(DefaultPolicy)
+
+	advisorID := "lecturer-1"
+	mockStudentRepo.On("FindByUserID", "user-asisten").Return(&model.Student{ID: "student-9"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-asisten").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-admin-if").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+	mockStudentRepo.On("FindByID", "student-si").Return(&model.Student{ID: "student-si", ProgramStudy: "Sistem Informasi", AdvisorID: &advisorID}, nil)
+
+	// Dosen wali yang juga mahasiswa: bimbingan + prestasi sendiri
+	filter, err := authz.Filter(&model.JWTClaims{UserID: "user-asisten", Roles: []string{"Mahasiswa", "Dosen Wali"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeAdvisee, StudentIDs: []string{"student-9"}, AdvisorIDs: []string{"lecturer-1"}}, filter)
+
+	// Admin prodi Informatika yang juga dosen wali mahasiswa Sistem Informasi
+	claims := &model.JWTClaims{
+		UserID:     "user-admin-if",
+		Roles:      []string{"Admin", "Dosen Wali"},
+		RoleScopes: map[string][]string{"Admin": {"Informatika"}},
+	}
+	filter, err = authz.Filter(claims, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessFilter{Scope: ScopeProgramStudy, AdvisorIDs: []string{"lecturer-1"}, ProgramStudies: []string{"Informatika"}}, filter)
+	assert.True(t, authz.Can(claims, ActionAchievementRead, Target{StudentID: "student-si"}))
+
+	// Profil mahasiswa tidak ada, tapi scope dosen wali tetap berlaku
+	mockStudentRepo.On("FindByUserID", "user-dosen").Return(nil, assert.AnError)
+	mockLecturerRepo.On("FindByUserID", "user-dosen").Return(&model.Lecturer{ID: "lecturer-2"}, nil)
+	filter, err = authz.Filter(&model.JWTClaims{UserID: "user-dosen", Roles: []string{"Mahasiswa", "Dosen Wali"}}, ActionAchievementRead)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lecturer-2"}, filter.AdvisorIDs)
+	assert.Empty(t, filter.StudentIDs)
+}
+
+func TestGetAchievements_ProgramStudyScope(t *testing.T) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	authz, mockStudentRepo, mockLecturerRepo := setupAuthorizerTest(kaprodiPolicy)
+	service := NewAchievementService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, new(mocks.MockUserRepository), noPipelines(), achievementTypes(), noPointRules(), noDuplicates(), noSemesters(), noTags(), authz, TagPolicy{})
+
+	app := fiber.New()
+	app.Get("/achievements", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-kaprodi", Roles: []string{"Kaprodi"}})
+		return service.GetAchievements(c)
+	})
+
+	mockLecturerRepo.On("FindByUserID", "user-kaprodi").Return(&model.Lecturer{ID: "lecturer-9", Department: "Informatika"}, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{ProgramStudies: []string{"Informatika"}}, "", 10, 0).Return([]model.AchievementReference{}, nil)
+	mockAchievementRepo.On("CountReferencesInScope", repository.ReferenceScope{ProgramStudies: []string{"Informatika"}}, "").Return(0, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertExpectations(t)
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== ACHIEVEMENT COMMENTS ======================
+// Thread diskusi per achievement antara mahasiswa pemilik dan dosen wali /
+// verifikator. Siapa pun yang boleh membaca achievement (policy
+// achievement:read, sama dengan GET /achievements/:id) boleh membaca dan
+// menulis komentar. Komentar terikat ke reference, bukan ke status, jadi
+// tetap terlihat setelah achievement dibuka kembali dan di-submit ulang.
+//
+
+// CommentPolicy - Batas waktu penulis boleh mengubah / menghapus komentarnya
+type CommentPolicy struct {
+	EditWindow   time.Duration // sejak komentar dibuat
+	DeleteWindow time.Duration // sejak komentar dibuat
+}
+
+var DefaultCommentPolicy = CommentPolicy{
+	EditWindow:   15 * time.Minute,
+	DeleteWindow: time.Hour,
+}
+
+// commentAnchorFields - Field achievement yang bisa dijadikan anchor
+// (selain "details.<key>")
+var commentAnchorFields = []string{"title", "description", "achievement_type", "tags", "points"}
+
+type CommentService struct {
+	commentRepo     repository.CommentRepository
+	achievementRepo repository.AchievementRepository
+	userRepo        repository.UserRepository
+	authz           *Authorizer
+	policy          CommentPolicy
+	validate        *validator.Validate
+	now             func() time.Time
+}
+
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	achievementRepo repository.AchievementRepository,
+	userRepo repository.UserRepository,
+	authz *Authorizer,
+	policy CommentPolicy,
+) *CommentService {
+	return &CommentService{
+		commentRepo:     commentRepo,
+		achievementRepo: achievementRepo,
+		userRepo:        userRepo,
+		authz:           authz,
+		policy:          policy,
+		validate:        validator.New(),
+		now:             time.Now,
+	}
+}
+
+//
+// ==================== GET COMMENTS (GET /achievements/:id/comments) ======================
+// Return thread bertingkat (replies). Komentar yang dihapus tetap muncul
+// tanpa isi supaya balasannya tidak kehilangan konteks.
+//
+
+func (s *CommentService) GetComments(c *fiber.Ctx) error {
+	_, reference, err := s.authorizeAchievement(c)
+	if reference == nil {
+		return err
+	}
+
+	comments, err := s.commentRepo.GetByReferenceID(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch comments",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_id": reference.ID,
+			"comments":       s.buildCommentThread(comments),
+			"total":          len(comments),
+		},
+	})
+}
+
+//
+// ==================== CREATE COMMENT (POST /achievements/:id/comments) ======================
+//
+
+func (s *CommentService) CreateComment(c *fiber.Ctx) error {
+	claims, reference, err := s.authorizeAchievement(c)
+	if reference == nil {
+		return err
+	}
+
+	// Parse request
+	req := new(model.CommentCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi input
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if reference.Status == model.AchievementStatusDeleted {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot comment on a deleted achievement",
+		})
+	}
+
+	// Balasan harus ke komentar di achievement yang sama
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(*req.ParentID)
+		if err != nil || parent.AchievementReferenceID != reference.ID {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "parent comment not found",
+			})
+		}
+		if parent.DeletedAt != nil {
+			return c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "cannot reply to a deleted comment",
+			})
+		}
+	}
+
+	if req.AnchorType != nil {
+		if msg := s.validateAnchor(reference, *req.AnchorType, *req.AnchorRef); msg != "" {
+			return c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  msg,
+			})
+		}
+	}
+
+	authorID := claims.UserID
+	comment := &model.AchievementComment{
+		AchievementReferenceID: reference.ID,
+		ParentID:               req.ParentID,
+		AuthorID:               &authorID,
+		AuthorRole:             strings.Join(claims.Roles, ", "),
+		Body:                   req.Body,
+		AnchorType:             req.AnchorType,
+		AnchorRef:              req.AnchorRef,
+		AchievementStatus:      reference.Status,
+	}
+	if err := s.commentRepo.Create(comment); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create comment",
+		})
+	}
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "comment created successfully",
+		Data:    s.buildCommentResponse(comment, map[string]string{}),
+	})
+}
+
+//
+// ==================== UPDATE COMMENT (PUT /achievements/:id/comments/:commentId) ======================
+// Hanya penulis, dan hanya dalam EditWindow sejak komentar dibuat
+//
+
+func (s *CommentService) UpdateComment(c *fiber.Ctx) error {
+	_, comment, err := s.authorizeOwnComment(c)
+	if comment == nil {
+		return err
+	}
+
+	// Parse request
+	req := new(model.CommentUpdateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi input
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if s.now().Sub(comment.CreatedAt) > s.policy.EditWindow {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("comments can only be edited within %s of posting", s.policy.EditWindow),
+		})
+	}
+
+	if err := s.commentRepo.UpdateBody(comment.ID, req.Body); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update comment",
+		})
+	}
+
+	editedAt := s.now()
+	comment.Body = req.Body
+	comment.EditedAt = &editedAt
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "comment updated successfully",
+		Data:    s.buildCommentResponse(comment, map[string]string{}),
+	})
+}
+
+//
+// ==================== DELETE COMMENT (DELETE /achievements/:id/comments/:commentId) ======================
+// Soft delete oleh penulis dalam DeleteWindow; balasan tetap ada
+//
+
+func (s *CommentService) DeleteComment(c *fiber.Ctx) error {
+	_, comment, err := s.authorizeOwnComment(c)
+	if comment == nil {
+		return err
+	}
+
+	if s.now().Sub(comment.CreatedAt) > s.policy.DeleteWindow {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("comments can only be deleted within %s of posting", s.policy.DeleteWindow),
+		})
+	}
+
+	if err := s.commentRepo.SoftDelete(comment.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete comment",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "comment deleted successfully",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// authorizeAchievement - Load reference dari :id (achievement di trash = 404)
+// dan cek policy achievement:read (mahasiswa pemilik, dosen wali / delegasi,
+// admin). Return nil reference jika response error sudah dikirim.
+func (s *CommentService) authorizeAchievement(c *fiber.Ctx) (*model.JWTClaims, *model.AchievementReference, error) {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return nil, nil, c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
+		return nil, nil, c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return nil, nil, c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+	return claims, reference, nil
+}
+
+// authorizeOwnComment - Komentar :commentId milik achievement :id yang ditulis
+// user ini dan belum dihapus. Return nil comment jika response error sudah dikirim.
+func (s *CommentService) authorizeOwnComment(c *fiber.Ctx) (*model.JWTClaims, *model.AchievementComment, error) {
+	claims, reference, err := s.authorizeAchievement(c)
+	if reference == nil {
+		return nil, nil, err
+	}
+
+	comment, err := s.commentRepo.FindByID(c.Params("commentId"))
+	if err != nil || comment.AchievementReferenceID != reference.ID || comment.DeletedAt != nil {
+		return nil, nil, c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "comment not found",
+		})
+	}
+
+	if !comment.IsAuthor(claims.UserID) {
+		return nil, nil, c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "only the author can modify this comment",
+		})
+	}
+	return claims, comment, nil
+}
+
+// validateAnchor - Return pesan error jika anchor tidak menunjuk ke field /
+// attachment achievement yang ada, "" jika valid
+func (s *CommentService) validateAnchor(reference *model.AchievementReference, anchorType, anchorRef string) string {
+	switch anchorType {
+	case model.CommentAnchorField:
+		if contains(commentAnchorFields, anchorRef) {
+			return ""
+		}
+		if key := strings.TrimPrefix(anchorRef, "details."); key != anchorRef && key != "" {
+			return ""
+		}
+		return fmt.Sprintf("unknown achievement field '%s'", anchorRef)
+
+	case model.CommentAnchorAttachment:
+		achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+		if err != nil || !hasAttachment(achievement.Attachments, anchorRef) {
+			return fmt.Sprintf("attachment '%s' not found", anchorRef)
+		}
+		return ""
+	}
+	return fmt.Sprintf("unknown anchor type '%s'", anchorType)
+}
+
+// buildCommentThread - Susun komentar (urut created_at) menjadi pohon balasan
+func (s *CommentService) buildCommentThread(comments []model.AchievementComment) []map[string]interface{} {
+	names := map[string]string{}
+	nodes := make(map[string]map[string]interface{}, len(comments))
+	roots := []map[string]interface{}{}
+
+	for i := range comments {
+		comment := &comments[i]
+		node := s.buildCommentResponse(comment, names)
+		nodes[comment.ID] = node
+
+		// Parent selalu lebih dulu karena urut created_at
+		if comment.ParentID != nil {
+			if parent, ok := nodes[*comment.ParentID]; ok {
+				parent["replies"] = append(parent["replies"].([]map[string]interface{}), node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+func (s *CommentService) buildCommentResponse(comment *model.AchievementComment, names map[string]string) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":                 comment.ID,
+		"parent_id":          comment.ParentID,
+		"author_id":          comment.AuthorID,
+		"author_role":        comment.AuthorRole,
+		"body":               comment.Body,
+		"achievement_status": comment.AchievementStatus,
+		"deleted":            comment.DeletedAt != nil,
+		"created_at":         comment.CreatedAt.Format("2006-01-02 15:04:05"),
+		"replies":            []map[string]interface{}{},
+	}
+	if comment.AuthorID != nil {
+		response["author_name"] = s.authorName(names, *comment.AuthorID)
+	}
+	if comment.AnchorType != nil {
+		response["anchor"] = fiber.Map{
+			"type": *comment.AnchorType,
+			"ref":  comment.AnchorRef,
+		}
+	}
+	if comment.EditedAt != nil {
+		response["edited_at"] = comment.EditedAt.Format("2006-01-02 15:04:05")
+	}
+	if comment.DeletedAt != nil {
+		response["body"] = ""
+	}
+	return response
+}
+
+func (s *CommentService) authorName(cache map[string]string, userID string) string {
+	if name, ok := cache[userID]; ok {
+		return name
+	}
+	name := ""
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		name = user.FullName
+	}
+	cache[userID] = name
+	return name
+}
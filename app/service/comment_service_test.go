@@ -0,0 +1,239 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+type commentTestDeps struct {
+	commentRepo     *mocks.MockCommentRepository
+	achievementRepo *mocks.MockAchievementRepository
+}
+
+var commentTestNow = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func setupCommentTest() (*CommentService, commentTestDeps) {
+	deps := commentTestDeps{
+		commentRepo:     new(mocks.MockCommentRepository),
+		achievementRepo: new(mocks.MockAchievementRepository),
+	}
+	studentRepo := new(mocks.MockStudentRepository)
+	lecturerRepo := new(mocks.MockLecturerRepository)
+	userRepo := new(mocks.MockUserRepository)
+
+	authz := NewAuthorizer(studentRepo, lecturerRepo, noDelegations(), DefaultPolicy)
+	service := NewCommentService(deps.commentRepo, deps.achievementRepo, userRepo, authz, DefaultCommentPolicy)
+	service.now = func() time.Time { return commentTestNow }
+
+	// student-1 (dosen wali lecturer-1) pemilik ref-1, student-2 mahasiswa lain
+	advisorID := "lecturer-1"
+	student := &model.Student{ID: "student-1", UserID: "student-1", AdvisorID: &advisorID}
+	studentRepo.On("FindByID", "student-1").Return(student, nil).Maybe()
+	studentRepo.On("FindByUserID", "student-1").Return(student, nil).Maybe()
+	studentRepo.On("FindByUserID", "student-2").Return(&model.Student{ID: "student-2", UserID: "student-2"}, nil).Maybe()
+	studentRepo.On("FindByUserID", mock.Anything).Return(nil, errors.New("not found")).Maybe()
+	lecturerRepo.On("FindByUserID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", UserID: "lecturer-1"}, nil).Maybe()
+	lecturerRepo.On("FindByUserID", mock.Anything).Return(nil, errors.New("not found")).Maybe()
+	userRepo.On("FindByID", "student-1").Return(&model.User{ID: "student-1", FullName: "Mahasiswa Satu"}, nil).Maybe()
+	userRepo.On("FindByID", "lecturer-1").Return(&model.User{ID: "lecturer-1", FullName: "Dosen Wali Satu"}, nil).Maybe()
+
+	deps.achievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: "submitted",
+	}, nil).Maybe()
+	deps.achievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{
+		StudentID:   "student-1",
+		Title:       "Juara 1 Lomba",
+		Attachments: []model.Attachment{{FileName: "sertifikat.pdf", FileURL: "/uploads/sertifikat.pdf"}},
+	}, nil).Maybe()
+
+	return service, deps
+}
+
+func commentByAuthor(id, authorID string, createdAt time.Time) *model.AchievementComment {
+	return &model.AchievementComment{
+		ID:                     id,
+		AchievementReferenceID: "ref-1",
+		AuthorID:               &authorID,
+		Body:                   "Mohon lampirkan sertifikat",
+		AchievementStatus:      "submitted",
+		CreatedAt:              createdAt,
+	}
+}
+
+// ==================== CREATE COMMENT ====================
+
+func TestCreateComment(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		role   string
+		body   string
+		want   int
+	}{
+		{"mahasiswa pemilik", "student-1", "Mahasiswa", `{"body": "Sertifikat sudah saya unggah"}`, 201},
+		{"dosen wali anchor ke field", "lecturer-1", "Dosen Wali", `{"body": "Tingkat lomba belum diisi", "anchor_type": "field", "anchor_ref": "details.competitionLevel"}`, 201},
+		{"anchor ke attachment", "lecturer-1", "Dosen Wali", `{"body": "Scan kurang jelas", "anchor_type": "attachment", "anchor_ref": "/uploads/sertifikat.pdf"}`, 201},
+		{"balasan", "student-1", "Mahasiswa", `{"body": "Baik, Pak", "parent_id": "comment-1"}`, 201},
+		{"mahasiswa lain", "student-2", "Mahasiswa", `{"body": "Halo"}`, 403},
+		{"field tidak dikenal", "lecturer-1", "Dosen Wali", `{"body": "?", "anchor_type": "field", "anchor_ref": "nilai"}`, 400},
+		{"attachment tidak ada", "lecturer-1", "Dosen Wali", `{"body": "?", "anchor_type": "attachment", "anchor_ref": "/uploads/lain.pdf"}`, 400},
+		{"balasan ke komentar achievement lain", "student-1", "Mahasiswa", `{"body": "?", "parent_id": "comment-x"}`, 404},
+		{"anchor tanpa ref", "lecturer-1", "Dosen Wali", `{"body": "?", "anchor_type": "field"}`, 422},
+		{"isi kosong", "student-1", "Mahasiswa", `{"body": ""}`, 422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := setupCommentTest()
+
+			app := fiber.New()
+			app.Post("/achievements/:id/comments", withLecturerClaims(tt.userID, tt.role, service.CreateComment))
+
+			deps.commentRepo.On("FindByID", "comment-1").Return(commentByAuthor("comment-1", "lecturer-1", commentTestNow), nil)
+			other := commentByAuthor("comment-x", "lecturer-1", commentTestNow)
+			other.AchievementReferenceID = "ref-2"
+			deps.commentRepo.On("FindByID", "comment-x").Return(other, nil)
+			deps.commentRepo.On("Create", mock.AnythingOfType("*model.AchievementComment")).Return(nil)
+
+			req := httptest.NewRequest("POST", "/achievements/ref-1/comments", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				deps.commentRepo.AssertCalled(t, "Create", mock.MatchedBy(func(comment *model.AchievementComment) bool {
+					return comment.AchievementReferenceID == "ref-1" && comment.IsAuthor(tt.userID) &&
+						comment.AuthorRole == tt.role && comment.AchievementStatus == "submitted"
+				}))
+			} else {
+				deps.commentRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+// ==================== GET COMMENTS ====================
+
+func TestGetComments_DeletedAchievement(t *testing.T) {
+	service, deps := setupCommentTest()
+	deps.achievementRepo.On("GetReferenceByID", "ref-deleted").Return(&model.AchievementReference{
+		ID: "ref-deleted", StudentID: "student-1", Status: model.AchievementStatusDeleted,
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/comments", withLecturerClaims("student-1", "Mahasiswa", service.GetComments))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-deleted/comments", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+	deps.commentRepo.AssertNotCalled(t, "GetByReferenceID", mock.Anything)
+}
+
+func TestGetComments_ThreadAcrossResubmissions(t *testing.T) {
+	service, deps := setupCommentTest()
+
+	app := fiber.New()
+	app.Get("/achievements/:id/comments", withLecturerClaims("student-1", "Mahasiswa", service.GetComments))
+
+	// Ditolak, dibalas, lalu di-submit ulang; komentar pertama dihapus penulisnya
+	deletedAt := commentTestNow.Add(-time.Hour)
+	first := commentByAuthor("comment-1", "lecturer-1", commentTestNow.Add(-48*time.Hour))
+	first.AchievementStatus = "rejected"
+	first.DeletedAt = &deletedAt
+	reply := commentByAuthor("comment-2", "student-1", commentTestNow.Add(-47*time.Hour))
+	reply.ParentID = &first.ID
+	reply.AchievementStatus = "rejected"
+	latest := commentByAuthor("comment-3", "lecturer-1", commentTestNow.Add(-time.Hour))
+	deps.commentRepo.On("GetByReferenceID", "ref-1").Return([]model.AchievementComment{*first, *reply, *latest}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/comments", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Comments []map[string]interface{} `json:"comments"`
+			Total    int                      `json:"total"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, 3, result.Data.Total)
+	assert.Len(t, result.Data.Comments, 2)
+
+	root := result.Data.Comments[0]
+	assert.Equal(t, true, root["deleted"])
+	assert.Equal(t, "", root["body"])
+	assert.Equal(t, "rejected", root["achievement_status"])
+
+	replies := root["replies"].([]interface{})
+	assert.Len(t, replies, 1)
+	assert.Equal(t, "Mahasiswa Satu", replies[0].(map[string]interface{})["author_name"])
+	assert.Equal(t, "submitted", result.Data.Comments[1]["achievement_status"])
+}
+
+// ==================== UPDATE / DELETE COMMENT ====================
+
+func TestUpdateComment(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		createdAt time.Time
+		want      int
+	}{
+		{"penulis dalam batas waktu", "lecturer-1", commentTestNow.Add(-10 * time.Minute), 200},
+		{"batas waktu edit lewat", "lecturer-1", commentTestNow.Add(-20 * time.Minute), 403},
+		{"bukan penulis", "student-1", commentTestNow.Add(-time.Minute), 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := setupCommentTest()
+
+			app := fiber.New()
+			app.Put("/achievements/:id/comments/:commentId", withLecturerClaims(tt.userID, "Dosen Wali", service.UpdateComment))
+
+			deps.commentRepo.On("FindByID", "comment-1").Return(commentByAuthor("comment-1", "lecturer-1", tt.createdAt), nil)
+			deps.commentRepo.On("UpdateBody", "comment-1", "Mohon lampirkan sertifikat asli").Return(nil)
+
+			req := httptest.NewRequest("PUT", "/achievements/ref-1/comments/comment-1", strings.NewReader(`{"body": "Mohon lampirkan sertifikat asli"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 200 {
+				deps.commentRepo.AssertNotCalled(t, "UpdateBody", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	service, deps := setupCommentTest()
+
+	app := fiber.New()
+	app.Delete("/achievements/:id/comments/:commentId", withLecturerClaims("lecturer-1", "Dosen Wali", service.DeleteComment))
+
+	deps.commentRepo.On("FindByID", "comment-1").Return(commentByAuthor("comment-1", "lecturer-1", commentTestNow.Add(-30*time.Minute)), nil)
+	deps.commentRepo.On("FindByID", "comment-2").Return(commentByAuthor("comment-2", "lecturer-1", commentTestNow.Add(-2*time.Hour)), nil)
+	deps.commentRepo.On("SoftDelete", "comment-1").Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievements/ref-1/comments/comment-1", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// Lewat batas waktu hapus
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/achievements/ref-1/comments/comment-2", nil))
+	assert.Equal(t, 403, resp.StatusCode)
+
+	deps.commentRepo.AssertNumberOfCalls(t, "SoftDelete", 1)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== VERIFICATION DELEGATION ======================
+// Dosen wali yang cuti mendelegasikan verifikasi mahasiswa bimbingannya ke
+// dosen lain untuk rentang tanggal tertentu. Admin boleh membuat delegasi
+// atas nama dosen wali. Delegasi tidak perlu dihapus: Authorizer hanya
+// memakai delegasi yang sedang berlaku (scope "delegated").
+//
+
+type DelegationService struct {
+	delegationRepo repository.DelegationRepository
+	lecturerRepo   repository.LecturerRepository
+	userRepo       repository.UserRepository
+	authz          *Authorizer
+	audit          *AuditService
+	validate       *validator.Validate
+	now            func() time.Time
+}
+
+func NewDelegationService(
+	delegationRepo repository.DelegationRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+	authz *Authorizer,
+	audit *AuditService,
+) *DelegationService {
+	return &DelegationService{
+		delegationRepo: delegationRepo,
+		lecturerRepo:   lecturerRepo,
+		userRepo:       userRepo,
+		authz:          authz,
+		audit:          audit,
+		validate:       validator.New(),
+		now:            time.Now,
+	}
+}
+
+//
+// ==================== GET DELEGATIONS (GET /lecturers/:id/delegations) ======================
+//
+
+func (s *DelegationService) GetDelegations(c *fiber.Ctx) error {
+	lecturer, err := s.authorizeLecturer(c)
+	if lecturer == nil {
+		return err
+	}
+
+	delegations, err := s.delegationRepo.GetByLecturerID(lecturer.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch delegations",
+		})
+	}
+
+	responses := []map[string]interface{}{}
+	for i := range delegations {
+		responses = append(responses, s.buildDelegationResponse(&delegations[i]))
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"lecturer_id": lecturer.ID,
+			"delegations": responses,
+			"total":       len(responses),
+		},
+	})
+}
+
+//
+// ==================== CREATE DELEGATION (POST /lecturers/:id/delegations) ======================
+//
+
+func (s *DelegationService) CreateDelegation(c *fiber.Ctx) error {
+	lecturer, err := s.authorizeLecturer(c)
+	if lecturer == nil {
+		return err
+	}
+
+	// Parse request
+	req := new(model.DelegationCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi input
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if req.DelegateID == lecturer.ID {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot delegate to the same lecturer",
+		})
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "ends_at must be after starts_at",
+		})
+	}
+	if !req.EndsAt.After(s.now()) {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegation period has already ended",
+		})
+	}
+
+	// Delegate harus dosen yang masih aktif
+	delegate, err := s.lecturerRepo.FindByID(req.DelegateID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegate lecturer not found",
+		})
+	}
+	if delegate.RetiredAt != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegate lecturer is no longer active",
+		})
+	}
+
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	delegation := &model.VerificationDelegation{
+		LecturerID: lecturer.ID,
+		DelegateID: delegate.ID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		CreatedBy:  claims.UserID,
+	}
+	if err := s.delegationRepo.Create(delegation); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create delegation",
+		})
+	}
+
+	s.audit.Record(c, "delegation.create", "lecturer", lecturer.ID, map[string]interface{}{
+		"delegation_id": delegation.ID,
+		"delegate_id":   delegation.DelegateID,
+		"starts_at":     delegation.StartsAt,
+		"ends_at":       delegation.EndsAt,
+	})
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "delegation created successfully",
+		Data:    s.buildDelegationResponse(delegation),
+	})
+}
+
+//
+// ==================== REVOKE DELEGATION (DELETE /lecturers/:id/delegations/:delegationId) ======================
+// Mengakhiri delegasi lebih awal (mis. dosen wali kembali dari cuti)
+//
+
+func (s *DelegationService) RevokeDelegation(c *fiber.Ctx) error {
+	lecturer, err := s.authorizeLecturer(c)
+	if lecturer == nil {
+		return err
+	}
+
+	delegation, err := s.delegationRepo.FindByID(c.Params("delegationId"))
+	if err != nil || delegation.LecturerID != lecturer.ID {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegation not found",
+		})
+	}
+
+	revoked, err := s.delegationRepo.Revoke(delegation.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke delegation",
+		})
+	}
+	if !revoked {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegation already revoked",
+		})
+	}
+
+	s.audit.Record(c, "delegation.revoke", "lecturer", lecturer.ID, map[string]interface{}{
+		"delegation_id": delegation.ID,
+		"delegate_id":   delegation.DelegateID,
+	})
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "delegation revoked successfully",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// authorizeLecturer - Load dosen dari :id dan cek policy delegation:manage.
+// Return nil lecturer jika response error sudah dikirim (error = hasil kirim response).
+func (s *DelegationService) authorizeLecturer(c *fiber.Ctx) (*model.Lecturer, error) {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return nil, c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	lecturer, err := s.lecturerRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "lecturer not found",
+		})
+	}
+
+	// Dosen wali: delegasi miliknya sendiri, Admin: semua (atau departemen scope-nya)
+	if !s.authz.Can(claims, ActionDelegationManage, Target{Lecturer: lecturer}) {
+		return nil, c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+	return lecturer, nil
+}
+
+func (s *DelegationService) buildDelegationResponse(delegation *model.VerificationDelegation) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":          delegation.ID,
+		"lecturer_id": delegation.LecturerID,
+		"delegate_id": delegation.DelegateID,
+		"starts_at":   delegation.StartsAt.Format("2006-01-02 15:04:05"),
+		"ends_at":     delegation.EndsAt.Format("2006-01-02 15:04:05"),
+		"created_by":  delegation.CreatedBy,
+		"active":      delegation.IsActive(s.now()),
+		"created_at":  delegation.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if delegation.RevokedAt != nil {
+		response["revoked_at"] = delegation.RevokedAt.Format("2006-01-02 15:04:05")
+	}
+
+	if user, err := s.userRepo.FindByID(delegation.DelegateID); err == nil && user != nil {
+		response["delegate_name"] = user.FullName
+	}
+	return response
+}
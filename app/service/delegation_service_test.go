@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+type delegationTestDeps struct {
+	delegationRepo *mocks.MockDelegationRepository
+	lecturerRepo   *mocks.MockLecturerRepository
+	userRepo       *mocks.MockUserRepository
+	auditRepo      *mocks.MockAuditLogRepository
+}
+
+var delegationTestNow = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func setupDelegationTest() (*DelegationService, delegationTestDeps) {
+	deps := delegationTestDeps{
+		delegationRepo: new(mocks.MockDelegationRepository),
+		lecturerRepo:   new(mocks.MockLecturerRepository),
+		userRepo:       new(mocks.MockUserRepository),
+		auditRepo:      new(mocks.MockAuditLogRepository),
+	}
+
+	authz := NewAuthorizer(new(mocks.MockStudentRepository), deps.lecturerRepo, deps.delegationRepo, DefaultPolicy)
+	service := NewDelegationService(deps.delegationRepo, deps.lecturerRepo, deps.userRepo, authz, NewAuditService(deps.auditRepo))
+	service.now = func() time.Time { return delegationTestNow }
+
+	// Dosen wali yang cuti dan dosen pengganti
+	deps.lecturerRepo.On("FindByID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", Department: "Informatika"}, nil)
+	deps.lecturerRepo.On("FindByUserID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", Department: "Informatika"}, nil)
+	deps.lecturerRepo.On("FindByID", "lecturer-2").Return(&model.Lecturer{ID: "lecturer-2", Department: "Informatika"}, nil)
+	deps.lecturerRepo.On("FindByUserID", "lecturer-2").Return(&model.Lecturer{ID: "lecturer-2", Department: "Informatika"}, nil)
+	deps.userRepo.On("FindByID", "lecturer-2").Return(&model.User{ID: "lecturer-2", FullName: "Dosen Pengganti"}, nil).Maybe()
+
+	return service, deps
+}
+
+func withLecturerClaims(userID, role string, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID, Roles: []string{role}})
+		return handler(c)
+	}
+}
+
+// ==================== CREATE DELEGATION ====================
+
+func TestCreateDelegation(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		role   string
+		body   string
+		want   int
+	}{
+		{
+			"dosen wali mendelegasikan miliknya sendiri",
+			"lecturer-1", "Dosen Wali",
+			`{"delegate_id": "lecturer-2", "starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`,
+			201,
+		},
+		{
+			"admin atas nama dosen wali",
+			"admin-1", "Admin",
+			`{"delegate_id": "lecturer-2", "starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`,
+			201,
+		},
+		{
+			"dosen lain tidak boleh",
+			"lecturer-2", "Dosen Wali",
+			`{"delegate_id": "lecturer-2", "starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`,
+			403,
+		},
+		{
+			"delegasi ke diri sendiri",
+			"lecturer-1", "Dosen Wali",
+			`{"delegate_id": "lecturer-1", "starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`,
+			400,
+		},
+		{
+			"periode terbalik",
+			"lecturer-1", "Dosen Wali",
+			`{"delegate_id": "lecturer-2", "starts_at": "2025-03-24T00:00:00Z", "ends_at": "2025-03-10T00:00:00Z"}`,
+			400,
+		},
+		{
+			"periode sudah lewat",
+			"lecturer-1", "Dosen Wali",
+			`{"delegate_id": "lecturer-2", "starts_at": "2025-01-01T00:00:00Z", "ends_at": "2025-01-14T00:00:00Z"}`,
+			400,
+		},
+		{
+			"delegate tidak diisi",
+			"lecturer-1", "Dosen Wali",
+			`{"starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`,
+			422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := setupDelegationTest()
+
+			app := fiber.New()
+			app.Post("/lecturers/:id/delegations", withLecturerClaims(tt.userID, tt.role, service.CreateDelegation))
+
+			deps.delegationRepo.On("Create", mock.AnythingOfType("*model.VerificationDelegation")).Return(nil)
+			deps.auditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil)
+
+			req := httptest.NewRequest("POST", "/lecturers/lecturer-1/delegations", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				deps.delegationRepo.AssertCalled(t, "Create", mock.MatchedBy(func(d *model.VerificationDelegation) bool {
+					return d.LecturerID == "lecturer-1" && d.DelegateID == "lecturer-2" && d.CreatedBy == tt.userID
+				}))
+				deps.auditRepo.AssertCalled(t, "Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+					return e.Action == "delegation.create" && e.TargetID == "lecturer-1"
+				}))
+			} else {
+				deps.delegationRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateDelegation_RetiredDelegate(t *testing.T) {
+	service, deps := setupDelegationTest()
+
+	app := fiber.New()
+	app.Post("/lecturers/:id/delegations", withLecturerClaims("lecturer-1", "Dosen Wali", service.CreateDelegation))
+
+	retiredAt := delegationTestNow.AddDate(0, -1, 0)
+	deps.lecturerRepo.On("FindByID", "lecturer-3").Return(&model.Lecturer{ID: "lecturer-3", RetiredAt: &retiredAt}, nil)
+
+	req := httptest.NewRequest("POST", "/lecturers/lecturer-1/delegations",
+		strings.NewReader(`{"delegate_id": "lecturer-3", "starts_at": "2025-03-10T00:00:00Z", "ends_at": "2025-03-24T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+	deps.delegationRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// ==================== GET DELEGATIONS ====================
+
+func TestGetDelegations(t *testing.T) {
+	service, deps := setupDelegationTest()
+
+	app := fiber.New()
+	app.Get("/lecturers/:id/delegations", withLecturerClaims("lecturer-1", "Dosen Wali", service.GetDelegations))
+
+	revokedAt := delegationTestNow.AddDate(0, 0, -3)
+	deps.delegationRepo.On("GetByLecturerID", "lecturer-1").Return([]model.VerificationDelegation{
+		{ID: "delegation-2", LecturerID: "lecturer-1", DelegateID: "lecturer-2", StartsAt: delegationTestNow.AddDate(0, 0, -1), EndsAt: delegationTestNow.AddDate(0, 0, 7)},
+		{ID: "delegation-1", LecturerID: "lecturer-1", DelegateID: "lecturer-2", StartsAt: delegationTestNow.AddDate(0, 0, -10), EndsAt: delegationTestNow.AddDate(0, 0, 7), RevokedAt: &revokedAt},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/lecturers/lecturer-1/delegations", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Delegations []map[string]interface{} `json:"delegations"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(t, result.Data.Delegations, 2)
+	assert.Equal(t, true, result.Data.Delegations[0]["active"])
+	assert.Equal(t, false, result.Data.Delegations[1]["active"])
+	assert.Equal(t, "Dosen Pengganti", result.Data.Delegations[0]["delegate_name"])
+}
+
+// ==================== REVOKE DELEGATION ====================
+
+func TestRevokeDelegation(t *testing.T) {
+	service, deps := setupDelegationTest()
+
+	app := fiber.New()
+	app.Delete("/lecturers/:id/delegations/:delegationId", withLecturerClaims("lecturer-1", "Dosen Wali", service.RevokeDelegation))
+
+	deps.delegationRepo.On("FindByID", "delegation-1").Return(&model.VerificationDelegation{ID: "delegation-1", LecturerID: "lecturer-1", DelegateID: "lecturer-2"}, nil)
+	deps.delegationRepo.On("FindByID", "delegation-x").Return(&model.VerificationDelegation{ID: "delegation-x", LecturerID: "lecturer-9"}, nil)
+	deps.delegationRepo.On("Revoke", "delegation-1").Return(true, nil).Once()
+	deps.delegationRepo.On("Revoke", "delegation-1").Return(false, nil)
+	deps.auditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/lecturers/lecturer-1/delegations/delegation-1", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// Sudah dicabut
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/lecturers/lecturer-1/delegations/delegation-1", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+
+	// Delegasi milik dosen lain
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/lecturers/lecturer-1/delegations/delegation-x", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+
+	deps.auditRepo.AssertNumberOfCalls(t, "Create", 1)
+}
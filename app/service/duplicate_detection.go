@@ -0,0 +1,295 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== DUPLICATE DETECTION ======================
+// Saat submit, achievement dibandingkan dengan achievement lain yang sejenis
+// atau lampirannya sama: hash SHA-256 lampiran, nama & tanggal kegiatan di
+// details, dan kemiripan judul yang dinormalisasi. Kecocokan disimpan di
+// duplicate_flags untuk verifikator; submit tidak pernah ditolak karenanya.
+//
+
+const (
+	duplicateCandidateLimit = 1000 // achievement sejenis terbaru yang dibandingkan
+	similarTitleThreshold   = 0.85 // koefisien Dice bigram judul
+)
+
+// Key details yang berisi nama / tanggal kegiatan (lihat schema achievement_types)
+var (
+	eventNameKeys = []string{"eventName", "competitionName", "publicationTitle", "certificationName", "organizationName"}
+	eventDateKeys = []string{"eventDate", "publishedDate", "issuedDate", "periodStart", "awardDate"}
+)
+
+// detectDuplicates - Hitung ulang flag achievement yang baru disubmit
+func (s *AchievementService) detectDuplicates(achievement *model.Achievement, mongoID string) ([]model.DuplicateFlag, error) {
+	candidates, err := s.duplicateRepo.FindCandidates(achievement, duplicateCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := []model.DuplicateFlag{}
+	for i := range candidates {
+		if flag := compareAchievements(achievement, &candidates[i]); flag != nil {
+			flags = append(flags, *flag)
+		}
+	}
+	return flags, s.duplicateRepo.ReplaceFlags(mongoID, flags)
+}
+
+// compareAchievements - Flag jika b mungkin duplikat a (nil jika tidak)
+func compareAchievements(a, b *model.Achievement) *model.DuplicateFlag {
+	reasons := []string{}
+
+	if sharesAttachment(a.Attachments, b.Attachments) {
+		reasons = append(reasons, model.DuplicateReasonAttachmentHash)
+	}
+
+	nameA, dateA := eventOf(a)
+	nameB, dateB := eventOf(b)
+	if nameA != "" && dateA != "" && nameA == nameB && dateA == dateB {
+		reasons = append(reasons, model.DuplicateReasonSameEvent)
+	}
+
+	similarity := titleSimilarity(a.Title, b.Title)
+	if similarity >= similarTitleThreshold {
+		reasons = append(reasons, model.DuplicateReasonSimilarTitle)
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &model.DuplicateFlag{
+		MatchedMongoAchievementID: b.ID.Hex(),
+		Reasons:                   reasons,
+		TitleSimilarity:           math.Round(similarity*1000) / 1000,
+	}
+}
+
+func sharesAttachment(a, b []model.Attachment) bool {
+	hashes := make(map[string]bool)
+	for _, attachment := range a {
+		if attachment.SHA256 != "" {
+			hashes[attachment.SHA256] = true
+		}
+	}
+	for _, attachment := range b {
+		if hashes[attachment.SHA256] {
+			return true
+		}
+	}
+	return false
+}
+
+// eventOf - Nama kegiatan (dinormalisasi) dari details dan tanggal (YYYY-MM-DD):
+// event_date achievement, atau dari details untuk data lama
+func eventOf(achievement *model.Achievement) (string, string) {
+	var name, date string
+	details := achievement.Details
+	for _, key := range eventNameKeys {
+		if value, ok := details[key].(string); ok && strings.TrimSpace(value) != "" {
+			name = normalizeTitle(value)
+			break
+		}
+	}
+	if !achievement.EventDate.IsZero() {
+		return name, achievement.EventDate.Format(dateLayout)
+	}
+	for _, key := range eventDateKeys {
+		if value, ok := details[key].(string); ok && len(strings.TrimSpace(value)) >= 10 {
+			date = strings.TrimSpace(value)[:10]
+			break
+		}
+	}
+	return name, date
+}
+
+// normalizeTitle - Huruf kecil, tanda baca jadi spasi, spasi berlebih dibuang
+func normalizeTitle(title string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, title)
+	return strings.Join(strings.Fields(mapped), " ")
+}
+
+// titleSimilarity - Koefisien Dice bigram karakter judul yang dinormalisasi (0..1)
+func titleSimilarity(a, b string) float64 {
+	a, b = normalizeTitle(a), normalizeTitle(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	bigrams := func(value string) map[string]int {
+		runes := []rune(value)
+		out := make(map[string]int)
+		for i := 0; i+1 < len(runes); i++ {
+			out[string(runes[i:i+2])]++
+		}
+		return out
+	}
+	left, right := bigrams(a), bigrams(b)
+
+	total, shared := 0, 0
+	for bigram, count := range left {
+		total += count
+		if other, ok := right[bigram]; ok {
+			if other < count {
+				shared += other
+			} else {
+				shared += count
+			}
+		}
+	}
+	for _, count := range right {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(2*shared) / float64(total)
+}
+
+// duplicateMatch - Achievement yang cocok beserta link ke reference-nya.
+// ok = false jika achievement sudah dihapus.
+func duplicateMatch(achievementRepo repository.AchievementRepository, mongoID string) (*model.DuplicateMatch, bool) {
+	achievement, err := achievementRepo.GetAchievementByID(mongoID)
+	if err != nil {
+		return nil, false
+	}
+	refs, err := achievementRepo.GetReferencesByMongoID(mongoID)
+	if err != nil || len(refs) == 0 {
+		return nil, false
+	}
+
+	match := &model.DuplicateMatch{
+		MongoAchievementID: mongoID,
+		Title:              achievement.Title,
+		AchievementType:    achievement.AchievementType,
+		StudentID:          achievement.StudentID,
+		Reasons:            []string{},
+		References:         []model.DuplicateMatchRecord{},
+	}
+	for _, ref := range refs {
+		match.References = append(match.References, model.DuplicateMatchRecord{
+			ID:        ref.ID,
+			StudentID: ref.StudentID,
+			Status:    ref.Status,
+			Link:      "/api/v1/achievements/" + ref.ID,
+		})
+	}
+	return match, true
+}
+
+// mergeReasons - Gabung alasan tanpa duplikat, urut sesuai abjad
+func mergeReasons(current, added []string) []string {
+	for _, reason := range added {
+		if !contains(current, reason) {
+			current = append(current, reason)
+		}
+	}
+	sort.Strings(current)
+	return current
+}
+
+//
+// ==================== GET DUPLICATES (GET /achievements/:id/duplicates) ======================
+// Untuk verifikator: achievement lain yang mungkin duplikat achievement ini
+//
+
+func (s *AchievementService) GetDuplicates(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(c.Params("id"))
+	if err != nil || reference.Status == model.AchievementStatusDeleted {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementRead, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	flags, err := s.duplicateRepo.GetFlags(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch duplicate flags",
+		})
+	}
+
+	// Satu entri per achievement lain (flag bisa tercatat dari kedua arah)
+	matches := []*model.DuplicateMatch{}
+	byID := make(map[string]*model.DuplicateMatch)
+	for _, flag := range flags {
+		otherID := flag.MatchedMongoAchievementID
+		if otherID == reference.MongoAchievementID {
+			otherID = flag.MongoAchievementID
+		}
+
+		match, seen := byID[otherID]
+		if !seen {
+			var ok bool
+			if match, ok = duplicateMatch(s.achievementRepo, otherID); !ok {
+				continue
+			}
+			match.FlaggedAt = flag.CreatedAt
+			byID[otherID] = match
+			matches = append(matches, match)
+		}
+		match.Reasons = mergeReasons(match.Reasons, flag.Reasons)
+		if flag.TitleSimilarity > match.TitleSimilarity {
+			match.TitleSimilarity = flag.TitleSimilarity
+		}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_id":     reference.ID,
+			"possible_duplicate": len(matches) > 0,
+			"matches":            matches,
+		},
+	})
+}
+
+// markDuplicates - Tandai possible_duplicate untuk verifikator (role yang boleh
+// verify). Gagal mengambil flag tidak menggagalkan request.
+func (s *AchievementService) markDuplicates(claims *model.JWTClaims, responses []*model.AchievementResponse, mongoIDs []string) {
+	if len(s.authz.grants(claims, ActionAchievementVerify)) == 0 {
+		return
+	}
+	flagged, err := s.duplicateRepo.FlaggedIDs(mongoIDs)
+	if err != nil {
+		return
+	}
+	for i, response := range responses {
+		response.PossibleDuplicate = flagged[mongoIDs[i]]
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== TITLE SIMILARITY ====================
+
+func TestTitleSimilarity(t *testing.T) {
+	assert.Equal(t, "juara 1 lomba coding nasional 2025", normalizeTitle("  Juara-1 Lomba CODING: Nasional (2025)!"))
+
+	assert.Equal(t, 1.0, titleSimilarity("Juara 1 Lomba Coding", "juara 1, lomba coding"))
+	assert.GreaterOrEqual(t, titleSimilarity("Juara 1 Lomba Coding Nasional", "Juara I Lomba Coding Nasional"), similarTitleThreshold)
+	assert.Less(t, titleSimilarity("Juara 1 Lomba Coding Nasional", "Publikasi Jurnal Sinta 2"), similarTitleThreshold)
+	assert.Equal(t, 0.0, titleSimilarity("", "Lomba"))
+}
+
+// ==================== COMPARE ACHIEVEMENTS ====================
+
+func TestCompareAchievements(t *testing.T) {
+	submitted := &model.Achievement{
+		Title:       "Juara 1 Hackathon Nasional",
+		Details:     map[string]interface{}{"competitionName": "Hackathon Nasional", "eventDate": "2025-05-10"},
+		Attachments: []model.Attachment{{FileName: "sertifikat.pdf", SHA256: "abc123"}},
+	}
+
+	tests := []struct {
+		name  string
+		other model.Achievement
+		want  []string
+	}{
+		{
+			"lampiran identik",
+			model.Achievement{Title: "Sertifikat", Attachments: []model.Attachment{{SHA256: "abc123"}}},
+			[]string{model.DuplicateReasonAttachmentHash},
+		},
+		{
+			"kegiatan sama, judul beda",
+			model.Achievement{Title: "Finalis", Details: map[string]interface{}{"competitionName": "HACKATHON nasional", "eventDate": "2025-05-10T00:00:00Z"}},
+			[]string{model.DuplicateReasonSameEvent},
+		},
+		{
+			"kegiatan sama, tanggal beda",
+			model.Achievement{Title: "Finalis", Details: map[string]interface{}{"competitionName": "Hackathon Nasional", "eventDate": "2024-05-10"}},
+			nil,
+		},
+		{
+			"judul mirip",
+			model.Achievement{Title: "Juara I Hackathon Nasional"},
+			[]string{model.DuplicateReasonSimilarTitle},
+		},
+		{
+			"semua alasan",
+			model.Achievement{
+				Title:       "juara 1 hackathon nasional",
+				Details:     map[string]interface{}{"competitionName": "Hackathon Nasional", "eventDate": "2025-05-10"},
+				Attachments: []model.Attachment{{SHA256: "abc123"}},
+			},
+			[]string{model.DuplicateReasonAttachmentHash, model.DuplicateReasonSameEvent, model.DuplicateReasonSimilarTitle},
+		},
+		{
+			"tidak mirip (lampiran tanpa hash)",
+			model.Achievement{Title: "Publikasi Jurnal", Attachments: []model.Attachment{{FileName: "lama.pdf"}}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.other.ID = primitive.NewObjectID()
+			flag := compareAchievements(submitted, &tt.other)
+			if tt.want == nil {
+				assert.Nil(t, flag)
+				return
+			}
+			if assert.NotNil(t, flag) {
+				assert.Equal(t, tt.want, flag.Reasons)
+				assert.Equal(t, tt.other.ID.Hex(), flag.MatchedMongoAchievementID)
+			}
+		})
+	}
+}
+
+// ==================== SUBMIT: FLAG DUPLICATES ====================
+
+func TestSubmitForVerification_FlagsDuplicates(t *testing.T) {
+	service, mockAchievementRepo, mockPointRuleRepo := setupPointsTest()
+	mockPointRuleRepo.On("GetByType", "competition").Return([]model.PointRule{}, nil)
+	mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+	service.duplicateRepo = mockDuplicateRepo
+
+	matchedID := primitive.NewObjectID()
+	achievement := &model.Achievement{
+		AchievementType: "competition",
+		Title:           "Juara 1 Lomba Coding",
+		Attachments:     []model.Attachment{{SHA256: "abc123"}},
+	}
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: model.AchievementStatusDraft,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(achievement, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil)
+	mockDuplicateRepo.On("FindCandidates", achievement, duplicateCandidateLimit).Return([]model.Achievement{
+		{ID: matchedID, Title: "Juara 1 Lomba Coding"},
+		{ID: primitive.NewObjectID(), Title: "Publikasi Jurnal"},
+	}, nil)
+	mockDuplicateRepo.On("ReplaceFlags", "mongo-1", mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+		return service.SubmitForVerification(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockDuplicateRepo.AssertCalled(t, "ReplaceFlags", "mongo-1", mock.MatchedBy(func(flags []model.DuplicateFlag) bool {
+		return len(flags) == 1 && flags[0].MatchedMongoAchievementID == matchedID.Hex() && flags[0].TitleSimilarity == 1
+	}))
+}
+
+func TestSubmitForVerification_DuplicateCheckFailureDoesNotBlock(t *testing.T) {
+	service, mockAchievementRepo, mockPointRuleRepo := setupPointsTest()
+	mockPointRuleRepo.On("GetByType", "competition").Return([]model.PointRule{}, nil)
+	mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+	service.duplicateRepo = mockDuplicateRepo
+
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: model.AchievementStatusDraft,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{AchievementType: "competition"}, nil)
+	mockAchievementRepo.On("UpdateReference", mock.AnythingOfType("*model.AchievementReference"), mock.Anything, mock.Anything).Return(nil)
+	mockDuplicateRepo.On("FindCandidates", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+		return service.SubmitForVerification(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockDuplicateRepo.AssertNotCalled(t, "ReplaceFlags", mock.Anything, mock.Anything)
+}
+
+// ==================== GET DUPLICATES ====================
+
+func TestGetDuplicates(t *testing.T) {
+	service, mockAchievementRepo, _ := setupPointsTest()
+	mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+	service.duplicateRepo = mockDuplicateRepo
+
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: model.AchievementStatusSubmitted,
+	}, nil)
+	// mongo-2 cocok dari dua arah (submit mongo-1 dan submit mongo-2); mongo-3 sudah dihapus
+	mockDuplicateRepo.On("GetFlags", "mongo-1").Return([]model.DuplicateFlag{
+		{MongoAchievementID: "mongo-1", MatchedMongoAchievementID: "mongo-2", Reasons: []string{model.DuplicateReasonSimilarTitle}, TitleSimilarity: 0.9},
+		{MongoAchievementID: "mongo-2", MatchedMongoAchievementID: "mongo-1", Reasons: []string{model.DuplicateReasonAttachmentHash}, TitleSimilarity: 0.95},
+		{MongoAchievementID: "mongo-1", MatchedMongoAchievementID: "mongo-3", Reasons: []string{model.DuplicateReasonSameEvent}},
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-2").Return(&model.Achievement{StudentID: "student-2", Title: "Juara Lomba"}, nil)
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-2").Return([]model.AchievementReference{
+		{ID: "ref-2", StudentID: "student-2", MongoAchievementID: "mongo-2", Status: model.AchievementStatusVerified},
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-3").Return(&model.Achievement{StudentID: "student-1"}, nil)
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-3").Return([]model.AchievementReference{}, nil)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/duplicates", withLecturerClaims("lecturer-1", "Dosen Wali", service.GetDuplicates))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/duplicates", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			PossibleDuplicate bool                   `json:"possible_duplicate"`
+			Matches           []model.DuplicateMatch `json:"matches"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.True(t, result.Data.PossibleDuplicate)
+	if assert.Len(t, result.Data.Matches, 1) {
+		match := result.Data.Matches[0]
+		assert.Equal(t, "mongo-2", match.MongoAchievementID)
+		assert.Equal(t, []string{model.DuplicateReasonAttachmentHash, model.DuplicateReasonSimilarTitle}, match.Reasons)
+		assert.Equal(t, 0.95, match.TitleSimilarity)
+		assert.Equal(t, "/api/v1/achievements/ref-2", match.References[0].Link)
+	}
+}
+
+func TestGetAchievementByID_PossibleDuplicateForVerifierOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(*AchievementService) fiber.Handler
+		want    bool
+	}{
+		{"dosen wali", func(s *AchievementService) fiber.Handler {
+			return withLecturerClaims("lecturer-1", "Dosen Wali", s.GetAchievementByID)
+		}, true},
+		{"mahasiswa pemilik", func(s *AchievementService) fiber.Handler {
+			return func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "student-1", Roles: []string{"Mahasiswa"}})
+				return s.GetAchievementByID(c)
+			}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAchievementRepo, _ := setupPointsTest()
+			mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+			service.duplicateRepo = mockDuplicateRepo
+
+			mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+				ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: model.AchievementStatusSubmitted,
+			}, nil)
+			mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{StudentID: "student-1", Title: "Juara"}, nil)
+			mockDuplicateRepo.On("FlaggedIDs", []string{"mongo-1"}).Return(map[string]bool{"mongo-1": true}, nil).Maybe()
+
+			app := fiber.New()
+			app.Get("/achievements/:id", tt.handler(service))
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1", nil))
+			assert.Equal(t, 200, resp.StatusCode)
+
+			var result struct {
+				Data model.AchievementResponse `json:"data"`
+			}
+			json.NewDecoder(resp.Body).Decode(&result)
+			assert.Equal(t, tt.want, result.Data.PossibleDuplicate)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/utils"
+)
+
+//
+// ==================== JWKS (GET /.well-known/jwks.json) ======================
+// Public key untuk verifikasi JWT oleh service lain (format RFC 7517, bukan APIResponse)
+//
+
+type JWKSService struct{}
+
+func NewJWKSService() *JWKSService {
+	return &JWKSService{}
+}
+
+func (s *JWKSService) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(utils.JWKS())
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"project_uas/app/model"
+	"project_uas/utils"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+// useTestJWTKeys - Key Ed25519 sementara untuk test yang butuh sign / validasi token
+func useTestJWTKeys() {
+	ks, err := utils.NewEphemeralKeySet()
+	if err != nil {
+		panic(err)
+	}
+	utils.SetKeySet(ks)
+}
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+	path := filepath.Join(dir, kid+".pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	assert.NoError(t, err)
+}
+
+// setupKeyDir - Key lama (RSA, tinggal public key) + key baru (Ed25519) untuk skenario rotasi
+func setupKeyDir(t *testing.T) (string, *rsa.PrivateKey) {
+	dir := t.TempDir()
+
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	oldPublic, _ := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	writePEM(t, dir, "2025-01", "PUBLIC KEY", oldPublic)
+
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	newPrivate, _ := x509.MarshalPKCS8PrivateKey(newKey)
+	writePEM(t, dir, "2025-07", "PRIVATE KEY", newPrivate)
+
+	return dir, oldKey
+}
+
+// ==================== KEY ROTATION ====================
+
+func TestKeySet_RotationKeepsOldKeyForVerification(t *testing.T) {
+	dir, oldKey := setupKeyDir(t)
+
+	ks, err := utils.LoadKeySet(dir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-07", ks.SigningKeyID())
+	utils.SetKeySet(ks)
+
+	// Token baru: EdDSA + kid key aktif
+	token, err := utils.GenerateJWT(model.UserResponse{ID: "user-123", Username: "mahasiswa123"}, "session-1", time.Now())
+	assert.NoError(t, err)
+
+	parsed, _, _ := jwt.NewParser().ParseUnverified(token, &model.JWTClaims{})
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	assert.Equal(t, "2025-07", parsed.Header["kid"])
+
+	claims, err := utils.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-123", claims.UserID)
+
+	// Token lama yang di-sign key sebelumnya (RS256) masih valid
+	old := jwt.NewWithClaims(jwt.SigningMethodRS256, &model.JWTClaims{
+		UserID:    "user-123",
+		TokenType: utils.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	old.Header["kid"] = "2025-01"
+	oldToken, _ := old.SignedString(oldKey)
+
+	_, err = utils.ValidateToken(oldToken)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_RejectsUnknownKidAndHS256(t *testing.T) {
+	dir, _ := setupKeyDir(t)
+
+	ks, err := utils.LoadKeySet(dir, "2025-07")
+	assert.NoError(t, err)
+	utils.SetKeySet(ks)
+
+	claims := &model.JWTClaims{
+		UserID:    "user-123",
+		TokenType: utils.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	// Key lain dengan kid yang tidak dikenal
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	unknown.Header["kid"] = "unknown"
+	unknownToken, _ := unknown.SignedString(otherKey)
+
+	_, err = utils.ValidateToken(unknownToken)
+	assert.Error(t, err)
+
+	// HS256 ditolak kecuali JWT_ACCEPT_HS256 aktif
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("legacy-secret"))
+
+	_, err = utils.ValidateToken(legacyToken)
+	assert.Error(t, err)
+
+	ks.AcceptHS256([]byte("legacy-secret"))
+	_, err = utils.ValidateToken(legacyToken)
+	assert.NoError(t, err)
+}
+
+func TestLoadKeySet_SigningKeyMustHavePrivateKey(t *testing.T) {
+	dir, _ := setupKeyDir(t)
+
+	_, err := utils.LoadKeySet(dir, "2025-01")
+
+	assert.ErrorIs(t, err, utils.ErrNoSigningKey)
+}
+
+// ==================== JWKS ENDPOINT ====================
+
+func TestGetJWKS_PublishesAllVerificationKeys(t *testing.T) {
+	dir, _ := setupKeyDir(t)
+
+	ks, err := utils.LoadKeySet(dir, "")
+	assert.NoError(t, err)
+	utils.SetKeySet(ks)
+
+	app := fiber.New()
+	app.Get("/.well-known/jwks.json", NewJWKSService().GetJWKS)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var set utils.JWKSet
+	json.NewDecoder(resp.Body).Decode(&set)
+
+	assert.Len(t, set.Keys, 2)
+	assert.Equal(t, "2025-01", set.Keys[0].Kid)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+	assert.Equal(t, "2025-07", set.Keys[1].Kid)
+	assert.Equal(t, "OKP", set.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[1].Crv)
+	assert.NotEmpty(t, set.Keys[1].X)
+}
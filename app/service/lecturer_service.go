@@ -15,6 +15,7 @@ type LecturerService struct {
 	lecturerRepo repository.LecturerRepository
 	studentRepo  repository.StudentRepository
 	userRepo     repository.UserRepository
+	authz        *Authorizer
 	validate     *validator.Validate
 }
 
@@ -22,11 +23,13 @@ func NewLecturerService(
 	lecturerRepo repository.LecturerRepository,
 	studentRepo repository.StudentRepository,
 	userRepo repository.UserRepository,
+	authz *Authorizer,
 ) *LecturerService {
 	return &LecturerService{
 		lecturerRepo: lecturerRepo,
 		studentRepo:  studentRepo,
 		userRepo:     userRepo,
+		authz:        authz,
 		validate:     validator.New(),
 	}
 }
@@ -50,8 +53,21 @@ func (s *LecturerService) GetAllLecturers(c *fiber.Ctx) error {
 
 	offset := (page - 1) * pageSize
 
+	// Admin fakultas / prodi hanya melihat dosen di departemennya
+	var departments []string
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	if filter, err := s.authz.Filter(claims, ActionUserManage); err == nil && filter.Scope == ScopeProgramStudy {
+		departments = filter.ProgramStudies
+	}
+
 	// Get lecturers
-	lecturers, err := s.lecturerRepo.GetAll(pageSize, offset)
+	var lecturers []model.Lecturer
+	var err error
+	if departments != nil {
+		lecturers, err = s.lecturerRepo.GetAllByDepartments(departments, pageSize, offset)
+	} else {
+		lecturers, err = s.lecturerRepo.GetAll(pageSize, offset)
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -60,7 +76,12 @@ func (s *LecturerService) GetAllLecturers(c *fiber.Ctx) error {
 	}
 
 	// Count total
-	total, err := s.lecturerRepo.CountAll()
+	var total int
+	if departments != nil {
+		total, err = s.lecturerRepo.CountAllByDepartments(departments)
+	} else {
+		total, err = s.lecturerRepo.CountAll()
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -132,17 +153,13 @@ func (s *LecturerService) GetLecturerAdvisees(c *fiber.Ctx) error {
 		})
 	}
 
-	// Authorization: Dosen hanya bisa lihat advisees sendiri, Admin bisa lihat semua
-	if claims.Role == "Dosen Wali" {
-		currentLecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if currentLecturer == nil || currentLecturer.ID != lecturerID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: can only view your own advisees",
-			})
-		}
+	// Authorization: sesuai policy advisee:read (Dosen Wali: sendiri, Admin: semua)
+	if !s.authz.Can(claims, ActionAdviseeRead, Target{Lecturer: lecturer}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: can only view your own advisees",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Get all students
 	allStudents, err := s.studentRepo.GetAll(1000, 0) // Get all for filtering
@@ -21,7 +21,7 @@ func setupLecturerTest() (*LecturerService, *mocks.MockLecturerRepository, *mock
 	mockStudentRepo := new(mocks.MockStudentRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
 
-	service := NewLecturerService(mockLecturerRepo, mockStudentRepo, mockUserRepo)
+	service := NewLecturerService(mockLecturerRepo, mockStudentRepo, mockUserRepo, NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy))
 
 	return service, mockLecturerRepo, mockStudentRepo, mockUserRepo
 }
@@ -137,7 +137,7 @@ func TestGetLecturerAdvisees_Success_OwnAdvisees(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -204,7 +204,7 @@ func TestGetLecturerAdvisees_Success_Admin(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "admin-user",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -261,7 +261,7 @@ func TestGetLecturerAdvisees_LecturerNotFound(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "user-123",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -287,7 +287,7 @@ func TestGetLecturerAdvisees_Forbidden_NotOwnAdvisees(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -324,7 +324,7 @@ func TestGetLecturerAdvisees_NoAdvisees(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -391,7 +391,7 @@ func TestGetLecturerAdvisees_MultipleAdvisees(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -467,7 +467,7 @@ func TestGetLecturerAdvisees_RepositoryError(t *testing.T) {
 	app.Get("/lecturers/:id/advisees", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetLecturerAdvisees(c)
 	})
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== LOGIN LOCKOUT ======================
+// Menghitung gagal login per username dan per IP client.
+// Setelah melewati threshold, key dikunci sementara dengan durasi
+// yang berlipat dua setiap kali terkunci lagi (exponential backoff).
+// Tingkat backoff baru kembali ke awal setelah LockDecay tanpa gagal login.
+//
+
+type LockoutPolicy struct {
+	UsernameThreshold int           // gagal login per username sebelum dikunci
+	IPThreshold       int           // gagal login per IP sebelum dikunci
+	FailureWindow     time.Duration // counter direset jika tidak ada gagal login selama ini
+	BaseDuration      time.Duration // durasi lock pertama
+	MaxDuration       time.Duration // batas atas durasi lock
+	LockDecay         time.Duration // jumlah lock direset jika tidak ada gagal login selama ini
+}
+
+var DefaultLockoutPolicy = LockoutPolicy{
+	UsernameThreshold: 5,
+	IPThreshold:       20,
+	FailureWindow:     15 * time.Minute,
+	BaseDuration:      time.Minute,
+	MaxDuration:       time.Hour,
+	LockDecay:         24 * time.Hour,
+}
+
+const (
+	lockoutKeyUsername = "username"
+	lockoutKeyIP       = "ip"
+)
+
+type LockoutService struct {
+	lockoutRepo repository.LoginLockoutRepository
+	userRepo    repository.UserRepository
+	audit       *AuditService
+	policy      LockoutPolicy
+	now         func() time.Time
+}
+
+func NewLockoutService(
+	lockoutRepo repository.LoginLockoutRepository,
+	userRepo repository.UserRepository,
+	audit *AuditService,
+	policy LockoutPolicy,
+) *LockoutService {
+	return &LockoutService{
+		lockoutRepo: lockoutRepo,
+		userRepo:    userRepo,
+		audit:       audit,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// LockedUntil - Return waktu akhir lock jika username atau IP sedang terkunci
+func (s *LockoutService) LockedUntil(username, ip string) *time.Time {
+	var until *time.Time
+
+	for _, key := range s.keys(username, ip) {
+		lockout, err := s.lockoutRepo.Get(key[0], key[1])
+		if err != nil || lockout.LockedUntil == nil || !lockout.LockedUntil.After(s.now()) {
+			continue
+		}
+		if until == nil || lockout.LockedUntil.After(*until) {
+			until = lockout.LockedUntil
+		}
+	}
+
+	return until
+}
+
+// RecordFailure - Tambah counter gagal login, kunci jika melewati threshold.
+// Counter ditambah di database (gagal login lama di luar FailureWindow tidak
+// dihitung lagi) supaya tebakan paralel tetap terhitung semua. IP dicatat pada
+// username supaya ikut dibuka saat admin membuka lockout akun.
+func (s *LockoutService) RecordFailure(c *fiber.Ctx, username, ip string) {
+	now := s.now()
+
+	for _, key := range s.keys(username, ip) {
+		failedCount, lockCount, err := s.lockoutRepo.RecordFailure(key[0], key[1], now, now.Add(-s.policy.FailureWindow), now.Add(-s.policy.LockDecay))
+		if err != nil {
+			log.Printf("[LOCKOUT] Failed to record failure %s/%s: %v", key[0], key[1], err)
+			continue
+		}
+		if key[0] == lockoutKeyUsername && ip != "" {
+			if err := s.lockoutRepo.RecordFailedIP(key[1], ip); err != nil {
+				log.Printf("[LOCKOUT] Failed to record IP %s for %s: %v", ip, key[1], err)
+			}
+		}
+		if failedCount < s.threshold(key[0]) {
+			continue
+		}
+
+		lockCount++
+		lockedUntil := now.Add(s.lockDuration(lockCount))
+		locked, err := s.lockoutRepo.Lock(key[0], key[1], lockCount, lockedUntil)
+		if err != nil {
+			log.Printf("[LOCKOUT] Failed to lock %s/%s: %v", key[0], key[1], err)
+			continue
+		}
+		if !locked {
+			continue // sudah dikunci request paralel lain
+		}
+
+		s.audit.Record(c, "auth.lockout", key[0], key[1], map[string]interface{}{
+			"lock_count":   lockCount,
+			"locked_until": lockedUntil.Format("2006-01-02 15:04:05"),
+		})
+	}
+}
+
+// RecordSuccess - Login sukses mereset counter gagal username. Jumlah lock
+// tetap (decay setelah LockDecay) supaya satu login sukses di sela tebakan
+// tidak mengembalikan backoff ke durasi awal.
+// Counter IP sengaja tidak direset supaya tidak bisa di-bypass dengan login ke akun sendiri.
+func (s *LockoutService) RecordSuccess(username string) {
+	if err := s.lockoutRepo.ResetFailures(lockoutKeyUsername, normalizeUsername(username)); err != nil {
+		log.Printf("[LOCKOUT] Failed to reset %s: %v", username, err)
+	}
+}
+
+func (s *LockoutService) keys(username, ip string) [][2]string {
+	keys := [][2]string{{lockoutKeyUsername, normalizeUsername(username)}}
+	if ip != "" {
+		keys = append(keys, [2]string{lockoutKeyIP, ip})
+	}
+	return keys
+}
+
+func (s *LockoutService) threshold(keyType string) int {
+	if keyType == lockoutKeyIP {
+		return s.policy.IPThreshold
+	}
+	return s.policy.UsernameThreshold
+}
+
+// lockDuration - BaseDuration * 2^(lockCount-1), dibatasi MaxDuration
+func (s *LockoutService) lockDuration(lockCount int) time.Duration {
+	duration := s.policy.BaseDuration
+	for i := 1; i < lockCount; i++ {
+		duration *= 2
+		if duration >= s.policy.MaxDuration {
+			return s.policy.MaxDuration
+		}
+	}
+	return duration
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+//
+// ==================== GET USER LOCKOUT (GET /users/:id/lockout) ======================
+// Admin melihat status lockout user
+//
+
+func (s *LockoutService) GetUserLockout(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	response := model.LockoutStatusResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+	}
+
+	lockout, err := s.lockoutRepo.Get(lockoutKeyUsername, normalizeUsername(user.Username))
+	if err == nil {
+		response.FailedCount = lockout.FailedCount
+		response.LockCount = lockout.LockCount
+
+		if lockout.LockedUntil != nil {
+			lockedUntil := lockout.LockedUntil.Format("2006-01-02 15:04:05")
+			response.LockedUntil = &lockedUntil
+			response.Locked = lockout.LockedUntil.After(s.now())
+		}
+		if lockout.LastFailedAt != nil {
+			lastFailedAt := lockout.LastFailedAt.Format("2006-01-02 15:04:05")
+			response.LastFailedAt = &lastFailedAt
+		}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   response,
+	})
+}
+
+//
+// ==================== CLEAR USER LOCKOUT (DELETE /users/:id/lockout) ======================
+// Admin membuka lockout user: lockout akun beserta lockout IP yang pernah
+// gagal login ke akun tsb (IP lain tetap terkunci)
+//
+
+func (s *LockoutService) ClearUserLockout(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	username := normalizeUsername(user.Username)
+	clearedIPs, err := s.lockoutRepo.ClearUsername(username)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to clear lockout",
+		})
+	}
+
+	if err := s.audit.Record(c, "auth.unlock", lockoutKeyUsername, username, map[string]interface{}{
+		"user_id":     user.ID,
+		"cleared_ips": clearedIPs,
+	}); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to write audit log",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "lockout cleared successfully",
+		Data: fiber.Map{
+			"cleared_ips": clearedIPs,
+		},
+	})
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupLockoutTest(now time.Time) (*LockoutService, *mocks.MockLoginLockoutRepository, *mocks.MockAuditLogRepository, *mocks.MockUserRepository) {
+	mockLockoutRepo := new(mocks.MockLoginLockoutRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	service := NewLockoutService(mockLockoutRepo, mockUserRepo, NewAuditService(mockAuditRepo), DefaultLockoutPolicy)
+	service.now = func() time.Time { return now }
+
+	return service, mockLockoutRepo, mockAuditRepo, mockUserRepo
+}
+
+// ==================== LOCK DURATION ====================
+
+func TestLockDuration_ExponentialBackoff(t *testing.T) {
+	service, _, _, _ := setupLockoutTest(time.Now())
+
+	assert.Equal(t, time.Minute, service.lockDuration(1))
+	assert.Equal(t, 2*time.Minute, service.lockDuration(2))
+	assert.Equal(t, 4*time.Minute, service.lockDuration(3))
+	assert.Equal(t, time.Hour, service.lockDuration(20))
+}
+
+// ==================== RECORD FAILURE ====================
+
+func TestRecordFailure_LocksAtThreshold(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, mockAuditRepo, _ := setupLockoutTest(now)
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		service.RecordFailure(c, "Mahasiswa123", "10.0.0.1")
+		return c.SendStatus(401)
+	})
+
+	windowStart := now.Add(-DefaultLockoutPolicy.FailureWindow)
+	decayStart := now.Add(-DefaultLockoutPolicy.LockDecay)
+	mockLockoutRepo.On("RecordFailure", "username", "mahasiswa123", now, windowStart, decayStart).Return(5, 1, nil).Once()
+	mockLockoutRepo.On("RecordFailure", "ip", "10.0.0.1", now, windowStart, decayStart).Return(1, 0, nil).Once()
+	mockLockoutRepo.On("RecordFailedIP", "mahasiswa123", "10.0.0.1").Return(nil).Once()
+	mockLockoutRepo.On("Lock", "username", "mahasiswa123", 2, now.Add(2*time.Minute)).Return(true, nil).Once()
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "auth.lockout" && e.TargetType == "username" && e.TargetID == "mahasiswa123"
+	})).Return(nil).Once()
+
+	app.Test(httptest.NewRequest("POST", "/login", nil))
+
+	mockLockoutRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestRecordFailure_BelowThreshold(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, mockAuditRepo, _ := setupLockoutTest(now)
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		service.RecordFailure(c, "mahasiswa123", "")
+		return c.SendStatus(401)
+	})
+
+	// Counter direset di repository jika gagal login terakhir di luar window
+	mockLockoutRepo.On("RecordFailure", "username", "mahasiswa123", now, now.Add(-DefaultLockoutPolicy.FailureWindow), now.Add(-DefaultLockoutPolicy.LockDecay)).Return(1, 3, nil).Once()
+
+	app.Test(httptest.NewRequest("POST", "/login", nil))
+
+	mockLockoutRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestRecordFailure_ParallelFailuresLockOnce(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, mockAuditRepo, _ := setupLockoutTest(now)
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		service.RecordFailure(c, "mahasiswa123", "")
+		return c.SendStatus(401)
+	})
+
+	// Dua tebakan paralel sama-sama melewati threshold; hanya yang pertama
+	// berhasil mengunci (lock_count sudah berubah untuk request kedua)
+	windowStart := now.Add(-DefaultLockoutPolicy.FailureWindow)
+	decayStart := now.Add(-DefaultLockoutPolicy.LockDecay)
+	mockLockoutRepo.On("RecordFailure", "username", "mahasiswa123", now, windowStart, decayStart).Return(5, 0, nil).Once()
+	mockLockoutRepo.On("RecordFailure", "username", "mahasiswa123", now, windowStart, decayStart).Return(6, 0, nil).Once()
+	mockLockoutRepo.On("Lock", "username", "mahasiswa123", 1, now.Add(time.Minute)).Return(true, nil).Once()
+	mockLockoutRepo.On("Lock", "username", "mahasiswa123", 1, now.Add(time.Minute)).Return(false, nil).Once()
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Once()
+
+	app.Test(httptest.NewRequest("POST", "/login", nil))
+	app.Test(httptest.NewRequest("POST", "/login", nil))
+
+	mockLockoutRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// ==================== RECORD SUCCESS ====================
+
+func TestRecordSuccess_KeepsLockCount(t *testing.T) {
+	service, mockLockoutRepo, _, _ := setupLockoutTest(time.Now())
+	mockLockoutRepo.On("ResetFailures", "username", "mahasiswa123").Return(nil).Once()
+
+	service.RecordSuccess("Mahasiswa123")
+
+	// Lockout tidak dihapus: backoff berikutnya tetap berlipat sampai decay
+	mockLockoutRepo.AssertExpectations(t)
+	mockLockoutRepo.AssertNotCalled(t, "ClearUsername", mock.Anything)
+}
+
+// ==================== LOCKED UNTIL ====================
+
+func TestLockedUntil_ExpiredLockIgnored(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, _, _ := setupLockoutTest(now)
+
+	expired := now.Add(-time.Second)
+	mockLockoutRepo.On("Get", "username", "mahasiswa123").Return(&model.LoginLockout{LockedUntil: &expired}, nil)
+	mockLockoutRepo.On("Get", "ip", "10.0.0.1").Return(nil, sql.ErrNoRows)
+
+	assert.Nil(t, service.LockedUntil("mahasiswa123", "10.0.0.1"))
+}
+
+func TestLockedUntil_IPLocked(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, _, _ := setupLockoutTest(now)
+
+	until := now.Add(5 * time.Minute)
+	mockLockoutRepo.On("Get", "username", "mahasiswa123").Return(nil, sql.ErrNoRows)
+	mockLockoutRepo.On("Get", "ip", "10.0.0.1").Return(&model.LoginLockout{LockedUntil: &until}, nil)
+
+	result := service.LockedUntil("mahasiswa123", "10.0.0.1")
+	assert.NotNil(t, result)
+	assert.True(t, result.Equal(until))
+}
+
+// ==================== ADMIN ENDPOINTS ====================
+
+func TestGetUserLockout_Locked(t *testing.T) {
+	now := time.Now()
+	service, mockLockoutRepo, _, mockUserRepo := setupLockoutTest(now)
+
+	app := fiber.New()
+	app.Get("/users/:id/lockout", service.GetUserLockout)
+
+	until := now.Add(time.Minute)
+	mockUserRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", Username: "mahasiswa123"}, nil)
+	mockLockoutRepo.On("Get", "username", "mahasiswa123").Return(&model.LoginLockout{
+		LockCount:   1,
+		LockedUntil: &until,
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/users/user-123/lockout", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		Data model.LockoutStatusResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.True(t, body.Data.Locked)
+	assert.Equal(t, 1, body.Data.LockCount)
+}
+
+func TestClearUserLockout_Success(t *testing.T) {
+	service, mockLockoutRepo, mockAuditRepo, mockUserRepo := setupLockoutTest(time.Now())
+
+	app := fiber.New()
+	app.Delete("/users/:id/lockout", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1"})
+		return service.ClearUserLockout(c)
+	})
+
+	mockUserRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", Username: "mahasiswa123"}, nil)
+	mockLockoutRepo.On("ClearUsername", "mahasiswa123").Return([]string{"10.0.0.1"}, nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "auth.unlock" && e.ActorID != nil && *e.ActorID == "admin-1" &&
+			strings.Contains(e.Details, "10.0.0.1")
+	})).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/users/user-123/lockout", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Data struct {
+			ClearedIPs []string `json:"cleared_ips"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.Equal(t, []string{"10.0.0.1"}, body.Data.ClearedIPs)
+	mockLockoutRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestClearUserLockout_UserNotFound(t *testing.T) {
+	service, _, _, mockUserRepo := setupLockoutTest(time.Now())
+
+	app := fiber.New()
+	app.Delete("/users/:id/lockout", service.ClearUserLockout)
+
+	mockUserRepo.On("FindByID", "missing").Return(nil, errors.New("not found"))
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/users/missing/lockout", nil))
+
+	assert.Equal(t, 404, resp.StatusCode)
+}
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/utils"
+)
+
+//
+// ==================== MFA (TOTP) ======================
+// Enrollment: setup (secret + otpauth URI) -> confirm (kode pertama) -> recovery codes.
+// Role dengan mfa_required = true wajib MFA; user tanpa MFA di role tersebut
+// harus enrollment dulu lewat token mfa_pending saat login.
+//
+
+const (
+	mfaIssuer         = "Sistem Pelaporan Prestasi"
+	recoveryCodeCount = 10
+)
+
+var (
+	errMFAAlreadyEnabled = errors.New("mfa already enabled")
+	errMFANotEnrolled    = errors.New("mfa not enrolled")
+	errInvalidMFACode    = errors.New("invalid mfa code")
+)
+
+type MFAService struct {
+	mfaRepo  repository.MFARepository
+	userRepo repository.UserRepository
+	roleRepo repository.RoleRepository
+	audit    *AuditService
+	validate *validator.Validate
+	now      func() time.Time
+}
+
+func NewMFAService(
+	mfaRepo repository.MFARepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	audit *AuditService,
+) *MFAService {
+	return &MFAService{
+		mfaRepo:  mfaRepo,
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		audit:    audit,
+		validate: validator.New(),
+		now:      time.Now,
+	}
+}
+
+//
+// ==================== MFA STATUS (GET /auth/mfa) ======================
+//
+
+func (s *MFAService) GetStatus(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	response := model.MFAStatusResponse{}
+	if roles, err := s.userRepo.GetRoles(user.ID); err == nil {
+		response.Required = mfaRequired(roles)
+	}
+
+	if mfa, err := s.mfaRepo.FindByUserID(user.ID); err == nil && mfa.Enabled {
+		response.Enabled = true
+		if mfa.EnabledAt != nil {
+			enabledAt := mfa.EnabledAt.Format("2006-01-02 15:04:05")
+			response.EnabledAt = &enabledAt
+		}
+		response.RemainingRecoveryCodes, _ = s.mfaRepo.CountRecoveryCodes(user.ID)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   response,
+	})
+}
+
+//
+// ==================== MFA SETUP (POST /auth/mfa/setup) ======================
+// User yang sudah login memulai enrollment
+//
+
+func (s *MFAService) Setup(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+	return s.respondSetup(c, claims.UserID)
+}
+
+//
+// ==================== MFA ENROLL (POST /auth/mfa/enroll) ======================
+// Enrollment saat login untuk role yang wajib MFA (pakai token mfa_pending)
+//
+
+func (s *MFAService) Enroll(c *fiber.Ctx) error {
+	req := new(model.MFAEnrollRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	claims, err := utils.ValidateMFAToken(req.MFAToken)
+	if err != nil || !claims.EnrollmentRequired {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid mfa token",
+		})
+	}
+
+	return s.respondSetup(c, claims.UserID)
+}
+
+//
+// ==================== MFA CONFIRM (POST /auth/mfa/confirm) ======================
+// Kode pertama mengaktifkan MFA dan menghasilkan recovery codes
+//
+
+func (s *MFAService) Confirm(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.MFACodeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	codes, err := s.confirmEnrollment(c, claims.UserID, req.Code)
+	if err != nil {
+		return s.mfaError(c, err)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "mfa enabled, store the recovery codes in a safe place",
+		Data:    model.MFARecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+//
+// ==================== MFA DISABLE (POST /auth/mfa/disable) ======================
+// Butuh password + kode; tidak bisa jika role wajib MFA
+//
+
+func (s *MFAService) Disable(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.MFADisableRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if roles, err := s.userRepo.GetRoles(user.ID); err == nil && mfaRequired(roles) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "mfa is required for your role",
+		})
+	}
+
+	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "password is incorrect",
+		})
+	}
+
+	if err := s.verifyCode(user.ID, req.Code); err != nil {
+		return s.mfaError(c, err)
+	}
+
+	if err := s.mfaRepo.Delete(user.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to disable mfa",
+		})
+	}
+
+	s.audit.Record(c, "mfa.disable", "user", user.ID, nil)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "mfa disabled successfully",
+	})
+}
+
+//
+// ==================== REGENERATE RECOVERY CODES (POST /auth/mfa/recovery-codes) ======================
+// Recovery code lama tidak berlaku lagi
+//
+
+func (s *MFAService) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.MFACodeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if err := s.verifyCode(claims.UserID, req.Code); err != nil {
+		return s.mfaError(c, err)
+	}
+
+	codes, err := s.generateRecoveryCodes(claims.UserID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate recovery codes",
+		})
+	}
+
+	s.audit.Record(c, "mfa.recovery_codes", "user", claims.UserID, nil)
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   model.MFARecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+//
+// ==================== RESET USER MFA (DELETE /users/:id/mfa) ======================
+// Admin menghapus MFA user (mis. HP hilang dan recovery code habis)
+//
+
+func (s *MFAService) ResetUserMFA(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if err := s.mfaRepo.Delete(userID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to reset mfa",
+		})
+	}
+
+	s.audit.Record(c, "mfa.reset", "user", userID, nil)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "mfa reset successfully",
+	})
+}
+
+//
+// ==================== MFA POLICIES (GET /mfa/policies) ======================
+//
+
+func (s *MFAService) GetPolicies(c *fiber.Ctx) error {
+	roles, err := s.roleRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get roles",
+		})
+	}
+
+	policies := make([]model.MFAPolicyResponse, 0, len(roles))
+	for _, role := range roles {
+		policies = append(policies, model.MFAPolicyResponse{
+			RoleID:      role.ID,
+			RoleName:    role.Name,
+			MFARequired: role.MFARequired,
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   policies,
+	})
+}
+
+//
+// ==================== UPDATE MFA POLICY (PUT /mfa/policies/:roleId) ======================
+//
+
+func (s *MFAService) UpdatePolicy(c *fiber.Ctx) error {
+	roleID := c.Params("roleId")
+
+	req := new(model.MFAPolicyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	role, err := s.roleRepo.GetRoleByID(roleID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	if err := s.roleRepo.SetMFARequired(role.ID, *req.MFARequired); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update mfa policy",
+		})
+	}
+
+	s.audit.Record(c, "mfa.policy", "role", role.ID, map[string]interface{}{
+		"role_name":    role.Name,
+		"mfa_required": *req.MFARequired,
+	})
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.MFAPolicyResponse{
+			RoleID:      role.ID,
+			RoleName:    role.Name,
+			MFARequired: *req.MFARequired,
+		},
+	})
+}
+
+//
+// ==================== HELPER (dipakai juga oleh AuthService) ======================
+//
+
+// loginChallenge - Return challenge jika user harus memasukkan kode MFA, nil jika tidak perlu.
+// roles = role sesi; selectedRole dibawa di token mfa_pending sampai login selesai.
+func (s *MFAService) loginChallenge(user *model.User, roles []model.Role, selectedRole string) (*model.MFAChallengeResponse, error) {
+	mfa, err := s.mfaRepo.FindByUserID(user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	enabled := mfa != nil && mfa.Enabled
+	if !enabled && !mfaRequired(roles) {
+		return nil, nil
+	}
+
+	token, err := utils.GenerateMFAToken(user.ID, user.Username, selectedRole, !enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFAChallengeResponse{
+		MFARequired:        true,
+		MFAToken:           token,
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+// mfaRequired - MFA wajib jika salah satu role mewajibkan
+func mfaRequired(roles []model.Role) bool {
+	for _, role := range roles {
+		if role.MFARequired {
+			return true
+		}
+	}
+	return false
+}
+
+// completeLogin - Cek kode untuk token mfa_pending.
+// Jika enrollment belum dikonfirmasi, kode pertama mengaktifkan MFA dan recovery codes dikembalikan.
+func (s *MFAService) completeLogin(c *fiber.Ctx, userID, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errMFANotEnrolled
+	}
+
+	if !mfa.Enabled {
+		return s.confirmEnrollment(c, userID, code)
+	}
+
+	return nil, s.verifyCode(userID, code)
+}
+
+func (s *MFAService) respondSetup(c *fiber.Ctx, userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if mfa, err := s.mfaRepo.FindByUserID(user.ID); err == nil && mfa.Enabled {
+		return s.mfaError(c, errMFAAlreadyEnabled)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate mfa secret",
+		})
+	}
+
+	if err := s.mfaRepo.SaveSecret(user.ID, secret); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to save mfa secret",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.MFASetupResponse{
+			Secret:          secret,
+			ProvisioningURI: utils.TOTPProvisioningURI(mfaIssuer, user.Username, secret),
+		},
+	})
+}
+
+func (s *MFAService) confirmEnrollment(c *fiber.Ctx, userID, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errMFANotEnrolled
+	}
+	if mfa.Enabled {
+		return nil, errMFAAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, code, s.now())
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+
+	if err := s.mfaRepo.Enable(userID, step); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(c, "mfa.enable", "user", userID, nil)
+	return codes, nil
+}
+
+// verifyCode - Terima kode TOTP (sekali pakai per periode) atau recovery code
+func (s *MFAService) verifyCode(userID, code string) error {
+	mfa, err := s.mfaRepo.FindByUserID(userID)
+	if err != nil || !mfa.Enabled {
+		return errMFANotEnrolled
+	}
+
+	if step, ok := utils.ValidateTOTP(mfa.Secret, code, s.now()); ok {
+		used, err := s.mfaRepo.MarkStepUsed(userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) generateRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.GenerateRandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// mfaError - Mapping error MFA ke response HTTP
+func (s *MFAService) mfaError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errInvalidMFACode):
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid mfa code",
+		})
+	case errors.Is(err, errMFANotEnrolled):
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "mfa is not enrolled",
+		})
+	case errors.Is(err, errMFAAlreadyEnabled):
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "mfa already enabled",
+		})
+	default:
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to verify mfa code",
+		})
+	}
+}
+
+// normalizeRecoveryCode - Recovery code tidak case-sensitive dan boleh tanpa "-"
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+	"project_uas/utils"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupMFATest() (*MFAService, *mocks.MockMFARepository, *mocks.MockUserRepository, *mocks.MockRoleRepository, *mocks.MockAuditLogRepository) {
+	mockMFARepo := new(mocks.MockMFARepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	useTestJWTKeys()
+
+	service := NewMFAService(mockMFARepo, mockUserRepo, mockRoleRepo, NewAuditService(mockAuditRepo))
+
+	return service, mockMFARepo, mockUserRepo, mockRoleRepo, mockAuditRepo
+}
+
+func withUser(userID string, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: userID})
+		return handler(c)
+	}
+}
+
+// ==================== TOTP ====================
+
+// Test vector RFC 6238 Appendix B (SHA1)
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1234567890:  "005924",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := utils.TOTPCode(secret, utils.TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+
+	// Toleransi ±1 periode
+	_, ok := utils.ValidateTOTP(secret, "287082", time.Unix(59+30, 0))
+	assert.True(t, ok)
+	_, ok = utils.ValidateTOTP(secret, "287082", time.Unix(59+90, 0))
+	assert.False(t, ok)
+}
+
+// ==================== SETUP & CONFIRM ====================
+
+func TestMFASetup_ReturnsProvisioningURI(t *testing.T) {
+	service, mockMFARepo, mockUserRepo, _, _ := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/setup", withUser("user-123", service.Setup))
+
+	mockUserRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", Username: "dosen1"}, nil)
+	mockMFARepo.On("FindByUserID", "user-123").Return(nil, assert.AnError)
+	mockMFARepo.On("SaveSecret", "user-123", mock.AnythingOfType("string")).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/mfa/setup", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.MFASetupResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.NotEmpty(t, result.Data.Secret)
+	assert.True(t, strings.HasPrefix(result.Data.ProvisioningURI, "otpauth://totp/"))
+	assert.Contains(t, result.Data.ProvisioningURI, "secret="+result.Data.Secret)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestMFASetup_AlreadyEnabled(t *testing.T) {
+	service, mockMFARepo, mockUserRepo, _, _ := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/setup", withUser("user-123", service.Setup))
+
+	mockUserRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", Username: "dosen1"}, nil)
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Enabled: true}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/mfa/setup", nil))
+
+	assert.Equal(t, 409, resp.StatusCode)
+	mockMFARepo.AssertNotCalled(t, "SaveSecret", mock.Anything, mock.Anything)
+}
+
+func TestMFAConfirm_EnablesAndReturnsRecoveryCodes(t *testing.T) {
+	service, mockMFARepo, _, _, mockAuditRepo := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/confirm", withUser("user-123", service.Confirm))
+
+	secret, _ := utils.GenerateTOTPSecret()
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+
+	var storedHashes []string
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Secret: secret}, nil)
+	mockMFARepo.On("Enable", "user-123", mock.AnythingOfType("int64")).Return(nil)
+	mockMFARepo.On("ReplaceRecoveryCodes", "user-123", mock.AnythingOfType("[]string")).
+		Run(func(args mock.Arguments) { storedHashes = args.Get(1).([]string) }).
+		Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "mfa.enable"
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/mfa/confirm", strings.NewReader(`{"code": "`+code+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.MFARecoveryCodesResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(t, result.Data.RecoveryCodes, 10)
+
+	// Yang disimpan hanya hash
+	assert.Equal(t, utils.HashToken(normalizeRecoveryCode(result.Data.RecoveryCodes[0])), storedHashes[0])
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestMFAConfirm_InvalidCode(t *testing.T) {
+	service, mockMFARepo, _, _, _ := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/confirm", withUser("user-123", service.Confirm))
+
+	secret, _ := utils.GenerateTOTPSecret()
+	wrongCode, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now())+10)
+
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Secret: secret}, nil)
+
+	req := httptest.NewRequest("POST", "/mfa/confirm", strings.NewReader(`{"code": "`+wrongCode+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	mockMFARepo.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything)
+}
+
+// ==================== VERIFY CODE ====================
+
+func TestMFAVerifyCode_ReplayRejected(t *testing.T) {
+	service, mockMFARepo, _, _, _ := setupMFATest()
+
+	secret, _ := utils.GenerateTOTPSecret()
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Secret: secret, Enabled: true}, nil)
+	mockMFARepo.On("MarkStepUsed", "user-123", mock.AnythingOfType("int64")).Return(false, nil)
+
+	err := service.verifyCode("user-123", code)
+
+	assert.ErrorIs(t, err, errInvalidMFACode)
+	mockMFARepo.AssertNotCalled(t, "UseRecoveryCode", mock.Anything, mock.Anything)
+}
+
+func TestMFAVerifyCode_RecoveryCode(t *testing.T) {
+	service, mockMFARepo, _, _, _ := setupMFATest()
+
+	secret, _ := utils.GenerateTOTPSecret()
+
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Secret: secret, Enabled: true}, nil)
+	mockMFARepo.On("UseRecoveryCode", "user-123", utils.HashToken("abcde12345")).Return(true, nil)
+
+	// Case-insensitive dan "-" boleh dihilangkan
+	err := service.verifyCode("user-123", "ABCDE-12345")
+
+	assert.NoError(t, err)
+	mockMFARepo.AssertExpectations(t)
+}
+
+// ==================== DISABLE ====================
+
+func TestMFADisable_BlockedByRolePolicy(t *testing.T) {
+	service, mockMFARepo, mockUserRepo, _, _ := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/disable", withUser("admin-1", service.Disable))
+
+	mockUserRepo.On("FindByID", "admin-1").Return(&model.User{ID: "admin-1"}, nil)
+	mockUserRepo.On("GetRoles", "admin-1").Return([]model.Role{{ID: "role-admin", Name: "Admin", MFARequired: true}}, nil)
+
+	req := httptest.NewRequest("POST", "/mfa/disable", strings.NewReader(`{"password": "password123", "code": "123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+	mockMFARepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestMFADisable_Success(t *testing.T) {
+	service, mockMFARepo, mockUserRepo, _, mockAuditRepo := setupMFATest()
+
+	app := fiber.New()
+	app.Post("/mfa/disable", withUser("user-123", service.Disable))
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	secret, _ := utils.GenerateTOTPSecret()
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+
+	mockUserRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", PasswordHash: hashedPassword}, nil)
+	mockUserRepo.On("GetRoles", "user-123").Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+	mockMFARepo.On("FindByUserID", "user-123").Return(&model.UserMFA{UserID: "user-123", Secret: secret, Enabled: true}, nil)
+	mockMFARepo.On("MarkStepUsed", "user-123", mock.AnythingOfType("int64")).Return(true, nil)
+	mockMFARepo.On("Delete", "user-123").Return(nil)
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil)
+
+	req := httptest.NewRequest("POST", "/mfa/disable", strings.NewReader(`{"password": "password123", "code": "`+code+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockMFARepo.AssertExpectations(t)
+}
+
+// ==================== POLICY ====================
+
+func TestMFAUpdatePolicy_Success(t *testing.T) {
+	service, _, _, mockRoleRepo, mockAuditRepo := setupMFATest()
+
+	app := fiber.New()
+	app.Put("/mfa/policies/:roleId", withUser("admin-1", service.UpdatePolicy))
+
+	mockRoleRepo.On("GetRoleByID", "role-mhs").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	mockRoleRepo.On("SetMFARequired", "role-mhs", true).Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "mfa.policy" && e.TargetID == "role-mhs" && *e.ActorID == "admin-1"
+	})).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/mfa/policies/role-mhs", strings.NewReader(`{"mfa_required": true}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockRoleRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestMFAUpdatePolicy_MissingField(t *testing.T) {
+	service, _, _, _, _ := setupMFATest()
+
+	app := fiber.New()
+	app.Put("/mfa/policies/:roleId", withUser("admin-1", service.UpdatePolicy))
+
+	req := httptest.NewRequest("PUT", "/mfa/policies/role-mhs", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 422, resp.StatusCode)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/utils"
+)
+
+// Ukuran token reset (byte acak, dikirim dalam bentuk hex)
+const passwordResetTokenSize = 32
+
+type PasswordService struct {
+	userRepo    repository.UserRepository
+	resetRepo   repository.PasswordResetRepository
+	revocations *TokenRevocationService
+	mailer      utils.Mailer
+	baseURL     string
+	resetTTL    time.Duration
+	validate    *validator.Validate
+	now         func() time.Time
+}
+
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	revocations *TokenRevocationService,
+	mailer utils.Mailer,
+	baseURL string,
+	resetTTL time.Duration,
+) *PasswordService {
+	return &PasswordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		revocations: revocations,
+		mailer:      mailer,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		resetTTL:    resetTTL,
+		validate:    validator.New(),
+		now:         time.Now,
+	}
+}
+
+//
+// ==================== CHANGE PASSWORD (POST /auth/password/change) ======================
+// User mengganti password sendiri (wajib password lama)
+//
+
+func (s *PasswordService) ChangePassword(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.ChangePasswordRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, user.PasswordHash) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "current password is incorrect",
+		})
+	}
+
+	if req.CurrentPassword == req.NewPassword {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "new password must be different from current password",
+		})
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword, "password changed"); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to change password",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "password changed successfully, please login again",
+	})
+}
+
+//
+// ==================== FORGOT PASSWORD (POST /auth/password/forgot) ======================
+// Selalu return 200 supaya tidak bisa dipakai untuk mengecek email terdaftar
+//
+
+func (s *PasswordService) ForgotPassword(c *fiber.Ctx) error {
+	req := new(model.ForgotPasswordRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	response := model.APIResponse{
+		Status:  "success",
+		Message: "if the email is registered, a password reset link has been sent",
+	}
+
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil || !user.IsActive {
+		return c.JSON(response)
+	}
+
+	if err := s.sendResetToken(user); err != nil {
+		log.Printf("[PASSWORD] Failed to send reset token to user %s: %v", user.ID, err)
+	}
+
+	return c.JSON(response)
+}
+
+//
+// ==================== RESET PASSWORD (POST /auth/password/reset) ======================
+// Token hanya bisa dipakai sekali dan punya masa berlaku
+//
+
+func (s *PasswordService) ResetPassword(c *fiber.Ctx) error {
+	req := new(model.ResetPasswordRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	invalidToken := model.APIResponse{
+		Status: "error",
+		Error:  "invalid or expired reset token",
+	}
+
+	token, err := s.resetRepo.FindByHash(utils.HashToken(req.Token))
+	if err != nil || token.UsedAt != nil || !token.ExpiresAt.After(s.now()) {
+		return c.Status(400).JSON(invalidToken)
+	}
+
+	// Tandai dipakai dulu (atomic) supaya request paralel dengan token yang sama ditolak
+	ok, err := s.resetRepo.MarkUsed(token.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to reset password",
+		})
+	}
+	if !ok {
+		return c.Status(400).JSON(invalidToken)
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil || !user.IsActive {
+		return c.Status(400).JSON(invalidToken)
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword, "password reset"); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to reset password",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "password reset successfully, please login with your new password",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// setPassword - Simpan password baru, batalkan token reset lain, dan logout semua sesi
+func (s *PasswordService) setPassword(userID, newPassword, reason string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.resetRepo.InvalidateByUserID(userID); err != nil {
+		log.Printf("[PASSWORD] Failed to invalidate reset tokens for user %s: %v", userID, err)
+	}
+
+	return s.revocations.RevokeUserSessions(userID, reason)
+}
+
+// sendResetToken - Buat token reset baru (token lama dibatalkan) lalu kirim via mailer
+func (s *PasswordService) sendResetToken(user *model.User) error {
+	rawToken, err := utils.GenerateRandomToken(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+		return err
+	}
+
+	token := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: s.now().Add(s.resetTTL),
+	}
+	if err := s.resetRepo.Create(token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.baseURL, url.QueryEscape(rawToken))
+	body := fmt.Sprintf(
+		"Halo %s,\n\n"+
+			"Kami menerima permintaan reset password untuk akun %s.\n"+
+			"Buka link berikut untuk membuat password baru (berlaku %s):\n\n%s\n\n"+
+			"Token: %s\n\n"+
+			"Abaikan email ini jika Anda tidak meminta reset password.\n",
+		user.FullName, user.Username, s.resetTTL, link, rawToken,
+	)
+
+	return s.mailer.Send(utils.Mail{
+		To:      user.Email,
+		Subject: "Reset password",
+		Body:    body,
+	})
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+	"project_uas/utils"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+type passwordTestDeps struct {
+	userRepo       *mocks.MockUserRepository
+	resetRepo      *mocks.MockPasswordResetRepository
+	refreshRepo    *mocks.MockRefreshTokenRepository
+	revocationRepo *mocks.MockTokenRevocationRepository
+	outboxDir      string
+}
+
+// Hashing bcrypt (cost 12) bisa lebih lama dari timeout default app.Test,
+// jadi semua test di file ini memakai app.Test(req, -1)
+func setupPasswordTest(t *testing.T) (*PasswordService, passwordTestDeps) {
+	deps := passwordTestDeps{
+		userRepo:       new(mocks.MockUserRepository),
+		resetRepo:      new(mocks.MockPasswordResetRepository),
+		refreshRepo:    new(mocks.MockRefreshTokenRepository),
+		revocationRepo: new(mocks.MockTokenRevocationRepository),
+		outboxDir:      t.TempDir(),
+	}
+
+	mailer, err := utils.NewFileOutboxMailer(deps.outboxDir)
+	assert.NoError(t, err)
+
+	revocations := NewTokenRevocationService(deps.revocationRepo, deps.refreshRepo)
+	service := NewPasswordService(deps.userRepo, deps.resetRepo, revocations, mailer, "http://localhost:3000/", 30*time.Minute)
+
+	return service, deps
+}
+
+// expectSessionsRevoked - Semua sesi user harus di-revoke setelah password diganti
+func expectSessionsRevoked(deps passwordTestDeps, userID string) {
+	deps.resetRepo.On("InvalidateByUserID", userID).Return(nil)
+	deps.refreshRepo.On("RevokeByUserID", userID).Return(nil)
+	deps.revocationRepo.On("Create", mock.MatchedBy(func(rev *model.TokenRevocation) bool {
+		return rev.Kind == "user" && *rev.UserID == userID
+	})).Return(nil)
+}
+
+func readOutbox(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+
+	var mails []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		mails = append(mails, string(content))
+	}
+	return mails
+}
+
+// ==================== CHANGE PASSWORD ====================
+
+func TestChangePassword_Success(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/change", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123"})
+		return service.ChangePassword(c)
+	})
+
+	hashedPassword, _ := utils.HashPassword("oldpassword")
+	deps.userRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", PasswordHash: hashedPassword, IsActive: true}, nil)
+	deps.userRepo.On("UpdatePassword", "user-123", mock.MatchedBy(func(hash string) bool {
+		return utils.CheckPasswordHash("newpassword123", hash)
+	})).Return(nil)
+	expectSessionsRevoked(deps, "user-123")
+
+	body := `{"current_password": "oldpassword", "new_password": "newpassword123"}`
+	req := httptest.NewRequest("POST", "/password/change", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	deps.userRepo.AssertExpectations(t)
+	deps.resetRepo.AssertExpectations(t)
+	deps.refreshRepo.AssertExpectations(t)
+	deps.revocationRepo.AssertExpectations(t)
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/change", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123"})
+		return service.ChangePassword(c)
+	})
+
+	hashedPassword, _ := utils.HashPassword("oldpassword")
+	deps.userRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", PasswordHash: hashedPassword}, nil)
+
+	body := `{"current_password": "wrongpassword", "new_password": "newpassword123"}`
+	req := httptest.NewRequest("POST", "/password/change", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	deps.userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_TooShort(t *testing.T) {
+	service, _ := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/change", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123"})
+		return service.ChangePassword(c)
+	})
+
+	body := `{"current_password": "oldpassword", "new_password": "short"}`
+	req := httptest.NewRequest("POST", "/password/change", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 422, resp.StatusCode)
+}
+
+// ==================== FORGOT PASSWORD ====================
+
+func TestForgotPassword_SendsResetEmail(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/forgot", service.ForgotPassword)
+
+	user := &model.User{ID: "user-123", Username: "mahasiswa123", Email: "mahasiswa@test.com", IsActive: true}
+	deps.userRepo.On("FindByEmail", "mahasiswa@test.com").Return(user, nil)
+	deps.resetRepo.On("InvalidateByUserID", "user-123").Return(nil)
+
+	var stored *model.PasswordResetToken
+	deps.resetRepo.On("Create", mock.AnythingOfType("*model.PasswordResetToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*model.PasswordResetToken) }).
+		Return(nil)
+
+	body := `{"email": "mahasiswa@test.com"}`
+	req := httptest.NewRequest("POST", "/password/forgot", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	mails := readOutbox(t, deps.outboxDir)
+	assert.Len(t, mails, 1)
+	assert.Contains(t, mails[0], "To: mahasiswa@test.com")
+	assert.Contains(t, mails[0], "http://localhost:3000/reset-password?token=")
+
+	// Yang disimpan hanya hash dari token di email
+	token := regexp.MustCompile(`token=([0-9a-f]+)`).FindStringSubmatch(mails[0])[1]
+	assert.Equal(t, utils.HashToken(token), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, token)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), stored.ExpiresAt, time.Minute)
+}
+
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/forgot", service.ForgotPassword)
+
+	deps.userRepo.On("FindByEmail", "unknown@test.com").Return(nil, errors.New("not found"))
+
+	body := `{"email": "unknown@test.com"}`
+	req := httptest.NewRequest("POST", "/password/forgot", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	// Response sama dengan email terdaftar
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, readOutbox(t, deps.outboxDir))
+	deps.resetRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// ==================== RESET PASSWORD ====================
+
+func TestResetPassword_Success(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/reset", service.ResetPassword)
+
+	deps.resetRepo.On("FindByHash", utils.HashToken("valid-token")).Return(&model.PasswordResetToken{
+		ID:        "reset-1",
+		UserID:    "user-123",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+	deps.resetRepo.On("MarkUsed", "reset-1").Return(true, nil)
+	deps.userRepo.On("FindByID", "user-123").Return(&model.User{ID: "user-123", IsActive: true}, nil)
+	deps.userRepo.On("UpdatePassword", "user-123", mock.AnythingOfType("string")).Return(nil)
+	expectSessionsRevoked(deps, "user-123")
+
+	body := `{"token": "valid-token", "new_password": "newpassword123"}`
+	req := httptest.NewRequest("POST", "/password/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	deps.resetRepo.AssertExpectations(t)
+	deps.userRepo.AssertExpectations(t)
+	deps.refreshRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/reset", service.ResetPassword)
+
+	deps.resetRepo.On("FindByHash", utils.HashToken("expired-token")).Return(&model.PasswordResetToken{
+		ID:        "reset-1",
+		UserID:    "user-123",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	body := `{"token": "expired-token", "new_password": "newpassword123"}`
+	req := httptest.NewRequest("POST", "/password/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 400, resp.StatusCode)
+	deps.resetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything)
+}
+
+func TestResetPassword_TokenAlreadyUsed(t *testing.T) {
+	service, deps := setupPasswordTest(t)
+
+	app := fiber.New()
+	app.Post("/password/reset", service.ResetPassword)
+
+	// Request lain sudah memakai token ini lebih dulu
+	deps.resetRepo.On("FindByHash", utils.HashToken("used-token")).Return(&model.PasswordResetToken{
+		ID:        "reset-1",
+		UserID:    "user-123",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+	deps.resetRepo.On("MarkUsed", "reset-1").Return(false, nil)
+
+	body := `{"token": "used-token", "new_password": "newpassword123"}`
+	req := httptest.NewRequest("POST", "/password/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, 400, resp.StatusCode)
+	deps.userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
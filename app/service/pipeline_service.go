@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== VERIFICATION PIPELINE CONFIG ======================
+// Admin mengatur urutan stage verifikasi per jenis prestasi dan/atau tingkat
+// kompetisi. Pipeline yang sedang dipakai achievement berstatus submitted
+// tidak boleh diubah / dihapus supaya posisi stage-nya tidak bergeser.
+//
+
+type PipelineService struct {
+	pipelineRepo repository.PipelineRepository
+	authz        *Authorizer
+	audit        *AuditService
+	validate     *validator.Validate
+}
+
+func NewPipelineService(
+	pipelineRepo repository.PipelineRepository,
+	authz *Authorizer,
+	audit *AuditService,
+) *PipelineService {
+	return &PipelineService{
+		pipelineRepo: pipelineRepo,
+		authz:        authz,
+		audit:        audit,
+		validate:     validator.New(),
+	}
+}
+
+//
+// ==================== GET PIPELINES (GET /pipelines) ======================
+//
+
+func (s *PipelineService) GetPipelines(c *fiber.Ctx) error {
+	pipelines, err := s.pipelineRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch pipelines",
+		})
+	}
+	if pipelines == nil {
+		pipelines = []model.VerificationPipeline{}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"pipelines":      pipelines,
+			"total":          len(pipelines),
+			"default_stages": defaultStages,
+		},
+	})
+}
+
+//
+// ==================== CREATE PIPELINE (POST /pipelines) ======================
+//
+
+func (s *PipelineService) CreatePipeline(c *fiber.Ctx) error {
+	pipeline, err := s.parsePipeline(c, "")
+	if pipeline == nil {
+		return err
+	}
+
+	if err := s.pipelineRepo.Create(pipeline); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create pipeline",
+		})
+	}
+
+	s.audit.Record(c, "pipeline.create", "verification_pipeline", pipeline.ID, pipelineAuditDetails(pipeline))
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "pipeline created successfully",
+		Data:    pipeline,
+	})
+}
+
+//
+// ==================== UPDATE PIPELINE (PUT /pipelines/:id) ======================
+// Stage diganti seluruhnya sesuai urutan di request
+//
+
+func (s *PipelineService) UpdatePipeline(c *fiber.Ctx) error {
+	existing, err := s.pipelineRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "pipeline not found",
+		})
+	}
+	if sent, err := s.rejectInFlight(c, existing.ID); sent {
+		return err
+	}
+
+	pipeline, err := s.parsePipeline(c, existing.ID)
+	if pipeline == nil {
+		return err
+	}
+	pipeline.ID = existing.ID
+	pipeline.CreatedAt = existing.CreatedAt
+
+	if err := s.pipelineRepo.Update(pipeline); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update pipeline",
+		})
+	}
+
+	s.audit.Record(c, "pipeline.update", "verification_pipeline", pipeline.ID, pipelineAuditDetails(pipeline))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "pipeline updated successfully",
+		Data:    pipeline,
+	})
+}
+
+//
+// ==================== DELETE PIPELINE (DELETE /pipelines/:id) ======================
+//
+
+func (s *PipelineService) DeletePipeline(c *fiber.Ctx) error {
+	pipeline, err := s.pipelineRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "pipeline not found",
+		})
+	}
+	if sent, err := s.rejectInFlight(c, pipeline.ID); sent {
+		return err
+	}
+
+	if err := s.pipelineRepo.Delete(pipeline.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete pipeline",
+		})
+	}
+
+	s.audit.Record(c, "pipeline.delete", "verification_pipeline", pipeline.ID, pipelineAuditDetails(pipeline))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "pipeline deleted successfully",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// parsePipeline - Parse & validasi request. Return nil pipeline jika response
+// error sudah dikirim. excludeID = pipeline yang sedang diupdate.
+func (s *PipelineService) parsePipeline(c *fiber.Ctx, excludeID string) (*model.VerificationPipeline, error) {
+	req := new(model.PipelineRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	pipeline := &model.VerificationPipeline{
+		Name:             req.Name,
+		AchievementType:  optionalValue(req.AchievementType),
+		CompetitionLevel: optionalValue(req.CompetitionLevel),
+	}
+	for _, stage := range req.Stages {
+		// Role stage harus punya hak verifikasi di policy
+		if !s.authz.RoleCan(stage.Role, ActionAchievementVerify) {
+			return nil, c.Status(422).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("role '%s' cannot verify achievements", stage.Role),
+			})
+		}
+		pipeline.Stages = append(pipeline.Stages, model.PipelineStage{
+			Name:         stage.Name,
+			Role:         stage.Role,
+			ProgramStudy: optionalValue(stage.ProgramStudy),
+		})
+	}
+
+	// Satu pipeline per kombinasi jenis prestasi & tingkat kompetisi
+	pipelines, err := s.pipelineRepo.GetAll()
+	if err != nil {
+		return nil, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch pipelines",
+		})
+	}
+	for _, other := range pipelines {
+		if other.ID != excludeID && sameValue(other.AchievementType, pipeline.AchievementType) && sameValue(other.CompetitionLevel, pipeline.CompetitionLevel) {
+			return nil, c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("pipeline '%s' already covers this achievement type and competition level", other.Name),
+			})
+		}
+	}
+
+	return pipeline, nil
+}
+
+// rejectInFlight - 409 jika pipeline sedang dipakai achievement submitted.
+// sent = true jika response sudah dikirim.
+func (s *PipelineService) rejectInFlight(c *fiber.Ctx, pipelineID string) (bool, error) {
+	count, err := s.pipelineRepo.CountInFlight(pipelineID)
+	if err != nil {
+		return true, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to check pipeline usage",
+		})
+	}
+	if count > 0 {
+		return true, c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("pipeline is in use by %d achievement(s) awaiting verification", count),
+		})
+	}
+	return false, nil
+}
+
+func pipelineAuditDetails(pipeline *model.VerificationPipeline) map[string]interface{} {
+	roles := []string{}
+	for _, stage := range pipeline.Stages {
+		roles = append(roles, stage.Role)
+	}
+	return map[string]interface{}{
+		"name":              pipeline.Name,
+		"achievement_type":  pipeline.AchievementType,
+		"competition_level": pipeline.CompetitionLevel,
+		"stages":            roles,
+	}
+}
+
+// optionalValue - nil / "" jadi nil
+func optionalValue(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	return optionalString(*value)
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
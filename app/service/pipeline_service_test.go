@@ -0,0 +1,137 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupPipelineTest() (*PipelineService, *mocks.MockPipelineRepository, *mocks.MockAuditLogRepository) {
+	mockPipelineRepo := new(mocks.MockPipelineRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	authz := NewAuthorizer(new(mocks.MockStudentRepository), new(mocks.MockLecturerRepository), noDelegations(), DefaultPolicy)
+	service := NewPipelineService(mockPipelineRepo, authz, NewAuditService(mockAuditRepo))
+
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+	return service, mockPipelineRepo, mockAuditRepo
+}
+
+// ==================== CREATE PIPELINE ====================
+
+func TestCreatePipeline(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			"dosen wali lalu admin fakultas",
+			`{"name": "Internasional", "achievement_type": "competition", "competition_level": "international",
+			  "stages": [{"name": "Dosen Wali", "role": "Dosen Wali"}, {"name": "Kemahasiswaan", "role": "Admin", "program_study": "Fakultas Teknik"}]}`,
+			201,
+		},
+		{
+			"role tanpa hak verifikasi",
+			`{"name": "Salah", "stages": [{"name": "Mahasiswa", "role": "Mahasiswa"}]}`,
+			422,
+		},
+		{
+			"tanpa stage",
+			`{"name": "Kosong", "stages": []}`,
+			422,
+		},
+		{
+			"kombinasi sudah dipakai pipeline lain",
+			`{"name": "Duplikat", "achievement_type": "competition", "competition_level": "national", "stages": [{"name": "Dosen Wali", "role": "Dosen Wali"}]}`,
+			409,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockPipelineRepo, _ := setupPipelineTest()
+
+			app := fiber.New()
+			app.Post("/pipelines", service.CreatePipeline)
+
+			mockPipelineRepo.On("GetAll").Return([]model.VerificationPipeline{
+				{ID: "pipeline-9", Name: "Nasional", AchievementType: strPtr("competition"), CompetitionLevel: strPtr("national")},
+			}, nil)
+			mockPipelineRepo.On("Create", mock.AnythingOfType("*model.VerificationPipeline")).Return(nil)
+
+			req := httptest.NewRequest("POST", "/pipelines", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				mockPipelineRepo.AssertCalled(t, "Create", mock.MatchedBy(func(p *model.VerificationPipeline) bool {
+					return len(p.Stages) == 2 && p.Stages[1].Role == "Admin" && *p.Stages[1].ProgramStudy == "Fakultas Teknik"
+				}))
+			} else {
+				mockPipelineRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+// ==================== UPDATE / DELETE PIPELINE ====================
+
+func TestUpdatePipeline_InFlight(t *testing.T) {
+	service, mockPipelineRepo, _ := setupPipelineTest()
+
+	app := fiber.New()
+	app.Put("/pipelines/:id", service.UpdatePipeline)
+	app.Delete("/pipelines/:id", service.DeletePipeline)
+
+	mockPipelineRepo.On("FindByID", "pipeline-1").Return(internationalPipeline, nil)
+	mockPipelineRepo.On("CountInFlight", "pipeline-1").Return(3, nil)
+
+	req := httptest.NewRequest("PUT", "/pipelines/pipeline-1",
+		strings.NewReader(`{"name": "Internasional", "stages": [{"name": "Dosen Wali", "role": "Dosen Wali"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/pipelines/pipeline-1", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+
+	mockPipelineRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockPipelineRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestUpdatePipeline_Success(t *testing.T) {
+	service, mockPipelineRepo, mockAuditRepo := setupPipelineTest()
+
+	app := fiber.New()
+	app.Put("/pipelines/:id", service.UpdatePipeline)
+
+	mockPipelineRepo.On("FindByID", "pipeline-1").Return(internationalPipeline, nil)
+	mockPipelineRepo.On("CountInFlight", "pipeline-1").Return(0, nil)
+	mockPipelineRepo.On("GetAll").Return([]model.VerificationPipeline{*internationalPipeline}, nil)
+	mockPipelineRepo.On("Update", mock.AnythingOfType("*model.VerificationPipeline")).Return(nil)
+
+	// Kombinasi sama dengan dirinya sendiri bukan duplikat
+	req := httptest.NewRequest("PUT", "/pipelines/pipeline-1",
+		strings.NewReader(`{"name": "Internasional", "stages": [{"name": "Dosen Wali", "role": "Dosen Wali"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockPipelineRepo.AssertCalled(t, "Update", mock.MatchedBy(func(p *model.VerificationPipeline) bool {
+		return p.ID == "pipeline-1" && len(p.Stages) == 1
+	}))
+	mockAuditRepo.AssertCalled(t, "Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "pipeline.update" && e.TargetID == "pipeline-1"
+	}))
+}
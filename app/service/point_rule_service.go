@@ -0,0 +1,296 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== POINT RULES ======================
+// Admin mengatur poin per jenis prestasi, tingkat kompetisi, peringkat dan
+// jumlah anggota tim. Poin dihitung saat submit dan dikunci saat verified;
+// perubahan rule tidak mengubah poin achievement yang sudah disubmit.
+//
+
+type PointRuleService struct {
+	ruleRepo repository.PointRuleRepository
+	typeRepo repository.AchievementTypeRepository
+	audit    *AuditService
+	validate *validator.Validate
+}
+
+func NewPointRuleService(
+	ruleRepo repository.PointRuleRepository,
+	typeRepo repository.AchievementTypeRepository,
+	audit *AuditService,
+) *PointRuleService {
+	return &PointRuleService{
+		ruleRepo: ruleRepo,
+		typeRepo: typeRepo,
+		audit:    audit,
+		validate: validator.New(),
+	}
+}
+
+//
+// ==================== GET POINT RULES (GET /point-rules) ======================
+//
+
+func (s *PointRuleService) GetPointRules(c *fiber.Ctx) error {
+	rules, err := s.ruleRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch point rules",
+		})
+	}
+	if rules == nil {
+		rules = []model.PointRule{}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"point_rules": rules,
+			"total":       len(rules),
+		},
+	})
+}
+
+//
+// ==================== CREATE POINT RULE (POST /point-rules) ======================
+//
+
+func (s *PointRuleService) CreatePointRule(c *fiber.Ctx) error {
+	rule, err := s.parsePointRule(c)
+	if rule == nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create point rule",
+		})
+	}
+
+	s.audit.Record(c, "point_rule.create", "point_rule", rule.ID, pointRuleAuditDetails(rule))
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "point rule created successfully",
+		Data:    rule,
+	})
+}
+
+//
+// ==================== UPDATE POINT RULE (PUT /point-rules/:id) ======================
+//
+
+func (s *PointRuleService) UpdatePointRule(c *fiber.Ctx) error {
+	existing, err := s.ruleRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "point rule not found",
+		})
+	}
+
+	rule, err := s.parsePointRule(c)
+	if rule == nil {
+		return err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update point rule",
+		})
+	}
+
+	s.audit.Record(c, "point_rule.update", "point_rule", rule.ID, pointRuleAuditDetails(rule))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "point rule updated successfully",
+		Data:    rule,
+	})
+}
+
+//
+// ==================== DELETE POINT RULE (DELETE /point-rules/:id) ======================
+//
+
+func (s *PointRuleService) DeletePointRule(c *fiber.Ctx) error {
+	rule, err := s.ruleRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "point rule not found",
+		})
+	}
+
+	if err := s.ruleRepo.Delete(rule.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete point rule",
+		})
+	}
+
+	s.audit.Record(c, "point_rule.delete", "point_rule", rule.ID, pointRuleAuditDetails(rule))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "point rule deleted successfully",
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// parsePointRule - Parse & validasi request. Return nil rule jika response
+// error sudah dikirim.
+func (s *PointRuleService) parsePointRule(c *fiber.Ctx) (*model.PointRule, error) {
+	req := new(model.PointRuleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if _, err := s.typeRepo.FindByCode(req.AchievementType); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, c.Status(422).JSON(model.APIResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("unknown achievement type '%s'", req.AchievementType),
+			})
+		}
+		return nil, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievement type",
+		})
+	}
+	if invertedRange(req.RankMin, req.RankMax) || invertedRange(req.TeamSizeMin, req.TeamSizeMax) {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "range minimum must not exceed maximum",
+		})
+	}
+
+	return &model.PointRule{
+		Name:             req.Name,
+		AchievementType:  req.AchievementType,
+		CompetitionLevel: optionalValue(req.CompetitionLevel),
+		RankMin:          req.RankMin,
+		RankMax:          req.RankMax,
+		TeamSizeMin:      req.TeamSizeMin,
+		TeamSizeMax:      req.TeamSizeMax,
+		Points:           req.Points,
+	}, nil
+}
+
+func pointRuleAuditDetails(rule *model.PointRule) map[string]interface{} {
+	return map[string]interface{}{
+		"name":              rule.Name,
+		"achievement_type":  rule.AchievementType,
+		"competition_level": rule.CompetitionLevel,
+		"rank_min":          rule.RankMin,
+		"rank_max":          rule.RankMax,
+		"team_size_min":     rule.TeamSizeMin,
+		"team_size_max":     rule.TeamSizeMax,
+		"points":            rule.Points,
+	}
+}
+
+func invertedRange(min, max *int) bool {
+	return min != nil && max != nil && *min > *max
+}
+
+//
+// ==================== RULE MATCHING ======================
+// Kondisi dibaca dari details: competitionLevel, rank, teamSize (default 1).
+// Untuk prestasi tim, jumlah anggota diambil dari daftar Team.
+// Rule dengan syarat peringkat tidak cocok dengan achievement tanpa rank.
+// Dari rule yang cocok dipilih yang kondisinya paling banyak, lalu poin tertinggi.
+//
+
+func matchPointRule(rules []model.PointRule, achievement *model.Achievement) *model.PointRule {
+	level, _ := achievement.Details["competitionLevel"].(string)
+	rank, hasRank := detailInt(achievement.Details, "rank")
+	teamSize, ok := detailInt(achievement.Details, "teamSize")
+	if !ok || teamSize < 1 {
+		teamSize = 1
+	}
+	if len(achievement.Team) > 0 {
+		teamSize = len(achievement.Team)
+	}
+
+	var best *model.PointRule
+	bestScore := -1
+	for i := range rules {
+		rule := &rules[i]
+		score := 0
+
+		if rule.CompetitionLevel != nil {
+			if *rule.CompetitionLevel != level {
+				continue
+			}
+			score++
+		}
+		if rule.RankMin != nil || rule.RankMax != nil {
+			if !hasRank || !withinRange(rank, rule.RankMin, rule.RankMax) {
+				continue
+			}
+			score++
+		}
+		if rule.TeamSizeMin != nil || rule.TeamSizeMax != nil {
+			if !withinRange(teamSize, rule.TeamSizeMin, rule.TeamSizeMax) {
+				continue
+			}
+			score++
+		}
+
+		if score > bestScore || (score == bestScore && rule.Points > best.Points) {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+func withinRange(value int, min, max *int) bool {
+	return (min == nil || value >= *min) && (max == nil || value <= *max)
+}
+
+// detailInt - Angka bulat dari details (JSON: float64, MongoDB: int32 / int64)
+func detailInt(details map[string]interface{}, key string) (int, bool) {
+	switch v := details[key].(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
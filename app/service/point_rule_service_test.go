@@ -0,0 +1,147 @@
+package service
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupPointRuleTest() (*PointRuleService, *mocks.MockPointRuleRepository) {
+	mockRuleRepo := new(mocks.MockPointRuleRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	service := NewPointRuleService(mockRuleRepo, achievementTypes(), NewAuditService(mockAuditRepo))
+
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+	return service, mockRuleRepo
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// ==================== RULE MATCHING ====================
+
+func TestMatchPointRule(t *testing.T) {
+	international := "international"
+	national := "national"
+	rules := []model.PointRule{
+		{ID: "any", Points: 10},
+		{ID: "national", CompetitionLevel: &national, Points: 40},
+		{ID: "national-top3", CompetitionLevel: &national, RankMin: intPtr(1), RankMax: intPtr(3), Points: 60},
+		{ID: "national-top3-team", CompetitionLevel: &national, RankMin: intPtr(1), RankMax: intPtr(3), TeamSizeMin: intPtr(2), Points: 45},
+		{ID: "international", CompetitionLevel: &international, Points: 100},
+	}
+
+	tests := []struct {
+		name    string
+		details map[string]interface{}
+		want    string
+	}{
+		{"tanpa details", nil, "any"},
+		{"nasional tanpa peringkat", map[string]interface{}{"competitionLevel": "national"}, "national"},
+		{"nasional juara 2 individu", map[string]interface{}{"competitionLevel": "national", "rank": float64(2)}, "national-top3"},
+		{"nasional juara 2 tim (int32 dari MongoDB)", map[string]interface{}{"competitionLevel": "national", "rank": int32(2), "teamSize": int32(4)}, "national-top3-team"},
+		{"nasional peringkat 7", map[string]interface{}{"competitionLevel": "national", "rank": float64(7)}, "national"},
+		{"internasional", map[string]interface{}{"competitionLevel": "international", "rank": float64(1)}, "international"},
+		{"tingkat lain", map[string]interface{}{"competitionLevel": "local"}, "any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := matchPointRule(rules, &model.Achievement{AchievementType: "competition", Details: tt.details})
+			if assert.NotNil(t, rule) {
+				assert.Equal(t, tt.want, rule.ID)
+			}
+		})
+	}
+
+	// Prestasi tim: jumlah anggota dari daftar Team, details.teamSize diabaikan
+	team := &model.Achievement{
+		AchievementType: "competition",
+		Details:         map[string]interface{}{"competitionLevel": "national", "rank": float64(1), "teamSize": float64(1)},
+		Team:            []model.TeamMember{{StudentID: "student-1", Role: "ketua"}, {StudentID: "student-2", Role: "anggota"}},
+	}
+	if rule := matchPointRule(rules, team); assert.NotNil(t, rule) {
+		assert.Equal(t, "national-top3-team", rule.ID)
+	}
+
+	assert.Nil(t, matchPointRule(nil, &model.Achievement{AchievementType: "competition"}))
+}
+
+// ==================== CREATE POINT RULE ====================
+
+func TestCreatePointRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			"juara nasional",
+			`{"name": "Juara nasional", "achievement_type": "competition", "competition_level": "national", "rank_min": 1, "rank_max": 3, "points": 60}`,
+			201,
+		},
+		{
+			"jenis prestasi tidak terdaftar",
+			`{"name": "Hobi", "achievement_type": "hobby", "points": 5}`,
+			422,
+		},
+		{
+			"rentang peringkat terbalik",
+			`{"name": "Salah", "achievement_type": "competition", "rank_min": 5, "rank_max": 1, "points": 10}`,
+			422,
+		},
+		{
+			"poin negatif",
+			`{"name": "Salah", "achievement_type": "competition", "points": -5}`,
+			422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRuleRepo := setupPointRuleTest()
+			mockRuleRepo.On("Create", mock.AnythingOfType("*model.PointRule")).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/point-rules", service.CreatePointRule)
+
+			req := httptest.NewRequest("POST", "/point-rules", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == 201 {
+				mockRuleRepo.AssertCalled(t, "Create", mock.MatchedBy(func(rule *model.PointRule) bool {
+					return *rule.CompetitionLevel == "national" && *rule.RankMax == 3 && rule.TeamSizeMin == nil && rule.Points == 60
+				}))
+			} else {
+				mockRuleRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeletePointRule_NotFound(t *testing.T) {
+	service, mockRuleRepo := setupPointRuleTest()
+	mockRuleRepo.On("FindByID", "missing").Return(nil, sql.ErrNoRows)
+
+	app := fiber.New()
+	app.Delete("/point-rules/:id", service.DeletePointRule)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/point-rules/missing", nil))
+
+	assert.Equal(t, 404, resp.StatusCode)
+	mockRuleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
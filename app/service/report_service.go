@@ -14,6 +14,9 @@ type ReportService struct {
 	studentRepo     repository.StudentRepository
 	lecturerRepo    repository.LecturerRepository
 	userRepo        repository.UserRepository
+	duplicateRepo   repository.DuplicateRepository
+	calendarRepo    repository.AcademicCalendarRepository
+	authz           *Authorizer
 }
 
 func NewReportService(
@@ -21,12 +24,18 @@ func NewReportService(
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
 	userRepo repository.UserRepository,
+	duplicateRepo repository.DuplicateRepository,
+	calendarRepo repository.AcademicCalendarRepository,
+	authz *Authorizer,
 ) *ReportService {
 	return &ReportService{
 		achievementRepo: achievementRepo,
 		studentRepo:     studentRepo,
 		lecturerRepo:    lecturerRepo,
 		userRepo:        userRepo,
+		duplicateRepo:   duplicateRepo,
+		calendarRepo:    calendarRepo,
+		authz:           authz,
 	}
 }
 
@@ -39,6 +48,7 @@ func NewReportService(
 // • Total prestasi per periode
 // • Top mahasiswa berprestasi
 // • Distribusi tingkat kompetisi
+// Filter opsional: ?semester=<id>, ?event_from=, ?event_to= (tanggal kegiatan)
 //
 
 func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
@@ -51,59 +61,25 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	var references []model.AchievementReference
-	var err error
-
-	// Filter berdasarkan role (sesuai FR-011)
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa: hanya statistik prestasi sendiri
-		student, err := s.studentRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "student profile not found",
-			})
-		}
-
-		references, err = s.achievementRepo.GetReferencesByStudentID(student.ID, "", 10000, 0)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
-
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen Wali: statistik mahasiswa bimbingannya
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer profile not found",
-			})
-		}
+	eventFilter, err := eventFilterFromQuery(c, s.calendarRepo)
+	if eventFilter == nil {
+		return err
+	}
 
-		references, err = s.achievementRepo.GetReferencesByAdvisorID(lecturer.ID, "", 10000, 0)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
+	// Filter berdasarkan scope role (sesuai FR-011)
+	filter, err := s.authz.Filter(claims, ActionAchievementRead)
+	if err != nil {
+		return authzError(c, err)
+	}
 
-	} else if claims.Role == "Admin" {
-		// Admin: statistik seluruh sistem
-		references, err = s.achievementRepo.GetAllReferences("", 10000, 0)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
-	} else {
-		return c.Status(403).JSON(model.APIResponse{
+	references, err := referencesInScope(s.achievementRepo, filter, "", 10000, 0)
+	if err == nil {
+		references, err = filterByEventDate(s.achievementRepo, eventFilter, references)
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden",
+			Error:  "failed to fetch achievements",
 		})
 	}
 
@@ -120,6 +96,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 // ==================== GET STUDENT REPORT (GET /reports/student/:id) ======================
 // SRS Section 5.8: GET /api/v1/reports/student/:id
 // Authorization: Mahasiswa (own), Dosen Wali (advisees), Admin (all)
+// Filter opsional sama dengan GET /reports/statistics
 //
 
 func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
@@ -134,6 +111,11 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 		})
 	}
 
+	eventFilter, err := eventFilterFromQuery(c, s.calendarRepo)
+	if eventFilter == nil {
+		return err
+	}
+
 	// Get student
 	student, err := s.studentRepo.FindByID(studentID)
 	if err != nil {
@@ -144,32 +126,21 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 	}
 
 	// Authorization check
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa hanya bisa akses report sendiri
-		currentStudent, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if currentStudent == nil || currentStudent.ID != studentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen hanya bisa akses report mahasiswa bimbingannya
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if lecturer == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: not your advisee",
-			})
-		}
+	if !s.authz.Can(claims, ActionAchievementRead, Target{Student: student}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Get student user info
 	user, _ := s.userRepo.FindByID(student.UserID)
 
 	// Get all achievements
 	references, err := s.achievementRepo.GetReferencesByStudentID(studentID, "", 10000, 0)
+	if err == nil {
+		references, err = filterByEventDate(s.achievementRepo, eventFilter, references)
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -194,8 +165,8 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 			"title":  achievement.Title,
 			"type":   achievement.AchievementType,
 			"status": ref.Status,
-			"points": achievement.Points,
-			"date":   achievement.CreatedAt.Format("2006-01-02"),
+			"points": ref.AwardedPoints(),
+			"date":   eventDateOf(achievement).Format(dateLayout),
 		})
 	}
 
@@ -218,6 +189,7 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 		},
 		"by_type":            stats["by_type"],
 		"by_period":          stats["by_period"],
+		"by_semester":        stats["by_semester"],
 		"competition_levels": stats["competition_levels"],
 		"recent_achievements": recentAchievements,
 	}
@@ -237,51 +209,76 @@ func (s *ReportService) aggregateStatistics(references []model.AchievementRefere
 	byType := make(map[string]int)
 	byStatus := make(map[string]int)
 	byPeriod := make(map[string]int)
+	bySemester := make(map[string]int)          // nama semester -> jumlah ("unassigned" = di luar kalender)
 	competitionLevels := make(map[string]int)
 	studentPoints := make(map[string]int)      // student_id -> total_points
 	studentCounts := make(map[string]int)      // student_id -> achievement_count
 	studentNames := make(map[string]string)    // student_id -> full_name
 
+	// Prestasi tim punya satu reference per anggota: dihitung sekali per
+	// mahasiswa (poin & jumlah per anggota), tetapi total, jenis, periode dan
+	// tingkat kompetisi dihitung sekali per dokumen MongoDB.
+	achievements := make(map[string]*model.Achievement) // mongo_achievement_id -> achievement
+
+	semesters := loadSemesters(s.calendarRepo)
+
 	totalPoints := 0
-	totalAchievements := len(references)
+	totalAchievements := 0
+	totalParticipations := 0
 
 	// Process each achievement
 	for _, ref := range references {
-		// Get achievement details dari MongoDB
-		achievement, err := s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
-		if err != nil {
-			continue
-		}
+		// Get achievement details dari MongoDB (sekali per dokumen)
+		achievement, counted := achievements[ref.MongoAchievementID]
+		if !counted {
+			var err error
+			achievement, err = s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
+			if err != nil {
+				continue
+			}
+			achievements[ref.MongoAchievementID] = achievement
+			totalAchievements++
+
+			// Count by type
+			byType[achievement.AchievementType]++
+
+			// Count by period (year-month) & semester dari tanggal kegiatan
+			eventDate := eventDateOf(achievement)
+			byPeriod[eventDate.Format("2006-01")]++
+			if semester := semesterFor(semesters, eventDate); semester != nil {
+				bySemester[semester.Name]++
+			} else {
+				bySemester["unassigned"]++
+			}
 
-		// Count by type
-		byType[achievement.AchievementType]++
+			// Count competition levels
+			if achievement.AchievementType == "competition" {
+				if details, ok := achievement.Details["competitionLevel"].(string); ok {
+					competitionLevels[details]++
+				}
+			}
+		}
 
-		// Count by status
+		// Count by status (status per anggota)
 		byStatus[ref.Status]++
+		totalParticipations++
 
-		// Count by period (year-month)
-		period := achievement.CreatedAt.Format("2006-01")
-		byPeriod[period]++
-
-		// Count competition levels
-		if achievement.AchievementType == "competition" {
-			if details, ok := achievement.Details["competitionLevel"].(string); ok {
-				competitionLevels[details]++
-			}
+		// Accumulate points per student (hanya poin yang sudah dikunci saat verified)
+		points := 0
+		if ref.Status == model.AchievementStatusVerified {
+			points = ref.AwardedPoints()
 		}
-
-		// Accumulate points per student
-		studentPoints[achievement.StudentID] += achievement.Points
-		studentCounts[achievement.StudentID]++
-		totalPoints += achievement.Points
+		studentPoints[ref.StudentID] += points
+		studentCounts[ref.StudentID]++
+		totalPoints += points
 
 		// Get student name (cache untuk performa)
-		if _, exists := studentNames[achievement.StudentID]; !exists {
-			student, err := s.studentRepo.FindByID(achievement.StudentID)
+		if _, exists := studentNames[ref.StudentID]; !exists {
+			student, err := s.studentRepo.FindByID(ref.StudentID)
 			if err == nil {
 				user, err := s.userRepo.FindByID(student.UserID)
 				if err == nil {
-					studentNames[achievement.StudentID] = user.FullName
+					studentNames[ref.StudentID] = user.FullName
 				}
 			}
 		}
@@ -325,12 +322,127 @@ func (s *ReportService) aggregateStatistics(references []model.AchievementRefere
 
 	// Build response
 	return map[string]interface{}{
-		"total_achievements":  totalAchievements,
-		"total_points":        totalPoints,
-		"by_type":             byType,
-		"by_status":           byStatus,
-		"by_period":           byPeriod,
-		"competition_levels":  competitionLevels,
-		"top_students":        topStudents,
+		"total_achievements":   totalAchievements,
+		"total_participations": totalParticipations,
+		"total_points":         totalPoints,
+		"by_type":              byType,
+		"by_status":            byStatus,
+		"by_period":            byPeriod,
+		"by_semester":          bySemester,
+		"competition_levels":   competitionLevels,
+		"top_students":         topStudents,
+	}
+}
+
+//
+// ==================== GET DUPLICATE CLUSTERS (GET /reports/duplicates) ======================
+// Admin: kelompok achievement yang saling terhubung lewat duplicate flag
+// (A mirip B dan B mirip C → satu kelompok A, B, C). Admin yang dibatasi
+// program studi hanya melihat flag yang kedua achievement-nya dalam scope.
+//
+
+func (s *ReportService) GetDuplicateClusters(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	filter, err := s.authz.Filter(claims, ActionAchievementRead)
+	if err != nil {
+		return authzError(c, err)
+	}
+
+	flags, err := s.duplicateRepo.GetActiveFlags()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch duplicate flags",
+		})
+	}
+
+	// Reference dalam scope (nil = semua)
+	var inScope map[string]bool
+	if filter.Scope != ScopeAll {
+		references, err := referencesInScope(s.achievementRepo, filter, "", 10000, 0)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to fetch achievements",
+			})
+		}
+		inScope = make(map[string]bool)
+		for _, ref := range references {
+			inScope[ref.ID] = true
+			inScope[ref.MongoAchievementID] = true
+		}
+
+		scoped := []model.DuplicateFlag{}
+		for _, flag := range flags {
+			if inScope[flag.MongoAchievementID] && inScope[flag.MatchedMongoAchievementID] {
+				scoped = append(scoped, flag)
+			}
+		}
+		flags = scoped
 	}
+
+	// Union-find atas pasangan flag
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] == "" || parent[id] == id {
+			parent[id] = id
+			return id
+		}
+		parent[id] = find(parent[id])
+		return parent[id]
+	}
+	var order []string // urutan kemunculan (flag terbaru dulu)
+	for _, flag := range flags {
+		for _, id := range []string{flag.MongoAchievementID, flag.MatchedMongoAchievementID} {
+			if parent[id] == "" {
+				order = append(order, id)
+			}
+		}
+		parent[find(flag.MongoAchievementID)] = find(flag.MatchedMongoAchievementID)
+	}
+
+	clusters := []*model.DuplicateCluster{}
+	byRoot := make(map[string]*model.DuplicateCluster)
+	for _, id := range order {
+		root := find(id)
+		cluster, ok := byRoot[root]
+		if !ok {
+			cluster = &model.DuplicateCluster{Achievements: []model.DuplicateMatch{}, Flags: []model.DuplicateFlag{}}
+			byRoot[root] = cluster
+			clusters = append(clusters, cluster)
+		}
+		if match, ok := duplicateMatch(s.achievementRepo, id); ok {
+			if inScope != nil {
+				// Reference anggota tim dari program studi lain tidak ditampilkan
+				records := []model.DuplicateMatchRecord{}
+				for _, record := range match.References {
+					if inScope[record.ID] {
+						records = append(records, record)
+					}
+				}
+				match.References = records
+			}
+			cluster.Achievements = append(cluster.Achievements, *match)
+		}
+	}
+	for _, flag := range flags {
+		cluster := byRoot[find(flag.MongoAchievementID)]
+		cluster.Flags = append(cluster.Flags, flag)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"clusters": clusters,
+			"total":    len(clusters),
+		},
+	})
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http/httptest"
 	"testing"
@@ -8,8 +9,10 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"project_uas/app/model"
+	"project_uas/app/repository"
 	"project_uas/test/mocks"
 )
 
@@ -21,7 +24,7 @@ func setupReportTest() (*ReportService, *mocks.MockAchievementRepository, *mocks
 	mockLecturerRepo := new(mocks.MockLecturerRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
 
-	service := NewReportService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, mockUserRepo)
+	service := NewReportService(mockAchievementRepo, mockStudentRepo, mockLecturerRepo, mockUserRepo, noDuplicates(), noSemesters(), NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy))
 
 	return service, mockAchievementRepo, mockStudentRepo, mockLecturerRepo, mockUserRepo
 }
@@ -39,7 +42,7 @@ func TestGetStatistics_Success_Mahasiswa(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -89,7 +92,7 @@ func TestGetStatistics_Success_Mahasiswa(t *testing.T) {
 	}
 
 	mockStudentRepo.On("FindByUserID", userID).Return(student, nil)
-	mockAchievementRepo.On("GetReferencesByStudentID", studentID, "", 10000, 0).Return(references, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "", 10000, 0).Return(references, nil)
 	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(achievement1, nil)
 	mockAchievementRepo.On("GetAchievementByID", "mongo-2").Return(achievement2, nil)
 	mockStudentRepo.On("FindByID", studentID).Return(student, nil)
@@ -116,7 +119,7 @@ func TestGetStatistics_StudentNotFound(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -144,7 +147,7 @@ func TestGetStatistics_Success_DosenWali(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -187,7 +190,7 @@ func TestGetStatistics_Success_DosenWali(t *testing.T) {
 	}
 
 	mockLecturerRepo.On("FindByUserID", userID).Return(lecturer, nil)
-	mockAchievementRepo.On("GetReferencesByAdvisorID", lecturerID, "", 10000, 0).Return(references, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{AdvisorIDs: []string{lecturerID}}, "", 10000, 0).Return(references, nil)
 	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(achievement, nil)
 	mockStudentRepo.On("FindByID", studentID).Return(student, nil)
 	mockUserRepo.On("FindByID", student.UserID).Return(user, nil)
@@ -210,7 +213,7 @@ func TestGetStatistics_LecturerNotFound(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -224,6 +227,31 @@ func TestGetStatistics_LecturerNotFound(t *testing.T) {
 	mockLecturerRepo.AssertExpectations(t)
 }
 
+// ==================== FR-011: GET STATISTICS (ADMIN PRODI) ====================
+
+func TestGetStatistics_ScopedAdmin(t *testing.T) {
+	service, mockAchievementRepo, _, _, _ := setupReportTest()
+
+	app := fiber.New()
+	app.Get("/statistics", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{
+			UserID:     "admin-prodi",
+			Roles:      []string{"Admin"},
+			RoleScopes: map[string][]string{"Admin": {"Informatika", "Sistem Informasi"}},
+		})
+		return service.GetStatistics(c)
+	})
+
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{ProgramStudies: []string{"Informatika", "Sistem Informasi"}}, "", 10000, 0).Return([]model.AchievementReference{}, nil)
+
+	req := httptest.NewRequest("GET", "/statistics", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertExpectations(t)
+	mockAchievementRepo.AssertNotCalled(t, "GetAllReferences", "", 10000, 0)
+}
+
 // ==================== FR-011: GET STATISTICS (ADMIN) ====================
 
 func TestGetStatistics_Success_Admin(t *testing.T) {
@@ -234,7 +262,7 @@ func TestGetStatistics_Success_Admin(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "admin-user",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -317,7 +345,7 @@ func TestGetStatistics_ForbiddenRole(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "user-123",
-			Role:   "InvalidRole",
+			Roles:  []string{"InvalidRole"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -341,7 +369,7 @@ func TestGetStudentReport_Success_OwnReport(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -407,7 +435,7 @@ func TestGetStudentReport_Success_DosenWali(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -455,7 +483,7 @@ func TestGetStudentReport_Success_Admin(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "admin-user",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -495,7 +523,7 @@ func TestGetStudentReport_StudentNotFound(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "user-123",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -520,7 +548,7 @@ func TestGetStudentReport_Forbidden_NotOwner(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: otherUserID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -558,7 +586,7 @@ func TestGetStudentReport_Forbidden_NotAdvisor(t *testing.T) {
 	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStudentReport(c)
 	})
@@ -610,7 +638,7 @@ func TestGetStatistics_MultipleAchievementTypes(t *testing.T) {
 	app.Get("/statistics", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStatistics(c)
 	})
@@ -661,7 +689,7 @@ func TestGetStatistics_MultipleAchievementTypes(t *testing.T) {
 	}
 
 	mockStudentRepo.On("FindByUserID", userID).Return(student, nil)
-	mockAchievementRepo.On("GetReferencesByStudentID", studentID, "", 10000, 0).Return(references, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{StudentIDs: []string{studentID}}, "", 10000, 0).Return(references, nil)
 	
 	for i, ach := range achievements {
 		mockAchievementRepo.On("GetAchievementByID", references[i].MongoAchievementID).Return(ach, nil)
@@ -680,4 +708,244 @@ func TestGetStatistics_MultipleAchievementTypes(t *testing.T) {
 	mockStudentRepo.AssertExpectations(t)
 	mockAchievementRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestGetStatistics_TeamAchievementCountedOnce(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, mockUserRepo := setupReportTest()
+
+	app := fiber.New()
+	app.Get("/statistics", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-user", Roles: []string{"Admin"}})
+		return service.GetStatistics(c)
+	})
+
+	// Satu prestasi tim (3 anggota) dan satu prestasi individu
+	points := 45
+	references := []model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-team", Status: "verified", Points: &points},
+		{ID: "ref-2", StudentID: "student-2", MongoAchievementID: "mongo-team", Status: "verified", Points: &points},
+		{ID: "ref-3", StudentID: "student-3", MongoAchievementID: "mongo-team", Status: "submitted", Points: &points},
+		{ID: "ref-4", StudentID: "student-1", MongoAchievementID: "mongo-solo", Status: "submitted"},
+	}
+	team := &model.Achievement{
+		StudentID:       "student-1",
+		AchievementType: "competition",
+		Team: []model.TeamMember{
+			{StudentID: "student-1", Role: "ketua"},
+			{StudentID: "student-2", Role: "anggota"},
+			{StudentID: "student-3", Role: "anggota"},
+		},
+		Details:   map[string]interface{}{"competitionLevel": "national"},
+		CreatedAt: time.Now(),
+	}
+	solo := &model.Achievement{StudentID: "student-1", AchievementType: "publication", CreatedAt: time.Now()}
+
+	mockAchievementRepo.On("GetAllReferences", "", 10000, 0).Return(references, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(team, nil).Once()
+	mockAchievementRepo.On("GetAchievementByID", "mongo-solo").Return(solo, nil).Once()
+	for _, id := range []string{"student-1", "student-2", "student-3"} {
+		mockStudentRepo.On("FindByID", id).Return(&model.Student{ID: id, StudentID: "nim-" + id, UserID: id}, nil)
+		mockUserRepo.On("FindByID", id).Return(&model.User{ID: id, FullName: id}, nil)
+	}
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/statistics", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			TotalAchievements   int            `json:"total_achievements"`
+			TotalParticipations int            `json:"total_participations"`
+			TotalPoints         int            `json:"total_points"`
+			ByType              map[string]int `json:"by_type"`
+			CompetitionLevels   map[string]int `json:"competition_levels"`
+			TopStudents         []struct {
+				StudentID         string `json:"student_id"`
+				TotalAchievements int    `json:"total_achievements"`
+				TotalPoints       int    `json:"total_points"`
+			} `json:"top_students"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, 2, result.Data.TotalAchievements)
+	assert.Equal(t, 4, result.Data.TotalParticipations)
+	assert.Equal(t, 90, result.Data.TotalPoints) // poin per anggota yang sudah verified
+	assert.Equal(t, map[string]int{"competition": 1, "publication": 1}, result.Data.ByType)
+	assert.Equal(t, map[string]int{"national": 1}, result.Data.CompetitionLevels)
+
+	counts := map[string]int{}
+	for _, student := range result.Data.TopStudents {
+		counts[student.StudentID] = student.TotalAchievements
+	}
+	assert.Equal(t, map[string]int{"student-1": 2, "student-2": 1, "student-3": 1}, counts)
+	mockAchievementRepo.AssertExpectations(t)
+}
+
+func TestGetStatistics_ByEventDateAndSemester(t *testing.T) {
+	service, mockAchievementRepo, mockStudentRepo, _, mockUserRepo := setupReportTest()
+	mockCalendarRepo := new(mocks.MockAcademicCalendarRepository)
+	service.calendarRepo = mockCalendarRepo
+
+	semester := ganjil2025()
+	mockCalendarRepo.On("GetAll").Return([]model.AcademicSemester{semester}, nil)
+	mockCalendarRepo.On("FindByID", "semester-1").Return(&semester, nil)
+
+	references := []model.AchievementReference{
+		{ID: "ref-1", StudentID: "student-1", MongoAchievementID: "mongo-1", Status: "verified"},
+		{ID: "ref-2", StudentID: "student-1", MongoAchievementID: "mongo-2", Status: "verified"},
+		{ID: "ref-3", StudentID: "student-1", MongoAchievementID: "mongo-3", Status: "verified"},
+	}
+	// Diinput bulan Maret 2026, kegiatannya November 2025 / Maret 2026 / data lama tanpa event_date
+	inputAt := day("2026-03-02")
+	mockAchievementRepo.On("GetAllReferences", "", 10000, 0).Return(references, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{AchievementType: "competition", EventDate: day("2025-11-20"), CreatedAt: inputAt}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-2").Return(&model.Achievement{AchievementType: "competition", EventDate: day("2026-03-01"), CreatedAt: inputAt}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-3").Return(&model.Achievement{AchievementType: "competition", CreatedAt: day("2025-09-15")}, nil)
+	mockStudentRepo.On("FindByID", "student-1").Return(&model.Student{ID: "student-1", UserID: "student-1"}, nil)
+	mockUserRepo.On("FindByID", "student-1").Return(&model.User{ID: "student-1", FullName: "Mahasiswa"}, nil)
+
+	app := fiber.New()
+	app.Get("/statistics", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-user", Roles: []string{"Admin"}})
+		return service.GetStatistics(c)
+	})
+
+	type stats struct {
+		Data struct {
+			TotalAchievements int            `json:"total_achievements"`
+			ByPeriod          map[string]int `json:"by_period"`
+			BySemester        map[string]int `json:"by_semester"`
+		} `json:"data"`
+	}
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/statistics", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	var all stats
+	json.NewDecoder(resp.Body).Decode(&all)
+
+	assert.Equal(t, map[string]int{"2025-11": 1, "2026-03": 1, "2025-09": 1}, all.Data.ByPeriod)
+	assert.Equal(t, map[string]int{"2025/2026 Ganjil": 2, "unassigned": 1}, all.Data.BySemester)
+
+	// Filter semester: hanya achievement yang event_date-nya di dalam semester
+	mockAchievementRepo.On("GetAchievementIDsByEventDate", mock.Anything, mock.Anything).Return(map[string]bool{"mongo-1": true}, nil)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/statistics?semester=semester-1", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	var filtered stats
+	json.NewDecoder(resp.Body).Decode(&filtered)
+
+	assert.Equal(t, 1, filtered.Data.TotalAchievements)
+	assert.Equal(t, map[string]int{"2025/2026 Ganjil": 1}, filtered.Data.BySemester)
+}
+
+// ==================== DUPLICATE CLUSTERS ====================
+
+func TestGetDuplicateClusters(t *testing.T) {
+	service, mockAchievementRepo, _, _, _ := setupReportTest()
+	mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+	service.duplicateRepo = mockDuplicateRepo
+
+	// A-B dan B-C satu kelompok, D-E kelompok lain
+	mockDuplicateRepo.On("GetActiveFlags").Return([]model.DuplicateFlag{
+		{MongoAchievementID: "mongo-a", MatchedMongoAchievementID: "mongo-b", Reasons: []string{model.DuplicateReasonSimilarTitle}},
+		{MongoAchievementID: "mongo-d", MatchedMongoAchievementID: "mongo-e", Reasons: []string{model.DuplicateReasonAttachmentHash}},
+		{MongoAchievementID: "mongo-c", MatchedMongoAchievementID: "mongo-b", Reasons: []string{model.DuplicateReasonSameEvent}},
+	}, nil)
+	for _, id := range []string{"mongo-a", "mongo-b", "mongo-c", "mongo-d", "mongo-e"} {
+		mockAchievementRepo.On("GetAchievementByID", id).Return(&model.Achievement{StudentID: "student-1", Title: id}, nil)
+		mockAchievementRepo.On("GetReferencesByMongoID", id).Return([]model.AchievementReference{
+			{ID: "ref-" + id, StudentID: "student-1", MongoAchievementID: id, Status: "submitted"},
+		}, nil)
+	}
+
+	app := fiber.New()
+	app.Get("/reports/duplicates", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-user", Roles: []string{"Admin"}})
+		return service.GetDuplicateClusters(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/duplicates", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Clusters []model.DuplicateCluster `json:"clusters"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	clusters := map[int][]string{}
+	for _, cluster := range result.Data.Clusters {
+		var ids []string
+		for _, achievement := range cluster.Achievements {
+			ids = append(ids, achievement.MongoAchievementID)
+		}
+		clusters[len(cluster.Flags)] = ids
+	}
+	assert.Len(t, result.Data.Clusters, 2)
+	assert.ElementsMatch(t, []string{"mongo-a", "mongo-b", "mongo-c"}, clusters[2])
+	assert.ElementsMatch(t, []string{"mongo-d", "mongo-e"}, clusters[1])
+}
+
+func TestGetDuplicateClusters_ScopedAdmin(t *testing.T) {
+	service, mockAchievementRepo, _, _, _ := setupReportTest()
+	mockDuplicateRepo := new(mocks.MockDuplicateRepository)
+	service.duplicateRepo = mockDuplicateRepo
+
+	// mongo-a & mongo-b milik Informatika; mongo-x milik program studi lain.
+	// mongo-b prestasi tim dengan anggota dari program studi lain (ref-b-other).
+	mockDuplicateRepo.On("GetActiveFlags").Return([]model.DuplicateFlag{
+		{MongoAchievementID: "mongo-a", MatchedMongoAchievementID: "mongo-b", Reasons: []string{model.DuplicateReasonSimilarTitle}},
+		{MongoAchievementID: "mongo-a", MatchedMongoAchievementID: "mongo-x", Reasons: []string{model.DuplicateReasonSameEvent}},
+		{MongoAchievementID: "mongo-x", MatchedMongoAchievementID: "mongo-y", Reasons: []string{model.DuplicateReasonAttachmentHash}},
+	}, nil)
+	mockAchievementRepo.On("GetReferencesInScope", repository.ReferenceScope{ProgramStudies: []string{"Informatika"}}, "", 10000, 0).Return([]model.AchievementReference{
+		{ID: "ref-a", StudentID: "student-1", MongoAchievementID: "mongo-a"},
+		{ID: "ref-b", StudentID: "student-1", MongoAchievementID: "mongo-b"},
+	}, nil)
+	for _, id := range []string{"mongo-a", "mongo-b"} {
+		mockAchievementRepo.On("GetAchievementByID", id).Return(&model.Achievement{StudentID: "student-1", Title: id}, nil)
+	}
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-a").Return([]model.AchievementReference{
+		{ID: "ref-a", StudentID: "student-1", MongoAchievementID: "mongo-a", Status: "submitted"},
+	}, nil)
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-b").Return([]model.AchievementReference{
+		{ID: "ref-b", StudentID: "student-1", MongoAchievementID: "mongo-b", Status: "submitted"},
+		{ID: "ref-b-other", StudentID: "student-2", MongoAchievementID: "mongo-b", Status: "submitted"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/reports/duplicates", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{
+			UserID:     "admin-prodi",
+			Roles:      []string{"Admin"},
+			RoleScopes: map[string][]string{"Admin": {"Informatika"}},
+		})
+		return service.GetDuplicateClusters(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/duplicates", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Clusters []model.DuplicateCluster `json:"clusters"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	if assert.Len(t, result.Data.Clusters, 1) {
+		cluster := result.Data.Clusters[0]
+		assert.Len(t, cluster.Flags, 1)
+		var ids, refs []string
+		for _, achievement := range cluster.Achievements {
+			ids = append(ids, achievement.MongoAchievementID)
+			for _, record := range achievement.References {
+				refs = append(refs, record.ID)
+			}
+		}
+		assert.ElementsMatch(t, []string{"mongo-a", "mongo-b"}, ids)
+		assert.ElementsMatch(t, []string{"ref-a", "ref-b"}, refs)
+	}
+	mockAchievementRepo.AssertNotCalled(t, "GetAchievementByID", "mongo-x")
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== ROLE & PERMISSION MANAGEMENT ======================
+// Semua perubahan dicatat di audit log. Jika permission role berubah,
+// access token anggota role di-revoke supaya permission baru langsung berlaku.
+//
+
+// Permission yang tidak boleh dicabut dari role milik admin yang sedang login
+const roleManagePermission = "role:manage"
+
+type RoleService struct {
+	roleRepo    repository.RoleRepository
+	permRepo    repository.PermissionRepository
+	userRepo    repository.UserRepository
+	revocations *TokenRevocationService
+	audit       *AuditService
+	validate    *validator.Validate
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	permRepo repository.PermissionRepository,
+	userRepo repository.UserRepository,
+	revocations *TokenRevocationService,
+	audit *AuditService,
+) *RoleService {
+	return &RoleService{
+		roleRepo:    roleRepo,
+		permRepo:    permRepo,
+		userRepo:    userRepo,
+		revocations: revocations,
+		audit:       audit,
+		validate:    validator.New(),
+	}
+}
+
+//
+// ==================== GET PERMISSIONS (GET /permissions) ======================
+//
+
+func (s *RoleService) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := s.permRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch permissions",
+		})
+	}
+
+	if permissions == nil {
+		permissions = []model.Permission{}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   permissions,
+	})
+}
+
+//
+// ==================== GET ROLES (GET /roles) ======================
+//
+
+func (s *RoleService) GetRoles(c *fiber.Ctx) error {
+	roles, err := s.roleRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch roles",
+		})
+	}
+
+	responses := make([]model.RoleDetailResponse, 0, len(roles))
+	for i := range roles {
+		responses = append(responses, s.buildRoleResponse(&roles[i]))
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   responses,
+	})
+}
+
+//
+// ==================== GET ROLE BY ID (GET /roles/:id) ======================
+//
+
+func (s *RoleService) GetRoleByID(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   s.buildRoleResponse(role),
+	})
+}
+
+//
+// ==================== CREATE ROLE (POST /roles) ======================
+//
+
+func (s *RoleService) CreateRole(c *fiber.Ctx) error {
+	req := new(model.RoleCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if existing, _ := s.roleRepo.GetRoleByName(req.Name); existing != nil {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role name already exists",
+		})
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create role",
+		})
+	}
+
+	s.audit.Record(c, "role.create", "role", role.ID, map[string]interface{}{
+		"name": role.Name,
+	})
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role created successfully",
+		Data:    s.buildRoleResponse(role),
+	})
+}
+
+//
+// ==================== UPDATE ROLE (PUT /roles/:id) ======================
+// Role built-in hanya bisa diubah deskripsinya
+//
+
+func (s *RoleService) UpdateRole(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	req := new(model.RoleUpdateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	changes := map[string]interface{}{}
+
+	if req.Name != "" && req.Name != role.Name {
+		if role.IsBuiltin {
+			return c.Status(403).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "built-in role cannot be renamed",
+			})
+		}
+		if existing, _ := s.roleRepo.GetRoleByName(req.Name); existing != nil {
+			return c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "role name already exists",
+			})
+		}
+		changes["name"] = map[string]string{"from": role.Name, "to": req.Name}
+		role.Name = req.Name
+	}
+
+	if req.Description != nil && *req.Description != role.Description {
+		changes["description"] = map[string]string{"from": role.Description, "to": *req.Description}
+		role.Description = *req.Description
+	}
+
+	if len(changes) > 0 {
+		if err := s.roleRepo.Update(role); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to update role",
+			})
+		}
+
+		s.audit.Record(c, "role.update", "role", role.ID, changes)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role updated successfully",
+		Data:    s.buildRoleResponse(role),
+	})
+}
+
+//
+// ==================== DELETE ROLE (DELETE /roles/:id) ======================
+// Role built-in dan role yang masih punya anggota tidak bisa dihapus
+//
+
+func (s *RoleService) DeleteRole(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	if role.IsBuiltin {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "built-in role cannot be deleted",
+		})
+	}
+
+	members, err := s.roleRepo.CountMembers(role.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to count role members",
+		})
+	}
+	if members > 0 {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role still has members, reassign them first",
+		})
+	}
+
+	permissions, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+
+	if err := s.roleRepo.Delete(role.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete role",
+		})
+	}
+
+	s.audit.Record(c, "role.delete", "role", role.ID, map[string]interface{}{
+		"name":        role.Name,
+		"permissions": permissions,
+	})
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role deleted successfully",
+	})
+}
+
+//
+// ==================== GRANT PERMISSIONS (POST /roles/:id/permissions) ======================
+//
+
+func (s *RoleService) GrantPermissions(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	req := new(model.RolePermissionsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	// Validasi semua permission dulu supaya tidak ada grant setengah jalan
+	permissions := make([]*model.Permission, 0, len(req.Permissions))
+	for _, name := range req.Permissions {
+		permission, err := s.permRepo.FindByName(name)
+		if err != nil {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "permission not found: " + name,
+			})
+		}
+		permissions = append(permissions, permission)
+	}
+
+	var granted []string
+	for _, permission := range permissions {
+		ok, err := s.permRepo.Grant(role.ID, permission.ID)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to grant permission",
+			})
+		}
+		if ok {
+			granted = append(granted, permission.Name)
+		}
+	}
+
+	if len(granted) > 0 {
+		s.revokeMemberTokens(role.ID, "role permissions changed")
+		s.audit.Record(c, "role.grant", "role", role.ID, map[string]interface{}{
+			"name":        role.Name,
+			"permissions": granted,
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "permissions granted successfully",
+		Data:    s.buildRoleResponse(role),
+	})
+}
+
+//
+// ==================== REVOKE PERMISSION (DELETE /roles/:id/permissions/:permissionId) ======================
+//
+
+func (s *RoleService) RevokePermission(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	permission, err := s.permRepo.FindByID(c.Params("permissionId"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "permission not found",
+		})
+	}
+
+	// Mencegah admin mengunci dirinya sendiri dari manajemen role
+	if permission.Name == roleManagePermission && contains(claims.Roles, role.Name) {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot revoke role:manage from your own role",
+		})
+	}
+
+	ok, err := s.permRepo.Revoke(role.ID, permission.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke permission",
+		})
+	}
+	if !ok {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "permission is not assigned to role",
+		})
+	}
+
+	s.revokeMemberTokens(role.ID, "role permissions changed")
+	s.audit.Record(c, "role.revoke", "role", role.ID, map[string]interface{}{
+		"name":       role.Name,
+		"permission": permission.Name,
+	})
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "permission revoked successfully",
+		Data:    s.buildRoleResponse(role),
+	})
+}
+
+//
+// ==================== GET ROLE MEMBERS (GET /roles/:id/members) ======================
+//
+
+func (s *RoleService) GetRoleMembers(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	users, err := s.userRepo.GetAll(pageSize, offset, role.Name)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch role members",
+		})
+	}
+
+	total, err := s.userRepo.CountAll(role.Name)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to count role members",
+		})
+	}
+
+	members := make([]model.UserResponse, 0, len(users))
+	for _, user := range users {
+		roles, _ := s.userRepo.GetRoles(user.ID)
+		members = append(members, model.UserResponse{
+			ID:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			FullName:  user.FullName,
+			Roles:     roleNames(roles),
+			IsActive:  user.IsActive,
+			CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.UserListResponse{
+			Users:      members,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+func (s *RoleService) buildRoleResponse(role *model.Role) model.RoleDetailResponse {
+	permissions, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+	if permissions == nil {
+		permissions = []string{}
+	}
+	members, _ := s.roleRepo.CountMembers(role.ID)
+
+	return model.RoleDetailResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		MFARequired: role.MFARequired,
+		IsBuiltin:   role.IsBuiltin,
+		Permissions: permissions,
+		MemberCount: members,
+		CreatedAt:   role.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// revokeMemberTokens - Access token lama berisi permission lama, jadi anggota harus refresh
+func (s *RoleService) revokeMemberTokens(roleID, reason string) {
+	memberIDs, err := s.roleRepo.GetMemberIDs(roleID)
+	if err != nil {
+		log.Printf("[ROLE] Failed to get members of role %s: %v", roleID, err)
+		return
+	}
+
+	for _, userID := range memberIDs {
+		if err := s.revocations.RevokeUser(userID, reason); err != nil {
+			log.Printf("[ROLE] Failed to revoke tokens of user %s: %v", userID, err)
+		}
+	}
+}
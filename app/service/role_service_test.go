@@ -0,0 +1,296 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+type roleTestDeps struct {
+	roleRepo       *mocks.MockRoleRepository
+	permRepo       *mocks.MockPermissionRepository
+	userRepo       *mocks.MockUserRepository
+	revocationRepo *mocks.MockTokenRevocationRepository
+	auditRepo      *mocks.MockAuditLogRepository
+}
+
+func setupRoleTest() (*RoleService, roleTestDeps) {
+	deps := roleTestDeps{
+		roleRepo:       new(mocks.MockRoleRepository),
+		permRepo:       new(mocks.MockPermissionRepository),
+		userRepo:       new(mocks.MockUserRepository),
+		revocationRepo: new(mocks.MockTokenRevocationRepository),
+		auditRepo:      new(mocks.MockAuditLogRepository),
+	}
+
+	revocations := NewTokenRevocationService(deps.revocationRepo, new(mocks.MockRefreshTokenRepository))
+	service := NewRoleService(deps.roleRepo, deps.permRepo, deps.userRepo, revocations, NewAuditService(deps.auditRepo))
+
+	return service, deps
+}
+
+func withRole(role string, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Roles: []string{role}})
+		return handler(c)
+	}
+}
+
+func expectRoleResponse(deps roleTestDeps, roleID string, permissions []string, members int) {
+	deps.permRepo.On("GetPermissionsByRoleID", roleID).Return(permissions, nil)
+	deps.roleRepo.On("CountMembers", roleID).Return(members, nil)
+}
+
+// ==================== CREATE ROLE ====================
+
+func TestCreateRole_Success(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Post("/roles", withRole("Admin", service.CreateRole))
+
+	deps.roleRepo.On("GetRoleByName", "Kaprodi").Return(nil, assert.AnError)
+	deps.roleRepo.On("Create", mock.MatchedBy(func(r *model.Role) bool {
+		return r.Name == "Kaprodi" && !r.IsBuiltin
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.Role).ID = "role-9"
+	}).Return(nil)
+	deps.auditRepo.On("Create", mock.MatchedBy(func(l *model.AuditLog) bool {
+		return l.Action == "role.create" && l.TargetID == "role-9"
+	})).Return(nil)
+	expectRoleResponse(deps, "role-9", nil, 0)
+
+	body := `{"name":" Kaprodi ","description":"Ketua program studi"}`
+	req := httptest.NewRequest("POST", "/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	var result struct {
+		Data model.RoleDetailResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, "Kaprodi", result.Data.Name)
+	assert.Equal(t, []string{}, result.Data.Permissions)
+	deps.auditRepo.AssertExpectations(t)
+}
+
+func TestCreateRole_DuplicateName(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Post("/roles", withRole("Admin", service.CreateRole))
+
+	deps.roleRepo.On("GetRoleByName", "Admin").Return(&model.Role{ID: "role-1", Name: "Admin"}, nil)
+
+	req := httptest.NewRequest("POST", "/roles", strings.NewReader(`{"name":"Admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 409, resp.StatusCode)
+	deps.roleRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// ==================== UPDATE ROLE ====================
+
+func TestUpdateRole_BuiltinCannotBeRenamed(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Put("/roles/:id", withRole("Admin", service.UpdateRole))
+
+	deps.roleRepo.On("GetRoleByID", "role-1").Return(&model.Role{ID: "role-1", Name: "Mahasiswa", IsBuiltin: true}, nil)
+
+	req := httptest.NewRequest("PUT", "/roles/role-1", strings.NewReader(`{"name":"Student"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+	deps.roleRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateRole_BuiltinDescriptionAllowed(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Put("/roles/:id", withRole("Admin", service.UpdateRole))
+
+	deps.roleRepo.On("GetRoleByID", "role-1").Return(&model.Role{ID: "role-1", Name: "Mahasiswa", IsBuiltin: true}, nil)
+	deps.roleRepo.On("Update", mock.MatchedBy(func(r *model.Role) bool {
+		return r.Name == "Mahasiswa" && r.Description == "Mahasiswa aktif"
+	})).Return(nil)
+	deps.auditRepo.On("Create", mock.MatchedBy(func(l *model.AuditLog) bool {
+		return l.Action == "role.update"
+	})).Return(nil)
+	expectRoleResponse(deps, "role-1", []string{"achievement:create"}, 3)
+
+	req := httptest.NewRequest("PUT", "/roles/role-1", strings.NewReader(`{"name":"Mahasiswa","description":"Mahasiswa aktif"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+	deps.roleRepo.AssertExpectations(t)
+	deps.auditRepo.AssertExpectations(t)
+}
+
+// ==================== DELETE ROLE ====================
+
+func TestDeleteRole_BuiltinForbidden(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Delete("/roles/:id", withRole("Admin", service.DeleteRole))
+
+	deps.roleRepo.On("GetRoleByID", "role-1").Return(&model.Role{ID: "role-1", Name: "Admin", IsBuiltin: true}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/roles/role-1", nil))
+	assert.Equal(t, 403, resp.StatusCode)
+	deps.roleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestDeleteRole_HasMembers(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Delete("/roles/:id", withRole("Admin", service.DeleteRole))
+
+	deps.roleRepo.On("GetRoleByID", "role-9").Return(&model.Role{ID: "role-9", Name: "Kaprodi"}, nil)
+	deps.roleRepo.On("CountMembers", "role-9").Return(2, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/roles/role-9", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+	deps.roleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestDeleteRole_Success(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Delete("/roles/:id", withRole("Admin", service.DeleteRole))
+
+	deps.roleRepo.On("GetRoleByID", "role-9").Return(&model.Role{ID: "role-9", Name: "Kaprodi"}, nil)
+	deps.roleRepo.On("CountMembers", "role-9").Return(0, nil)
+	deps.permRepo.On("GetPermissionsByRoleID", "role-9").Return([]string{"report:read"}, nil)
+	deps.roleRepo.On("Delete", "role-9").Return(nil)
+	deps.auditRepo.On("Create", mock.MatchedBy(func(l *model.AuditLog) bool {
+		return l.Action == "role.delete" && l.TargetID == "role-9"
+	})).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/roles/role-9", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	deps.auditRepo.AssertExpectations(t)
+}
+
+// ==================== GRANT / REVOKE PERMISSION ====================
+
+func TestGrantPermissions_RevokesMemberTokens(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Post("/roles/:id/permissions", withRole("Admin", service.GrantPermissions))
+
+	deps.roleRepo.On("GetRoleByID", "role-9").Return(&model.Role{ID: "role-9", Name: "Kaprodi"}, nil)
+	deps.permRepo.On("FindByName", "report:read").Return(&model.Permission{ID: "perm-1", Name: "report:read"}, nil)
+	deps.permRepo.On("FindByName", "user:manage").Return(&model.Permission{ID: "perm-2", Name: "user:manage"}, nil)
+	deps.permRepo.On("Grant", "role-9", "perm-1").Return(true, nil)
+	deps.permRepo.On("Grant", "role-9", "perm-2").Return(false, nil) // sudah dimiliki
+	deps.roleRepo.On("GetMemberIDs", "role-9").Return([]string{"user-1", "user-2"}, nil)
+	deps.revocationRepo.On("Create", mock.MatchedBy(func(r *model.TokenRevocation) bool {
+		return r.UserID != nil && (*r.UserID == "user-1" || *r.UserID == "user-2")
+	})).Return(nil).Twice()
+	deps.auditRepo.On("Create", mock.MatchedBy(func(l *model.AuditLog) bool {
+		return l.Action == "role.grant" && strings.Contains(l.Details, "report:read") && !strings.Contains(l.Details, "user:manage")
+	})).Return(nil)
+	expectRoleResponse(deps, "role-9", []string{"report:read", "user:manage"}, 2)
+
+	req := httptest.NewRequest("POST", "/roles/role-9/permissions", strings.NewReader(`{"permissions":["report:read","user:manage"]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+	deps.revocationRepo.AssertExpectations(t)
+	deps.auditRepo.AssertExpectations(t)
+}
+
+func TestGrantPermissions_UnknownPermission(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Post("/roles/:id/permissions", withRole("Admin", service.GrantPermissions))
+
+	deps.roleRepo.On("GetRoleByID", "role-9").Return(&model.Role{ID: "role-9", Name: "Kaprodi"}, nil)
+	deps.permRepo.On("FindByName", "report:read").Return(&model.Permission{ID: "perm-1", Name: "report:read"}, nil)
+	deps.permRepo.On("FindByName", "nope:nope").Return(nil, assert.AnError)
+
+	req := httptest.NewRequest("POST", "/roles/role-9/permissions", strings.NewReader(`{"permissions":["report:read","nope:nope"]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+	assert.Equal(t, 404, resp.StatusCode)
+	deps.permRepo.AssertNotCalled(t, "Grant", mock.Anything, mock.Anything)
+}
+
+func TestRevokePermission_OwnRoleManageBlocked(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Delete("/roles/:id/permissions/:permissionId", withRole("Admin", service.RevokePermission))
+
+	deps.roleRepo.On("GetRoleByID", "role-1").Return(&model.Role{ID: "role-1", Name: "Admin", IsBuiltin: true}, nil)
+	deps.permRepo.On("FindByID", "perm-9").Return(&model.Permission{ID: "perm-9", Name: "role:manage"}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/roles/role-1/permissions/perm-9", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+	deps.permRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestRevokePermission_NotAssigned(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Delete("/roles/:id/permissions/:permissionId", withRole("Admin", service.RevokePermission))
+
+	deps.roleRepo.On("GetRoleByID", "role-9").Return(&model.Role{ID: "role-9", Name: "Kaprodi"}, nil)
+	deps.permRepo.On("FindByID", "perm-1").Return(&model.Permission{ID: "perm-1", Name: "report:read"}, nil)
+	deps.permRepo.On("Revoke", "role-9", "perm-1").Return(false, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/roles/role-9/permissions/perm-1", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+	deps.roleRepo.AssertNotCalled(t, "GetMemberIDs", mock.Anything)
+}
+
+// ==================== ROLE MEMBERS ====================
+
+func TestGetRoleMembers_Paginated(t *testing.T) {
+	service, deps := setupRoleTest()
+
+	app := fiber.New()
+	app.Get("/roles/:id/members", withRole("Admin", service.GetRoleMembers))
+
+	deps.roleRepo.On("GetRoleByID", "role-2").Return(&model.Role{ID: "role-2", Name: "Mahasiswa"}, nil)
+	deps.userRepo.On("GetAll", 2, 2, "Mahasiswa").Return([]model.User{{ID: "user-3", Username: "mhs3"}}, nil)
+	deps.userRepo.On("CountAll", "Mahasiswa").Return(3, nil)
+	deps.userRepo.On("GetRoles", "user-3").Return([]model.Role{{ID: "role-2", Name: "Mahasiswa"}, {ID: "role-1", Name: "Admin"}}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/roles/role-2/members?page=2&page_size=2", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.UserListResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(t, result.Data.Users, 1)
+	assert.Equal(t, 2, result.Data.TotalPages)
+	assert.Equal(t, []string{"Mahasiswa", "Admin"}, result.Data.Users[0].Roles)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/utils"
+)
+
+//
+// ==================== VERIFICATION SLA ======================
+// Scheduler di proses server mencari achievement yang terlalu lama berstatus
+// submitted. Setelah ReminderAfter pemegang stage aktif diingatkan (stage
+// dosen wali: dosen wali mahasiswa, atau delegasinya jika sedang aktif),
+// setelah EscalateAfter admin program studi mahasiswa menerima eskalasi.
+// Tiap level dikirim sekali per submit (lihat verification_sla_notifications).
+//
+
+// SLAPolicy - Batas umur submit (dihitung dari submitted_at)
+type SLAPolicy struct {
+	ReminderAfter time.Duration
+	EscalateAfter time.Duration
+}
+
+type SLAService struct {
+	slaRepo      repository.SLARepository
+	lecturerRepo repository.LecturerRepository
+	pipelineRepo repository.PipelineRepository
+	authz        *Authorizer
+	mailer       utils.Mailer
+	policy       SLAPolicy
+	now          func() time.Time
+}
+
+func NewSLAService(
+	slaRepo repository.SLARepository,
+	lecturerRepo repository.LecturerRepository,
+	pipelineRepo repository.PipelineRepository,
+	authz *Authorizer,
+	mailer utils.Mailer,
+	policy SLAPolicy,
+) *SLAService {
+	return &SLAService{
+		slaRepo:      slaRepo,
+		lecturerRepo: lecturerRepo,
+		pipelineRepo: pipelineRepo,
+		authz:        authz,
+		mailer:       mailer,
+		policy:       policy,
+		now:          time.Now,
+	}
+}
+
+// SLARunResult - Jumlah notifikasi yang dikirim dalam satu putaran
+type SLARunResult struct {
+	Reminders   int
+	Escalations int
+}
+
+// Start - Jalankan pengecekan SLA secara berkala (dijalankan di goroutine dari main)
+func (s *SLAService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			result, err := s.RunOnce()
+			if err != nil {
+				log.Printf("[SLA] Failed to check overdue verifications: %v", err)
+				continue
+			}
+			if result.Reminders > 0 || result.Escalations > 0 {
+				log.Printf("[SLA] Sent %d reminder(s), %d escalation(s)", result.Reminders, result.Escalations)
+			}
+		}
+	}()
+}
+
+// RunOnce - Satu putaran pengecekan pada waktu s.now(). Kegagalan kirim untuk
+// satu achievement hanya di-log; achievement tsb dicoba lagi di putaran berikutnya.
+func (s *SLAService) RunOnce() (SLARunResult, error) {
+	result := SLARunResult{}
+	now := s.now()
+
+	overdue, err := s.slaRepo.GetOverdue(now.Add(-s.policy.ReminderAfter), repository.OverdueFilter{})
+	if err != nil {
+		return result, err
+	}
+
+	lookup := newStageLookup()
+	for i := range overdue {
+		item := &overdue[i]
+
+		stage, holders, err := s.stageHolders(item, lookup)
+		if err != nil {
+			log.Printf("[SLA] Failed to resolve verifier for achievement %s: %v", item.ReferenceID, err)
+		} else if len(holders) > 0 {
+			remind := func(item *model.OverdueAchievement, to model.User) utils.Mail {
+				return s.reminderMail(item, stage, to)
+			}
+			sent, err := s.notifyOnce(item, model.SLALevelReminder, holders, remind)
+			if err != nil {
+				log.Printf("[SLA] Failed to remind verifier for achievement %s: %v", item.ReferenceID, err)
+			} else if sent {
+				result.Reminders++
+			}
+		}
+
+		if now.Sub(item.SubmittedAt) < s.policy.EscalateAfter {
+			continue
+		}
+
+		admins, err := s.roleHolders(lookup, "Admin", item.ProgramStudy, "")
+		if err != nil {
+			log.Printf("[SLA] Failed to load admins for program study %q: %v", item.ProgramStudy, err)
+			continue
+		}
+		if len(admins) == 0 {
+			log.Printf("[SLA] No admin to escalate achievement %s (program study %q)", item.ReferenceID, item.ProgramStudy)
+			continue
+		}
+
+		sent, err := s.notifyOnce(item, model.SLALevelEscalation, admins, s.escalationMail)
+		if err != nil {
+			log.Printf("[SLA] Failed to escalate achievement %s: %v", item.ReferenceID, err)
+		} else if sent {
+			result.Escalations++
+		}
+	}
+
+	return result, nil
+}
+
+// stageLookup - Cache stage pipeline, pemegang role & delegasi aktif per
+// dosen wali dalam satu putaran
+type stageLookup struct {
+	stages    map[string][]model.PipelineStage
+	holders   map[string][]model.User
+	delegates map[string][]model.User
+}
+
+func newStageLookup() *stageLookup {
+	return &stageLookup{
+		stages:    make(map[string][]model.PipelineStage),
+		holders:   make(map[string][]model.User),
+		delegates: make(map[string][]model.User),
+	}
+}
+
+// stageHolders - Stage aktif achievement dan user yang berwenang memutuskannya
+// (aturan yang sama dengan decideStage). Stage dosen wali: delegasi yang aktif
+// saat ini, atau dosen wali sendiri jika tidak ada. Stage lain: pemegang role
+// stage di program studi stage, atau jika stage tidak terikat program studi,
+// di program studi mahasiswa lalu yang tanpa batas program studi.
+func (s *SLAService) stageHolders(item *model.OverdueAchievement, lookup *stageLookup) (model.PipelineStage, []model.User, error) {
+	stages := defaultStages
+	if item.PipelineID != nil {
+		cached, ok := lookup.stages[*item.PipelineID]
+		if !ok {
+			var err error
+			cached, err = pipelineStages(s.pipelineRepo, item.PipelineID)
+			if err != nil {
+				return model.PipelineStage{}, nil, err
+			}
+			lookup.stages[*item.PipelineID] = cached
+		}
+		stages = cached
+	}
+	stage, _ := activeStage(stages, item.CurrentStage)
+
+	if isAdvisorStage(stage) {
+		if item.AdvisorID == nil {
+			return stage, nil, nil
+		}
+		delegates, ok := lookup.delegates[*item.AdvisorID]
+		if !ok {
+			var err error
+			delegates, err = s.slaRepo.GetActiveDelegates(*item.AdvisorID, s.now())
+			if err != nil {
+				return stage, nil, err
+			}
+			lookup.delegates[*item.AdvisorID] = delegates
+		}
+		if len(delegates) > 0 {
+			return stage, delegates, nil
+		}
+		advisor := model.User{ID: *item.AdvisorID, FullName: item.AdvisorName, Email: item.AdvisorEmail}
+		return stage, []model.User{advisor}, nil
+	}
+
+	if stage.ProgramStudy != nil {
+		holders, err := s.roleHolders(lookup, stage.Role, *stage.ProgramStudy)
+		return stage, holders, err
+	}
+	holders, err := s.roleHolders(lookup, stage.Role, item.ProgramStudy, "")
+	return stage, holders, err
+}
+
+// roleHolders - Pemegang role di program studi pertama (urutan programStudies)
+// yang punya pemegang
+func (s *SLAService) roleHolders(lookup *stageLookup, role string, programStudies ...string) ([]model.User, error) {
+	for _, programStudy := range programStudies {
+		key := role + "|" + programStudy
+		holders, ok := lookup.holders[key]
+		if !ok {
+			var err error
+			holders, err = s.slaRepo.GetRoleHolders(role, programStudy)
+			if err != nil {
+				return nil, err
+			}
+			lookup.holders[key] = holders
+		}
+		if len(holders) > 0 {
+			return holders, nil
+		}
+	}
+	return nil, nil
+}
+
+// notifyOnce - Kirim notifikasi level tsb jika belum pernah untuk submit ini.
+// User tanpa email dilewati. Return true jika email dikirim.
+func (s *SLAService) notifyOnce(item *model.OverdueAchievement, level string, to []model.User, build func(*model.OverdueAchievement, model.User) utils.Mail) (bool, error) {
+	recipients := []model.User{}
+	emails := []string{}
+	for _, user := range to {
+		if user.Email != "" {
+			recipients = append(recipients, user)
+			emails = append(emails, user.Email)
+		}
+	}
+	if len(recipients) == 0 {
+		return false, nil
+	}
+
+	notified, err := s.slaRepo.HasNotified(item.ReferenceID, level, item.SubmittedAt)
+	if err != nil || notified {
+		return false, err
+	}
+
+	for _, user := range recipients {
+		if err := s.mailer.Send(build(item, user)); err != nil {
+			return false, err
+		}
+	}
+
+	return true, s.slaRepo.RecordNotification(&model.SLANotification{
+		AchievementReferenceID: item.ReferenceID,
+		Level:                  level,
+		SubmittedAt:            item.SubmittedAt,
+		Recipients:             strings.Join(emails, ","),
+	})
+}
+
+func (s *SLAService) reminderMail(item *model.OverdueAchievement, stage model.PipelineStage, to model.User) utils.Mail {
+	delegation := ""
+	if isAdvisorStage(stage) && item.AdvisorID != nil && to.ID != *item.AdvisorID {
+		delegation = fmt.Sprintf("Anda menerima pengingat ini sebagai delegasi %s.\n", item.AdvisorName)
+	}
+	return utils.Mail{
+		To:      to.Email,
+		Subject: "Pengingat verifikasi prestasi mahasiswa",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\nPrestasi %s (%s) menunggu verifikasi di stage %s sejak %s (%s).\n%sMohon segera diverifikasi.\n\nID achievement: %s\n",
+			to.FullName, item.StudentName, item.ProgramStudy, stage.Name,
+			item.SubmittedAt.Format("2006-01-02 15:04"), overdueDays(s.now().Sub(item.SubmittedAt)), delegation, item.ReferenceID,
+		),
+	}
+}
+
+func (s *SLAService) escalationMail(item *model.OverdueAchievement, to model.User) utils.Mail {
+	advisor := item.AdvisorName
+	if advisor == "" {
+		advisor = "(belum ada dosen wali)"
+	}
+	return utils.Mail{
+		To:      to.Email,
+		Subject: "Eskalasi: verifikasi prestasi melewati batas waktu",
+		Body: fmt.Sprintf(
+			"Prestasi %s (%s) belum diverifikasi sejak %s (%s).\nDosen wali: %s\n\nID achievement: %s\n",
+			item.StudentName, item.ProgramStudy,
+			item.SubmittedAt.Format("2006-01-02 15:04"), overdueDays(s.now().Sub(item.SubmittedAt)), advisor, item.ReferenceID,
+		),
+	}
+}
+
+// overdueDays - "9 hari" dari umur submit
+func overdueDays(age time.Duration) string {
+	return fmt.Sprintf("%d hari", int(age.Hours()/24))
+}
+
+//
+// ==================== GET OVERDUE (GET /lecturers/:id/overdue) ======================
+// Achievement yang melewati SLA dan menunggu keputusan dosen ini, dengan aturan
+// penerima reminder yang sama (Dosen Wali: sendiri, Admin: semua). Mahasiswa
+// bimbingan sendiri tetap tampil di stage dosen wali walaupun reminder sedang
+// dikirim ke delegasi.
+//
+
+func (s *SLAService) GetOverdue(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	lecturer, err := s.lecturerRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "lecturer not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAdviseeRead, Target{Lecturer: lecturer}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: can only view your own advisees",
+		})
+	}
+
+	now := s.now()
+	filter := repository.OverdueFilter{UserID: lecturer.UserID, At: now}
+	overdue, err := s.slaRepo.GetOverdue(now.Add(-s.policy.ReminderAfter), filter)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch overdue achievements",
+		})
+	}
+
+	lookup := newStageLookup()
+	items := []map[string]interface{}{}
+	for i := range overdue {
+		item := &overdue[i]
+		stage, holders, err := s.stageHolders(item, lookup)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to resolve verification stage",
+			})
+		}
+		ownAdvisee := isAdvisorStage(stage) && item.AdvisorID != nil && *item.AdvisorID == lecturer.ID
+		if !ownAdvisee && !holdsStage(holders, lecturer.UserID) {
+			continue
+		}
+
+		age := now.Sub(item.SubmittedAt)
+		level := model.SLALevelReminder
+		if age >= s.policy.EscalateAfter {
+			level = model.SLALevelEscalation
+		}
+
+		items = append(items, map[string]interface{}{
+			"id":            item.ReferenceID,
+			"student_id":    item.StudentID,
+			"student_name":  item.StudentName,
+			"program_study": item.ProgramStudy,
+			"submitted_at":  item.SubmittedAt.Format("2006-01-02 15:04:05"),
+			"current_stage": item.CurrentStage,
+			"stage":         stage.Name,
+			"overdue_hours": int((age - s.policy.ReminderAfter).Hours()),
+			"level":         level,
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"lecturer_id":          lecturer.ID,
+			"sla_hours":            int(s.policy.ReminderAfter.Hours()),
+			"escalate_after_hours": int(s.policy.EscalateAfter.Hours()),
+			"overdue":              items,
+			"total":                len(items),
+		},
+	})
+}
+
+// holdsStage - userID termasuk pemegang stage
+func holdsStage(holders []model.User, userID string) bool {
+	for _, holder := range holders {
+		if holder.ID == userID {
+			return true
+		}
+	}
+	return false
+}
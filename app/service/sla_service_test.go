@@ -0,0 +1,229 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/test/mocks"
+	"project_uas/utils"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+// recordingMailer - Mailer yang hanya menyimpan email terkirim
+type recordingMailer struct {
+	sent []utils.Mail
+	err  error
+}
+
+func (m *recordingMailer) Send(mail utils.Mail) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+var slaTestNow = time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+
+var slaTestPolicy = SLAPolicy{ReminderAfter: 7 * 24 * time.Hour, EscalateAfter: 14 * 24 * time.Hour}
+
+func setupSLATest() (*SLAService, *mocks.MockSLARepository, *mocks.MockLecturerRepository, *recordingMailer) {
+	mockSLARepo := new(mocks.MockSLARepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mailer := &recordingMailer{}
+
+	authz := NewAuthorizer(new(mocks.MockStudentRepository), mockLecturerRepo, noDelegations(), DefaultPolicy)
+	service := NewSLAService(mockSLARepo, mockLecturerRepo, new(mocks.MockPipelineRepository), authz, mailer, slaTestPolicy)
+	service.now = func() time.Time { return slaTestNow }
+
+	return service, mockSLARepo, mockLecturerRepo, mailer
+}
+
+func overdueItem(id string, age time.Duration) model.OverdueAchievement {
+	advisorID := "lecturer-1"
+	return model.OverdueAchievement{
+		ReferenceID:  id,
+		StudentID:    "student-1",
+		StudentName:  "Mahasiswa Satu",
+		ProgramStudy: "Informatika",
+		AdvisorID:    &advisorID,
+		AdvisorName:  "Dosen Wali Satu",
+		AdvisorEmail: "dosen1@kampus.ac.id",
+		SubmittedAt:  slaTestNow.Add(-age),
+	}
+}
+
+// ==================== SCHEDULER ====================
+
+func TestSLARunOnce_RemindAndEscalate(t *testing.T) {
+	service, mockSLARepo, _, mailer := setupSLATest()
+
+	day := 24 * time.Hour
+	fresh := overdueItem("ref-8d", 8*day)       // lewat SLA: reminder
+	stale := overdueItem("ref-15d", 15*day)     // lewat batas eskalasi, reminder sudah dikirim
+	noAdvisor := overdueItem("ref-20d", 20*day) // tanpa dosen wali: hanya eskalasi
+	noAdvisor.AdvisorID, noAdvisor.AdvisorName, noAdvisor.AdvisorEmail = nil, "", ""
+
+	mockSLARepo.On("GetOverdue", slaTestNow.Add(-7*day), repository.OverdueFilter{}).Return([]model.OverdueAchievement{fresh, stale, noAdvisor}, nil)
+	mockSLARepo.On("HasNotified", "ref-8d", model.SLALevelReminder, fresh.SubmittedAt).Return(false, nil)
+	mockSLARepo.On("HasNotified", "ref-15d", model.SLALevelReminder, stale.SubmittedAt).Return(true, nil)
+	mockSLARepo.On("HasNotified", "ref-15d", model.SLALevelEscalation, stale.SubmittedAt).Return(false, nil)
+	mockSLARepo.On("HasNotified", "ref-20d", model.SLALevelEscalation, noAdvisor.SubmittedAt).Return(true, nil)
+	mockSLARepo.On("GetActiveDelegates", "lecturer-1", slaTestNow).Return([]model.User{}, nil)
+	mockSLARepo.On("GetRoleHolders", "Admin", "Informatika").Return([]model.User{
+		{ID: "admin-1", Email: "admin.if@kampus.ac.id"},
+		{ID: "admin-2", Email: "kaprodi.if@kampus.ac.id"},
+	}, nil).Once()
+	mockSLARepo.On("RecordNotification", mock.AnythingOfType("*model.SLANotification")).Return(nil)
+
+	result, err := service.RunOnce()
+
+	assert.NoError(t, err)
+	assert.Equal(t, SLARunResult{Reminders: 1, Escalations: 1}, result)
+
+	assert.Len(t, mailer.sent, 3)
+	assert.Equal(t, "dosen1@kampus.ac.id", mailer.sent[0].To)
+	assert.Contains(t, mailer.sent[0].Body, "8 hari")
+	assert.Equal(t, "admin.if@kampus.ac.id", mailer.sent[1].To)
+	assert.Equal(t, "kaprodi.if@kampus.ac.id", mailer.sent[2].To)
+
+	mockSLARepo.AssertCalled(t, "RecordNotification", mock.MatchedBy(func(n *model.SLANotification) bool {
+		return n.AchievementReferenceID == "ref-15d" && n.Level == model.SLALevelEscalation &&
+			n.Recipients == "admin.if@kampus.ac.id,kaprodi.if@kampus.ac.id"
+	}))
+	mockSLARepo.AssertNumberOfCalls(t, "RecordNotification", 2)
+	// Delegasi dosen wali yang sama hanya dimuat sekali per putaran
+	mockSLARepo.AssertNumberOfCalls(t, "GetActiveDelegates", 1)
+}
+
+func TestSLARunOnce_SendFailureIsRetried(t *testing.T) {
+	service, mockSLARepo, _, mailer := setupSLATest()
+	mailer.err = errors.New("smtp down")
+
+	item := overdueItem("ref-8d", 8*24*time.Hour)
+	mockSLARepo.On("GetOverdue", mock.Anything, mock.Anything).Return([]model.OverdueAchievement{item}, nil)
+	mockSLARepo.On("HasNotified", "ref-8d", model.SLALevelReminder, item.SubmittedAt).Return(false, nil)
+	mockSLARepo.On("GetActiveDelegates", "lecturer-1", slaTestNow).Return([]model.User{}, nil)
+
+	result, err := service.RunOnce()
+
+	// Gagal kirim tidak dicatat, jadi dicoba lagi di putaran berikutnya
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Reminders)
+	mockSLARepo.AssertNotCalled(t, "RecordNotification", mock.Anything)
+}
+
+// pipelineAtStage - Item di stage position pipeline-1 (Dosen Wali → Kaprodi)
+func pipelineAtStage(service *SLAService, item *model.OverdueAchievement, position int) {
+	mockPipelineRepo := new(mocks.MockPipelineRepository)
+	mockPipelineRepo.On("FindByID", "pipeline-1").Return(&model.VerificationPipeline{
+		ID: "pipeline-1",
+		Stages: []model.PipelineStage{
+			{Position: 1, Name: "Dosen Wali", Role: "Dosen Wali"},
+			{Position: 2, Name: "Kaprodi", Role: "Kaprodi"},
+		},
+	}, nil).Once()
+	service.pipelineRepo = mockPipelineRepo
+
+	pipelineID := "pipeline-1"
+	item.PipelineID, item.CurrentStage = &pipelineID, &position
+}
+
+func TestSLARunOnce_RemindStageHolder(t *testing.T) {
+	service, mockSLARepo, _, mailer := setupSLATest()
+
+	day := 24 * time.Hour
+	delegated := overdueItem("ref-delegated", 8*day) // dosen wali sedang mendelegasikan
+	kaprodi := overdueItem("ref-kaprodi", 9*day)     // sudah disetujui dosen wali, menunggu Kaprodi
+	pipelineAtStage(service, &kaprodi, 2)
+
+	mockSLARepo.On("GetOverdue", mock.Anything, mock.Anything).Return([]model.OverdueAchievement{delegated, kaprodi}, nil)
+	mockSLARepo.On("HasNotified", mock.Anything, model.SLALevelReminder, mock.Anything).Return(false, nil)
+	mockSLARepo.On("GetActiveDelegates", "lecturer-1", slaTestNow).Return([]model.User{
+		{ID: "lecturer-2", FullName: "Dosen Dua", Email: "dosen2@kampus.ac.id"},
+	}, nil).Once()
+	mockSLARepo.On("GetRoleHolders", "Kaprodi", "Informatika").Return([]model.User{
+		{ID: "kaprodi-if", FullName: "Kaprodi IF", Email: "kaprodi.if@kampus.ac.id"},
+	}, nil)
+	mockSLARepo.On("RecordNotification", mock.AnythingOfType("*model.SLANotification")).Return(nil)
+
+	result, err := service.RunOnce()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Reminders)
+	if assert.Len(t, mailer.sent, 2) {
+		assert.Equal(t, "dosen2@kampus.ac.id", mailer.sent[0].To)
+		assert.Contains(t, mailer.sent[0].Body, "sebagai delegasi Dosen Wali Satu")
+		assert.Equal(t, "kaprodi.if@kampus.ac.id", mailer.sent[1].To)
+		assert.Contains(t, mailer.sent[1].Body, "stage Kaprodi")
+	}
+	mockSLARepo.AssertNotCalled(t, "GetRoleHolders", "Kaprodi", "")
+}
+
+// ==================== GET OVERDUE ====================
+
+func TestGetOverdue(t *testing.T) {
+	service, mockSLARepo, mockLecturerRepo, _ := setupSLATest()
+
+	mockLecturerRepo.On("FindByID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", UserID: "lecturer-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "lecturer-1").Return(&model.Lecturer{ID: "lecturer-1", UserID: "lecturer-1"}, nil)
+	mockLecturerRepo.On("FindByUserID", "lecturer-2").Return(&model.Lecturer{ID: "lecturer-2", UserID: "lecturer-2"}, nil)
+
+	// ref-delegated: bimbingan lecturer-3 yang mendelegasikan ke lecturer-1;
+	// ref-kaprodi: bimbingan lecturer-1 tapi sudah di stage Kaprodi;
+	// ref-15d & ref-8d: bimbingan lecturer-1 yang sedang mendelegasikan ke
+	// lecturer-4 (tetap tampil untuk dosen wali sendiri)
+	delegatorID := "lecturer-3"
+	delegated := overdueItem("ref-delegated", 10*24*time.Hour)
+	delegated.AdvisorID = &delegatorID
+	kaprodi := overdueItem("ref-kaprodi", 9*24*time.Hour)
+	pipelineAtStage(service, &kaprodi, 2)
+
+	filter := repository.OverdueFilter{UserID: "lecturer-1", At: slaTestNow}
+	mockSLARepo.On("GetOverdue", slaTestNow.Add(-slaTestPolicy.ReminderAfter), filter).Return([]model.OverdueAchievement{
+		overdueItem("ref-15d", 15*24*time.Hour),
+		delegated,
+		kaprodi,
+		overdueItem("ref-8d", 8*24*time.Hour),
+	}, nil)
+	mockSLARepo.On("GetActiveDelegates", "lecturer-1", slaTestNow).Return([]model.User{{ID: "lecturer-4"}}, nil)
+	mockSLARepo.On("GetActiveDelegates", "lecturer-3", slaTestNow).Return([]model.User{{ID: "lecturer-1"}}, nil)
+	mockSLARepo.On("GetRoleHolders", "Kaprodi", "Informatika").Return([]model.User{{ID: "kaprodi-if"}}, nil)
+
+	app := fiber.New()
+	app.Get("/lecturers/:id/overdue", withLecturerClaims("lecturer-1", "Dosen Wali", service.GetOverdue))
+	app.Get("/other/lecturers/:id/overdue", withLecturerClaims("lecturer-2", "Dosen Wali", service.GetOverdue))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/lecturers/lecturer-1/overdue", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Overdue []map[string]interface{} `json:"overdue"`
+			Total   int                      `json:"total"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if assert.Equal(t, 3, result.Data.Total) {
+		assert.Equal(t, "ref-15d", result.Data.Overdue[0]["id"])
+		assert.Equal(t, model.SLALevelEscalation, result.Data.Overdue[0]["level"])
+		assert.Equal(t, float64(8*24), result.Data.Overdue[0]["overdue_hours"])
+		assert.Equal(t, "ref-delegated", result.Data.Overdue[1]["id"])
+		assert.Equal(t, "ref-8d", result.Data.Overdue[2]["id"])
+		assert.Equal(t, model.SLALevelReminder, result.Data.Overdue[2]["level"])
+	}
+
+	// Dosen lain tidak boleh melihat
+	resp, _ = app.Test(httptest.NewRequest("GET", "/other/lecturers/lecturer-1/overdue", nil))
+	assert.Equal(t, 403, resp.StatusCode)
+}
@@ -16,6 +16,7 @@ type StudentService struct {
 	lecturerRepo    repository.LecturerRepository
 	userRepo        repository.UserRepository
 	achievementRepo repository.AchievementRepository
+	authz           *Authorizer
 	validate        *validator.Validate
 }
 
@@ -24,12 +25,14 @@ func NewStudentService(
 	lecturerRepo repository.LecturerRepository,
 	userRepo repository.UserRepository,
 	achievementRepo repository.AchievementRepository,
+	authz *Authorizer,
 ) *StudentService {
 	return &StudentService{
 		studentRepo:     studentRepo,
 		lecturerRepo:    lecturerRepo,
 		userRepo:        userRepo,
 		achievementRepo: achievementRepo,
+		authz:           authz,
 		validate:        validator.New(),
 	}
 }
@@ -53,8 +56,21 @@ func (s *StudentService) GetAllStudents(c *fiber.Ctx) error {
 
 	offset := (page - 1) * pageSize
 
+	// Admin fakultas / prodi hanya melihat mahasiswa di program studinya
+	var programStudies []string
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	if filter, err := s.authz.Filter(claims, ActionUserManage); err == nil && filter.Scope == ScopeProgramStudy {
+		programStudies = filter.ProgramStudies
+	}
+
 	// Get students with pagination
-	students, err := s.studentRepo.GetAll(pageSize, offset)
+	var students []model.Student
+	var err error
+	if programStudies != nil {
+		students, err = s.studentRepo.GetAllByProgramStudies(programStudies, pageSize, offset)
+	} else {
+		students, err = s.studentRepo.GetAll(pageSize, offset)
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -63,7 +79,12 @@ func (s *StudentService) GetAllStudents(c *fiber.Ctx) error {
 	}
 
 	// Count total
-	total, err := s.studentRepo.CountAll()
+	var total int
+	if programStudies != nil {
+		total, err = s.studentRepo.CountAllByProgramStudies(programStudies)
+	} else {
+		total, err = s.studentRepo.CountAll()
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -206,26 +227,12 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 	}
 
 	// Authorization check
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa hanya bisa akses prestasi sendiri
-		currentStudent, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if currentStudent == nil || currentStudent.ID != studentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen hanya bisa akses prestasi mahasiswa bimbingannya
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if lecturer == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: not your advisee",
-			})
-		}
+	if !s.authz.Can(claims, ActionAchievementRead, Target{Student: student}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Parse query params
 	page, _ := strconv.Atoi(c.Query("page", "1"))
@@ -268,19 +275,31 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 
 		response := model.AchievementResponse{
 			ID:              ref.ID,
-			StudentID:       achievement.StudentID,
+			StudentID:       ref.StudentID,
 			AchievementType: achievement.AchievementType,
 			Title:           achievement.Title,
 			Description:     achievement.Description,
 			Details:         achievement.Details,
 			Attachments:     achievement.Attachments,
 			Tags:            achievement.Tags,
-			Points:          achievement.Points,
+			Team:            achievement.Team,
+			Points:          ref.AwardedPoints(),
 			Status:          ref.Status,
 			CreatedAt:       achievement.CreatedAt.Format("2006-01-02 15:04:05"),
 			UpdatedAt:       achievement.UpdatedAt.Format("2006-01-02 15:04:05"),
 		}
 
+		if len(achievement.Team) > 0 {
+			response.OwnerID = &achievement.StudentID
+		}
+		if !achievement.EventDate.IsZero() {
+			response.EventDate = achievement.EventDate.Format(dateLayout)
+		}
+		if achievement.EventEndDate != nil {
+			eventEndDate := achievement.EventEndDate.Format(dateLayout)
+			response.EventEndDate = &eventEndDate
+		}
+
 		if ref.SubmittedAt != nil {
 			submittedAt := ref.SubmittedAt.Format("2006-01-02 15:04:05")
 			response.SubmittedAt = &submittedAt
@@ -320,9 +339,10 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 
 func (s *StudentService) SetAdvisor(c *fiber.Ctx) error {
 	studentID := c.Params("id")
+	claims, _ := c.Locals("user").(*model.JWTClaims)
 
 	// Cari student
-	_, err := s.studentRepo.FindByID(studentID)
+	student, err := s.studentRepo.FindByID(studentID)
 	if err != nil {
 		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
@@ -330,6 +350,14 @@ func (s *StudentService) SetAdvisor(c *fiber.Ctx) error {
 		})
 	}
 
+	// Admin fakultas / prodi hanya untuk mahasiswa di program studinya
+	if !s.authz.Can(claims, ActionUserManage, Target{Student: student}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
 	// Parse request
 	req := new(model.SetAdvisorRequest)
 	if err := c.BodyParser(req); err != nil {
@@ -356,6 +384,13 @@ func (s *StudentService) SetAdvisor(c *fiber.Ctx) error {
 		})
 	}
 
+	if !s.authz.Can(claims, ActionUserManage, Target{Lecturer: advisor}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "advisor is outside your scope",
+		})
+	}
+
 	// Update advisor
 	if err := s.studentRepo.SetAdvisor(studentID, req.AdvisorID); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
@@ -23,7 +23,7 @@ func setupStudentTest() (*StudentService, *mocks.MockStudentRepository, *mocks.M
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockAchievementRepo := new(mocks.MockAchievementRepository)
 
-	service := NewStudentService(mockStudentRepo, mockLecturerRepo, mockUserRepo, mockAchievementRepo)
+	service := NewStudentService(mockStudentRepo, mockLecturerRepo, mockUserRepo, mockAchievementRepo, NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy))
 
 	return service, mockStudentRepo, mockLecturerRepo, mockUserRepo, mockAchievementRepo
 }
@@ -196,7 +196,7 @@ func TestGetStudentAchievements_Success_Mahasiswa(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -252,7 +252,7 @@ func TestGetStudentAchievements_Success_DosenWali(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -292,7 +292,7 @@ func TestGetStudentAchievements_Forbidden_NotOwner(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: otherUserID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -330,7 +330,7 @@ func TestGetStudentAchievements_Forbidden_NotAdvisor(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Dosen Wali",
+			Roles:  []string{"Dosen Wali"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -367,7 +367,7 @@ func TestGetStudentAchievements_Success_Admin(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: "admin-user",
-			Role:   "Admin",
+			Roles:  []string{"Admin"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -400,7 +400,7 @@ func TestGetStudentAchievements_WithStatusFilter(t *testing.T) {
 	app.Get("/students/:id/achievements", func(c *fiber.Ctx) error {
 		c.Locals("user", &model.JWTClaims{
 			UserID: userID,
-			Role:   "Mahasiswa",
+			Roles:  []string{"Mahasiswa"},
 		})
 		return service.GetStudentAchievements(c)
 	})
@@ -429,7 +429,7 @@ func TestSetAdvisor_Success(t *testing.T) {
 	service, mockStudentRepo, mockLecturerRepo, mockUserRepo, _ := setupStudentTest()
 
 	app := fiber.New()
-	app.Put("/students/:id/advisor", service.SetAdvisor)
+	app.Put("/students/:id/advisor", withAdmin(service.SetAdvisor))
 
 	studentID := "student-123"
 	advisorID := "advisor-123"
@@ -474,7 +474,7 @@ func TestSetAdvisor_StudentNotFound(t *testing.T) {
 	service, mockStudentRepo, _, _, _ := setupStudentTest()
 
 	app := fiber.New()
-	app.Put("/students/:id/advisor", service.SetAdvisor)
+	app.Put("/students/:id/advisor", withAdmin(service.SetAdvisor))
 
 	studentID := "invalid-student"
 
@@ -495,7 +495,7 @@ func TestSetAdvisor_AdvisorNotFound(t *testing.T) {
 	service, mockStudentRepo, mockLecturerRepo, _, _ := setupStudentTest()
 
 	app := fiber.New()
-	app.Put("/students/:id/advisor", service.SetAdvisor)
+	app.Put("/students/:id/advisor", withAdmin(service.SetAdvisor))
 
 	studentID := "student-123"
 	advisorID := "invalid-advisor"
@@ -524,7 +524,7 @@ func TestSetAdvisor_InvalidRequestBody(t *testing.T) {
 	service, mockStudentRepo, _, _, _ := setupStudentTest()
 
 	app := fiber.New()
-	app.Put("/students/:id/advisor", service.SetAdvisor)
+	app.Put("/students/:id/advisor", withAdmin(service.SetAdvisor))
 
 	studentID := "student-123"
 
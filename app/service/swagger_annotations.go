@@ -3,22 +3,125 @@
 
 	// ==================== AUTH SERVICE ANNOTATIONS ======================
 
+	// JWKS godoc
+	// @Summary JSON Web Key Set
+	// @Description Public keys (RS256 / EdDSA) for verifying access tokens, selected by the "kid" header. Old keys stay listed during rotation. Served at /.well-known/jwks.json (outside /api/v1).
+	// @Tags Authentication
+	// @Produce json
+	// @Success 200 {object} utils.JWKSet "Key set"
+	// @Router /.well-known/jwks.json [get]
+	func (s *JWKSService) GetJWKSSwagger() {}
+
 	// Login godoc
 	// @Summary Login to the system
-	// @Description Authenticate user with username/email and password
+	// @Description Authenticate user with username/email and password. Permissions are the union of all the user's roles, or of the optional "role" only when given. Repeated failures per username or IP lock login temporarily (exponential backoff). If the user has MFA enabled or one of the session roles requires MFA, returns an mfa_pending token (data=MFAChallengeResponse) to be exchanged at /auth/mfa/verify.
 	// @Tags Authentication
 	// @Accept json
 	// @Produce json
 	// @Param request body model.LoginRequest true "Login credentials"
-	// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful"
+	// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful (or model.MFAChallengeResponse if MFA code is required)"
 	// @Failure 400 {object} model.APIResponse "Invalid request body"
 	// @Failure 401 {object} model.APIResponse "Invalid username or password"
+	// @Failure 403 {object} model.APIResponse "Account is inactive or selected role is not assigned"
+	// @Failure 429 {object} model.APIResponse "Too many failed login attempts (see Retry-After header)"
 	// @Router /auth/login [post]
 	func (s *AuthService) LoginSwagger() {}
 
+	// VerifyMFA godoc
+	// @Summary Login step 2: verify MFA code
+	// @Description Exchange mfa_pending token + TOTP code (or recovery code) for access & refresh token. During first-time enrollment the first valid code enables MFA and recovery codes are returned once.
+	// @Tags Authentication
+	// @Accept json
+	// @Produce json
+	// @Param request body model.MFAVerifyRequest true "MFA token and code"
+	// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful"
+	// @Failure 400 {object} model.APIResponse "MFA is not enrolled"
+	// @Failure 401 {object} model.APIResponse "Invalid mfa token / code"
+	// @Failure 403 {object} model.APIResponse "Account is inactive"
+	// @Failure 429 {object} model.APIResponse "Too many failed attempts"
+	// @Router /auth/mfa/verify [post]
+	func (s *AuthService) VerifyMFASwagger() {}
+
+	// EnrollMFA godoc
+	// @Summary Start MFA enrollment during login
+	// @Description For roles that require MFA: use the mfa_pending token (enrollment_required=true) to get a TOTP secret and otpauth:// provisioning URI (render as QR code)
+	// @Tags MFA
+	// @Accept json
+	// @Produce json
+	// @Param request body model.MFAEnrollRequest true "MFA pending token"
+	// @Success 200 {object} model.APIResponse{data=model.MFASetupResponse} "TOTP secret"
+	// @Failure 401 {object} model.APIResponse "Invalid mfa token"
+	// @Failure 409 {object} model.APIResponse "MFA already enabled"
+	// @Router /auth/mfa/enroll [post]
+	func (s *MFAService) EnrollSwagger() {}
+
+	// GetMFAStatus godoc
+	// @Summary Get own MFA status
+	// @Tags MFA
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=model.MFAStatusResponse} "MFA status"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Router /auth/mfa [get]
+	func (s *MFAService) GetStatusSwagger() {}
+
+	// SetupMFA godoc
+	// @Summary Start MFA enrollment
+	// @Description Generate TOTP secret and otpauth:// provisioning URI (render as QR code). MFA is active after /auth/mfa/confirm.
+	// @Tags MFA
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=model.MFASetupResponse} "TOTP secret"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 409 {object} model.APIResponse "MFA already enabled"
+	// @Router /auth/mfa/setup [post]
+	func (s *MFAService) SetupSwagger() {}
+
+	// ConfirmMFA godoc
+	// @Summary Confirm MFA enrollment
+	// @Description Enable MFA with the first TOTP code. Recovery codes are only shown once.
+	// @Tags MFA
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.MFACodeRequest true "TOTP code"
+	// @Success 200 {object} model.APIResponse{data=model.MFARecoveryCodesResponse} "MFA enabled"
+	// @Failure 400 {object} model.APIResponse "MFA is not enrolled"
+	// @Failure 401 {object} model.APIResponse "Invalid mfa code"
+	// @Failure 409 {object} model.APIResponse "MFA already enabled"
+	// @Router /auth/mfa/confirm [post]
+	func (s *MFAService) ConfirmSwagger() {}
+
+	// DisableMFA godoc
+	// @Summary Disable own MFA
+	// @Description Requires password and TOTP / recovery code. Not allowed if the role requires MFA.
+	// @Tags MFA
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.MFADisableRequest true "Password and code"
+	// @Success 200 {object} model.APIResponse "MFA disabled"
+	// @Failure 401 {object} model.APIResponse "Invalid password or code"
+	// @Failure 403 {object} model.APIResponse "MFA is required for your role"
+	// @Router /auth/mfa/disable [post]
+	func (s *MFAService) DisableSwagger() {}
+
+	// RegenerateRecoveryCodes godoc
+	// @Summary Regenerate MFA recovery codes
+	// @Description Old recovery codes are invalidated
+	// @Tags MFA
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.MFACodeRequest true "TOTP or recovery code"
+	// @Success 200 {object} model.APIResponse{data=model.MFARecoveryCodesResponse} "New recovery codes"
+	// @Failure 401 {object} model.APIResponse "Invalid mfa code"
+	// @Router /auth/mfa/recovery-codes [post]
+	func (s *MFAService) RegenerateRecoveryCodesSwagger() {}
+
 	// Refresh godoc
 	// @Summary Refresh access token
-	// @Description Get new access token using refresh token
+	// @Description Rotate refresh token (single use) and get new access token. Reusing a refresh token revokes its whole session.
 	// @Tags Authentication
 	// @Accept json
 	// @Produce json
@@ -29,6 +132,47 @@
 	// @Router /auth/refresh [post]
 	func (s *AuthService) RefreshSwagger() {}
 
+	// ChangePassword godoc
+	// @Summary Change own password
+	// @Description Change password of currently authenticated user (requires current password). All sessions are logged out afterwards.
+	// @Tags Authentication
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.ChangePasswordRequest true "Current and new password"
+	// @Success 200 {object} model.APIResponse "Password changed"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized / current password is incorrect"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /auth/password/change [post]
+	func (s *PasswordService) ChangePasswordSwagger() {}
+
+	// ForgotPassword godoc
+	// @Summary Request password reset
+	// @Description Send single-use password reset token to user's email. Always returns 200 whether the email is registered or not.
+	// @Tags Authentication
+	// @Accept json
+	// @Produce json
+	// @Param request body model.ForgotPasswordRequest true "Registered email"
+	// @Success 200 {object} model.APIResponse "Reset email sent if the email is registered"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /auth/password/forgot [post]
+	func (s *PasswordService) ForgotPasswordSwagger() {}
+
+	// ResetPassword godoc
+	// @Summary Reset password with token
+	// @Description Set new password using reset token from email. Token can only be used once and expires. All sessions are logged out afterwards.
+	// @Tags Authentication
+	// @Accept json
+	// @Produce json
+	// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+	// @Success 200 {object} model.APIResponse "Password reset"
+	// @Failure 400 {object} model.APIResponse "Invalid or expired reset token"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /auth/password/reset [post]
+	func (s *PasswordService) ResetPasswordSwagger() {}
+
 	// Profile godoc
 	// @Summary Get current user profile
 	// @Description Get profile of currently authenticated user
@@ -44,7 +188,7 @@
 
 	// Logout godoc
 	// @Summary Logout from system
-	// @Description Logout current user (invalidate token on client side)
+	// @Description Logout current user and revoke the current session's refresh tokens server-side
 	// @Tags Authentication
 	// @Accept json
 	// @Produce json
@@ -58,7 +202,7 @@
 
 	// CreateUser godoc
 	// @Summary Create new user (Admin only)
-	// @Description Create new user with role and profile
+	// @Description Create new user with role and profile. Admin scoped to program studies can only create Mahasiswa / Dosen Wali with a profile inside their scope. scope_program_study limits the assigned role to one program study.
 	// @Tags Users
 	// @Accept json
 	// @Produce json
@@ -67,16 +211,16 @@
 	// @Success 201 {object} model.APIResponse{data=model.UserResponse} "User created"
 	// @Failure 400 {object} model.APIResponse "Invalid request body"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or role / program study outside admin scope"
 	// @Failure 404 {object} model.APIResponse "Role not found"
 	// @Failure 409 {object} model.APIResponse "Username/Email already exists"
-	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Failure 422 {object} model.APIResponse "Validation error, or profile missing for scoped admin"
 	// @Router /users [post]
 	func (s *UserService) CreateUserSwagger() {}
 
 	// GetUsers godoc
 	// @Summary Get all users (Admin only)
-	// @Description Get list of all users with pagination and role filter
+	// @Description Get list of all users with pagination and role filter. Admin scoped to program studies only sees students and lecturers inside their scope.
 	// @Tags Users
 	// @Accept json
 	// @Produce json
@@ -100,7 +244,7 @@
 	// @Param id path string true "User ID (UUID)"
 	// @Success 200 {object} model.APIResponse{data=model.UserResponse} "User details"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or user outside admin scope"
 	// @Failure 404 {object} model.APIResponse "User not found"
 	// @Router /users/{id} [get]
 	func (s *UserService) GetUserByIDSwagger() {}
@@ -117,7 +261,7 @@
 	// @Success 200 {object} model.APIResponse{data=model.UserResponse} "User updated"
 	// @Failure 400 {object} model.APIResponse "Invalid request body"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or user outside admin scope"
 	// @Failure 404 {object} model.APIResponse "User not found"
 	// @Failure 409 {object} model.APIResponse "Email already used"
 	// @Router /users/{id} [put]
@@ -133,27 +277,245 @@
 	// @Param id path string true "User ID (UUID)"
 	// @Success 200 {object} model.APIResponse "User deleted"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or user outside admin scope"
 	// @Failure 404 {object} model.APIResponse "User not found"
 	// @Router /users/{id} [delete]
 	func (s *UserService) DeleteUserSwagger() {}
 
-	// AssignRole godoc
-	// @Summary Assign role to user (Admin only)
-	// @Description Change user's role
+	// AddRole godoc
+	// @Summary Add role to user (Admin only)
+	// @Description Add a role to user. Mahasiswa / Dosen Wali profile is created from the request, or restored if it was retired. scope_program_study limits the assignment to one program study. Existing tokens of the user are revoked.
 	// @Tags Users
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
 	// @Param id path string true "User ID (UUID)"
-	// @Param request body model.AssignRoleRequest true "Role name"
-	// @Success 200 {object} model.APIResponse{data=model.UserResponse} "Role assigned"
+	// @Param request body model.AddRoleRequest true "Role name and optional profile"
+	// @Success 200 {object} model.APIResponse{data=model.UserResponse} "Role added"
 	// @Failure 400 {object} model.APIResponse "Invalid request body"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or user / role outside admin scope"
 	// @Failure 404 {object} model.APIResponse "User or role not found"
-	// @Router /users/{id}/role [put]
-	func (s *UserService) AssignRoleSwagger() {}
+	// @Failure 409 {object} model.APIResponse "User already has this role assignment"
+	// @Router /users/{id}/roles [post]
+	func (s *UserService) AddRoleSwagger() {}
+
+	// RemoveRole godoc
+	// @Summary Remove role from user (Admin only)
+	// @Description Remove a role from user. Mahasiswa / Dosen Wali profile is retired (kept for history). Existing tokens of the user are revoked.
+	// @Tags Users
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "User ID (UUID)"
+	// @Param roleId path string true "Role ID (UUID)"
+	// @Success 200 {object} model.APIResponse{data=model.UserResponse} "Role removed"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or user outside admin scope"
+	// @Failure 404 {object} model.APIResponse "User or role not found, or role not assigned"
+	// @Failure 409 {object} model.APIResponse "User must keep at least one role"
+	// @Router /users/{id}/roles/{roleId} [delete]
+	func (s *UserService) RemoveRoleSwagger() {}
+
+	// GetUserLockout godoc
+	// @Summary Get user login lockout status (Admin only)
+	// @Description Show failed login counter and lock state for user's username
+	// @Tags Users
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "User ID (UUID)"
+	// @Success 200 {object} model.APIResponse{data=model.LockoutStatusResponse} "Lockout status"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 404 {object} model.APIResponse "User not found"
+	// @Router /users/{id}/lockout [get]
+	func (s *LockoutService) GetUserLockoutSwagger() {}
+
+	// ClearUserLockout godoc
+	// @Summary Clear user login lockout (Admin only)
+	// @Description Reset failed login counter and unlock user's username plus the IP addresses that failed against it; returns cleared_ips (written to audit log)
+	// @Tags Users
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "User ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Lockout cleared"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 404 {object} model.APIResponse "User not found"
+	// @Router /users/{id}/lockout [delete]
+	func (s *LockoutService) ClearUserLockoutSwagger() {}
+
+	// ResetUserMFA godoc
+	// @Summary Reset user MFA (Admin only)
+	// @Description Remove user's TOTP secret and recovery codes (e.g. lost device). User must enroll again if the role requires MFA.
+	// @Tags Users
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "User ID (UUID)"
+	// @Success 200 {object} model.APIResponse "MFA reset"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 404 {object} model.APIResponse "User not found"
+	// @Router /users/{id}/mfa [delete]
+	func (s *MFAService) ResetUserMFASwagger() {}
+
+	// GetMFAPolicies godoc
+	// @Summary List MFA policy per role (Admin only)
+	// @Tags MFA
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.MFAPolicyResponse} "MFA policies"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Router /mfa/policies [get]
+	func (s *MFAService) GetPoliciesSwagger() {}
+
+	// UpdateMFAPolicy godoc
+	// @Summary Make MFA mandatory / optional for a role (Admin only)
+	// @Tags MFA
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param roleId path string true "Role ID (UUID)"
+	// @Param request body model.MFAPolicyRequest true "Policy"
+	// @Success 200 {object} model.APIResponse{data=model.MFAPolicyResponse} "Policy updated"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 404 {object} model.APIResponse "Role not found"
+	// @Router /mfa/policies/{roleId} [put]
+	func (s *MFAService) UpdatePolicySwagger() {}
+
+	// ==================== ROLE SERVICE ANNOTATIONS ======================
+
+	// GetRoles godoc
+	// @Summary List roles with permissions and member count (Admin only)
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.RoleDetailResponse} "Roles"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Router /roles [get]
+	func (s *RoleService) GetRolesSwagger() {}
+
+	// GetRoleByID godoc
+	// @Summary Get role detail (Admin only)
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Success 200 {object} model.APIResponse{data=model.RoleDetailResponse} "Role"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Failure 404 {object} model.APIResponse "Role not found"
+	// @Router /roles/{id} [get]
+	func (s *RoleService) GetRoleByIDSwagger() {}
+
+	// CreateRole godoc
+	// @Summary Create custom role (Admin only)
+	// @Tags Roles
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.RoleCreateRequest true "Role"
+	// @Success 201 {object} model.APIResponse{data=model.RoleDetailResponse} "Role created"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Failure 409 {object} model.APIResponse "Role name already exists"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /roles [post]
+	func (s *RoleService) CreateRoleSwagger() {}
+
+	// UpdateRole godoc
+	// @Summary Update role name / description (Admin only)
+	// @Description Built-in roles can only have their description changed
+	// @Tags Roles
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Param request body model.RoleUpdateRequest true "Changes"
+	// @Success 200 {object} model.APIResponse{data=model.RoleDetailResponse} "Role updated"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - built-in role cannot be renamed"
+	// @Failure 404 {object} model.APIResponse "Role not found"
+	// @Failure 409 {object} model.APIResponse "Role name already exists"
+	// @Router /roles/{id} [put]
+	func (s *RoleService) UpdateRoleSwagger() {}
+
+	// DeleteRole godoc
+	// @Summary Delete custom role (Admin only)
+	// @Description Built-in roles and roles that still have members cannot be deleted
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Role deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - built-in role"
+	// @Failure 404 {object} model.APIResponse "Role not found"
+	// @Failure 409 {object} model.APIResponse "Role still has members"
+	// @Router /roles/{id} [delete]
+	func (s *RoleService) DeleteRoleSwagger() {}
+
+	// GetRoleMembers godoc
+	// @Summary List users that have the role (Admin only)
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Param page query int false "Page number" default(1)
+	// @Param page_size query int false "Page size" default(10)
+	// @Success 200 {object} model.APIResponse{data=model.UserListResponse} "Role members"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Failure 404 {object} model.APIResponse "Role not found"
+	// @Router /roles/{id}/members [get]
+	func (s *RoleService) GetRoleMembersSwagger() {}
+
+	// GrantPermissions godoc
+	// @Summary Grant permissions to role (Admin only)
+	// @Description Members' access tokens are revoked so the new permissions apply on next refresh
+	// @Tags Roles
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Param request body model.RolePermissionsRequest true "Permission names"
+	// @Success 200 {object} model.APIResponse{data=model.RoleDetailResponse} "Permissions granted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Failure 404 {object} model.APIResponse "Role or permission not found"
+	// @Router /roles/{id}/permissions [post]
+	func (s *RoleService) GrantPermissionsSwagger() {}
+
+	// RevokePermission godoc
+	// @Summary Revoke permission from role (Admin only)
+	// @Description role:manage cannot be revoked from the caller's own role
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Role ID (UUID)"
+	// @Param permissionId path string true "Permission ID (UUID)"
+	// @Success 200 {object} model.APIResponse{data=model.RoleDetailResponse} "Permission revoked"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Failure 404 {object} model.APIResponse "Role / permission not found or not assigned"
+	// @Failure 409 {object} model.APIResponse "Would lock caller out of role management"
+	// @Router /roles/{id}/permissions/{permissionId} [delete]
+	func (s *RoleService) RevokePermissionSwagger() {}
+
+	// GetPermissions godoc
+	// @Summary List all permissions (Admin only)
+	// @Tags Roles
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.Permission} "Permissions"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - role:manage required"
+	// @Router /permissions [get]
+	func (s *RoleService) GetPermissionsSwagger() {}
 
 	// ==================== STUDENT SERVICE ANNOTATIONS ======================
 
@@ -215,7 +577,7 @@
 	// @Success 200 {object} model.APIResponse "Advisor set successfully"
 	// @Failure 400 {object} model.APIResponse "Invalid request body"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, or student / advisor outside admin scope"
 	// @Failure 404 {object} model.APIResponse "Student or advisor not found"
 	// @Failure 422 {object} model.APIResponse "Validation error"
 	// @Router /students/{id}/advisor [put]
@@ -252,11 +614,434 @@
 	// @Router /lecturers/{id}/advisees [get]
 	func (s *LecturerService) GetLecturerAdviseesSwagger() {}
 
+	// GetDelegations godoc
+	// @Summary Get lecturer's verification delegations
+	// @Description List delegations created by this lecturer, with active flag. Dosen Wali: own, Admin: all
+	// @Tags Lecturers
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Lecturer ID (UUID)"
+	// @Success 200 {object} model.APIResponse "List of delegations"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Lecturer not found"
+	// @Router /lecturers/{id}/delegations [get]
+	func (s *DelegationService) GetDelegationsSwagger() {}
+
+	// CreateDelegation godoc
+	// @Summary Delegate verification to another lecturer
+	// @Description Delegate lecturer can verify / reject this lecturer's advisees between starts_at and ends_at. The delegation expires automatically after ends_at. Dosen Wali: own, Admin: on behalf of the lecturer
+	// @Tags Lecturers
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Lecturer ID (UUID)"
+	// @Param request body model.DelegationCreateRequest true "Delegate and period"
+	// @Success 201 {object} model.APIResponse "Delegation created"
+	// @Failure 400 {object} model.APIResponse "Invalid period, self delegation, or inactive delegate"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Lecturer or delegate not found"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /lecturers/{id}/delegations [post]
+	func (s *DelegationService) CreateDelegationSwagger() {}
+
+	// RevokeDelegation godoc
+	// @Summary Revoke verification delegation
+	// @Description End a delegation before ends_at
+	// @Tags Lecturers
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Lecturer ID (UUID)"
+	// @Param delegationId path string true "Delegation ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Delegation revoked"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Lecturer or delegation not found"
+	// @Failure 409 {object} model.APIResponse "Delegation already revoked"
+	// @Router /lecturers/{id}/delegations/{delegationId} [delete]
+	func (s *DelegationService) RevokeDelegationSwagger() {}
+
+	// GetOverdue godoc
+	// @Summary Get overdue verifications of a lecturer's advisees
+	// @Description Submitted achievements older than the verification SLA (VERIFICATION_SLA). level is "escalation" once older than VERIFICATION_ESCALATE_AFTER. Only achievements whose active stage is held by this lecturer are listed: the advisor stage of their advisees (or of lecturers delegating to them; an active delegation moves it to the delegate), and stages of roles they hold. A background scheduler reminds the same stage holders and escalates to the program study admins by email.
+	// @Tags Lecturers
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Lecturer ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Overdue achievements"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Can only view own advisees"
+	// @Failure 404 {object} model.APIResponse "Lecturer not found"
+	// @Failure 500 {object} model.APIResponse "Failed to fetch overdue achievements"
+	// @Router /lecturers/{id}/overdue [get]
+	func (s *SLAService) GetOverdueSwagger() {}
+
+	// GetPipelines godoc
+	// @Summary List verification pipelines (Admin only)
+	// @Description Get all verification pipelines with their ordered stages, plus the default stages used when no pipeline matches
+	// @Tags Pipelines
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse "List of pipelines"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Router /pipelines [get]
+	func (s *PipelineService) GetPipelinesSwagger() {}
+
+	// CreatePipeline godoc
+	// @Summary Create verification pipeline (Admin only)
+	// @Description Create an ordered list of verification stages for an achievement type and/or competition level (empty = any). Each stage is held by a role, optionally only by that role's assignment scoped to program_study.
+	// @Tags Pipelines
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.PipelineRequest true "Pipeline"
+	// @Success 201 {object} model.APIResponse "Pipeline created"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 409 {object} model.APIResponse "Another pipeline covers the same achievement type and competition level"
+	// @Failure 422 {object} model.APIResponse "Validation error or stage role cannot verify"
+	// @Router /pipelines [post]
+	func (s *PipelineService) CreatePipelineSwagger() {}
+
+	// UpdatePipeline godoc
+	// @Summary Update verification pipeline (Admin only)
+	// @Description Replace the pipeline and all its stages. Not allowed while achievements are awaiting verification through it.
+	// @Tags Pipelines
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Pipeline ID (UUID)"
+	// @Param request body model.PipelineRequest true "Pipeline"
+	// @Success 200 {object} model.APIResponse "Pipeline updated"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Pipeline not found"
+	// @Failure 409 {object} model.APIResponse "Pipeline in use or duplicate match"
+	// @Failure 422 {object} model.APIResponse "Validation error or stage role cannot verify"
+	// @Router /pipelines/{id} [put]
+	func (s *PipelineService) UpdatePipelineSwagger() {}
+
+	// DeletePipeline godoc
+	// @Summary Delete verification pipeline (Admin only)
+	// @Description Delete a pipeline. Not allowed while achievements are awaiting verification through it.
+	// @Tags Pipelines
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Pipeline ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Pipeline deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Pipeline not found"
+	// @Failure 409 {object} model.APIResponse "Pipeline in use"
+	// @Router /pipelines/{id} [delete]
+	func (s *PipelineService) DeletePipelineSwagger() {}
+
+	// ==================== ACADEMIC CALENDAR SERVICE ANNOTATIONS ======================
+
+	// GetSemesters godoc
+	// @Summary List academic semesters
+	// @Description Get the academic calendar ordered by start date. With date, only the semester containing that date is returned (empty if none).
+	// @Tags Academic Calendar
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param date query string false "Date to map to a semester (YYYY-MM-DD)"
+	// @Success 200 {object} model.APIResponse{data=[]model.AcademicSemester} "List of semesters"
+	// @Failure 400 {object} model.APIResponse "Invalid date"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Router /academic-calendar [get]
+	func (s *AcademicCalendarService) GetSemestersSwagger() {}
+
+	// CreateSemester godoc
+	// @Summary Create academic semester (Admin only)
+	// @Description Add a semester, e.g. academic_year "2025/2026" and term "Ganjil". The name is "<academic_year> <term>". Date ranges of semesters must not overlap.
+	// @Tags Academic Calendar
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.AcademicSemesterRequest true "Semester data"
+	// @Success 201 {object} model.APIResponse{data=model.AcademicSemester} "Semester created"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 409 {object} model.APIResponse "Semester already exists or dates overlap another semester"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /academic-calendar [post]
+	func (s *AcademicCalendarService) CreateSemesterSwagger() {}
+
+	// UpdateSemester godoc
+	// @Summary Update academic semester (Admin only)
+	// @Description Replace a semester. Achievements are mapped by event date, so they move with the new range.
+	// @Tags Academic Calendar
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Semester ID (UUID)"
+	// @Param request body model.AcademicSemesterRequest true "Semester data"
+	// @Success 200 {object} model.APIResponse{data=model.AcademicSemester} "Semester updated"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Semester not found"
+	// @Failure 409 {object} model.APIResponse "Semester already exists or dates overlap another semester"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /academic-calendar/{id} [put]
+	func (s *AcademicCalendarService) UpdateSemesterSwagger() {}
+
+	// DeleteSemester godoc
+	// @Summary Delete academic semester (Admin only)
+	// @Description Remove a semester from the calendar. Achievements keep their event date; they are reported as unassigned.
+	// @Tags Academic Calendar
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Semester ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Semester deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Semester not found"
+	// @Router /academic-calendar/{id} [delete]
+	func (s *AcademicCalendarService) DeleteSemesterSwagger() {}
+
+	// ==================== TAG SERVICE ANNOTATIONS ======================
+
+	// GetTags godoc
+	// @Summary List / autocomplete tags
+	// @Description Get the curated tag vocabulary with the number of achievements using each tag (its name or any synonym). With q, names and synonyms are matched case-insensitively: prefix matches first, then by usage. canonical_only tells whether achievements may only use these tags.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param q query string false "Search text (autocomplete)"
+	// @Param limit query int false "Max results (default 10 with q, max 100)"
+	// @Success 200 {object} model.APIResponse{data=[]model.TagUsage} "List of tags"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Router /tags [get]
+	func (s *TagService) GetTagsSwagger() {}
+
+	// GetUnmanagedTags godoc
+	// @Summary List free-text tags (Admin only)
+	// @Description Tags used by achievements that are not in the vocabulary, grouped by case-insensitive form and ordered by usage. Candidates to create or merge into an existing tag.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.UnmanagedTag} "List of free-text tags"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Router /tags/unmanaged [get]
+	func (s *TagService) GetUnmanagedTagsSwagger() {}
+
+	// CreateTag godoc
+	// @Summary Create tag (Admin only)
+	// @Description Add a canonical tag with its synonyms. Names and synonyms are unique (case-insensitive) across the vocabulary. Existing achievements are not changed; use merge to rewrite them.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.TagRequest true "Tag data"
+	// @Success 201 {object} model.APIResponse{data=model.Tag} "Tag created"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 409 {object} model.APIResponse "Name or synonym already used by another tag"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /tags [post]
+	func (s *TagService) CreateTagSwagger() {}
+
+	// UpdateTag godoc
+	// @Summary Update tag (Admin only)
+	// @Description Replace the name and all synonyms of a tag. Existing achievements are not changed; use merge to rewrite them.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Tag ID (UUID)"
+	// @Param request body model.TagRequest true "Tag data"
+	// @Success 200 {object} model.APIResponse{data=model.Tag} "Tag updated"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Tag not found"
+	// @Failure 409 {object} model.APIResponse "Name or synonym already used by another tag"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /tags/{id} [put]
+	func (s *TagService) UpdateTagSwagger() {}
+
+	// DeleteTag godoc
+	// @Summary Delete tag (Admin only)
+	// @Description Remove a tag and its synonyms from the vocabulary. Achievements keep the tag text as a free-text tag.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Tag ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Tag deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Tag not found"
+	// @Router /tags/{id} [delete]
+	func (s *TagService) DeleteTagSwagger() {}
+
+	// MergeTags godoc
+	// @Summary Merge tags (Admin only)
+	// @Description Merge sources into this tag. A source that is the name or a synonym of another tag merges that whole tag (it is deleted and its name and synonyms become synonyms here); any other source text becomes a synonym. Afterwards every achievement using the name or a synonym of this tag is rewritten to the canonical name. Safe to retry if rewriting fails.
+	// @Tags Tags
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Target tag ID (UUID)"
+	// @Param request body model.TagMergeRequest true "Tags or free-text tags to merge"
+	// @Success 200 {object} model.APIResponse "Tags merged (data.tag, data.merged_tags, data.achievements_updated)"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Tag not found"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Failure 500 {object} model.APIResponse "Merge failed, or achievements were only partly rewritten (retry)"
+	// @Router /tags/{id}/merge [post]
+	func (s *TagService) MergeTagsSwagger() {}
+
+	// ==================== POINT RULE SERVICE ANNOTATIONS ======================
+
+	// GetPointRules godoc
+	// @Summary List point rules (Admin only)
+	// @Description Get all point rules grouped by achievement type
+	// @Tags Point Rules
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse "List of point rules"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Router /point-rules [get]
+	func (s *PointRuleService) GetPointRulesSwagger() {}
+
+	// CreatePointRule godoc
+	// @Summary Create point rule (Admin only)
+	// @Description Create a rule for an achievement type. competition_level, rank range and team size range are optional (empty = any) and read from details.competitionLevel, details.rank and details.teamSize (the member count for team achievements). The matching rule with the most conditions wins, then the highest points.
+	// @Tags Point Rules
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.PointRuleRequest true "Point rule"
+	// @Success 201 {object} model.APIResponse{data=model.PointRule} "Point rule created"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 422 {object} model.APIResponse "Validation error, unknown achievement type or inverted range"
+	// @Router /point-rules [post]
+	func (s *PointRuleService) CreatePointRuleSwagger() {}
+
+	// UpdatePointRule godoc
+	// @Summary Update point rule (Admin only)
+	// @Description Replace a point rule. Achievements already submitted keep their points.
+	// @Tags Point Rules
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Point rule ID (UUID)"
+	// @Param request body model.PointRuleRequest true "Point rule"
+	// @Success 200 {object} model.APIResponse{data=model.PointRule} "Point rule updated"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Point rule not found"
+	// @Failure 422 {object} model.APIResponse "Validation error, unknown achievement type or inverted range"
+	// @Router /point-rules/{id} [put]
+	func (s *PointRuleService) UpdatePointRuleSwagger() {}
+
+	// DeletePointRule godoc
+	// @Summary Delete point rule (Admin only)
+	// @Description Delete a point rule. Achievements already submitted keep their points.
+	// @Tags Point Rules
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Point rule ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Point rule deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Point rule not found"
+	// @Router /point-rules/{id} [delete]
+	func (s *PointRuleService) DeletePointRuleSwagger() {}
+
+	// ==================== ACHIEVEMENT TYPE SERVICE ANNOTATIONS ======================
+
+	// GetAchievementTypes godoc
+	// @Summary List achievement types
+	// @Description Get registered achievement types with the JSON Schema of their details, for rendering forms. Only active types unless include_inactive=true.
+	// @Tags Achievement Types
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param include_inactive query bool false "Include inactive types"
+	// @Success 200 {object} model.APIResponse "List of achievement types"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Router /achievement-types [get]
+	func (s *AchievementTypeService) GetAchievementTypesSwagger() {}
+
+	// GetAchievementType godoc
+	// @Summary Get achievement type
+	// @Description Get one achievement type with its details schema
+	// @Tags Achievement Types
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param code path string true "Achievement type code"
+	// @Success 200 {object} model.APIResponse{data=model.AchievementType} "Achievement type"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 404 {object} model.APIResponse "Achievement type not found"
+	// @Router /achievement-types/{code} [get]
+	func (s *AchievementTypeService) GetAchievementTypeSwagger() {}
+
+	// CreateAchievementType godoc
+	// @Summary Create achievement type (Admin only)
+	// @Description Register an achievement type. details_schema supports type, properties, required, additionalProperties, items, enum, minimum, maximum, minLength, maxLength, minItems, maxItems, pattern and format (date, date-time, email, uri).
+	// @Tags Achievement Types
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.AchievementTypeCreateRequest true "Achievement type"
+	// @Success 201 {object} model.APIResponse{data=model.AchievementType} "Achievement type created"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 409 {object} model.APIResponse "Code already exists"
+	// @Failure 422 {object} model.APIResponse "Validation error or unsupported schema"
+	// @Router /achievement-types [post]
+	func (s *AchievementTypeService) CreateAchievementTypeSwagger() {}
+
+	// UpdateAchievementType godoc
+	// @Summary Update achievement type (Admin only)
+	// @Description Update name, description, details schema or active flag. Existing achievements are not re-validated.
+	// @Tags Achievement Types
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param code path string true "Achievement type code"
+	// @Param request body model.AchievementTypeRequest true "Achievement type"
+	// @Success 200 {object} model.APIResponse{data=model.AchievementType} "Achievement type updated"
+	// @Failure 400 {object} model.APIResponse "Invalid request body"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement type not found"
+	// @Failure 422 {object} model.APIResponse "Validation error or unsupported schema"
+	// @Router /achievement-types/{code} [put]
+	func (s *AchievementTypeService) UpdateAchievementTypeSwagger() {}
+
 	// ==================== ACHIEVEMENT SERVICE ANNOTATIONS ======================
 
 	// CreateAchievement godoc
 	// @Summary Create achievement (Mahasiswa only)
-	// @Description Create new achievement draft
+	// @Description Create new achievement draft. Points are not set by the student; they are calculated from point rules on submit. For a team achievement, list every member (including yourself) with their role in team; each member gets their own entry, verified by their own advisor. event_date (and event_end_date for multi-day events) is required; it decides the period and academic semester the achievement is reported in. Tags matching a name or synonym in the tag vocabulary are stored under the canonical name; when TAGS_CANONICAL_ONLY is enabled, other tags are rejected.
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
@@ -267,7 +1052,7 @@
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden - Mahasiswa only"
 	// @Failure 404 {object} model.APIResponse "Student profile not found"
-	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Failure 422 {object} model.APIResponse "Validation error, event_end_date before event_date, unknown tags (canonical-only mode), or details do not match the achievement type schema (data.fields lists each violation)"
 	// @Router /achievements [post]
 	func (s *AchievementService) CreateAchievementSwagger() {}
 
@@ -281,9 +1066,14 @@
 	// @Param page query int false "Page number" default(1)
 	// @Param page_size query int false "Page size" default(10)
 	// @Param status query string false "Filter by status" Enums(draft, submitted, verified, rejected)
+	// @Param semester query string false "Academic semester ID (event_date within the semester)"
+	// @Param event_from query string false "Event date from (YYYY-MM-DD, inclusive)"
+	// @Param event_to query string false "Event date to (YYYY-MM-DD, inclusive)"
 	// @Success 200 {object} model.APIResponse{data=model.AchievementListResponse} "List of achievements"
+	// @Failure 400 {object} model.APIResponse "Invalid event date filter"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Semester not found"
 	// @Router /achievements [get]
 	func (s *AchievementService) GetAchievementsSwagger() {}
 
@@ -304,7 +1094,7 @@
 
 	// UpdateAchievement godoc
 	// @Summary Update achievement (Mahasiswa only, draft status)
-	// @Description Update achievement data (only if status is draft)
+	// @Description Update achievement data (only if status is draft). A team achievement can only be changed by its owner while every member's entry is draft; team replaces the member list (an empty list makes it individual). Tags are mapped to canonical names as on create.
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
@@ -314,62 +1104,162 @@
 	// @Success 200 {object} model.APIResponse{data=model.AchievementResponse} "Achievement updated"
 	// @Failure 400 {object} model.APIResponse "Can only update draft achievements"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement, or not the team owner"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
+	// @Failure 422 {object} model.APIResponse "Details do not match the achievement type schema (data.fields lists each violation), invalid team, or unknown tags (canonical-only mode)"
 	// @Router /achievements/{id} [put]
 	func (s *AchievementService) UpdateAchievementSwagger() {}
 
+	// GetAchievementTeam godoc
+	// @Summary Get team members of an achievement
+	// @Description List the members of a team achievement with their role and the status of each member's entry. An individual achievement returns its owner only.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Team members"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Router /achievements/{id}/team [get]
+	func (s *AchievementService) GetAchievementTeamSwagger() {}
+
+	// GetDuplicates godoc
+	// @Summary Get possible duplicates of an achievement (verifier)
+	// @Description List other achievements flagged as possible duplicates when either one was submitted, with the reasons (attachment_hash, same_event, similar_title) and links to the matching records. Achievement responses carry possible_duplicate for verifiers.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse{data=[]model.DuplicateMatch} "Possible duplicates (data.matches)"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Router /achievements/{id}/duplicates [get]
+	func (s *AchievementService) GetDuplicatesSwagger() {}
+
+	// GetPoints godoc
+	// @Summary Get achievement points
+	// @Description Get points, the point rule applied and the adjustment history. Drafts get an estimate from the current rules (estimated = true). Points are frozen once verified.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Achievement points"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Router /achievements/{id}/points [get]
+	func (s *AchievementService) GetPointsSwagger() {}
+
+	// AdjustPoints godoc
+	// @Summary Adjust achievement points (verifier of the active stage)
+	// @Description Override the calculated points while the achievement is awaiting verification. A written justification is required and recorded.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param request body model.AdjustPointsRequest true "New points and justification"
+	// @Success 200 {object} model.APIResponse{data=model.PointAdjustment} "Points adjusted"
+	// @Failure 400 {object} model.APIResponse "Achievement is not awaiting verification"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not the verifier of the active stage"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Achievement was verified or withdrawn meanwhile"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /achievements/{id}/points [put]
+	func (s *AchievementService) AdjustPointsSwagger() {}
+
 	// DeleteAchievement godoc
 	// @Summary Delete achievement (Mahasiswa only, draft status)
-	// @Description Soft delete achievement (only if status is draft)
+	// @Description Move achievement to the trash (only if status is draft). It can be restored until the retention period ends, then it is deleted permanently together with its attachment files. Deleting a team achievement (owner only) removes every member's entry.
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
 	// @Param id path string true "Achievement Reference ID (UUID)"
 	// @Success 200 {object} model.APIResponse "Achievement deleted"
-	// @Failure 400 {object} model.APIResponse "Can only delete draft achievements"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - only draft achievements can be deleted"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
 	// @Router /achievements/{id} [delete]
 	func (s *AchievementService) DeleteAchievementSwagger() {}
 
+	// GetTrash godoc
+	// @Summary Get own achievements in the trash (Mahasiswa only)
+	// @Description List deleted achievements of the logged-in student with the time each one will be deleted permanently (purge_at). Team achievements appear only in the owner's trash.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.TrashItem} "Achievements in the trash, total and retention_days"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Student profile not found"
+	// @Router /achievements/trash [get]
+	func (s *TrashService) GetTrashSwagger() {}
+
+	// RestoreAchievement godoc
+	// @Summary Restore achievement from the trash (Mahasiswa only)
+	// @Description Restore a deleted achievement as draft. Restoring a team achievement (owner only) restores the entries of every current team member.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Achievement restored"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - achievement is not in the trash"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement / not the team owner"
+	// @Failure 404 {object} model.APIResponse "Achievement not found or already deleted permanently"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
+	// @Router /achievements/{id}/restore [post]
+	func (s *TrashService) RestoreAchievementSwagger() {}
+
 	// SubmitForVerification godoc
 	// @Summary Submit achievement for verification (Mahasiswa only)
-	// @Description Submit draft achievement to advisor for verification
+	// @Description Submit draft achievement for verification. The verification pipeline is chosen from achievement_type and details.competitionLevel; without a matching pipeline only the advisor verifies. When the owner of a team achievement submits, every member entry still in draft is submitted too (data.team_submitted). The achievement is then compared with existing ones (attachment SHA-256, event name and date, similar title); matches are flagged for the verifier (data.duplicates) and never block the submission.
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
 	// @Param id path string true "Achievement Reference ID (UUID)"
 	// @Success 200 {object} model.APIResponse "Achievement submitted"
-	// @Failure 400 {object} model.APIResponse "Achievement already submitted"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - only draft achievements can be submitted"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
 	// @Router /achievements/{id}/submit [post]
 	func (s *AchievementService) SubmitForVerificationSwagger() {}
 
 	// VerifyAchievement godoc
-	// @Summary Verify achievement (Dosen Wali only)
-	// @Description Approve submitted achievement (only for your advisees)
+	// @Summary Verify achievement (current pipeline stage)
+	// @Description Approve the current verification stage. The achievement becomes verified only when the final stage approves; earlier stages advance current_stage. Advisor stages accept your advisees or advisees of a lecturer who delegated verification to you (recorded with on_behalf_of).
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
 	// @Param id path string true "Achievement Reference ID (UUID)"
-	// @Success 200 {object} model.APIResponse "Achievement verified"
-	// @Failure 400 {object} model.APIResponse "Achievement must be in submitted status"
+	// @Success 200 {object} model.APIResponse "Stage approved or achievement verified"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - achievement must be submitted"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Not advisor of this student"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not the assignee of the current stage"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
 	// @Router /achievements/{id}/verify [post]
 	func (s *AchievementService) VerifyAchievementSwagger() {}
 
 	// RejectAchievement godoc
-	// @Summary Reject achievement (Dosen Wali only)
-	// @Description Reject submitted achievement with notes
+	// @Summary Reject achievement (current pipeline stage)
+	// @Description Reject submitted achievement with notes at any stage. Delegated actions are recorded with on_behalf_of.
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
@@ -377,17 +1267,99 @@
 	// @Param id path string true "Achievement Reference ID (UUID)"
 	// @Param request body model.RejectAchievementRequest true "Rejection note"
 	// @Success 200 {object} model.APIResponse "Achievement rejected"
-	// @Failure 400 {object} model.APIResponse "Achievement must be in submitted status"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - achievement must be submitted"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
-	// @Failure 403 {object} model.APIResponse "Forbidden - Not advisor of this student"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not the assignee of the current stage"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
 	// @Failure 422 {object} model.APIResponse "Validation error - rejection note required"
 	// @Router /achievements/{id}/reject [post]
 	func (s *AchievementService) RejectAchievementSwagger() {}
 
+	// BulkVerifyAchievements godoc
+	// @Summary Verify many achievements (current pipeline stage)
+	// @Description Approve the current stage of up to 100 achievements. Each item is checked and written individually, like POST /achievements/{id}/verify; failures are reported per item with the status code the single endpoint would return. An optional note (shared or per item in notes) is stored in the history.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.BulkDecisionRequest true "Reference IDs and optional notes"
+	// @Success 200 {object} model.APIResponse{data=[]BulkItemResult} "Per-item results"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 422 {object} model.APIResponse "Validation error - 1 to 100 ids"
+	// @Router /achievements/bulk/verify [post]
+	func (s *AchievementService) BulkVerifyAchievementsSwagger() {}
+
+	// BulkRejectAchievements godoc
+	// @Summary Reject many achievements (current pipeline stage)
+	// @Description Reject up to 100 submitted achievements. Every item needs a rejection note, either the shared note or notes[id]. Results are reported per item.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param request body model.BulkDecisionRequest true "Reference IDs and rejection notes"
+	// @Success 200 {object} model.APIResponse{data=[]BulkItemResult} "Per-item results"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 422 {object} model.APIResponse "Validation error - 1 to 100 ids"
+	// @Router /achievements/bulk/reject [post]
+	func (s *AchievementService) BulkRejectAchievementsSwagger() {}
+
+	// RequestRevision godoc
+	// @Summary Request revision (current pipeline stage)
+	// @Description Send a submitted achievement back to the student for changes (status revision_requested). Unlike reject, the student is expected to reopen, fix and resubmit it.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param request body model.RequestRevisionRequest true "Revision note"
+	// @Success 200 {object} model.APIResponse "Revision requested"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - achievement must be submitted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not the assignee of the current stage"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
+	// @Failure 422 {object} model.APIResponse "Validation error - note required"
+	// @Router /achievements/{id}/request-revision [post]
+	func (s *AchievementService) RequestRevisionSwagger() {}
+
+	// WithdrawAchievement godoc
+	// @Summary Withdraw submitted achievement (Mahasiswa only)
+	// @Description Pull a submitted achievement back to draft before it is reviewed
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Achievement withdrawn to draft"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - only submitted achievements can be withdrawn"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
+	// @Router /achievements/{id}/withdraw [post]
+	func (s *AchievementService) WithdrawAchievementSwagger() {}
+
+	// ReopenAchievement godoc
+	// @Summary Reopen rejected achievement (Mahasiswa only)
+	// @Description Move a rejected or revision_requested achievement back to draft so it can be edited and resubmitted. The advisor's note is kept.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Achievement reopened as draft"
+	// @Failure 400 {object} model.APIResponse "Illegal status transition - only rejected or revision_requested achievements can be reopened"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 409 {object} model.APIResponse "Status changed by another request"
+	// @Router /achievements/{id}/reopen [post]
+	func (s *AchievementService) ReopenAchievementSwagger() {}
+
 	// UploadAttachment godoc
 	// @Summary Upload attachment file (Mahasiswa only)
-	// @Description Upload file attachment to achievement (PDF, JPG, PNG max 5MB)
+	// @Description Upload file attachment to achievement (PDF, JPG, PNG max 5MB). The SHA-256 of the file is stored for duplicate detection.
 	// @Tags Achievements
 	// @Accept multipart/form-data
 	// @Produce json
@@ -404,7 +1376,7 @@
 
 	// GetAchievementHistory godoc
 	// @Summary Get achievement status history
-	// @Description Get timeline of achievement status changes
+	// @Description Get timeline of achievement status changes, one entry per transition (from/to status, actor, role, note, on_behalf_of)
 	// @Tags Achievements
 	// @Accept json
 	// @Produce json
@@ -414,36 +1386,161 @@
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden"
 	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 500 {object} model.APIResponse "Failed to fetch history"
 	// @Router /achievements/{id}/history [get]
 	func (s *AchievementService) GetAchievementHistorySwagger() {}
 
+	// GetAchievementVersions godoc
+	// @Summary Get achievement content versions
+	// @Description List snapshots of the achievement document, one per save (create, update, attachment upload). Each version carries the status and status_history_id current at the time of the edit.
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Achievement versions"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Failure 500 {object} model.APIResponse "Failed to fetch versions"
+	// @Router /achievements/{id}/versions [get]
+	func (s *AchievementService) GetAchievementVersionsSwagger() {}
+
+	// DiffAchievementVersions godoc
+	// @Summary Compare two achievement versions
+	// @Description Field-level diff between two versions: scalar fields, details (nested keys as details.a.b), tags and attachments (matched by file_url)
+	// @Tags Achievements
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param from query int true "Base version number"
+	// @Param to query int true "Target version number"
+	// @Success 200 {object} model.APIResponse{data=model.AchievementDiff} "Version diff"
+	// @Failure 400 {object} model.APIResponse "Invalid version numbers"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement or version not found"
+	// @Router /achievements/{id}/versions/diff [get]
+	func (s *AchievementService) DiffAchievementVersionsSwagger() {}
+
+	// ==================== COMMENT SERVICE ANNOTATIONS ======================
+
+	// GetComments godoc
+	// @Summary Get achievement comments
+	// @Description Threaded discussion on an achievement (nested replies, oldest first). Comments are kept across resubmissions; achievement_status shows the status when each comment was posted. Deleted comments are returned with an empty body.
+	// @Tags Comments
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Comment thread"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement not found"
+	// @Router /achievements/{id}/comments [get]
+	func (s *CommentService) GetCommentsSwagger() {}
+
+	// CreateComment godoc
+	// @Summary Post a comment
+	// @Description Post a comment or reply (parent_id). Optionally anchor it to a field (title, description, achievement_type, tags, points, details.<key>) or to an attachment (anchor_ref = file_url).
+	// @Tags Comments
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param request body model.CommentCreateRequest true "Comment"
+	// @Success 201 {object} model.APIResponse "Comment created"
+	// @Failure 400 {object} model.APIResponse "Invalid anchor or parent"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden"
+	// @Failure 404 {object} model.APIResponse "Achievement or parent comment not found"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /achievements/{id}/comments [post]
+	func (s *CommentService) CreateCommentSwagger() {}
+
+	// UpdateComment godoc
+	// @Summary Edit a comment
+	// @Description Author only, within the edit window (default 15 minutes after posting)
+	// @Tags Comments
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param commentId path string true "Comment ID (UUID)"
+	// @Param request body model.CommentUpdateRequest true "New body"
+	// @Success 200 {object} model.APIResponse "Comment updated"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Not the author or edit window expired"
+	// @Failure 404 {object} model.APIResponse "Comment not found"
+	// @Failure 422 {object} model.APIResponse "Validation error"
+	// @Router /achievements/{id}/comments/{commentId} [put]
+	func (s *CommentService) UpdateCommentSwagger() {}
+
+	// DeleteComment godoc
+	// @Summary Delete a comment
+	// @Description Soft delete by the author within the delete window (default 1 hour after posting). Replies stay in the thread.
+	// @Tags Comments
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Achievement Reference ID (UUID)"
+	// @Param commentId path string true "Comment ID (UUID)"
+	// @Success 200 {object} model.APIResponse "Comment deleted"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Not the author or delete window expired"
+	// @Failure 404 {object} model.APIResponse "Comment not found"
+	// @Router /achievements/{id}/comments/{commentId} [delete]
+	func (s *CommentService) DeleteCommentSwagger() {}
+
 	// ==================== REPORT SERVICE ANNOTATIONS ======================
 
 	// GetStatistics godoc
 	// @Summary Get achievement statistics
-	// @Description Get statistics based on role (Mahasiswa: own, Dosen: advisees, Admin: all)
+	// @Description Get statistics based on role (Mahasiswa: own, Dosen: advisees, Admin: all). by_period (YYYY-MM) and by_semester are keyed by event date; older achievements without one use their creation date.
 	// @Tags Reports
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
+	// @Param semester query string false "Academic semester ID (event_date within the semester)"
+	// @Param event_from query string false "Event date from (YYYY-MM-DD, inclusive)"
+	// @Param event_to query string false "Event date to (YYYY-MM-DD, inclusive)"
 	// @Success 200 {object} model.APIResponse "Achievement statistics"
+	// @Failure 400 {object} model.APIResponse "Invalid event date filter"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden"
-	// @Failure 404 {object} model.APIResponse "Profile not found"
+	// @Failure 404 {object} model.APIResponse "Profile or semester not found"
 	// @Router /reports/statistics [get]
 	func (s *ReportService) GetStatisticsSwagger() {}
 
 	// GetStudentReport godoc
 	// @Summary Get student achievement report
-	// @Description Get comprehensive achievement report for a student
+	// @Description Get comprehensive achievement report for a student, optionally limited to a semester or event date range
 	// @Tags Reports
 	// @Accept json
 	// @Produce json
 	// @Security BearerAuth
 	// @Param id path string true "Student ID (UUID)"
+	// @Param semester query string false "Academic semester ID (event_date within the semester)"
+	// @Param event_from query string false "Event date from (YYYY-MM-DD, inclusive)"
+	// @Param event_to query string false "Event date to (YYYY-MM-DD, inclusive)"
 	// @Success 200 {object} model.APIResponse "Student report"
+	// @Failure 400 {object} model.APIResponse "Invalid event date filter"
 	// @Failure 401 {object} model.APIResponse "Unauthorized"
 	// @Failure 403 {object} model.APIResponse "Forbidden - Not authorized for this student"
-	// @Failure 404 {object} model.APIResponse "Student not found"
+	// @Failure 404 {object} model.APIResponse "Student or semester not found"
 	// @Router /reports/student/{id} [get]
-	func (s *ReportService) GetStudentReportSwagger() {}
\ No newline at end of file
+	func (s *ReportService) GetStudentReportSwagger() {}
+
+	// GetDuplicateClusters godoc
+	// @Summary Get duplicate clusters (Admin only)
+	// @Description Group achievements linked by duplicate flags into clusters (A matches B and B matches C gives one cluster). Deleted achievements are left out. Admins scoped to program studies only see flags where both achievements are in scope.
+	// @Tags Reports
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} model.APIResponse{data=[]model.DuplicateCluster} "Duplicate clusters (data.clusters)"
+	// @Failure 401 {object} model.APIResponse "Unauthorized"
+	// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+	// @Router /reports/duplicates [get]
+	func (s *ReportService) GetDuplicateClustersSwagger() {}
\ No newline at end of file
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== TAG TAXONOMY ======================
+// Admin mengelola kosakata tag (nama kanonik + sinonim). Tag achievement
+// yang cocok dengan nama / sinonim selalu disimpan dalam nama kanoniknya;
+// jika TagPolicy.CanonicalOnly aktif, tag di luar kosakata ditolak.
+// Merge menulis ulang tag di dokumen MongoDB yang sudah ada.
+//
+
+const (
+	defaultTagSuggestions = 10  // batas hasil autocomplete (?q=) jika limit tidak diisi
+	maxTagSuggestions     = 100 // batas maksimum ?limit=
+)
+
+// TagPolicy - Penegakan kosakata tag untuk achievement baru / yang diubah
+type TagPolicy struct {
+	CanonicalOnly bool
+}
+
+type TagService struct {
+	tagRepo  repository.TagRepository
+	audit    *AuditService
+	policy   TagPolicy
+	validate *validator.Validate
+}
+
+func NewTagService(
+	tagRepo repository.TagRepository,
+	audit *AuditService,
+	policy TagPolicy,
+) *TagService {
+	return &TagService{
+		tagRepo:  tagRepo,
+		audit:    audit,
+		policy:   policy,
+		validate: validator.New(),
+	}
+}
+
+//
+// ==================== GET TAGS (GET /tags) ======================
+// Autocomplete: ?q= mencocokkan nama & sinonim (awalan lebih dulu, lalu yang
+// paling sering dipakai). Tanpa q: seluruh kosakata.
+//
+
+func (s *TagService) GetTags(c *fiber.Ctx) error {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch tags",
+		})
+	}
+	usage, err := s.tagRepo.CountUsage()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to count tag usage",
+		})
+	}
+
+	index := tagIndex(tags)
+	counts := make(map[string]int)
+	for value, count := range usage {
+		if tag, ok := index[model.NormalizeTag(value)]; ok {
+			counts[tag.ID] += count
+		}
+	}
+
+	query := model.NormalizeTag(c.Query("q"))
+	limit := c.QueryInt("limit", 0)
+	if query != "" && limit <= 0 {
+		limit = defaultTagSuggestions
+	}
+	limit = min(limit, maxTagSuggestions)
+
+	// rank: 0 = awalan cocok, 1 = cocok di tengah
+	type match struct {
+		usage model.TagUsage
+		rank  int
+	}
+	matches := []match{}
+	for _, tag := range tags {
+		rank := 0
+		if query != "" {
+			rank = -1
+			for _, variant := range tag.Variants() {
+				if strings.HasPrefix(variant, query) {
+					rank = 0
+					break
+				}
+				if strings.Contains(variant, query) {
+					rank = 1
+				}
+			}
+			if rank < 0 {
+				continue
+			}
+		}
+		matches = append(matches, match{model.TagUsage{Tag: tag, UsageCount: counts[tag.ID]}, rank})
+	}
+
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].rank != matches[j].rank {
+				return matches[i].rank < matches[j].rank
+			}
+			return matches[i].usage.UsageCount > matches[j].usage.UsageCount
+		})
+	}
+
+	result := []model.TagUsage{}
+	for _, m := range matches {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		result = append(result, m.usage)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"tags":           result,
+			"total":          len(result),
+			"canonical_only": s.policy.CanonicalOnly,
+		},
+	})
+}
+
+//
+// ==================== GET UNMANAGED TAGS (GET /tags/unmanaged) ======================
+// Tag teks bebas yang dipakai achievement tapi belum ada di kosakata,
+// dikelompokkan per bentuk normal (paling sering dipakai dulu)
+//
+
+func (s *TagService) GetUnmanagedTags(c *fiber.Ctx) error {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch tags",
+		})
+	}
+	usage, err := s.tagRepo.CountUsage()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to count tag usage",
+		})
+	}
+
+	index := tagIndex(tags)
+	groups := make(map[string]*model.UnmanagedTag)
+	top := make(map[string]int) // pemakaian penulisan yang dijadikan Name
+	for value, count := range usage {
+		normalized := model.NormalizeTag(value)
+		if _, managed := index[normalized]; managed || normalized == "" {
+			continue
+		}
+		group, ok := groups[normalized]
+		if !ok {
+			group = &model.UnmanagedTag{Variants: []string{}}
+			groups[normalized] = group
+		}
+		group.Variants = append(group.Variants, value)
+		group.UsageCount += count
+		if count > top[normalized] || (count == top[normalized] && value < group.Name) {
+			group.Name = value
+			top[normalized] = count
+		}
+	}
+
+	result := []model.UnmanagedTag{}
+	for _, group := range groups {
+		sort.Strings(group.Variants)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UsageCount != result[j].UsageCount {
+			return result[i].UsageCount > result[j].UsageCount
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"tags":  result,
+			"total": len(result),
+		},
+	})
+}
+
+//
+// ==================== CREATE TAG (POST /tags) ======================
+// Achievement yang sudah ada tidak diubah; gunakan merge untuk menulis ulang
+//
+
+func (s *TagService) CreateTag(c *fiber.Ctx) error {
+	tag, err := s.parseTag(c, "")
+	if tag == nil {
+		return err
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create tag",
+		})
+	}
+
+	s.audit.Record(c, "tag.create", "tag", tag.ID, tagAuditDetails(tag))
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "tag created successfully",
+		Data:    tag,
+	})
+}
+
+//
+// ==================== UPDATE TAG (PUT /tags/:id) ======================
+// Mengganti nama dan seluruh sinonim
+//
+
+func (s *TagService) UpdateTag(c *fiber.Ctx) error {
+	existing, err := s.tagRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "tag not found",
+		})
+	}
+
+	tag, err := s.parseTag(c, existing.ID)
+	if tag == nil {
+		return err
+	}
+	tag.ID = existing.ID
+	tag.CreatedAt = existing.CreatedAt
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update tag",
+		})
+	}
+
+	s.audit.Record(c, "tag.update", "tag", tag.ID, tagAuditDetails(tag))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "tag updated successfully",
+		Data:    tag,
+	})
+}
+
+//
+// ==================== DELETE TAG (DELETE /tags/:id) ======================
+// Achievement tetap menyimpan teks tag-nya (menjadi tag teks bebas)
+//
+
+func (s *TagService) DeleteTag(c *fiber.Ctx) error {
+	tag, err := s.tagRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "tag not found",
+		})
+	}
+
+	if err := s.tagRepo.Delete(tag.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete tag",
+		})
+	}
+
+	s.audit.Record(c, "tag.delete", "tag", tag.ID, tagAuditDetails(tag))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "tag deleted successfully",
+	})
+}
+
+//
+// ==================== MERGE TAGS (POST /tags/:id/merge) ======================
+// Sumber yang merupakan tag lain: tag itu dihapus, nama & sinonimnya menjadi
+// sinonim target. Sumber teks bebas menjadi sinonim target. Setelah itu semua
+// achievement yang memakai nama / sinonim target ditulis ulang ke nama target.
+// Aman diulang jika penulisan ulang MongoDB gagal.
+//
+
+func (s *TagService) MergeTags(c *fiber.Ctx) error {
+	target, err := s.tagRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "tag not found",
+		})
+	}
+
+	req := new(model.TagMergeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch tags",
+		})
+	}
+	index := tagIndex(tags)
+
+	own := make(map[string]bool)
+	for _, variant := range target.Variants() {
+		own[variant] = true
+	}
+	addSynonym := func(value string) {
+		value = strings.Join(strings.Fields(value), " ")
+		if normalized := model.NormalizeTag(value); !own[normalized] {
+			own[normalized] = true
+			target.Synonyms = append(target.Synonyms, value)
+		}
+	}
+
+	sourceIDs := []string{}
+	merged := []string{}
+	for _, source := range req.Sources {
+		normalized := model.NormalizeTag(source)
+		if normalized == "" || own[normalized] {
+			continue
+		}
+		if other, ok := index[normalized]; ok && other.ID != target.ID {
+			sourceIDs = append(sourceIDs, other.ID)
+			merged = append(merged, other.Name)
+			addSynonym(other.Name)
+			for _, synonym := range other.Synonyms {
+				addSynonym(synonym)
+			}
+			continue
+		}
+		addSynonym(source)
+	}
+
+	if err := s.tagRepo.Merge(target, sourceIDs); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to merge tags",
+		})
+	}
+
+	details := tagAuditDetails(target)
+	details["merged_tags"] = merged
+	s.audit.Record(c, "tag.merge", "tag", target.ID, details)
+
+	rewritten, err := s.tagRepo.RewriteAchievementTags(target.Variants(), target.Name)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("tags merged but only %d achievements were rewritten; retry the merge", rewritten),
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "tags merged successfully",
+		Data: fiber.Map{
+			"tag":                  target,
+			"merged_tags":          merged,
+			"achievements_updated": rewritten,
+		},
+	})
+}
+
+//
+// ==================== HELPER ======================
+//
+
+// parseTag - Parse & validasi request; nama dan sinonim tidak boleh dipakai
+// tag lain (selain excludeID). Return nil tag jika response error sudah dikirim.
+func (s *TagService) parseTag(c *fiber.Ctx, excludeID string) (*model.Tag, error) {
+	req := new(model.TagRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	tag := &model.Tag{
+		Name:     strings.Join(strings.Fields(req.Name), " "),
+		Synonyms: []string{},
+	}
+	if tag.Name == "" {
+		return nil, c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "name must not be blank",
+		})
+	}
+	seen := map[string]bool{model.NormalizeTag(tag.Name): true}
+	for _, synonym := range req.Synonyms {
+		synonym = strings.Join(strings.Fields(synonym), " ")
+		if normalized := model.NormalizeTag(synonym); normalized != "" && !seen[normalized] {
+			seen[normalized] = true
+			tag.Synonyms = append(tag.Synonyms, synonym)
+		}
+	}
+
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch tags",
+		})
+	}
+	for _, other := range tags {
+		if other.ID == excludeID {
+			continue
+		}
+		for _, variant := range other.Variants() {
+			if seen[variant] {
+				return nil, c.Status(409).JSON(model.APIResponse{
+					Status: "error",
+					Error:  fmt.Sprintf("'%s' is already used by tag '%s' (merge the tags instead)", variant, other.Name),
+				})
+			}
+		}
+	}
+
+	return tag, nil
+}
+
+func tagAuditDetails(tag *model.Tag) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     tag.Name,
+		"synonyms": tag.Synonyms,
+	}
+}
+
+// tagIndex - Bentuk normal nama & sinonim → tag kanonik
+func tagIndex(tags []model.Tag) map[string]*model.Tag {
+	index := make(map[string]*model.Tag)
+	for i := range tags {
+		for _, variant := range tags[i].Variants() {
+			index[variant] = &tags[i]
+		}
+	}
+	return index
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupTagTest() (*TagService, *mocks.MockTagRepository) {
+	mockTagRepo := new(mocks.MockTagRepository)
+	mockAuditRepo := new(mocks.MockAuditLogRepository)
+
+	service := NewTagService(mockTagRepo, NewAuditService(mockAuditRepo), TagPolicy{})
+
+	mockAuditRepo.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+	return service, mockTagRepo
+}
+
+// vocabulary - Kosakata tag untuk test
+func vocabulary() []model.Tag {
+	return []model.Tag{
+		{ID: "tag-ai", Name: "AI", Synonyms: []string{"Artificial Intelligence"}},
+		{ID: "tag-ml", Name: "Machine Learning", Synonyms: []string{"ML"}},
+		{ID: "tag-web", Name: "Web Development", Synonyms: []string{}},
+	}
+}
+
+// ==================== GET TAGS ====================
+
+func TestGetTags_Autocomplete(t *testing.T) {
+	service, mockTagRepo := setupTagTest()
+	mockTagRepo.On("GetAll").Return(vocabulary(), nil)
+	mockTagRepo.On("CountUsage").Return(map[string]int{
+		"AI":                      2,
+		"ai":                      1,
+		"artificial intelligence": 4,
+		"Machine Learning":        3,
+		"robotik":                 5,
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/tags", service.GetTags)
+
+	type result struct {
+		Data struct {
+			Tags []model.TagUsage `json:"tags"`
+		} `json:"data"`
+	}
+	search := func(query string) []model.TagUsage {
+		resp, _ := app.Test(httptest.NewRequest("GET", "/tags"+query, nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		var body result
+		json.NewDecoder(resp.Body).Decode(&body)
+		return body.Data.Tags
+	}
+
+	// Awalan nama / sinonim lebih dulu, lalu yang cocok di tengah kata
+	tags := search("?q=a")
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "AI", tags[0].Name)
+		assert.Equal(t, 7, tags[0].UsageCount)
+		assert.Equal(t, "Machine Learning", tags[1].Name)
+		assert.Equal(t, 3, tags[1].UsageCount)
+	}
+
+	tags = search("?q=ml")
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "Machine Learning", tags[0].Name)
+	}
+
+	assert.Len(t, search(""), 3)
+	assert.Len(t, search("?q=web&limit=0"), 1)
+	assert.Empty(t, search("?q=robot"))
+}
+
+func TestGetUnmanagedTags(t *testing.T) {
+	service, mockTagRepo := setupTagTest()
+	mockTagRepo.On("GetAll").Return(vocabulary(), nil)
+	mockTagRepo.On("CountUsage").Return(map[string]int{
+		"ai":      1,
+		"Robotik": 3,
+		"robotik": 1,
+		"IoT":     2,
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/tags/unmanaged", service.GetUnmanagedTags)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/tags/unmanaged", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		Data struct {
+			Tags []model.UnmanagedTag `json:"tags"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	assert.Equal(t, []model.UnmanagedTag{
+		{Name: "Robotik", Variants: []string{"Robotik", "robotik"}, UsageCount: 4},
+		{Name: "IoT", Variants: []string{"IoT"}, UsageCount: 2},
+	}, body.Data.Tags)
+}
+
+// ==================== CREATE TAG ====================
+
+func TestCreateTag(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"tag baru", `{"name": " Internet  of Things ", "synonyms": ["IoT", "iot", "Internet of things"]}`, 201},
+		{"nama dipakai sinonim tag lain", `{"name": "artificial intelligence"}`, 409},
+		{"sinonim dipakai tag lain", `{"name": "Deep Learning", "synonyms": ["ml"]}`, 409},
+		{"nama kosong", `{"name": "   "}`, 422},
+		{"tanpa nama", `{"synonyms": ["IoT"]}`, 422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockTagRepo := setupTagTest()
+			mockTagRepo.On("GetAll").Return(vocabulary(), nil).Maybe()
+			mockTagRepo.On("Create", mock.AnythingOfType("*model.Tag")).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Post("/tags", service.CreateTag)
+
+			req := httptest.NewRequest("POST", "/tags", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 201 {
+				mockTagRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			// Spasi dirapikan; sinonim yang sama dengan nama / sinonim lain dibuang
+			mockTagRepo.AssertCalled(t, "Create", mock.MatchedBy(func(tag *model.Tag) bool {
+				return tag.Name == "Internet of Things" && assert.ObjectsAreEqual([]string{"IoT"}, tag.Synonyms)
+			}))
+		})
+	}
+}
+
+func TestUpdateTag_KeepsOwnSynonyms(t *testing.T) {
+	service, mockTagRepo := setupTagTest()
+	existing := vocabulary()[0]
+	mockTagRepo.On("FindByID", "tag-ai").Return(&existing, nil)
+	mockTagRepo.On("GetAll").Return(vocabulary(), nil)
+	mockTagRepo.On("Update", mock.AnythingOfType("*model.Tag")).Return(nil)
+
+	app := fiber.New()
+	app.Put("/tags/:id", service.UpdateTag)
+
+	body := `{"name": "Artificial Intelligence", "synonyms": ["AI"]}`
+	req := httptest.NewRequest("PUT", "/tags/tag-ai", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockTagRepo.AssertCalled(t, "Update", mock.MatchedBy(func(tag *model.Tag) bool {
+		return tag.ID == "tag-ai" && tag.Name == "Artificial Intelligence"
+	}))
+}
+
+// ==================== MERGE TAGS ====================
+
+func TestMergeTags(t *testing.T) {
+	service, mockTagRepo := setupTagTest()
+	target := vocabulary()[0]
+	mockTagRepo.On("FindByID", "tag-ai").Return(&target, nil)
+	mockTagRepo.On("GetAll").Return(vocabulary(), nil)
+	mockTagRepo.On("Merge", mock.AnythingOfType("*model.Tag"), []string{"tag-ml"}).Return(nil)
+	mockTagRepo.On("RewriteAchievementTags", mock.Anything, "AI").Return(4, nil)
+
+	app := fiber.New()
+	app.Post("/tags/:id/merge", service.MergeTags)
+
+	// "ml" = sinonim tag lain (tag itu ikut di-merge), "A.I." = teks bebas,
+	// "ai" = nama target sendiri (diabaikan)
+	body := `{"sources": ["ml", "A.I.", "ai"]}`
+	req := httptest.NewRequest("POST", "/tags/tag-ai/merge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	wantSynonyms := []string{"Artificial Intelligence", "Machine Learning", "ML", "A.I."}
+	mockTagRepo.AssertCalled(t, "Merge", mock.MatchedBy(func(tag *model.Tag) bool {
+		return tag.ID == "tag-ai" && assert.ObjectsAreEqual(wantSynonyms, tag.Synonyms)
+	}), []string{"tag-ml"})
+	mockTagRepo.AssertCalled(t, "RewriteAchievementTags",
+		[]string{"ai", "artificial intelligence", "machine learning", "ml", "a.i."}, "AI")
+
+	var result struct {
+		Data struct {
+			MergedTags          []string `json:"merged_tags"`
+			AchievementsUpdated int      `json:"achievements_updated"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"Machine Learning"}, result.Data.MergedTags)
+	assert.Equal(t, 4, result.Data.AchievementsUpdated)
+}
+
+func TestMergeTags_EmptySources(t *testing.T) {
+	service, mockTagRepo := setupTagTest()
+	target := vocabulary()[0]
+	mockTagRepo.On("FindByID", "tag-ai").Return(&target, nil)
+
+	app := fiber.New()
+	app.Post("/tags/:id/merge", service.MergeTags)
+
+	req := httptest.NewRequest("POST", "/tags/tag-ai/merge", strings.NewReader(`{"sources": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 422, resp.StatusCode)
+	mockTagRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything)
+}
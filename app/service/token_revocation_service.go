@@ -0,0 +1,185 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+	"project_uas/utils"
+)
+
+//
+// ==================== TOKEN REVOCATION (CACHE + POSTGRES) ======================
+// Revocation disimpan di tabel token_revocations dan di-cache di memory,
+// sehingga middleware AuthRequired tidak perlu query DB di setiap request.
+// Cache di-sync ulang secara berkala supaya revocation dari instance lain ikut terbaca.
+//
+
+type TokenRevocationService struct {
+	revocationRepo   repository.TokenRevocationRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> expires_at
+	users  map[string]time.Time // user_id -> revoked_before
+}
+
+func NewTokenRevocationService(
+	revocationRepo repository.TokenRevocationRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+) *TokenRevocationService {
+	return &TokenRevocationService{
+		revocationRepo:   revocationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		tokens:           make(map[string]time.Time),
+		users:            make(map[string]time.Time),
+	}
+}
+
+// Load - Isi ulang cache dari database
+func (s *TokenRevocationService) Load() error {
+	revocations, err := s.revocationRepo.GetActive()
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]time.Time)
+	users := make(map[string]time.Time)
+	for _, rev := range revocations {
+		switch rev.Kind {
+		case "token":
+			if rev.JTI != nil {
+				tokens[*rev.JTI] = rev.ExpiresAt
+			}
+		case "user":
+			if rev.UserID != nil && rev.RevokedBefore != nil {
+				if current, ok := users[*rev.UserID]; !ok || rev.RevokedBefore.After(current) {
+					users[*rev.UserID] = *rev.RevokedBefore
+				}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.tokens = tokens
+	s.users = users
+	s.mu.Unlock()
+	return nil
+}
+
+// StartSync - Sync cache dari DB secara berkala (dijalankan di goroutine dari main)
+func (s *TokenRevocationService) StartSync(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := s.revocationRepo.DeleteExpired(); err != nil {
+				log.Printf("[REVOCATION] Failed to delete expired revocations: %v", err)
+			}
+			if err := s.Load(); err != nil {
+				log.Printf("[REVOCATION] Failed to sync cache: %v", err)
+			}
+		}
+	}()
+}
+
+// IsRevoked - Dipakai middleware AuthRequired (tanpa hit DB)
+func (s *TokenRevocationService) IsRevoked(claims *model.JWTClaims) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if claims.ID != "" {
+		if _, ok := s.tokens[claims.ID]; ok {
+			return true
+		}
+	}
+
+	if revokedBefore, ok := s.users[claims.UserID]; ok {
+		// Token tanpa iat dianggap lama
+		if claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revokedBefore) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RevokeToken - Revoke satu access token berdasarkan jti (mis. saat logout)
+func (s *TokenRevocationService) RevokeToken(claims *model.JWTClaims, reason string) error {
+	if claims.ID == "" {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(utils.AccessTokenTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	jti := claims.ID
+	userID := claims.UserID
+	revocation := &model.TokenRevocation{
+		Kind:      "token",
+		JTI:       &jti,
+		UserID:    &userID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.revocationRepo.Create(revocation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = expiresAt
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeUser - Semua access token user yang diterbitkan sebelum sekarang jadi tidak valid.
+// Refresh token tetap berlaku, jadi user cukup refresh untuk dapat role/permission terbaru.
+func (s *TokenRevocationService) RevokeUser(userID string, reason string) error {
+	// iat di JWT presisi detik (dibulatkan ke bawah), jadi dibulatkan ke atas
+	// supaya token yang diterbitkan di detik yang sama ikut ter-revoke. Token
+	// baru diberi iat >= revokedBefore lewat IssuedAt.
+	revokedBefore := time.Now().Truncate(time.Second).Add(time.Second)
+
+	revocation := &model.TokenRevocation{
+		Kind:          "user",
+		UserID:        &userID,
+		RevokedBefore: &revokedBefore,
+		Reason:        reason,
+		ExpiresAt:     revokedBefore.Add(utils.AccessTokenTTL),
+	}
+	if err := s.revocationRepo.Create(revocation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if current, ok := s.users[userID]; !ok || revokedBefore.After(current) {
+		s.users[userID] = revokedBefore
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// IssuedAt - iat untuk access token baru user: sekarang, atau revoked_before
+// jika user baru saja di-revoke di detik yang sama (lihat RevokeUser)
+func (s *TokenRevocationService) IssuedAt(userID string) time.Time {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if revokedBefore, ok := s.users[userID]; ok && revokedBefore.After(now) {
+		return revokedBefore
+	}
+	return now
+}
+
+// RevokeUserSessions - Revoke access token + semua refresh token user
+// (dipakai saat user dinonaktifkan atau dihapus)
+func (s *TokenRevocationService) RevokeUserSessions(userID string, reason string) error {
+	if err := s.refreshTokenRepo.RevokeByUserID(userID); err != nil {
+		return err
+	}
+	return s.RevokeUser(userID, reason)
+}
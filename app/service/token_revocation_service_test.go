@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupRevocationTest() (*TokenRevocationService, *mocks.MockTokenRevocationRepository, *mocks.MockRefreshTokenRepository) {
+	mockRevocationRepo := new(mocks.MockTokenRevocationRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+
+	service := NewTokenRevocationService(mockRevocationRepo, mockRefreshRepo)
+
+	return service, mockRevocationRepo, mockRefreshRepo
+}
+
+func accessClaims(userID, jti string, issuedAt time.Time) *model.JWTClaims {
+	return &model.JWTClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(2 * time.Hour)),
+		},
+	}
+}
+
+// ==================== LOAD CACHE ====================
+
+func TestRevocationLoad_TokenAndUser(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	jti := "jti-1"
+	userID := "user-1"
+	revokedBefore := time.Now().Add(-time.Minute)
+
+	mockRevocationRepo.On("GetActive").Return([]model.TokenRevocation{
+		{Kind: "token", JTI: &jti, ExpiresAt: time.Now().Add(time.Hour)},
+		{Kind: "user", UserID: &userID, RevokedBefore: &revokedBefore, ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	err := service.Load()
+
+	assert.NoError(t, err)
+	assert.True(t, service.IsRevoked(accessClaims("user-2", jti, time.Now())))
+	assert.True(t, service.IsRevoked(accessClaims(userID, "jti-old", revokedBefore.Add(-time.Hour))))
+	assert.False(t, service.IsRevoked(accessClaims(userID, "jti-new", time.Now())))
+	assert.False(t, service.IsRevoked(accessClaims("user-2", "jti-other", time.Now())))
+}
+
+func TestRevocationLoad_RepositoryError(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	mockRevocationRepo.On("GetActive").Return(nil, errors.New("db down"))
+
+	err := service.Load()
+
+	assert.Error(t, err)
+}
+
+// ==================== REVOKE ====================
+
+func TestRevokeToken_UpdatesCache(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	claims := accessClaims("user-1", "jti-1", time.Now())
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+
+	assert.False(t, service.IsRevoked(claims))
+	assert.NoError(t, service.RevokeToken(claims, "logout"))
+	assert.True(t, service.IsRevoked(claims))
+	mockRevocationRepo.AssertExpectations(t)
+}
+
+func TestRevokeUser_OnlyOlderTokens(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+
+	oldToken := accessClaims("user-1", "jti-old", time.Now().Add(-10*time.Minute))
+	assert.NoError(t, service.RevokeUser("user-1", "role changed"))
+
+	newToken := accessClaims("user-1", "jti-new", time.Now().Add(time.Second))
+	assert.True(t, service.IsRevoked(oldToken))
+	assert.False(t, service.IsRevoked(newToken))
+}
+
+func TestRevokeUser_SameSecondTokens(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+
+	// Diterbitkan di detik yang sama sebelum revoke (iat dibulatkan ke bawah)
+	sameSecond := accessClaims("user-1", "jti-same", time.Now())
+	assert.NoError(t, service.RevokeUser("user-1", "user deactivated"))
+
+	reissued := accessClaims("user-1", "jti-new", service.IssuedAt("user-1"))
+	assert.True(t, service.IsRevoked(sameSecond))
+	assert.False(t, service.IsRevoked(reissued))
+	assert.WithinDuration(t, time.Now(), service.IssuedAt("user-2"), time.Second)
+}
+
+func TestRevokeUser_RepositoryErrorKeepsCache(t *testing.T) {
+	service, mockRevocationRepo, _ := setupRevocationTest()
+
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(errors.New("db down"))
+
+	oldToken := accessClaims("user-1", "jti-old", time.Now().Add(-10*time.Minute))
+	assert.Error(t, service.RevokeUser("user-1", "role changed"))
+	assert.False(t, service.IsRevoked(oldToken))
+}
+
+func TestRevokeUserSessions_RevokesRefreshTokens(t *testing.T) {
+	service, mockRevocationRepo, mockRefreshRepo := setupRevocationTest()
+
+	mockRefreshRepo.On("RevokeByUserID", "user-1").Return(nil)
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+
+	assert.NoError(t, service.RevokeUserSessions("user-1", "user deactivated"))
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
+}
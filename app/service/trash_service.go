@@ -0,0 +1,341 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== TRASH & RETENTION ======================
+// Achievement yang dihapus hanya ditandai (deletedAt di MongoDB, status
+// deleted di PostgreSQL) dan bisa di-restore pemiliknya. Scheduler menghapus
+// permanen dokumen MongoDB, reference, dan file lampiran setelah Retention.
+//
+
+// TrashPolicy - Masa simpan trash dan folder file lampiran
+type TrashPolicy struct {
+	Retention  time.Duration
+	UploadsDir string // folder fisik untuk FileURL "/uploads/<nama file>"
+}
+
+type TrashService struct {
+	achievementRepo repository.AchievementRepository
+	studentRepo     repository.StudentRepository
+	authz           *Authorizer
+	policy          TrashPolicy
+	now             func() time.Time
+}
+
+func NewTrashService(
+	achievementRepo repository.AchievementRepository,
+	studentRepo repository.StudentRepository,
+	authz *Authorizer,
+	policy TrashPolicy,
+) *TrashService {
+	return &TrashService{
+		achievementRepo: achievementRepo,
+		studentRepo:     studentRepo,
+		authz:           authz,
+		policy:          policy,
+		now:             time.Now,
+	}
+}
+
+//
+// ==================== GET TRASH (GET /achievements/trash) ======================
+// Achievement milik mahasiswa yang login di trash, terbaru dihapus dulu.
+// Prestasi tim hanya muncul di trash pembuatnya.
+//
+
+func (s *TrashService) GetTrash(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	student, err := s.studentRepo.FindByUserID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "student profile not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementDelete, Target{StudentID: student.ID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	references, err := s.achievementRepo.GetDeletedReferences(student.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch trash",
+		})
+	}
+
+	items := []model.TrashItem{}
+	for i := range references {
+		ref := &references[i]
+
+		// Lewati dokumen yang sudah dihapus permanen, entri anggota tim, dan
+		// reference anggota yang dikeluarkan dari prestasi tim yang masih aktif
+		achievement, err := s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
+		if err != nil || achievement.DeletedAt == nil || achievement.StudentID != ref.StudentID {
+			continue
+		}
+
+		items = append(items, model.TrashItem{
+			ID:              ref.ID,
+			Title:           achievement.Title,
+			AchievementType: achievement.AchievementType,
+			IsTeam:          len(achievement.Team) > 0,
+			DeletedAt:       ref.TrashedAt(),
+			PurgeAt:         ref.TrashedAt().Add(s.policy.Retention),
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievements":   items,
+			"total":          len(items),
+			"retention_days": int(s.policy.Retention / (24 * time.Hour)),
+		},
+	})
+}
+
+//
+// ==================== RESTORE ACHIEVEMENT (POST /achievements/:id/restore) ======================
+// Kembalikan achievement dari trash sebagai draft. Prestasi tim di-restore
+// pembuatnya untuk semua anggota tim saat dihapus.
+//
+
+func (s *TrashService) RestoreAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	if !s.authz.Can(claims, ActionAchievementDelete, Target{StudentID: reference.StudentID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	// Precondition: Hanya achievement berstatus deleted
+	status, err := nextStatus(reference.Status, transitionRestore)
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+	if achievement.StudentID != reference.StudentID {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "only the team owner can restore a team achievement",
+		})
+	}
+	if achievement.DeletedAt == nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement is not in the trash",
+		})
+	}
+
+	deleted, err := s.achievementRepo.GetDeletedReferencesByMongoID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch team members",
+		})
+	}
+	references := restorableReferences(achievement, deleted)
+
+	// 1. Reference (semua anggota) kembali ke draft dalam satu transaksi
+	teamRefs := make([]*model.AchievementReference, len(references))
+	for i := range references {
+		teamRefs[i] = &references[i]
+	}
+	if err := s.achievementRepo.UpdateReferences(teamRefs, status, statusChange(claims, nil, "")); err != nil {
+		return statusUpdateFailed(c, err, "failed to update reference status")
+	}
+
+	// 2. Keluarkan dokumen MongoDB dari trash; jika gagal, reference
+	// dikembalikan ke trash agar restore bisa diulang
+	if err := s.achievementRepo.RestoreAchievement(reference.MongoAchievementID); err != nil {
+		if undoErr := s.achievementRepo.UpdateReferences(teamRefs, model.AchievementStatusDeleted, statusChange(claims, nil, "")); undoErr != nil {
+			log.Printf("[TRASH] Failed to undo restore of %s: %v", reference.MongoAchievementID, undoErr)
+		}
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to restore achievement in MongoDB",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "achievement restored successfully",
+	})
+}
+
+// restorableReferences - Satu reference terhapus per anggota tim saat ini
+// (yang terbaru dihapus). Reference anggota yang sudah dikeluarkan dari tim
+// tidak ikut di-restore.
+func restorableReferences(achievement *model.Achievement, deleted []model.AchievementReference) []model.AchievementReference {
+	members := map[string]bool{achievement.StudentID: true}
+	for _, member := range achievement.Team {
+		members[member.StudentID] = true
+	}
+
+	references := []model.AchievementReference{}
+	for _, ref := range deleted {
+		if !members[ref.StudentID] {
+			continue
+		}
+		members[ref.StudentID] = false
+		references = append(references, ref)
+	}
+	return references
+}
+
+//
+// ==================== RETENTION PURGE (scheduler) ======================
+//
+
+// TrashPurgeResult - Jumlah data yang dihapus permanen dalam satu putaran
+type TrashPurgeResult struct {
+	Achievements int // dokumen MongoDB
+	References   int
+	Files        int
+}
+
+// Start - Jalankan purge trash secara berkala (dijalankan di goroutine dari main)
+func (s *TrashService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			result, err := s.RunOnce()
+			if err != nil {
+				log.Printf("[TRASH] Failed to purge expired achievements: %v", err)
+				continue
+			}
+			if result.References > 0 {
+				log.Printf("[TRASH] Purged %d achievement(s), %d reference(s), %d file(s)", result.Achievements, result.References, result.Files)
+			}
+		}
+	}()
+}
+
+// RunOnce - Satu putaran purge pada waktu s.now(). Kegagalan untuk satu
+// achievement hanya di-log; achievement tsb dicoba lagi di putaran berikutnya.
+func (s *TrashService) RunOnce() (TrashPurgeResult, error) {
+	result := TrashPurgeResult{}
+
+	expired, err := s.achievementRepo.GetReferencesDeletedBefore(s.now().Add(-s.policy.Retention))
+	if err != nil {
+		return result, err
+	}
+
+	// Kelompokkan per achievement (prestasi tim punya beberapa reference)
+	order := []string{}
+	groups := map[string][]string{}
+	for _, ref := range expired {
+		if _, ok := groups[ref.MongoAchievementID]; !ok {
+			order = append(order, ref.MongoAchievementID)
+		}
+		groups[ref.MongoAchievementID] = append(groups[ref.MongoAchievementID], ref.ID)
+	}
+
+	for _, mongoID := range order {
+		purged, files, err := s.purge(mongoID, groups[mongoID])
+		if err != nil {
+			log.Printf("[TRASH] Failed to purge achievement %s: %v", mongoID, err)
+			continue
+		}
+		if purged {
+			result.Achievements++
+		}
+		result.References += len(groups[mongoID])
+		result.Files += files
+	}
+
+	return result, nil
+}
+
+// purge - Hapus permanen reference yang kedaluwarsa. Dokumen MongoDB dan file
+// lampiran ikut dihapus hanya jika tidak ada reference lain yang masih aktif
+// atau belum kedaluwarsa (mis. anggota yang dikeluarkan dari prestasi tim
+// yang masih aktif). Urutan file → MongoDB → PostgreSQL agar putaran
+// berikutnya bisa melanjutkan purge yang gagal di tengah jalan.
+func (s *TrashService) purge(mongoID string, referenceIDs []string) (purged bool, files int, err error) {
+	active, err := s.achievementRepo.GetReferencesByMongoID(mongoID)
+	if err != nil {
+		return false, 0, err
+	}
+	deleted, err := s.achievementRepo.GetDeletedReferencesByMongoID(mongoID)
+	if err != nil {
+		return false, 0, err
+	}
+	if len(active) > 0 || len(deleted) > len(referenceIDs) {
+		return false, 0, s.achievementRepo.PurgeReferences(mongoID, referenceIDs)
+	}
+
+	achievement, err := s.achievementRepo.GetAchievementByID(mongoID)
+	switch {
+	case err == nil:
+		for _, attachment := range achievement.Attachments {
+			path := filepath.Join(s.policy.UploadsDir, filepath.Base(attachment.FileURL))
+			err := os.Remove(path)
+			if err == nil {
+				files++
+			} else if !os.IsNotExist(err) {
+				return false, files, err
+			}
+		}
+		if err := s.achievementRepo.DeleteAchievement(mongoID); err != nil {
+			return false, files, err
+		}
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return false, 0, err
+	}
+
+	return true, files, s.achievementRepo.PurgeReferences(mongoID, referenceIDs)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project_uas/app/model"
+	"project_uas/test/mocks"
+)
+
+// ==================== HELPER FUNCTIONS ====================
+
+func setupTrashTest(uploadsDir string) (*TrashService, *mocks.MockAchievementRepository) {
+	mockAchievementRepo := new(mocks.MockAchievementRepository)
+	mockStudentRepo := new(mocks.MockStudentRepository)
+	mockLecturerRepo := new(mocks.MockLecturerRepository)
+
+	service := NewTrashService(
+		mockAchievementRepo,
+		mockStudentRepo,
+		NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy),
+		TrashPolicy{Retention: 30 * 24 * time.Hour, UploadsDir: uploadsDir},
+	)
+	service.now = func() time.Time { return day("2025-10-01") }
+
+	mockStudentRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123", UserID: "user-123"}, nil).Maybe()
+	return service, mockAchievementRepo
+}
+
+// trashed - Reference berstatus deleted sejak deletedAt
+func trashed(id, studentID, mongoID string, deletedAt time.Time) model.AchievementReference {
+	return model.AchievementReference{
+		ID:                 id,
+		StudentID:          studentID,
+		MongoAchievementID: mongoID,
+		Status:             model.AchievementStatusDeleted,
+		DeletedAt:          &deletedAt,
+	}
+}
+
+// studentApp - App test dengan user Mahasiswa (user-123) yang login
+func studentApp(route string, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.All(route, func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Roles: []string{"Mahasiswa"}})
+		return handler(c)
+	})
+	return app
+}
+
+// ==================== GET TRASH ====================
+
+func TestGetTrash(t *testing.T) {
+	service, mockAchievementRepo := setupTrashTest(t.TempDir())
+	deletedAt := day("2025-09-20")
+	mockAchievementRepo.On("GetDeletedReferences", "student-123").Return([]model.AchievementReference{
+		trashed("ref-own", "student-123", "mongo-own", deletedAt),
+		trashed("ref-member", "student-123", "mongo-team", deletedAt),    // entri anggota tim
+		trashed("ref-removed", "student-123", "mongo-active", deletedAt), // dikeluarkan dari tim yang masih aktif
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-own").Return(&model.Achievement{
+		StudentID: "student-123", Title: "Juara 1 Hackathon", AchievementType: "competition", DeletedAt: &deletedAt,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(&model.Achievement{
+		StudentID: "student-456", Team: []model.TeamMember{{StudentID: "student-123"}}, DeletedAt: &deletedAt,
+	}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-active").Return(&model.Achievement{StudentID: "student-456"}, nil)
+
+	app := studentApp("/achievements/trash", service.GetTrash)
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/trash", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		Data struct {
+			Achievements  []model.TrashItem `json:"achievements"`
+			RetentionDays int               `json:"retention_days"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	assert.Equal(t, 30, body.Data.RetentionDays)
+	if assert.Len(t, body.Data.Achievements, 1) {
+		item := body.Data.Achievements[0]
+		assert.Equal(t, "ref-own", item.ID)
+		assert.Equal(t, "Juara 1 Hackathon", item.Title)
+		assert.True(t, item.PurgeAt.Equal(day("2025-10-20")))
+	}
+}
+
+// ==================== RESTORE ACHIEVEMENT ====================
+
+func TestRestoreAchievement_Team(t *testing.T) {
+	service, mockAchievementRepo := setupTrashTest(t.TempDir())
+	deletedAt := day("2025-09-20")
+	owner := trashed("ref-owner", "student-123", "mongo-team", deletedAt)
+	mockAchievementRepo.On("GetReferenceByID", "ref-owner").Return(&owner, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-team").Return(&model.Achievement{
+		StudentID: "student-123",
+		Team:      []model.TeamMember{{StudentID: "student-123"}, {StudentID: "student-456"}},
+		DeletedAt: &deletedAt,
+	}, nil)
+	mockAchievementRepo.On("GetDeletedReferencesByMongoID", "mongo-team").Return([]model.AchievementReference{
+		owner,
+		trashed("ref-456", "student-456", "mongo-team", deletedAt),
+		trashed("ref-789", "student-789", "mongo-team", day("2025-09-01")), // dikeluarkan dari tim sebelumnya
+		trashed("ref-456-old", "student-456", "mongo-team", day("2025-08-01")),
+	}, nil)
+	mockAchievementRepo.On("RestoreAchievement", "mongo-team").Return(nil)
+	mockAchievementRepo.On("UpdateReferences", mock.AnythingOfType("[]*model.AchievementReference"), model.AchievementStatusDraft, mock.AnythingOfType("*model.AchievementStatusHistory")).Return(nil)
+
+	app := studentApp("/achievements/:id/restore", service.RestoreAchievement)
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-owner/restore", nil))
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockAchievementRepo.AssertNumberOfCalls(t, "UpdateReferences", 1)
+	mockAchievementRepo.AssertCalled(t, "UpdateReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+		return len(refs) == 2 && refs[0].ID == "ref-owner" && refs[1].ID == "ref-456"
+	}), model.AchievementStatusDraft, mock.Anything)
+	mockAchievementRepo.AssertCalled(t, "RestoreAchievement", "mongo-team")
+}
+
+// deletedAt di MongoDB baru dikosongkan setelah reference di-restore; jika
+// gagal, reference dikembalikan ke trash
+func TestRestoreAchievement_MongoFailureUndoesReferences(t *testing.T) {
+	service, mockAchievementRepo := setupTrashTest(t.TempDir())
+	deletedAt := day("2025-09-20")
+	owner := trashed("ref-1", "student-123", "mongo-1", deletedAt)
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&owner, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{StudentID: "student-123", DeletedAt: &deletedAt}, nil)
+	mockAchievementRepo.On("GetDeletedReferencesByMongoID", "mongo-1").Return([]model.AchievementReference{owner}, nil)
+	mockAchievementRepo.On("UpdateReferences", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockAchievementRepo.On("RestoreAchievement", "mongo-1").Return(errors.New("mongo unavailable"))
+
+	app := studentApp("/achievements/:id/restore", service.RestoreAchievement)
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/restore", nil))
+
+	assert.Equal(t, 500, resp.StatusCode)
+	mockAchievementRepo.AssertCalled(t, "UpdateReferences", mock.Anything, model.AchievementStatusDraft, mock.Anything)
+	mockAchievementRepo.AssertCalled(t, "UpdateReferences", mock.Anything, model.AchievementStatusDeleted, mock.Anything)
+}
+
+func TestRestoreAchievement_NotDeleted(t *testing.T) {
+	service, mockAchievementRepo := setupTrashTest(t.TempDir())
+	mockAchievementRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{
+		ID: "ref-1", StudentID: "student-123", Status: model.AchievementStatusDraft,
+	}, nil)
+
+	app := studentApp("/achievements/:id/restore", service.RestoreAchievement)
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/restore", nil))
+
+	assert.Equal(t, 400, resp.StatusCode)
+	mockAchievementRepo.AssertNotCalled(t, "RestoreAchievement", mock.Anything)
+}
+
+// ==================== RETENTION PURGE ====================
+
+func TestTrashRunOnce(t *testing.T) {
+	uploadsDir := t.TempDir()
+	os.WriteFile(filepath.Join(uploadsDir, "sertifikat.pdf"), []byte("pdf"), 0644)
+
+	service, mockAchievementRepo := setupTrashTest(uploadsDir)
+	mockAchievementRepo.On("GetReferencesDeletedBefore", day("2025-09-01")).Return([]model.AchievementReference{
+		trashed("ref-1", "student-123", "mongo-expired", day("2025-08-01")),
+		trashed("ref-2", "student-456", "mongo-expired", day("2025-08-01")),
+		trashed("ref-3", "student-789", "mongo-active", day("2025-08-01")), // anggota yang dikeluarkan
+		trashed("ref-4", "student-123", "mongo-gone", day("2025-08-01")),   // dokumen sudah terhapus di putaran sebelumnya
+	}, nil)
+
+	// Semua reference kedaluwarsa: dokumen, file, dan reference dihapus
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-expired").Return([]model.AchievementReference{}, nil)
+	mockAchievementRepo.On("GetDeletedReferencesByMongoID", "mongo-expired").Return([]model.AchievementReference{{ID: "ref-1"}, {ID: "ref-2"}}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-expired").Return(&model.Achievement{
+		Attachments: []model.Attachment{{FileURL: "/uploads/sertifikat.pdf"}, {FileURL: "/uploads/hilang.pdf"}},
+	}, nil)
+	mockAchievementRepo.On("DeleteAchievement", "mongo-expired").Return(nil)
+	mockAchievementRepo.On("PurgeReferences", "mongo-expired", []string{"ref-1", "ref-2"}).Return(nil)
+
+	// Achievement masih aktif: hanya reference yang dihapus
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-active").Return([]model.AchievementReference{{ID: "ref-active"}}, nil)
+	mockAchievementRepo.On("GetDeletedReferencesByMongoID", "mongo-active").Return([]model.AchievementReference{{ID: "ref-3"}}, nil)
+	mockAchievementRepo.On("PurgeReferences", "mongo-active", []string{"ref-3"}).Return(nil)
+
+	mockAchievementRepo.On("GetReferencesByMongoID", "mongo-gone").Return([]model.AchievementReference{}, nil)
+	mockAchievementRepo.On("GetDeletedReferencesByMongoID", "mongo-gone").Return([]model.AchievementReference{{ID: "ref-4"}}, nil)
+	mockAchievementRepo.On("GetAchievementByID", "mongo-gone").Return(nil, mongo.ErrNoDocuments)
+	mockAchievementRepo.On("PurgeReferences", "mongo-gone", []string{"ref-4"}).Return(nil)
+
+	result, err := service.RunOnce()
+
+	assert.NoError(t, err)
+	assert.Equal(t, TrashPurgeResult{Achievements: 2, References: 4, Files: 1}, result)
+	assert.NoFileExists(t, filepath.Join(uploadsDir, "sertifikat.pdf"))
+	mockAchievementRepo.AssertExpectations(t)
+	mockAchievementRepo.AssertNotCalled(t, "DeleteAchievement", "mongo-active")
+	mockAchievementRepo.AssertNotCalled(t, "DeleteAchievement", "mongo-gone")
+}
@@ -18,6 +18,8 @@ type UserService struct {
 	permRepo     repository.PermissionRepository
 	studentRepo  repository.StudentRepository
 	lecturerRepo repository.LecturerRepository
+	revocations  *TokenRevocationService
+	authz        *Authorizer
 	validate     *validator.Validate
 }
 
@@ -27,6 +29,8 @@ func NewUserService(
 	permRepo repository.PermissionRepository,
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
+	revocations *TokenRevocationService,
+	authz *Authorizer,
 ) *UserService {
 	return &UserService{
 		userRepo:     userRepo,
@@ -34,6 +38,8 @@ func NewUserService(
 		permRepo:     permRepo,
 		studentRepo:  studentRepo,
 		lecturerRepo: lecturerRepo,
+		revocations:  revocations,
+		authz:        authz,
 		validate:     validator.New(),
 	}
 }
@@ -43,6 +49,12 @@ func NewUserService(
 //
 
 func (s *UserService) CreateUser(c *fiber.Ctx) error {
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
 	req := new(model.UserCreateRequest)
 
 	// Parse request body
@@ -88,6 +100,14 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	// User baru dari admin fakultas / prodi wajib punya profil di program studinya
+	if perr := checkAssignScope(filter, role.Name, req.ScopeProgramStudy, req.StudentProfile, req.LecturerProfile, true); perr != nil {
+		return c.Status(perr.status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  perr.message,
+		})
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -103,7 +123,6 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
 		FullName:     req.FullName,
-		RoleID:       role.ID,
 		IsActive:     true,
 	}
 
@@ -114,96 +133,26 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	// Create profile berdasarkan role
-	if role.Name == "Mahasiswa" && req.StudentProfile != nil {
-		// Validasi student profile
-		if err := s.validate.Struct(req.StudentProfile); err != nil {
-			// Rollback user creation (delete user)
-			s.userRepo.Delete(user.ID)
-			return c.Status(422).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "invalid student profile: " + err.Error(),
-			})
-		}
-
-		// Cek student_id sudah ada atau belum
-		existingStudent, _ := s.studentRepo.FindByStudentID(req.StudentProfile.StudentID)
-		if existingStudent != nil {
-			s.userRepo.Delete(user.ID)
-			return c.Status(409).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "student_id already exists",
-			})
-		}
-
-		// Jika ada advisor_id, validasi lecturer exists
-		if req.StudentProfile.AdvisorID != nil {
-			_, err := s.lecturerRepo.FindByID(*req.StudentProfile.AdvisorID)
-			if err != nil {
-				s.userRepo.Delete(user.ID)
-				return c.Status(404).JSON(model.APIResponse{
-					Status: "error",
-					Error:  "advisor not found",
-				})
-			}
-		}
-
-		// Create student profile
-		student := &model.Student{
-			UserID:       user.ID,
-			StudentID:    req.StudentProfile.StudentID,
-			ProgramStudy: req.StudentProfile.ProgramStudy,
-			AcademicYear: req.StudentProfile.AcademicYear,
-			AdvisorID:    req.StudentProfile.AdvisorID,
-		}
-
-		if err := s.studentRepo.Create(student); err != nil {
-			s.userRepo.Delete(user.ID)
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to create student profile",
-			})
-		}
+	if _, err := s.userRepo.AddRole(user.ID, role.ID, req.ScopeProgramStudy); err != nil {
+		s.userRepo.Delete(user.ID)
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to assign role",
+		})
 	}
 
-	if role.Name == "Dosen Wali" && req.LecturerProfile != nil {
-		// Validasi lecturer profile
-		if err := s.validate.Struct(req.LecturerProfile); err != nil {
-			s.userRepo.Delete(user.ID)
-			return c.Status(422).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "invalid lecturer profile: " + err.Error(),
-			})
-		}
-
-		// Cek lecturer_id sudah ada atau belum
-		existingLecturer, _ := s.lecturerRepo.FindByLecturerID(req.LecturerProfile.LecturerID)
-		if existingLecturer != nil {
-			s.userRepo.Delete(user.ID)
-			return c.Status(409).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer_id already exists",
-			})
-		}
-
-		// Create lecturer profile
-		lecturer := &model.Lecturer{
-			UserID:     user.ID,
-			LecturerID: req.LecturerProfile.LecturerID,
-			Department: req.LecturerProfile.Department,
-		}
-
-		if err := s.lecturerRepo.Create(lecturer); err != nil {
-			s.userRepo.Delete(user.ID)
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to create lecturer profile",
-			})
-		}
+	// Create profile berdasarkan role (rollback user jika gagal)
+	if perr := s.attachProfile(user.ID, role.Name, req.StudentProfile, req.LecturerProfile); perr != nil {
+		s.userRepo.Delete(user.ID)
+		return c.Status(perr.status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  perr.message,
+		})
 	}
 
 	// Build response dengan profile
-	userResponse := s.buildUserResponse(user, role.Name)
+	role.ScopeProgramStudy = req.ScopeProgramStudy
+	userResponse := s.buildUserResponse(user, []model.Role{*role})
 
 	return c.Status(201).JSON(model.APIResponse{
 		Status:  "success",
@@ -217,6 +166,12 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 //
 
 func (s *UserService) GetUsers(c *fiber.Ctx) error {
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
 	// Parse query params
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
@@ -233,7 +188,12 @@ func (s *UserService) GetUsers(c *fiber.Ctx) error {
 	offset := (page - 1) * pageSize
 
 	// Get users from repository
-	users, err := s.userRepo.GetAll(pageSize, offset, roleName)
+	var users []model.User
+	if filter.Scope == ScopeAll {
+		users, err = s.userRepo.GetAll(pageSize, offset, roleName)
+	} else {
+		users, err = s.userRepo.GetAllByProgramStudies(filter.ProgramStudies, pageSize, offset, roleName)
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -242,7 +202,12 @@ func (s *UserService) GetUsers(c *fiber.Ctx) error {
 	}
 
 	// Get total count
-	total, err := s.userRepo.CountAll(roleName)
+	var total int
+	if filter.Scope == ScopeAll {
+		total, err = s.userRepo.CountAll(roleName)
+	} else {
+		total, err = s.userRepo.CountAllByProgramStudies(filter.ProgramStudies, roleName)
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -253,8 +218,7 @@ func (s *UserService) GetUsers(c *fiber.Ctx) error {
 	// Build response
 	var userResponses []model.UserResponse
 	for _, user := range users {
-		roleName, _ := s.userRepo.GetRoleName(user.RoleID)
-		userResp := s.buildUserResponse(&user, roleName)
+		userResp := s.buildUserResponse(&user, s.userRoles(user.ID))
 		userResponses = append(userResponses, *userResp)
 	}
 
@@ -281,6 +245,12 @@ func (s *UserService) GetUsers(c *fiber.Ctx) error {
 func (s *UserService) GetUserByID(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return c.Status(404).JSON(model.APIResponse{
@@ -289,8 +259,14 @@ func (s *UserService) GetUserByID(c *fiber.Ctx) error {
 		})
 	}
 
-	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
-	userResponse := s.buildUserResponse(user, roleName)
+	if !s.canManageUser(claims, filter, userID) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	userResponse := s.buildUserResponse(user, s.userRoles(user.ID))
 
 	return c.JSON(model.APIResponse{
 		Status: "success",
@@ -305,6 +281,12 @@ func (s *UserService) GetUserByID(c *fiber.Ctx) error {
 func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
 	// Cari user
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -314,6 +296,13 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	if !s.canManageUser(claims, filter, userID) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
 	// Parse request
 	req := new(model.UserUpdateRequest)
 	if err := c.BodyParser(req); err != nil {
@@ -340,7 +329,9 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		user.FullName = req.FullName
 	}
 
+	deactivated := false
 	if req.IsActive != nil {
+		deactivated = user.IsActive && !*req.IsActive
 		user.IsActive = *req.IsActive
 	}
 
@@ -352,8 +343,17 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
-	userResponse := s.buildUserResponse(user, roleName)
+	// User dinonaktifkan: semua token yang sudah terbit langsung tidak berlaku
+	if deactivated {
+		if err := s.revocations.RevokeUserSessions(userID, "user deactivated"); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to revoke user sessions",
+			})
+		}
+	}
+
+	userResponse := s.buildUserResponse(user, s.userRoles(user.ID))
 
 	return c.JSON(model.APIResponse{
 		Status:  "success",
@@ -369,32 +369,52 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 func (s *UserService) DeleteUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
-	// Cari user dulu
-	user, err := s.userRepo.FindByID(userID)
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
 	if err != nil {
+		return authzError(c, err)
+	}
+
+	// Cari user dulu
+	if _, err := s.userRepo.FindByID(userID); err != nil {
 		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "user not found",
 		})
 	}
 
+	if !s.canManageUser(claims, filter, userID) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
 	// Hapus profile dulu (jika ada) - CASCADE DELETE seharusnya handle ini
-	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
+	names := roleNames(s.userRoles(userID))
 
-	if roleName == "Mahasiswa" {
+	if contains(names, "Mahasiswa") {
 		student, _ := s.studentRepo.FindByUserID(userID)
 		if student != nil {
 			s.studentRepo.Delete(student.ID)
 		}
 	}
 
-	if roleName == "Dosen Wali" {
+	if contains(names, "Dosen Wali") {
 		lecturer, _ := s.lecturerRepo.FindByUserID(userID)
 		if lecturer != nil {
 			s.lecturerRepo.Delete(lecturer.ID)
 		}
 	}
 
+	// Revoke semua token user sebelum dihapus
+	if err := s.revocations.RevokeUserSessions(userID, "user deleted"); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke user sessions",
+		})
+	}
+
 	// Hapus user
 	if err := s.userRepo.Delete(userID); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
@@ -410,12 +430,19 @@ func (s *UserService) DeleteUser(c *fiber.Ctx) error {
 }
 
 //
-// ==================== ASSIGN ROLE (PUT /users/:id/role) ======================
+// ==================== ADD ROLE (POST /users/:id/roles) ======================
+// Profile mahasiswa / dosen dibuat (atau diaktifkan lagi jika pernah di-retire)
 //
 
-func (s *UserService) AssignRole(c *fiber.Ctx) error {
+func (s *UserService) AddRole(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
 	// Cari user
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -425,8 +452,15 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 		})
 	}
 
+	if !s.canManageUser(claims, filter, userID) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
 	// Parse request
-	req := new(model.AssignRoleRequest)
+	req := new(model.AddRoleRequest)
 	if err := c.BodyParser(req); err != nil {
 		return c.Status(400).JSON(model.APIResponse{
 			Status: "error",
@@ -434,6 +468,13 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
 	// Get role by name
 	role, err := s.roleRepo.GetRoleByName(req.RoleName)
 	if err != nil {
@@ -443,42 +484,284 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 		})
 	}
 
-	// Update role
-	if err := s.userRepo.UpdateRole(userID, role.ID); err != nil {
+	if perr := checkAssignScope(filter, role.Name, req.ScopeProgramStudy, req.StudentProfile, req.LecturerProfile, false); perr != nil {
+		return c.Status(perr.status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  perr.message,
+		})
+	}
+
+	added, err := s.userRepo.AddRole(userID, role.ID, req.ScopeProgramStudy)
+	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to assign role",
+			Error:  "failed to add role",
+		})
+	}
+	if !added {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user already has this role assignment",
+		})
+	}
+
+	if perr := s.ensureProfile(userID, role.Name, req.StudentProfile, req.LecturerProfile); perr != nil {
+		s.userRepo.RemoveRole(userID, role.ID)
+		return c.Status(perr.status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  perr.message,
 		})
 	}
 
-	// Refresh user data
-	user, _ = s.userRepo.FindByID(userID)
-	userResponse := s.buildUserResponse(user, role.Name)
+	// Access token lama masih membawa role/permission lama
+	if err := s.revocations.RevokeUser(userID, "role changed"); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke existing tokens",
+		})
+	}
+
+	userResponse := s.buildUserResponse(user, s.userRoles(userID))
 
 	return c.JSON(model.APIResponse{
 		Status:  "success",
-		Message: "role assigned successfully",
+		Message: "role added successfully",
 		Data:    userResponse,
 	})
 }
 
+//
+// ==================== REMOVE ROLE (DELETE /users/:id/roles/:roleId) ======================
+// Profile mahasiswa / dosen di-retire, bukan dihapus, supaya data prestasi tetap ada
+//
+
+func (s *UserService) RemoveRole(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	claims, _ := c.Locals("user").(*model.JWTClaims)
+	filter, err := s.authz.Filter(claims, ActionUserManage)
+	if err != nil {
+		return authzError(c, err)
+	}
+
+	// Cari user
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if !s.canManageUser(claims, filter, userID) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	role, err := s.roleRepo.GetRoleByID(c.Params("roleId"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	// Mencegah admin mencabut aksesnya sendiri
+	if userID == claims.UserID {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot remove a role from yourself",
+		})
+	}
+
+	if perr := checkAssignScope(filter, role.Name, "", nil, nil, false); perr != nil {
+		return c.Status(perr.status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  perr.message,
+		})
+	}
+
+	names := roleNames(s.userRoles(userID))
+	if !contains(names, role.Name) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user does not have this role",
+		})
+	}
+	if len(names) == 1 {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user must keep at least one role",
+		})
+	}
+
+	if _, err := s.userRepo.RemoveRole(userID, role.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to remove role",
+		})
+	}
+
+	if err := s.retireProfile(userID, role.Name); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to retire profile",
+		})
+	}
+
+	// Access token lama masih membawa role/permission lama
+	if err := s.revocations.RevokeUser(userID, "role changed"); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke existing tokens",
+		})
+	}
+
+	userResponse := s.buildUserResponse(user, s.userRoles(userID))
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role removed successfully",
+		Data:    userResponse,
+	})
+}
+
+//
+// ==================== HELPER: PROFILE PER ROLE ======================
+//
+
+type profileError struct {
+	status  int
+	message string
+}
+
+// attachProfile - Buat profile mahasiswa / dosen untuk user baru (jika data profile dikirim)
+func (s *UserService) attachProfile(userID, roleName string, studentReq *model.StudentProfileRequest, lecturerReq *model.LecturerProfileRequest) *profileError {
+	if roleName == "Mahasiswa" && studentReq != nil {
+		// Validasi student profile
+		if err := s.validate.Struct(studentReq); err != nil {
+			return &profileError{422, "invalid student profile: " + err.Error()}
+		}
+
+		// Cek student_id sudah ada atau belum
+		existingStudent, _ := s.studentRepo.FindByStudentID(studentReq.StudentID)
+		if existingStudent != nil {
+			return &profileError{409, "student_id already exists"}
+		}
+
+		// Jika ada advisor_id, validasi lecturer exists
+		if studentReq.AdvisorID != nil {
+			if _, err := s.lecturerRepo.FindByID(*studentReq.AdvisorID); err != nil {
+				return &profileError{404, "advisor not found"}
+			}
+		}
+
+		// Create student profile
+		student := &model.Student{
+			UserID:       userID,
+			StudentID:    studentReq.StudentID,
+			ProgramStudy: studentReq.ProgramStudy,
+			AcademicYear: studentReq.AcademicYear,
+			AdvisorID:    studentReq.AdvisorID,
+		}
+
+		if err := s.studentRepo.Create(student); err != nil {
+			return &profileError{500, "failed to create student profile"}
+		}
+	}
+
+	if roleName == "Dosen Wali" && lecturerReq != nil {
+		// Validasi lecturer profile
+		if err := s.validate.Struct(lecturerReq); err != nil {
+			return &profileError{422, "invalid lecturer profile: " + err.Error()}
+		}
+
+		// Cek lecturer_id sudah ada atau belum
+		existingLecturer, _ := s.lecturerRepo.FindByLecturerID(lecturerReq.LecturerID)
+		if existingLecturer != nil {
+			return &profileError{409, "lecturer_id already exists"}
+		}
+
+		// Create lecturer profile
+		lecturer := &model.Lecturer{
+			UserID:     userID,
+			LecturerID: lecturerReq.LecturerID,
+			Department: lecturerReq.Department,
+		}
+
+		if err := s.lecturerRepo.Create(lecturer); err != nil {
+			return &profileError{500, "failed to create lecturer profile"}
+		}
+	}
+
+	return nil
+}
+
+// ensureProfile - Aktifkan lagi profile yang pernah di-retire, atau buat baru
+func (s *UserService) ensureProfile(userID, roleName string, studentReq *model.StudentProfileRequest, lecturerReq *model.LecturerProfileRequest) *profileError {
+	if roleName == "Mahasiswa" {
+		if student, err := s.studentRepo.FindByUserID(userID); err == nil {
+			if student.RetiredAt != nil {
+				if err := s.studentRepo.SetRetired(student.ID, false); err != nil {
+					return &profileError{500, "failed to restore student profile"}
+				}
+			}
+			return nil
+		}
+	}
+
+	if roleName == "Dosen Wali" {
+		if lecturer, err := s.lecturerRepo.FindByUserID(userID); err == nil {
+			if lecturer.RetiredAt != nil {
+				if err := s.lecturerRepo.SetRetired(lecturer.ID, false); err != nil {
+					return &profileError{500, "failed to restore lecturer profile"}
+				}
+			}
+			return nil
+		}
+	}
+
+	return s.attachProfile(userID, roleName, studentReq, lecturerReq)
+}
+
+// retireProfile - Tandai profile sebagai retired saat role-nya dicabut
+func (s *UserService) retireProfile(userID, roleName string) error {
+	if roleName == "Mahasiswa" {
+		if student, err := s.studentRepo.FindByUserID(userID); err == nil && student.RetiredAt == nil {
+			return s.studentRepo.SetRetired(student.ID, true)
+		}
+	}
+
+	if roleName == "Dosen Wali" {
+		if lecturer, err := s.lecturerRepo.FindByUserID(userID); err == nil && lecturer.RetiredAt == nil {
+			return s.lecturerRepo.SetRetired(lecturer.ID, true)
+		}
+	}
+
+	return nil
+}
+
 //
 // ==================== HELPER: BUILD USER RESPONSE ======================
 //
 
-func (s *UserService) buildUserResponse(user *model.User, roleName string) *model.UserResponse {
+func (s *UserService) buildUserResponse(user *model.User, roles []model.Role) *model.UserResponse {
+	names := roleNames(roles)
 	response := &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Role:      roleName,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		FullName:   user.FullName,
+		Roles:      names,
+		RoleScopes: roleScopes(roles),
+		IsActive:   user.IsActive,
+		CreatedAt:  user.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 
 	// Load profile jika ada
-	if roleName == "Mahasiswa" {
+	if contains(names, "Mahasiswa") {
 		student, err := s.studentRepo.FindByUserID(user.ID)
 		if err == nil {
 			response.StudentProfile = &model.StudentResponse{
@@ -493,7 +776,7 @@ func (s *UserService) buildUserResponse(user *model.User, roleName string) *mode
 		}
 	}
 
-	if roleName == "Dosen Wali" {
+	if contains(names, "Dosen Wali") {
 		lecturer, err := s.lecturerRepo.FindByUserID(user.ID)
 		if err == nil {
 			response.LecturerProfile = &model.LecturerResponse{
@@ -508,3 +791,96 @@ func (s *UserService) buildUserResponse(user *model.User, roleName string) *mode
 
 	return response
 }
+
+func (s *UserService) userRoles(userID string) []model.Role {
+	roles, _ := s.userRepo.GetRoles(userID)
+	return roles
+}
+
+//
+// ==================== HELPER: ADMIN SCOPE ======================
+// Setiap handler user mengambil scope admin lewat authz.Filter(ActionUserManage):
+// ScopeAll = semua user, selain itu hanya program studi assignment. Admin
+// fakultas / prodi (role Admin ber-scope program studi) hanya mengelola
+// mahasiswa / dosen di program studinya, bukan admin atau role lain.
+//
+
+// canManageUser - User target ada dalam scope admin
+func (s *UserService) canManageUser(claims *model.JWTClaims, filter *AccessFilter, userID string) bool {
+	if filter.Scope == ScopeAll {
+		return true
+	}
+	if !s.authz.Can(claims, ActionUserManage, Target{UserID: userID}) {
+		return false
+	}
+	for _, role := range s.userRoles(userID) {
+		if !isProfileRole(role.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAssignScope - Role (dan profil baru) yang boleh di-assign admin ber-scope
+func checkAssignScope(filter *AccessFilter, roleName, scopeProgramStudy string, studentReq *model.StudentProfileRequest, lecturerReq *model.LecturerProfileRequest, profileRequired bool) *profileError {
+	if filter.Scope == ScopeAll {
+		return nil
+	}
+	if !isProfileRole(roleName) || scopeProgramStudy != "" {
+		return &profileError{403, "scoped administrators can only assign Mahasiswa or Dosen Wali roles"}
+	}
+
+	var programStudy string
+	switch {
+	case roleName == "Mahasiswa" && studentReq != nil:
+		programStudy = studentReq.ProgramStudy
+	case roleName == "Dosen Wali" && lecturerReq != nil:
+		programStudy = lecturerReq.Department
+	case profileRequired:
+		return &profileError{422, "profile is required for scoped administrators"}
+	default:
+		return nil
+	}
+
+	if !contains(filter.ProgramStudies, programStudy) {
+		return &profileError{403, "program study is outside your scope"}
+	}
+	return nil
+}
+
+func isProfileRole(roleName string) bool {
+	return roleName == "Mahasiswa" || roleName == "Dosen Wali"
+}
+
+// roleNames - Nama role unik (role ber-scope bisa muncul beberapa kali)
+func roleNames(roles []model.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !contains(names, role.Name) {
+			names = append(names, role.Name)
+		}
+	}
+	return names
+}
+
+// roleScopes - role -> program studi, hanya untuk role yang semua assignment-nya ber-scope
+func roleScopes(roles []model.Role) map[string][]string {
+	unscoped := map[string]bool{}
+	for _, role := range roles {
+		if role.ScopeProgramStudy == "" {
+			unscoped[role.Name] = true
+		}
+	}
+
+	var scopes map[string][]string
+	for _, role := range roles {
+		if unscoped[role.Name] {
+			continue
+		}
+		if scopes == nil {
+			scopes = map[string][]string{}
+		}
+		scopes[role.Name] = append(scopes[role.Name], role.ScopeProgramStudy)
+	}
+	return scopes
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http/httptest"
 	"strings"
@@ -17,25 +18,48 @@ import (
 
 // ==================== HELPER FUNCTIONS ====================
 
-func setupUserTest() (*UserService, *mocks.MockUserRepository, *mocks.MockRoleRepository, *mocks.MockPermissionRepository, *mocks.MockStudentRepository, *mocks.MockLecturerRepository) {
+func setupUserTest() (*UserService, *mocks.MockUserRepository, *mocks.MockRoleRepository, *mocks.MockPermissionRepository, *mocks.MockStudentRepository, *mocks.MockLecturerRepository, *mocks.MockTokenRevocationRepository, *mocks.MockRefreshTokenRepository) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockRoleRepo := new(mocks.MockRoleRepository)
 	mockPermRepo := new(mocks.MockPermissionRepository)
 	mockStudentRepo := new(mocks.MockStudentRepository)
 	mockLecturerRepo := new(mocks.MockLecturerRepository)
+	mockRevocationRepo := new(mocks.MockTokenRevocationRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
 
-	service := NewUserService(mockUserRepo, mockRoleRepo, mockPermRepo, mockStudentRepo, mockLecturerRepo)
+	revocations := NewTokenRevocationService(mockRevocationRepo, mockRefreshRepo)
+	authz := NewAuthorizer(mockStudentRepo, mockLecturerRepo, noDelegations(), DefaultPolicy)
+	service := NewUserService(mockUserRepo, mockRoleRepo, mockPermRepo, mockStudentRepo, mockLecturerRepo, revocations, authz)
 
-	return service, mockUserRepo, mockRoleRepo, mockPermRepo, mockStudentRepo, mockLecturerRepo
+	return service, mockUserRepo, mockRoleRepo, mockPermRepo, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo
+}
+
+func withAdmin(handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Roles: []string{"Admin"}})
+		return handler(c)
+	}
+}
+
+// withProdiAdmin - Admin yang assignment-nya dibatasi program studi Informatika
+func withProdiAdmin(handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{
+			UserID:     "admin-if",
+			Roles:      []string{"Admin"},
+			RoleScopes: map[string][]string{"Admin": {"Informatika"}},
+		})
+		return handler(c)
+	}
 }
 
 // ==================== FR-009: CREATE USER ====================
 
 func TestCreateUser_Success_Mahasiswa(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	roleID := "role-123"
 	advisorID := "advisor-123"
@@ -49,6 +73,7 @@ func TestCreateUser_Success_Mahasiswa(t *testing.T) {
 	mockUserRepo.On("FindByEmail", "mahasiswa@test.com").Return(nil, errors.New("not found"))
 	mockRoleRepo.On("GetRoleByName", "Mahasiswa").Return(role, nil)
 	mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	mockUserRepo.On("AddRole", mock.AnythingOfType("string"), roleID, "").Return(true, nil)
 	mockStudentRepo.On("FindByStudentID", "123456789").Return(nil, errors.New("not found"))
 	mockLecturerRepo.On("FindByID", advisorID).Return(&model.Lecturer{ID: advisorID}, nil)
 	mockStudentRepo.On("Create", mock.AnythingOfType("*model.Student")).Return(nil)
@@ -83,10 +108,10 @@ func TestCreateUser_Success_Mahasiswa(t *testing.T) {
 }
 
 func TestCreateUser_Success_DosenWali(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, _, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, mockRoleRepo, _, _, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	roleID := "role-123"
 
@@ -99,6 +124,7 @@ func TestCreateUser_Success_DosenWali(t *testing.T) {
 	mockUserRepo.On("FindByEmail", "dosen@test.com").Return(nil, errors.New("not found"))
 	mockRoleRepo.On("GetRoleByName", "Dosen Wali").Return(role, nil)
 	mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	mockUserRepo.On("AddRole", mock.AnythingOfType("string"), roleID, "").Return(true, nil)
 	mockLecturerRepo.On("FindByLecturerID", "L123456").Return(nil, errors.New("not found"))
 	mockLecturerRepo.On("Create", mock.AnythingOfType("*model.Lecturer")).Return(nil)
 	mockLecturerRepo.On("FindByUserID", mock.AnythingOfType("string")).Return(&model.Lecturer{
@@ -130,10 +156,10 @@ func TestCreateUser_Success_DosenWali(t *testing.T) {
 }
 
 func TestCreateUser_Success_Admin(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, _, _ := setupUserTest()
+	service, mockUserRepo, mockRoleRepo, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	roleID := "role-admin"
 
@@ -146,6 +172,7 @@ func TestCreateUser_Success_Admin(t *testing.T) {
 	mockUserRepo.On("FindByEmail", "admin@test.com").Return(nil, errors.New("not found"))
 	mockRoleRepo.On("GetRoleByName", "Admin").Return(role, nil)
 	mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	mockUserRepo.On("AddRole", mock.AnythingOfType("string"), roleID, "").Return(true, nil)
 
 	body := `{
 		"username": "admin123",
@@ -166,10 +193,10 @@ func TestCreateUser_Success_Admin(t *testing.T) {
 }
 
 func TestCreateUser_UsernameExists(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	existingUser := &model.User{
 		ID:       "existing-id",
@@ -196,10 +223,10 @@ func TestCreateUser_UsernameExists(t *testing.T) {
 }
 
 func TestCreateUser_EmailExists(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	existingUser := &model.User{
 		ID:    "existing-id",
@@ -227,10 +254,10 @@ func TestCreateUser_EmailExists(t *testing.T) {
 }
 
 func TestCreateUser_RoleNotFound(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, _, _ := setupUserTest()
+	service, mockUserRepo, mockRoleRepo, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	mockUserRepo.On("FindByUsername", "mahasiswa123").Return(nil, errors.New("not found"))
 	mockUserRepo.On("FindByEmail", "mahasiswa@test.com").Return(nil, errors.New("not found"))
@@ -255,10 +282,10 @@ func TestCreateUser_RoleNotFound(t *testing.T) {
 }
 
 func TestCreateUser_StudentIDExists(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, _ := setupUserTest()
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Post("/users", service.CreateUser)
+	app.Post("/users", withAdmin(service.CreateUser))
 
 	roleID := "role-123"
 	role := &model.Role{
@@ -275,6 +302,7 @@ func TestCreateUser_StudentIDExists(t *testing.T) {
 	mockUserRepo.On("FindByEmail", "mahasiswa@test.com").Return(nil, errors.New("not found"))
 	mockRoleRepo.On("GetRoleByName", "Mahasiswa").Return(role, nil)
 	mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	mockUserRepo.On("AddRole", mock.AnythingOfType("string"), roleID, "").Return(true, nil)
 	mockStudentRepo.On("FindByStudentID", "123456789").Return(existingStudent, nil)
 	mockUserRepo.On("Delete", mock.AnythingOfType("string")).Return(nil)
 
@@ -304,32 +332,30 @@ func TestCreateUser_StudentIDExists(t *testing.T) {
 // ==================== GET USERS ====================
 
 func TestGetUsers_Success(t *testing.T) {
-	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Get("/users", service.GetUsers)
+	app.Get("/users", withAdmin(service.GetUsers))
 
 	users := []model.User{
 		{
 			ID:        "user-1",
 			Username:  "user1",
 			Email:     "user1@test.com",
-			RoleID:    "role-1",
 			CreatedAt: time.Now(),
 		},
 		{
 			ID:        "user-2",
 			Username:  "user2",
 			Email:     "user2@test.com",
-			RoleID:    "role-2",
 			CreatedAt: time.Now(),
 		},
 	}
 
 	mockUserRepo.On("GetAll", 10, 0, "").Return(users, nil)
 	mockUserRepo.On("CountAll", "").Return(2, nil)
-	mockUserRepo.On("GetRoleName", "role-1").Return("Mahasiswa", nil)
-	mockUserRepo.On("GetRoleName", "role-2").Return("Admin", nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{{ID: "role-1", Name: "Mahasiswa"}}, nil)
+	mockUserRepo.On("GetRoles", "user-2").Return([]model.Role{{ID: "role-2", Name: "Admin"}}, nil)
 	
 	// ← TAMBAH MOCK INI untuk buildUserResponse
 	mockStudentRepo.On("FindByUserID", "user-1").Return(nil, errors.New("not found"))
@@ -345,24 +371,23 @@ func TestGetUsers_Success(t *testing.T) {
 }
 
 func TestGetUsers_WithRoleFilter(t *testing.T) {
-	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Get("/users", service.GetUsers)
+	app.Get("/users", withAdmin(service.GetUsers))
 
 	users := []model.User{
 		{
 			ID:        "user-1",
 			Username:  "mahasiswa1",
 			Email:     "mhs1@test.com",
-			RoleID:    "role-mhs",
 			CreatedAt: time.Now(),
 		},
 	}
 
 	mockUserRepo.On("GetAll", 10, 0, "Mahasiswa").Return(users, nil)
 	mockUserRepo.On("CountAll", "Mahasiswa").Return(1, nil)
-	mockUserRepo.On("GetRoleName", "role-mhs").Return("Mahasiswa", nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
 	
 	// ← TAMBAH INI
 	mockStudentRepo.On("FindByUserID", "user-1").Return(nil, errors.New("not found"))
@@ -375,10 +400,10 @@ func TestGetUsers_WithRoleFilter(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 func TestGetUsers_WithPagination(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Get("/users", service.GetUsers)
+	app.Get("/users", withAdmin(service.GetUsers))
 
 	users := []model.User{}
 
@@ -395,10 +420,10 @@ func TestGetUsers_WithPagination(t *testing.T) {
 // ==================== GET USER BY ID ====================
 
 func TestGetUserByID_Success(t *testing.T) {
-	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Get("/users/:id", service.GetUserByID)
+	app.Get("/users/:id", withAdmin(service.GetUserByID))
 
 	userID := "user-123"
 	user := &model.User{
@@ -406,13 +431,12 @@ func TestGetUserByID_Success(t *testing.T) {
 		Username:  "mahasiswa123",
 		Email:     "mahasiswa@test.com",
 		FullName:  "John Doe",
-		RoleID:    "role-123",
 		IsActive:  true,
 		CreatedAt: time.Now(),
 	}
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
-	mockUserRepo.On("GetRoleName", "role-123").Return("Mahasiswa", nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-123", Name: "Mahasiswa"}}, nil)
 	
 	// ← TAMBAH INI
 	mockStudentRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
@@ -426,10 +450,10 @@ func TestGetUserByID_Success(t *testing.T) {
 }
 
 func TestGetUserByID_NotFound(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Get("/users/:id", service.GetUserByID)
+	app.Get("/users/:id", withAdmin(service.GetUserByID))
 
 	mockUserRepo.On("FindByID", "invalid-id").Return(nil, errors.New("user not found"))
 
@@ -443,10 +467,10 @@ func TestGetUserByID_NotFound(t *testing.T) {
 // ==================== UPDATE USER ====================
 
 func TestUpdateUser_Success(t *testing.T) {
-	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo := setupUserTest()
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Put("/users/:id", service.UpdateUser)
+	app.Put("/users/:id", withAdmin(service.UpdateUser))
 
 	userID := "user-123"
 	user := &model.User{
@@ -454,14 +478,13 @@ func TestUpdateUser_Success(t *testing.T) {
 		Username:  "mahasiswa123",
 		Email:     "old@test.com",
 		FullName:  "Old Name",
-		RoleID:    "role-123",
 		CreatedAt: time.Now(),
 	}
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
 	mockUserRepo.On("FindByEmail", "new@test.com").Return(nil, errors.New("not found"))
 	mockUserRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
-	mockUserRepo.On("GetRoleName", "role-123").Return("Mahasiswa", nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-123", Name: "Mahasiswa"}}, nil)
 	
 	// ← TAMBAH INI
 	mockStudentRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
@@ -482,10 +505,10 @@ func TestUpdateUser_Success(t *testing.T) {
 }
 
 func TestUpdateUser_EmailConflict(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Put("/users/:id", service.UpdateUser)
+	app.Put("/users/:id", withAdmin(service.UpdateUser))
 
 	userID := "user-123"
 	user := &model.User{
@@ -513,23 +536,63 @@ func TestUpdateUser_EmailConflict(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUpdateUser_DeactivateRevokesSessions(t *testing.T) {
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
+
+	app := fiber.New()
+	app.Put("/users/:id", withAdmin(service.UpdateUser))
+
+	userID := "user-123"
+	user := &model.User{
+		ID:        userID,
+		Username:  "mahasiswa123",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+
+	mockUserRepo.On("FindByID", userID).Return(user, nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *model.User) bool { return !u.IsActive })).Return(nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.MatchedBy(func(rev *model.TokenRevocation) bool {
+		return rev.Kind == "user" && *rev.UserID == userID && rev.Reason == "user deactivated"
+	})).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-123", Name: "Mahasiswa"}}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
+	mockLecturerRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
+
+	body := `{"is_active": false}`
+
+	req := httptest.NewRequest("PUT", "/users/"+userID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockUserRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
+}
+
 // ==================== DELETE USER ====================
 
 func TestDeleteUser_Success(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, mockRevocationRepo, mockRefreshRepo := setupUserTest()
 
 	app := fiber.New()
-	app.Delete("/users/:id", service.DeleteUser)
+	app.Delete("/users/:id", withAdmin(service.DeleteUser))
 
 	userID := "user-123"
 	user := &model.User{
 		ID:       userID,
 		Username: "mahasiswa123",
-		RoleID:   "role-123",
 	}
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
-	mockUserRepo.On("GetRoleName", "role-123").Return("Admin", nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-123", Name: "Admin"}}, nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.MatchedBy(func(rev *model.TokenRevocation) bool {
+		return rev.Kind == "user" && *rev.UserID == userID
+	})).Return(nil)
 	mockUserRepo.On("Delete", userID).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/users/"+userID, nil)
@@ -537,13 +600,15 @@ func TestDeleteUser_Success(t *testing.T) {
 
 	assert.Equal(t, 200, resp.StatusCode)
 	mockUserRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
 }
 
 func TestDeleteUser_NotFound(t *testing.T) {
-	service, mockUserRepo, _, _, _, _ := setupUserTest()
+	service, mockUserRepo, _, _, _, _, _, _ := setupUserTest()
 
 	app := fiber.New()
-	app.Delete("/users/:id", service.DeleteUser)
+	app.Delete("/users/:id", withAdmin(service.DeleteUser))
 
 	mockUserRepo.On("FindByID", "invalid-id").Return(nil, errors.New("user not found"))
 
@@ -554,57 +619,139 @@ func TestDeleteUser_NotFound(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
-// ==================== ASSIGN ROLE ====================
+// ==================== ADD / REMOVE ROLE ====================
 
-func TestAssignRole_Success(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, _, _ := setupUserTest()
+func TestAddRole_Success(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
 
 	app := fiber.New()
-	app.Put("/users/:id/role", service.AssignRole)
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
 
 	userID := "user-123"
-	newRoleID := "role-456"
-
 	user := &model.User{
-		ID:       userID,
-		Username: "mahasiswa123",
-		RoleID:   "old-role",
-	}
-
-	newRole := &model.Role{
-		ID:   newRoleID,
-		Name: "Admin",
-	}
-
-	updatedUser := &model.User{
 		ID:        userID,
 		Username:  "mahasiswa123",
-		RoleID:    newRoleID,
 		CreatedAt: time.Now(),
 	}
 
-	mockUserRepo.On("FindByID", userID).Return(user, nil).Once()
-	mockRoleRepo.On("GetRoleByName", "Admin").Return(newRole, nil)
-	mockUserRepo.On("UpdateRole", userID, newRoleID).Return(nil)
-	mockUserRepo.On("FindByID", userID).Return(updatedUser, nil).Once()
-
-	body := `{"role_name": "Admin"}`
+	mockUserRepo.On("FindByID", userID).Return(user, nil)
+	mockRoleRepo.On("GetRoleByName", "Admin").Return(&model.Role{ID: "role-admin", Name: "Admin"}, nil)
+	mockUserRepo.On("AddRole", userID, "role-admin", "").Return(true, nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.MatchedBy(func(rev *model.TokenRevocation) bool {
+		return rev.Kind == "user" && *rev.UserID == userID && rev.Reason == "role changed"
+	})).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-admin", Name: "Admin"},
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: "student-1", UserID: userID}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
 
-	req := httptest.NewRequest("PUT", "/users/"+userID+"/role", strings.NewReader(body))
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(`{"role_name": "Admin"}`))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.UserResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"Mahasiswa", "Admin"}, result.Data.Roles)
+	assert.NotNil(t, result.Data.StudentProfile)
+
 	mockUserRepo.AssertExpectations(t)
-	mockRoleRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
 }
 
-func TestAssignRole_InvalidRole(t *testing.T) {
-	service, mockUserRepo, mockRoleRepo, _, _, _ := setupUserTest()
+func TestAddRole_CreatesLecturerProfile(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, _, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
 
 	app := fiber.New()
-	app.Put("/users/:id/role", service.AssignRole)
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, CreatedAt: time.Now()}, nil)
+	mockRoleRepo.On("GetRoleByName", "Dosen Wali").Return(&model.Role{ID: "role-dosen", Name: "Dosen Wali"}, nil)
+	mockUserRepo.On("AddRole", userID, "role-dosen", "").Return(true, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(nil, errors.New("not found")).Once()
+	mockLecturerRepo.On("FindByLecturerID", "L123456").Return(nil, errors.New("not found"))
+	mockLecturerRepo.On("Create", mock.MatchedBy(func(l *model.Lecturer) bool {
+		return l.UserID == userID && l.Department == "Informatika"
+	})).Return(nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-dosen", Name: "Dosen Wali"}}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: userID, LecturerID: "L123456"}, nil)
+
+	body := `{"role_name": "Dosen Wali", "lecturer_profile": {"lecturer_id": "L123456", "department": "Informatika"}}`
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockLecturerRepo.AssertExpectations(t)
+}
+
+func TestAddRole_RestoresRetiredProfile(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
+
+	app := fiber.New()
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
+
+	userID := "user-123"
+	retiredAt := time.Now()
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, CreatedAt: time.Now()}, nil)
+	mockRoleRepo.On("GetRoleByName", "Mahasiswa").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	mockUserRepo.On("AddRole", userID, "role-mhs", "").Return(true, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(&model.Student{ID: userID, RetiredAt: &retiredAt}, nil)
+	mockStudentRepo.On("SetRetired", userID, false).Return(nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
+
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(`{"role_name": "Mahasiswa"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockStudentRepo.AssertExpectations(t)
+	mockStudentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAddRole_AlreadyAssigned(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, _, _, mockRevocationRepo, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID}, nil)
+	mockRoleRepo.On("GetRoleByName", "Admin").Return(&model.Role{ID: "role-admin", Name: "Admin"}, nil)
+	mockUserRepo.On("AddRole", userID, "role-admin", "").Return(false, nil)
+
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(`{"role_name": "Admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	mockRevocationRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAddRole_InvalidRole(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, _, _, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
 
 	userID := "user-123"
 	user := &model.User{
@@ -617,7 +764,7 @@ func TestAssignRole_InvalidRole(t *testing.T) {
 
 	body := `{"role_name": "InvalidRole"}`
 
-	req := httptest.NewRequest("PUT", "/users/"+userID+"/role", strings.NewReader(body))
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, _ := app.Test(req)
@@ -625,4 +772,234 @@ func TestAssignRole_InvalidRole(t *testing.T) {
 	assert.Equal(t, 404, resp.StatusCode)
 	mockUserRepo.AssertExpectations(t)
 	mockRoleRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestRemoveRole_RetiresProfile(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
+
+	app := fiber.New()
+	app.Delete("/users/:id/roles/:roleId", withAdmin(service.RemoveRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, CreatedAt: time.Now()}, nil)
+	mockRoleRepo.On("GetRoleByID", "role-dosen").Return(&model.Role{ID: "role-dosen", Name: "Dosen Wali"}, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-dosen", Name: "Dosen Wali"},
+	}, nil).Once()
+	mockUserRepo.On("RemoveRole", userID, "role-dosen").Return(true, nil)
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: userID}, nil)
+	mockLecturerRepo.On("SetRetired", userID, true).Return(nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
+
+	req := httptest.NewRequest("DELETE", "/users/"+userID+"/roles/role-dosen", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockUserRepo.AssertExpectations(t)
+	mockLecturerRepo.AssertExpectations(t)
+	mockLecturerRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	mockRevocationRepo.AssertExpectations(t)
+}
+
+func TestRemoveRole_LastRole(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, _, _, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Delete("/users/:id/roles/:roleId", withAdmin(service.RemoveRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID}, nil)
+	mockRoleRepo.On("GetRoleByID", "role-mhs").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+
+	req := httptest.NewRequest("DELETE", "/users/"+userID+"/roles/role-mhs", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	mockUserRepo.AssertNotCalled(t, "RemoveRole", mock.Anything, mock.Anything)
+}
+
+func TestRemoveRole_NotAssigned(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, _, _, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Delete("/users/:id/roles/:roleId", withAdmin(service.RemoveRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID}, nil)
+	mockRoleRepo.On("GetRoleByID", "role-admin").Return(&model.Role{ID: "role-admin", Name: "Admin"}, nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-dosen", Name: "Dosen Wali"},
+	}, nil)
+
+	req := httptest.NewRequest("DELETE", "/users/"+userID+"/roles/role-admin", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+// ==================== SCOPED ADMIN ====================
+
+func TestGetUsers_ScopedAdmin(t *testing.T) {
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Get("/users", withProdiAdmin(service.GetUsers))
+
+	users := []model.User{{ID: "user-1", Username: "mhs1", CreatedAt: time.Now()}}
+
+	mockUserRepo.On("GetAllByProgramStudies", []string{"Informatika"}, 10, 0, "").Return(users, nil)
+	mockUserRepo.On("CountAllByProgramStudies", []string{"Informatika"}, "").Return(1, nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{{ID: "role-mhs", Name: "Mahasiswa"}}, nil)
+	mockStudentRepo.On("FindByUserID", "user-1").Return(&model.Student{ID: "user-1", ProgramStudy: "Informatika"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-1").Return(nil, errors.New("not found"))
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	mockUserRepo.AssertExpectations(t)
+	mockUserRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetUserByID_ScopedAdminOutsideScope(t *testing.T) {
+	service, mockUserRepo, _, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Get("/users/:id", withProdiAdmin(service.GetUserByID))
+
+	mockUserRepo.On("FindByID", "user-si").Return(&model.User{ID: "user-si"}, nil)
+	mockStudentRepo.On("FindByUserID", "user-si").Return(&model.Student{ID: "user-si", ProgramStudy: "Sistem Informasi"}, nil)
+	mockLecturerRepo.On("FindByUserID", "user-si").Return(nil, errors.New("not found"))
+
+	req := httptest.NewRequest("GET", "/users/user-si", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+}
+
+func TestGetUserByID_ScopedAdminCannotManageAdmins(t *testing.T) {
+	service, mockUserRepo, _, _, mockStudentRepo, _, _, _ := setupUserTest()
+
+	app := fiber.New()
+	app.Get("/users/:id", withProdiAdmin(service.GetUserByID))
+
+	// Mahasiswa Informatika yang juga admin global
+	mockUserRepo.On("FindByID", "user-1").Return(&model.User{ID: "user-1"}, nil)
+	mockStudentRepo.On("FindByUserID", "user-1").Return(&model.Student{ID: "user-1", ProgramStudy: "Informatika"}, nil)
+	mockUserRepo.On("GetRoles", "user-1").Return([]model.Role{
+		{ID: "role-mhs", Name: "Mahasiswa"},
+		{ID: "role-admin", Name: "Admin"},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/users/user-1", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+}
+
+func TestCreateUser_ScopedAdmin(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{
+			"mahasiswa di program studi sendiri",
+			`{"username": "mhs1", "email": "mhs1@test.com", "password": "password123", "full_name": "Mhs", "role_name": "Mahasiswa",
+				"student_profile": {"student_id": "123", "program_study": "Informatika", "academic_year": "2024"}}`,
+			201,
+		},
+		{
+			"mahasiswa di program studi lain",
+			`{"username": "mhs1", "email": "mhs1@test.com", "password": "password123", "full_name": "Mhs", "role_name": "Mahasiswa",
+				"student_profile": {"student_id": "123", "program_study": "Sistem Informasi", "academic_year": "2024"}}`,
+			403,
+		},
+		{
+			"tanpa profil",
+			`{"username": "mhs1", "email": "mhs1@test.com", "password": "password123", "full_name": "Mhs", "role_name": "Mahasiswa"}`,
+			422,
+		},
+		{
+			"membuat admin",
+			`{"username": "mhs1", "email": "mhs1@test.com", "password": "password123", "full_name": "Mhs", "role_name": "Admin"}`,
+			403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, _, _ := setupUserTest()
+
+			app := fiber.New()
+			app.Post("/users", withProdiAdmin(service.CreateUser))
+
+			mockUserRepo.On("FindByUsername", "mhs1").Return(nil, errors.New("not found"))
+			mockUserRepo.On("FindByEmail", "mhs1@test.com").Return(nil, errors.New("not found"))
+			mockRoleRepo.On("GetRoleByName", "Mahasiswa").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+			mockRoleRepo.On("GetRoleByName", "Admin").Return(&model.Role{ID: "role-admin", Name: "Admin"}, nil)
+			mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+			mockUserRepo.On("AddRole", mock.AnythingOfType("string"), "role-mhs", "").Return(true, nil)
+			mockStudentRepo.On("FindByStudentID", "123").Return(nil, errors.New("not found"))
+			mockStudentRepo.On("Create", mock.AnythingOfType("*model.Student")).Return(nil)
+			mockStudentRepo.On("FindByUserID", mock.AnythingOfType("string")).Return(&model.Student{ID: "student-id"}, nil)
+			mockLecturerRepo.On("FindByUserID", mock.AnythingOfType("string")).Return(nil, errors.New("not found"))
+
+			req := httptest.NewRequest("POST", "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, -1)
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 201 {
+				mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAddRole_ScopedAssignment(t *testing.T) {
+	service, mockUserRepo, mockRoleRepo, _, mockStudentRepo, mockLecturerRepo, mockRevocationRepo, mockRefreshRepo := setupUserTest()
+
+	app := fiber.New()
+	app.Post("/users/:id/roles", withAdmin(service.AddRole))
+
+	userID := "user-123"
+
+	mockUserRepo.On("FindByID", userID).Return(&model.User{ID: userID, CreatedAt: time.Now()}, nil)
+	mockRoleRepo.On("GetRoleByName", "Admin").Return(&model.Role{ID: "role-admin", Name: "Admin"}, nil)
+	mockUserRepo.On("AddRole", userID, "role-admin", "Informatika").Return(true, nil)
+	mockRefreshRepo.On("RevokeByUserID", userID).Return(nil)
+	mockRevocationRepo.On("Create", mock.AnythingOfType("*model.TokenRevocation")).Return(nil)
+	mockUserRepo.On("GetRoles", userID).Return([]model.Role{
+		{ID: "role-admin", Name: "Admin", ScopeProgramStudy: "Informatika"},
+		{ID: "role-admin", Name: "Admin", ScopeProgramStudy: "Sistem Informasi"},
+		{ID: "role-dosen", Name: "Dosen Wali"},
+	}, nil)
+	mockStudentRepo.On("FindByUserID", userID).Return(nil, errors.New("not found"))
+	mockLecturerRepo.On("FindByUserID", userID).Return(&model.Lecturer{ID: userID}, nil)
+
+	req := httptest.NewRequest("POST", "/users/"+userID+"/roles", strings.NewReader(`{"role_name": "Admin", "scope_program_study": "Informatika"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Data model.UserResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, []string{"Admin", "Dosen Wali"}, result.Data.Roles)
+	assert.Equal(t, map[string][]string{"Admin": {"Informatika", "Sistem Informasi"}}, result.Data.RoleScopes)
+	mockUserRepo.AssertExpectations(t)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"project_uas/app/model"
+	"project_uas/app/repository"
+)
+
+//
+// ==================== VERIFICATION PIPELINE ======================
+// Saat submit, achievement mendapat pipeline paling spesifik untuk jenis
+// prestasi & details.competitionLevel-nya (lihat PipelineRepository) dan mulai
+// di stage 1. Verify di stage terakhir membuat status verified; verify di
+// stage lain hanya memajukan current_stage. Reject / request revision boleh
+// dilakukan pemegang stage aktif mana pun.
+//
+
+// defaultStages - Tanpa pipeline yang cocok: satu stage oleh dosen wali
+var defaultStages = []model.PipelineStage{
+	{Position: 1, Name: "Dosen Wali", Role: "Dosen Wali"},
+}
+
+// stageDecision - Stage aktif yang boleh diputuskan user
+type stageDecision struct {
+	stage      model.PipelineStage
+	final      bool
+	onBehalfOf string // dosen wali yang diwakili (delegasi)
+}
+
+// pipelineFor - Pipeline untuk achievement (nil = defaultStages)
+func (s *AchievementService) pipelineFor(achievement *model.Achievement) (*model.VerificationPipeline, error) {
+	level, _ := achievement.Details["competitionLevel"].(string)
+
+	pipeline, err := s.pipelineRepo.FindForAchievement(achievement.AchievementType, level)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return pipeline, err
+}
+
+// pipelineStages - Stage pipeline yang sedang dijalani reference (pipelineID nil = defaultStages)
+func pipelineStages(pipelineRepo repository.PipelineRepository, pipelineID *string) ([]model.PipelineStage, error) {
+	if pipelineID == nil {
+		return defaultStages, nil
+	}
+
+	pipeline, err := pipelineRepo.FindByID(*pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pipeline.Stages) == 0 {
+		return defaultStages, nil
+	}
+	return pipeline.Stages, nil
+}
+
+// activeStage - Stage aktif dari current_stage; final = stage terakhir.
+// Reference lama (sebelum pipeline) atau belum disubmit: stage 1.
+func activeStage(stages []model.PipelineStage, currentStage *int) (stage model.PipelineStage, final bool) {
+	position := 1
+	if currentStage != nil && *currentStage > 1 {
+		position = *currentStage
+	}
+	if position > len(stages) {
+		position = len(stages)
+	}
+	return stages[position-1], position == len(stages)
+}
+
+// isAdvisorStage - Stage dipegang dosen wali mahasiswa (atau delegasinya)
+func isAdvisorStage(stage model.PipelineStage) bool {
+	return stage.Role == "Dosen Wali" && stage.ProgramStudy == nil
+}
+
+// decisionError - Keputusan verifikasi satu achievement gagal (status HTTP + pesan).
+// Dipakai bersama endpoint per item dan bulk.
+type decisionError struct {
+	code    int
+	message string
+}
+
+func (e *decisionError) Error() string {
+	return e.message
+}
+
+// decideStage - Stage aktif reference yang boleh diputuskan user
+func (s *AchievementService) decideStage(claims *model.JWTClaims, reference *model.AchievementReference) (*stageDecision, *decisionError) {
+	stages, err := pipelineStages(s.pipelineRepo, reference.PipelineID)
+	if err != nil {
+		return nil, &decisionError{500, "failed to load verification pipeline"}
+	}
+	stage, final := activeStage(stages, reference.CurrentStage)
+
+	allowed, onBehalfOf := s.authz.ActingForStage(claims, stage, Target{StudentID: reference.StudentID})
+	if !allowed {
+		return nil, &decisionError{403, fmt.Sprintf("forbidden: stage '%s' must be decided by %s", stage.Name, stageAssignee(stage))}
+	}
+
+	return &stageDecision{stage: stage, final: final, onBehalfOf: onBehalfOf}, nil
+}
+
+// authorizeStage - decideStage untuk handler per item.
+// Return nil jika response error sudah dikirim (error = hasil kirim response).
+func (s *AchievementService) authorizeStage(c *fiber.Ctx, claims *model.JWTClaims, reference *model.AchievementReference) (*stageDecision, error) {
+	decision, failure := s.decideStage(claims, reference)
+	if failure != nil {
+		return nil, c.Status(failure.code).JSON(model.APIResponse{
+			Status: "error",
+			Error:  failure.message,
+		})
+	}
+	return decision, nil
+}
+
+// approveStage - Setujui stage aktif: stage terakhir membuat status verified,
+// stage lain memajukan current_stage. Reference + history ditulis dalam satu
+// transaksi (UpdateReference).
+func (s *AchievementService) approveStage(claims *model.JWTClaims, reference *model.AchievementReference, note *string) (*stageDecision, *decisionError) {
+	decision, failure := s.decideStage(claims, reference)
+	if failure != nil {
+		return nil, failure
+	}
+
+	action := transitionApproveStage
+	if decision.final {
+		action = transitionVerify
+	}
+	status, err := nextStatus(reference.Status, action)
+	if err != nil {
+		return nil, &decisionError{400, err.Error()}
+	}
+
+	history := statusChange(claims, note, decision.onBehalfOf)
+	history.Stage = &decision.stage.Position
+
+	from := reference.State()
+	reference.Status = status
+	if decision.final {
+		now := time.Now()
+		reference.VerifiedAt = &now
+		reference.VerifiedBy = &claims.UserID
+		reference.OnBehalfOf = optionalString(decision.onBehalfOf)
+		reference.PointsFrozenAt = &now // poin tidak bisa disesuaikan lagi
+	} else {
+		nextStage := decision.stage.Position + 1
+		reference.CurrentStage = &nextStage
+	}
+
+	if err := s.achievementRepo.UpdateReference(reference, from, history); err != nil {
+		return nil, updateFailure(err, "failed to verify achievement")
+	}
+	return decision, nil
+}
+
+// rejectStage - Tolak achievement di stage aktif dengan catatan
+func (s *AchievementService) rejectStage(claims *model.JWTClaims, reference *model.AchievementReference, note string) (*stageDecision, *decisionError) {
+	decision, failure := s.decideStage(claims, reference)
+	if failure != nil {
+		return nil, failure
+	}
+
+	// Hanya bisa reject jika status = submitted
+	status, err := nextStatus(reference.Status, transitionReject)
+	if err != nil {
+		return nil, &decisionError{400, err.Error()}
+	}
+
+	// Penolak & delegasi dicatat di history
+	from := reference.State()
+	reference.Status = status
+	reference.RejectionNote = &note
+
+	history := statusChange(claims, &note, decision.onBehalfOf)
+	history.Stage = &decision.stage.Position
+
+	if err := s.achievementRepo.UpdateReference(reference, from, history); err != nil {
+		return nil, updateFailure(err, "failed to reject achievement")
+	}
+	return decision, nil
+}
+
+// updateFailure - decisionError untuk UpdateReference yang gagal (lihat statusUpdateFailed)
+func updateFailure(err error, message string) *decisionError {
+	if err == sql.ErrNoRows {
+		return &decisionError{409, errStatusChanged}
+	}
+	return &decisionError{500, message}
+}
+
+// stageAssignee - Deskripsi pemegang stage untuk pesan error
+func stageAssignee(stage model.PipelineStage) string {
+	if isAdvisorStage(stage) {
+		return "the student's advisor"
+	}
+	if stage.ProgramStudy != nil {
+		return fmt.Sprintf("%s of %s", stage.Role, *stage.ProgramStudy)
+	}
+	return stage.Role
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Generate private key untuk JWT_KEYS_DIR.
+// Contoh: go run ./cmd/keygen -alg EdDSA -kid 2025-07 -dir ./keys
+// Saat rotasi: buat key baru, ganti JWT_SIGNING_KEY_ID, dan biarkan key lama
+// di folder yang sama sampai semua token lama expired.
+func main() {
+	alg := flag.String("alg", "EdDSA", "algoritma key: EdDSA atau RS256")
+	kid := flag.String("kid", "", "key id (nama file <kid>.pem)")
+	dir := flag.String("dir", "./keys", "folder output")
+	flag.Parse()
+
+	if *kid == "" {
+		log.Fatal("-kid is required")
+	}
+
+	var privateKey interface{}
+	switch *alg {
+	case "EdDSA":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			log.Fatal("Failed to generate key:", err)
+		}
+		privateKey = key
+	case "RS256":
+		key, err := rsa.GenerateKey(rand.Reader, 3072)
+		if err != nil {
+			log.Fatal("Failed to generate key:", err)
+		}
+		privateKey = key
+	default:
+		log.Fatalf("Unsupported -alg %q (use EdDSA or RS256)", *alg)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		log.Fatal("Failed to encode key:", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatal("Failed to create key folder:", err)
+	}
+
+	path := filepath.Join(*dir, *kid+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatal("Failed to create key file:", err)
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		log.Fatal("Failed to write key file:", err)
+	}
+
+	log.Printf("✅ %s key written to %s", *alg, path)
+}
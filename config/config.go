@@ -1,9 +1,34 @@
 package config
 
+import "time"
+
+const (
+	EnvDevelopment   = "development"
+	DefaultJWTSecret = "default-secret-key"
+)
+
 type Config struct {
 	DBUrl     string
 	MongoURL  string
 	MongoDB   string
 	Port      string
 	JWTSecret string
-}
\ No newline at end of file
+
+	AppEnv          string // "development" / "production"
+	JWTKeysDir      string // folder berisi <kid>.pem (private key untuk signing, public key untuk verifikasi)
+	JWTSigningKeyID string // kid yang dipakai untuk sign token baru
+	JWTAcceptHS256  bool   // tetap terima token HS256 lama (JWT_SECRET) selama masa migrasi
+
+	AppBaseURL       string        // dipakai untuk link di email (reset password)
+	MailOutboxDir    string        // folder outbox untuk FileOutboxMailer
+	PasswordResetTTL time.Duration // masa berlaku token reset password
+
+	VerificationSLA           time.Duration // submitted lebih lama dari ini: dosen wali diingatkan
+	VerificationEscalateAfter time.Duration // submitted lebih lama dari ini: eskalasi ke admin program studi
+	VerificationSLAInterval   time.Duration // jeda antar pengecekan scheduler SLA
+
+	TagsCanonicalOnly bool // achievement baru / yang diubah hanya boleh memakai tag dari kosakata
+
+	TrashRetention     time.Duration // achievement di trash lebih lama dari ini dihapus permanen
+	TrashPurgeInterval time.Duration // jeda antar purge trash
+}
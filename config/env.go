@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,12 +22,53 @@ func LoadEnv() {
 		MongoURL:   getEnv("MONGO_URL", "mongodb://localhost:27017"),
 		MongoDB:    getEnv("MONGO_DB", "uas_achievements"),
 		Port:       getEnv("PORT", "3000"),
-		JWTSecret:  getEnv("JWT_SECRET", "default-secret-key"),
+		JWTSecret:  getEnv("JWT_SECRET", DefaultJWTSecret),
+
+		AppEnv:          getEnv("APP_ENV", EnvDevelopment),
+		JWTKeysDir:      os.Getenv("JWT_KEYS_DIR"),
+		JWTSigningKeyID: os.Getenv("JWT_SIGNING_KEY_ID"),
+		JWTAcceptHS256:  os.Getenv("JWT_ACCEPT_HS256") == "true",
+
+		AppBaseURL:       getEnv("APP_BASE_URL", "http://localhost:3000"),
+		MailOutboxDir:    getEnv("MAIL_OUTBOX_DIR", "./mail_outbox"),
+		PasswordResetTTL: getDurationEnv("PASSWORD_RESET_TTL", 30*time.Minute),
+
+		VerificationSLA:           getDurationEnv("VERIFICATION_SLA", 7*24*time.Hour),
+		VerificationEscalateAfter: getDurationEnv("VERIFICATION_ESCALATE_AFTER", 14*24*time.Hour),
+		VerificationSLAInterval:   getDurationEnv("VERIFICATION_SLA_INTERVAL", time.Hour),
+
+		TagsCanonicalOnly: os.Getenv("TAGS_CANONICAL_ONLY") == "true",
+
+		TrashRetention:     getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour),
 	}
 
 	log.Println("Environment variables loaded successfully")
 }
 
+// Validate - Cek konfigurasi yang tidak aman dipakai di luar development
+func (c Config) Validate() error {
+	if c.VerificationEscalateAfter <= c.VerificationSLA {
+		return errors.New("VERIFICATION_ESCALATE_AFTER must be longer than VERIFICATION_SLA")
+	}
+
+	if c.IsDevelopment() {
+		return nil
+	}
+
+	if c.JWTSecret == DefaultJWTSecret {
+		return errors.New("JWT_SECRET must be set outside development (default secret is not allowed)")
+	}
+	if c.JWTKeysDir == "" {
+		return errors.New("JWT_KEYS_DIR must be set outside development")
+	}
+	return nil
+}
+
+func (c Config) IsDevelopment() bool {
+	return c.AppEnv == EnvDevelopment
+}
+
 // Helper function untuk get env dengan default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -33,4 +76,18 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// Helper function untuk get env durasi (format time.ParseDuration, mis. "30m")
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
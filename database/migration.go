@@ -15,6 +15,8 @@ func RunMigrations(db *sql.DB) error {
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			name VARCHAR(50) UNIQUE NOT NULL,
 			description TEXT,
+			mfa_required BOOLEAN NOT NULL DEFAULT FALSE,
+			is_builtin BOOLEAN NOT NULL DEFAULT FALSE,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
@@ -41,17 +43,42 @@ func RunMigrations(db *sql.DB) error {
 			email VARCHAR(100) UNIQUE NOT NULL,
 			password_hash VARCHAR(255) NOT NULL,
 			full_name VARCHAR(100) NOT NULL,
-			role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
 			is_active BOOLEAN DEFAULT true,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Create user_roles junction table (satu user bisa punya banyak role)
+		// program_study: assignment dibatasi program studi / departemen ('' = semua)
+		`CREATE TABLE IF NOT EXISTS user_roles (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role_id UUID NOT NULL REFERENCES roles(id) ON DELETE RESTRICT,
+			program_study VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, role_id, program_study)
+		)`,
+
+		// Database lama (satu role per user di users.role_id): salin role ke
+		// user_roles sebagai assignment tanpa batas program studi, baru kolomnya dihapus
+		`DO $$
+		BEGIN
+			IF EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'role_id'
+			) THEN
+				INSERT INTO user_roles (user_id, role_id, program_study)
+				SELECT id, role_id, '' FROM users WHERE role_id IS NOT NULL
+				ON CONFLICT DO NOTHING;
+				ALTER TABLE users DROP COLUMN role_id;
+			END IF;
+		END $$`,
+
 		// Create lecturers table
 		`CREATE TABLE IF NOT EXISTS lecturers (
 			id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
 			lecturer_id VARCHAR(50) UNIQUE NOT NULL,
 			department VARCHAR(100),
+			retired_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
@@ -62,33 +89,328 @@ func RunMigrations(db *sql.DB) error {
 			program_study VARCHAR(100),
 			academic_year INT,
 			advisor_id UUID REFERENCES lecturers(id) ON DELETE SET NULL,
+			retired_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Create achievement_types table (registry jenis prestasi)
+		// details_schema = JSON Schema untuk field details achievement jenis tsb
+		`CREATE TABLE IF NOT EXISTS achievement_types (
+			code VARCHAR(50) PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			details_schema JSONB NOT NULL,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create point_rules table (poin per jenis prestasi; kolom kondisi NULL = semua)
+		`CREATE TABLE IF NOT EXISTS point_rules (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(100) NOT NULL,
+			achievement_type VARCHAR(50) NOT NULL REFERENCES achievement_types(code) ON DELETE CASCADE,
+			competition_level VARCHAR(50),
+			rank_min INT CHECK (rank_min > 0),
+			rank_max INT CHECK (rank_max >= rank_min),
+			team_size_min INT CHECK (team_size_min > 0),
+			team_size_max INT CHECK (team_size_max >= team_size_min),
+			points INT NOT NULL CHECK (points >= 0),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create academic_semesters table (kalender akademik; rentang tidak tumpang tindih, dicek di service)
+		`CREATE TABLE IF NOT EXISTS academic_semesters (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(50) UNIQUE NOT NULL,
+			academic_year VARCHAR(9) NOT NULL,
+			term VARCHAR(10) NOT NULL CHECK (term IN ('Ganjil', 'Genap')),
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL CHECK (end_date >= start_date),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (academic_year, term)
+		)`,
+
+		// Create tags table (kosakata tag yang dikurasi admin; normalized = huruf kecil, spasi dirapikan)
+		`CREATE TABLE IF NOT EXISTS tags (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(50) NOT NULL,
+			normalized VARCHAR(50) UNIQUE NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create tag_synonyms table (sinonim dipetakan ke tag kanonik; bentrok dengan nama tag dicek di service)
+		`CREATE TABLE IF NOT EXISTS tag_synonyms (
+			tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			synonym VARCHAR(50) NOT NULL,
+			normalized VARCHAR(50) UNIQUE NOT NULL
+		)`,
+
+		// Create verification_pipelines table
+		// Pipeline dipilih per jenis prestasi dan/atau tingkat kompetisi (NULL = semua);
+		// achievement tanpa pipeline yang cocok hanya diverifikasi dosen wali
+		`CREATE TABLE IF NOT EXISTS verification_pipelines (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(100) NOT NULL,
+			achievement_type VARCHAR(50),
+			competition_level VARCHAR(50),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create verification_pipeline_stages table (urutan stage, position mulai 1)
+		// Stage dipegang role; program_study diisi jika hanya admin yang di-assign
+		// ke program studi tsb yang boleh menyetujui
+		`CREATE TABLE IF NOT EXISTS verification_pipeline_stages (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			pipeline_id UUID NOT NULL REFERENCES verification_pipelines(id) ON DELETE CASCADE,
+			position INT NOT NULL CHECK (position > 0),
+			name VARCHAR(100) NOT NULL,
+			role VARCHAR(50) NOT NULL,
+			program_study VARCHAR(100),
+			UNIQUE (pipeline_id, position)
+		)`,
+
 		// Create achievement_references table
 		`CREATE TABLE IF NOT EXISTS achievement_references (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
 			mongo_achievement_id VARCHAR(24) NOT NULL,
-			status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'deleted')),
+			status VARCHAR(20) NOT NULL,
 			submitted_at TIMESTAMP,
 			verified_at TIMESTAMP,
 			verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			on_behalf_of UUID REFERENCES lecturers(id) ON DELETE SET NULL,
 			rejection_note TEXT,
+			pipeline_id UUID REFERENCES verification_pipelines(id) ON DELETE SET NULL,
+			current_stage INT,
+			points INT,
+			point_rule_id UUID REFERENCES point_rules(id) ON DELETE SET NULL,
+			points_frozen_at TIMESTAMP,
+			deleted_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Status workflow (lihat service/achievement_workflow.go). Constraint di-drop
+		// dulu supaya database lama ikut mendapat status baru.
+		`ALTER TABLE achievement_references DROP CONSTRAINT IF EXISTS achievement_references_status_check`,
+		`ALTER TABLE achievement_references ADD CONSTRAINT achievement_references_status_check
+			CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'revision_requested', 'deleted'))`,
+
+		// Create achievement_status_history table (append-only, satu baris per transisi)
+		`CREATE TABLE IF NOT EXISTS achievement_status_history (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			from_status VARCHAR(20),
+			to_status VARCHAR(20) NOT NULL,
+			actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			actor_role VARCHAR(100),
+			on_behalf_of UUID REFERENCES lecturers(id) ON DELETE SET NULL,
+			note TEXT,
+			stage INT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Backfill history untuk reference yang dibuat sebelum tabel history ada
+		// (hanya transisi yang masih terlihat dari kolom reference)
+		`INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, actor_id, created_at)
+		SELECT ar.id, NULL, 'draft', ar.student_id, ar.created_at
+		FROM achievement_references ar
+		WHERE NOT EXISTS (SELECT 1 FROM achievement_status_history h WHERE h.achievement_reference_id = ar.id)`,
+		`INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, actor_id, created_at)
+		SELECT ar.id, 'draft', 'submitted', ar.student_id, ar.submitted_at
+		FROM achievement_references ar
+		WHERE ar.submitted_at IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM achievement_status_history h WHERE h.achievement_reference_id = ar.id AND h.to_status = 'submitted')`,
+		`INSERT INTO achievement_status_history (achievement_reference_id, from_status, to_status, actor_id, on_behalf_of, note, created_at)
+		SELECT ar.id, CASE WHEN ar.submitted_at IS NULL THEN 'draft' ELSE 'submitted' END, ar.status, ar.verified_by, ar.on_behalf_of, ar.rejection_note, COALESCE(ar.verified_at, ar.updated_at)
+		FROM achievement_references ar
+		WHERE ar.status IN ('verified', 'rejected', 'deleted')
+			AND NOT EXISTS (SELECT 1 FROM achievement_status_history h WHERE h.achievement_reference_id = ar.id AND h.to_status = ar.status)`,
+
+		// Create point_adjustments table (penyesuaian poin oleh verifikator, wajib justifikasi)
+		`CREATE TABLE IF NOT EXISTS point_adjustments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			from_points INT NOT NULL,
+			to_points INT NOT NULL CHECK (to_points >= 0),
+			justification TEXT NOT NULL,
+			adjusted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			on_behalf_of UUID REFERENCES lecturers(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create duplicate_flags table (hasil cek duplikat saat submit, per dokumen MongoDB)
+		`CREATE TABLE IF NOT EXISTS duplicate_flags (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			mongo_achievement_id VARCHAR(24) NOT NULL,
+			matched_mongo_achievement_id VARCHAR(24) NOT NULL,
+			reasons VARCHAR(100) NOT NULL,
+			title_similarity DOUBLE PRECISION NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (mongo_achievement_id, matched_mongo_achievement_id)
+		)`,
+
+		// Create achievement_comments table (diskusi per achievement, tidak ikut reset saat resubmit)
+		`CREATE TABLE IF NOT EXISTS achievement_comments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			parent_id UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+			author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			author_role VARCHAR(100) NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			anchor_type VARCHAR(20) CHECK (anchor_type IN ('field', 'attachment')),
+			anchor_ref VARCHAR(500),
+			achievement_status VARCHAR(20) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			edited_at TIMESTAMP,
+			deleted_at TIMESTAMP
+		)`,
+
+		// Create verification_sla_notifications table
+		// Reminder / eskalasi yang sudah dikirim per submit (submitted_at), supaya
+		// scheduler SLA tidak mengirim ulang di setiap putaran
+		`CREATE TABLE IF NOT EXISTS verification_sla_notifications (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			level VARCHAR(20) NOT NULL CHECK (level IN ('reminder', 'escalation')),
+			submitted_at TIMESTAMP NOT NULL,
+			recipients TEXT NOT NULL DEFAULT '',
+			sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (achievement_reference_id, level, submitted_at)
+		)`,
+
+		// Create verification_delegations table
+		// Dosen wali (lecturer_id) mendelegasikan verifikasi ke dosen lain (delegate_id)
+		// selama [starts_at, ends_at); otomatis tidak berlaku setelah ends_at
+		`CREATE TABLE IF NOT EXISTS verification_delegations (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			lecturer_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+			delegate_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			revoked_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			CHECK (lecturer_id <> delegate_id),
+			CHECK (ends_at > starts_at)
+		)`,
+
+		// Create refresh_tokens table (server-side refresh token store)
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			family_id UUID NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			revoked_at TIMESTAMP,
+			replaced_by UUID,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create token_revocations table (revoke access token per jti atau per user)
+		`CREATE TABLE IF NOT EXISTS token_revocations (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			kind VARCHAR(10) NOT NULL CHECK (kind IN ('token', 'user')),
+			jti VARCHAR(64),
+			user_id UUID,
+			revoked_before TIMESTAMP,
+			reason VARCHAR(100),
+			expires_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create password_reset_tokens table (hanya hash token yang disimpan)
+		`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create user_mfa table (TOTP secret per user)
+		`CREATE TABLE IF NOT EXISTS user_mfa (
+			user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			secret VARCHAR(64) NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			last_used_step BIGINT NOT NULL DEFAULT 0,
+			enabled_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Create mfa_recovery_codes table (hanya hash yang disimpan)
+		`CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			code_hash VARCHAR(64) NOT NULL,
+			used_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create login_lockouts table (counter gagal login per username / IP)
+		// failed_ips: IP asal gagal login ke username (dibuka bersama akun oleh admin)
+		`CREATE TABLE IF NOT EXISTS login_lockouts (
+			key_type VARCHAR(10) NOT NULL CHECK (key_type IN ('username', 'ip')),
+			key_value VARCHAR(100) NOT NULL,
+			failed_count INT NOT NULL DEFAULT 0,
+			lock_count INT NOT NULL DEFAULT 0,
+			locked_until TIMESTAMP,
+			last_failed_at TIMESTAMP,
+			failed_ips TEXT[] NOT NULL DEFAULT '{}',
+			PRIMARY KEY (key_type, key_value)
+		)`,
+
+		// Create audit_logs table
+		`CREATE TABLE IF NOT EXISTS audit_logs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			actor_id UUID,
+			action VARCHAR(100) NOT NULL,
+			target_type VARCHAR(50) NOT NULL,
+			target_id VARCHAR(100) NOT NULL,
+			details TEXT,
+			ip_address VARCHAR(64),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
 		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_role_permissions_role_id ON role_permissions(role_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_students_student_id ON students(student_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_students_advisor_id ON students(advisor_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_students_program_study ON students(program_study)`,
+		`CREATE INDEX IF NOT EXISTS idx_lecturers_department ON lecturers(department)`,
 		`CREATE INDEX IF NOT EXISTS idx_lecturers_lecturer_id ON lecturers(lecturer_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_student_id ON achievement_references(student_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_mongo_id ON achievement_references(mongo_achievement_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_achievement_refs_mongo_student ON achievement_references(mongo_achievement_id, student_id) WHERE status <> 'deleted'`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_status ON achievement_references(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_deleted_at ON achievement_references(deleted_at) WHERE status = 'deleted'`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_pipelines_match ON verification_pipelines(COALESCE(achievement_type, ''), COALESCE(competition_level, ''))`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_status_history_ref_id ON achievement_status_history(achievement_reference_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_comments_ref_id ON achievement_comments(achievement_reference_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_point_rules_type ON point_rules(achievement_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_academic_semesters_dates ON academic_semesters(start_date, end_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_tag_synonyms_tag_id ON tag_synonyms(tag_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_point_adjustments_ref_id ON point_adjustments(achievement_reference_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_duplicate_flags_matched ON duplicate_flags(matched_mongo_achievement_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_references_submitted ON achievement_references(submitted_at) WHERE status = 'submitted'`,
+		`CREATE INDEX IF NOT EXISTS idx_verification_delegations_lecturer_id ON verification_delegations(lecturer_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_verification_delegations_delegate_id ON verification_delegations(delegate_id, ends_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_token_revocations_expires_at ON token_revocations(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs(target_type, target_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at)`,
 	}
 
 	for i, migration := range migrations {
@@ -108,9 +430,30 @@ func DropTables(db *sql.DB) error {
 	log.Println("Dropping all tables...")
 
 	drops := []string{
+		`DROP TABLE IF EXISTS audit_logs CASCADE`,
+		`DROP TABLE IF EXISTS login_lockouts CASCADE`,
+		`DROP TABLE IF EXISTS mfa_recovery_codes CASCADE`,
+		`DROP TABLE IF EXISTS user_mfa CASCADE`,
+		`DROP TABLE IF EXISTS password_reset_tokens CASCADE`,
+		`DROP TABLE IF EXISTS token_revocations CASCADE`,
+		`DROP TABLE IF EXISTS refresh_tokens CASCADE`,
+		`DROP TABLE IF EXISTS verification_delegations CASCADE`,
+		`DROP TABLE IF EXISTS achievement_comments CASCADE`,
+		`DROP TABLE IF EXISTS duplicate_flags CASCADE`,
+		`DROP TABLE IF EXISTS point_adjustments CASCADE`,
+		`DROP TABLE IF EXISTS verification_sla_notifications CASCADE`,
+		`DROP TABLE IF EXISTS achievement_status_history CASCADE`,
 		`DROP TABLE IF EXISTS achievement_references CASCADE`,
+		`DROP TABLE IF EXISTS verification_pipeline_stages CASCADE`,
+		`DROP TABLE IF EXISTS verification_pipelines CASCADE`,
+		`DROP TABLE IF EXISTS point_rules CASCADE`,
+		`DROP TABLE IF EXISTS academic_semesters CASCADE`,
+		`DROP TABLE IF EXISTS tag_synonyms CASCADE`,
+		`DROP TABLE IF EXISTS tags CASCADE`,
+		`DROP TABLE IF EXISTS achievement_types CASCADE`,
 		`DROP TABLE IF EXISTS students CASCADE`,
 		`DROP TABLE IF EXISTS lecturers CASCADE`,
+		`DROP TABLE IF EXISTS user_roles CASCADE`,
 		`DROP TABLE IF EXISTS users CASCADE`,
 		`DROP TABLE IF EXISTS role_permissions CASCADE`,
 		`DROP TABLE IF EXISTS permissions CASCADE`,
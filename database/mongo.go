@@ -6,6 +6,7 @@ import (
 	"project_uas/config"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -30,4 +31,30 @@ func ConnectMongoDB() {
 
 	MongoDB = client.Database(config.AppConfig.MongoDB)
 	log.Println("MongoDB connected successfully")
+
+	ensureMongoIndexes(ctx)
+}
+
+// ensureMongoIndexes - Index MongoDB (idempotent, aman dijalankan setiap start)
+func ensureMongoIndexes(ctx context.Context) {
+	// Nomor versi unik per achievement
+	_, err := MongoDB.Collection("achievement_versions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Println("Failed to create achievement_versions index:", err)
+	}
+
+	// Kandidat cek duplikat: sejenis (terbaru dulu) dan hash lampiran; filter tanggal kegiatan;
+	// penulisan ulang tag saat merge
+	_, err = MongoDB.Collection("achievements").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "achievementType", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "attachments.sha256", Value: 1}}},
+		{Keys: bson.D{{Key: "eventDate", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+	})
+	if err != nil {
+		log.Println("Failed to create achievements indexes:", err)
+	}
 }
\ No newline at end of file
@@ -24,6 +24,14 @@ func RunSeeders(db *sql.DB) error {
 	if err := seedUsers(db); err != nil {
 		return err
 	}
+
+	if err := seedAchievementTypes(db); err != nil {
+		return err
+	}
+
+	if err := seedPointRules(db); err != nil {
+		return err
+	}
 	
 	log.Println("All seeders completed successfully! ✅")
 	return nil
@@ -32,21 +40,24 @@ func RunSeeders(db *sql.DB) error {
 func seedRoles(db *sql.DB) error {
 	log.Println("Seeding roles...")
 
+	// mfaRequired: role dengan akses sensitif wajib login dengan TOTP.
+	// Role bawaan ditandai is_builtin karena namanya dipakai langsung di kode.
 	roles := []struct {
 		name        string
 		description string
+		mfaRequired bool
 	}{
-		{"Admin", "Pengelola sistem"},
-		{"Mahasiswa", "Pelapor prestasi"},
-		{"Dosen Wali", "Verifikator prestasi"},
+		{"Admin", "Pengelola sistem", true},
+		{"Mahasiswa", "Pelapor prestasi", false},
+		{"Dosen Wali", "Verifikator prestasi", true},
 	}
 
 	for _, role := range roles {
 		_, err := db.Exec(`
-			INSERT INTO roles (name, description) 
-			VALUES ($1, $2) 
+			INSERT INTO roles (name, description, mfa_required, is_builtin) 
+			VALUES ($1, $2, $3, TRUE) 
 			ON CONFLICT (name) DO NOTHING
-		`, role.name, role.description)
+		`, role.name, role.description, role.mfaRequired)
 
 		if err != nil {
 			log.Printf("Failed to seed role %s: %v", role.name, err)
@@ -68,6 +79,7 @@ func seedPermissions(db *sql.DB) error {
 		description string
 	}{
 		{"user:manage", "user", "manage", "Mengelola data user"},
+		{"role:manage", "role", "manage", "Mengelola role dan permission"},
 		{"achievement:create", "achievement", "create", "Membuat prestasi baru"},
 		{"achievement:read", "achievement", "read", "Membaca prestasi"},
 		{"achievement:update", "achievement", "update", "Mengupdate prestasi"},
@@ -98,6 +110,7 @@ func seedRolePermissions(db *sql.DB) error {
 
 	adminPerms := []string{
 		"user:manage",
+		"role:manage",
 		"achievement:read",
 		"achievement:create",
 		"achievement:update",
@@ -155,35 +168,180 @@ func seedUsers(db *sql.DB) error {
 	}
 
 	users := []struct {
-		username  string
-		email     string
-		password  string
-		fullName  string
-		role      string
-		isActive  bool
+		username string
+		email    string
+		password string
+		fullName string
+		roles    []string
+		isActive bool
 	}{
-		{"admin", "admin@example.com", defaultPassword, "Administrator", "Admin", true},
-		{"mahasiswa1", "mahasiswa1@example.com", defaultPassword, "Budi Santoso", "Mahasiswa", true},
-		{"mahasiswa2", "mahasiswa2@example.com", defaultPassword, "Siti Aminah", "Mahasiswa", true},
-		{"dosenwali1", "dosenwali1@example.com", defaultPassword, "Dr. Ahmad Rahman", "Dosen Wali", true},
-		{"dosenwali2", "dosenwali2@example.com", defaultPassword, "Dr. Dewi Sartika", "Dosen Wali", true},
+		{"admin", "admin@example.com", defaultPassword, "Administrator", []string{"Admin"}, true},
+		{"mahasiswa1", "mahasiswa1@example.com", defaultPassword, "Budi Santoso", []string{"Mahasiswa"}, true},
+		{"mahasiswa2", "mahasiswa2@example.com", defaultPassword, "Siti Aminah", []string{"Mahasiswa"}, true},
+		{"dosenwali1", "dosenwali1@example.com", defaultPassword, "Dr. Ahmad Rahman", []string{"Dosen Wali"}, true},
+		{"dosenwali2", "dosenwali2@example.com", defaultPassword, "Dr. Dewi Sartika", []string{"Dosen Wali"}, true},
 	}
 
 	for _, user := range users {
 		_, err := db.Exec(`
-			INSERT INTO users (username, email, password_hash, full_name, role_id, is_active)
-			SELECT $1, $2, $3, $4, r.id, $5
-			FROM roles r
-			WHERE r.name = $6
+			INSERT INTO users (username, email, password_hash, full_name, is_active)
+			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (username) DO NOTHING
-		`, user.username, user.email, user.password, user.fullName, user.isActive, user.role)
+		`, user.username, user.email, user.password, user.fullName, user.isActive)
 
 		if err != nil {
 			log.Printf("Failed to seed user %s: %v", user.username, err)
 			return err
 		}
+
+		for _, role := range user.roles {
+			_, err := db.Exec(`
+				INSERT INTO user_roles (user_id, role_id)
+				SELECT u.id, r.id
+				FROM users u, roles r
+				WHERE u.username = $1 AND r.name = $2
+				ON CONFLICT DO NOTHING
+			`, user.username, role)
+
+			if err != nil {
+				log.Printf("Failed to assign role %s to %s: %v", role, user.username, err)
+				return err
+			}
+		}
 	}
 
 	log.Println("Users seeded ✅")
 	return nil
-}
\ No newline at end of file
+}
+
+// seedAchievementTypes - Jenis prestasi bawaan beserta schema details-nya.
+// Schema yang sudah diubah admin tidak ditimpa (ON CONFLICT DO NOTHING).
+func seedAchievementTypes(db *sql.DB) error {
+	log.Println("Seeding achievement types...")
+
+	types := []struct {
+		code        string
+		name        string
+		description string
+		schema      string
+	}{
+		{"competition", "Kompetisi", "Lomba / kompetisi akademik maupun non-akademik", `{
+			"type": "object",
+			"required": ["competitionName", "competitionLevel"],
+			"properties": {
+				"competitionName": {"type": "string", "title": "Nama Kompetisi", "minLength": 1, "maxLength": 200},
+				"competitionLevel": {"type": "string", "title": "Tingkat", "enum": ["international", "national", "regional", "local"]},
+				"rank": {"type": "integer", "title": "Peringkat", "minimum": 1},
+				"medalType": {"type": "string", "title": "Medali", "enum": ["gold", "silver", "bronze"]},
+				"organizer": {"type": "string", "title": "Penyelenggara"},
+				"eventDate": {"type": "string", "title": "Tanggal", "format": "date"},
+				"location": {"type": "string", "title": "Lokasi"}
+			}
+		}`},
+		{"publication", "Publikasi", "Publikasi ilmiah (jurnal, konferensi, buku)", `{
+			"type": "object",
+			"required": ["publicationType", "publicationTitle", "authors"],
+			"properties": {
+				"publicationType": {"type": "string", "title": "Jenis Publikasi", "enum": ["journal", "conference", "book"]},
+				"publicationTitle": {"type": "string", "title": "Judul", "minLength": 1},
+				"authors": {"type": "array", "title": "Penulis", "minItems": 1, "items": {"type": "string", "minLength": 1}},
+				"publisher": {"type": "string", "title": "Penerbit / Jurnal"},
+				"issn": {"type": "string", "title": "ISSN / ISBN"},
+				"doi": {"type": "string", "title": "DOI"},
+				"url": {"type": "string", "title": "Tautan", "format": "uri"},
+				"publishedDate": {"type": "string", "title": "Tanggal Terbit", "format": "date"}
+			}
+		}`},
+		{"certification", "Sertifikasi", "Sertifikasi kompetensi / profesi", `{
+			"type": "object",
+			"required": ["certificationName", "issuedBy"],
+			"properties": {
+				"certificationName": {"type": "string", "title": "Nama Sertifikasi", "minLength": 1},
+				"issuedBy": {"type": "string", "title": "Penerbit", "minLength": 1},
+				"certificationNumber": {"type": "string", "title": "Nomor Sertifikat"},
+				"issuedDate": {"type": "string", "title": "Tanggal Terbit", "format": "date"},
+				"validUntil": {"type": "string", "title": "Berlaku Hingga", "format": "date"}
+			}
+		}`},
+		{"organization", "Organisasi", "Kepengurusan organisasi / kepanitiaan", `{
+			"type": "object",
+			"required": ["organizationName", "position"],
+			"properties": {
+				"organizationName": {"type": "string", "title": "Nama Organisasi", "minLength": 1},
+				"position": {"type": "string", "title": "Jabatan", "minLength": 1},
+				"periodStart": {"type": "string", "title": "Mulai", "format": "date"},
+				"periodEnd": {"type": "string", "title": "Selesai", "format": "date"}
+			}
+		}`},
+		{"academic", "Akademik", "Prestasi akademik (beasiswa, IPK, penelitian)", `{
+			"type": "object",
+			"properties": {
+				"institution": {"type": "string", "title": "Institusi"},
+				"score": {"type": "number", "title": "Nilai", "minimum": 0},
+				"awardDate": {"type": "string", "title": "Tanggal", "format": "date"}
+			}
+		}`},
+		{"other", "Lainnya", "Prestasi lain di luar kategori di atas", `{
+			"type": "object"
+		}`},
+	}
+
+	for _, t := range types {
+		_, err := db.Exec(`
+			INSERT INTO achievement_types (code, name, description, details_schema)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (code) DO NOTHING
+		`, t.code, t.name, t.description, t.schema)
+
+		if err != nil {
+			log.Printf("Failed to seed achievement type %s: %v", t.code, err)
+			return err
+		}
+	}
+
+	log.Println("Achievement types seeded ✅")
+	return nil
+}
+
+// seedPointRules - Poin dasar per jenis prestasi / tingkat kompetisi.
+// Rule peringkat & tim ditambahkan admin lewat /point-rules.
+func seedPointRules(db *sql.DB) error {
+	log.Println("Seeding point rules...")
+
+	rules := []struct {
+		name             string
+		achievementType  string
+		competitionLevel *string
+		points           int
+	}{
+		{"Kompetisi internasional", "competition", strPtr("international"), 100},
+		{"Kompetisi nasional", "competition", strPtr("national"), 60},
+		{"Kompetisi regional", "competition", strPtr("regional"), 40},
+		{"Kompetisi lokal", "competition", strPtr("local"), 20},
+		{"Publikasi", "publication", nil, 50},
+		{"Sertifikasi", "certification", nil, 30},
+		{"Organisasi", "organization", nil, 20},
+		{"Akademik", "academic", nil, 25},
+		{"Lainnya", "other", nil, 10},
+	}
+
+	for _, rule := range rules {
+		_, err := db.Exec(`
+			INSERT INTO point_rules (name, achievement_type, competition_level, points)
+			SELECT $1::varchar, $2::varchar, $3::varchar, $4::int
+			WHERE NOT EXISTS (SELECT 1 FROM point_rules WHERE name = $1::varchar)
+		`, rule.name, rule.achievementType, rule.competitionLevel, rule.points)
+
+		if err != nil {
+			log.Printf("Failed to seed point rule %s: %v", rule.name, err)
+			return err
+		}
+	}
+
+	log.Println("Point rules seeded ✅")
+	return nil
+}
+
+func strPtr(value string) *string {
+	return &value
+}
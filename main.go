@@ -2,11 +2,13 @@ package main
 
 import (
 	"log"
+	"time"
 	"project_uas/app/repository"
 	"project_uas/routes"
 	"project_uas/app/service"
 	"project_uas/config"
 	"project_uas/database"
+	"project_uas/middleware"
 	"project_uas/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -46,7 +48,12 @@ import (
 func main() {
 	// Load config
 	config.LoadEnv()
-	utils.InitJWT()
+	if err := config.AppConfig.Validate(); err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
+	if err := utils.InitJWT(); err != nil {
+		log.Fatal("Failed to initialize JWT keys:", err)
+	}
 
 	// Connect databases
 	database.ConnectDatabase()
@@ -63,14 +70,72 @@ func main() {
 	studentRepo := repository.NewStudentRepository(sqlDB)
 	lecturerRepo := repository.NewLecturerRepository(sqlDB)
 	achievementRepo := repository.NewAchievementRepository(sqlDB, database.MongoDB)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(sqlDB)
+	revocationRepo := repository.NewTokenRevocationRepository(sqlDB)
+	lockoutRepo := repository.NewLoginLockoutRepository(sqlDB)
+	auditRepo := repository.NewAuditLogRepository(sqlDB)
+	passwordResetRepo := repository.NewPasswordResetRepository(sqlDB)
+	mfaRepo := repository.NewMFARepository(sqlDB)
+	delegationRepo := repository.NewDelegationRepository(sqlDB)
+	pipelineRepo := repository.NewPipelineRepository(sqlDB)
+	commentRepo := repository.NewCommentRepository(sqlDB)
+	slaRepo := repository.NewSLARepository(sqlDB)
+	achievementTypeRepo := repository.NewAchievementTypeRepository(sqlDB)
+	pointRuleRepo := repository.NewPointRuleRepository(sqlDB)
+	duplicateRepo := repository.NewDuplicateRepository(sqlDB, database.MongoDB)
+	calendarRepo := repository.NewAcademicCalendarRepository(sqlDB)
+	tagRepo := repository.NewTagRepository(sqlDB, database.MongoDB)
+
+	// Token revocation cache (dicek di middleware AuthRequired)
+	revocationService := service.NewTokenRevocationService(revocationRepo, refreshTokenRepo)
+	if err := revocationService.Load(); err != nil {
+		log.Fatal("Failed to load token revocations:", err)
+	}
+	revocationService.StartSync(30 * time.Second)
+	middleware.SetRevocationChecker(revocationService)
+
+	// Mailer (file outbox untuk development)
+	mailer, err := utils.NewFileOutboxMailer(config.AppConfig.MailOutboxDir)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, roleRepo, permRepo)
-	userService := service.NewUserService(userRepo, roleRepo, permRepo, studentRepo, lecturerRepo)
-	studentService := service.NewStudentService(studentRepo, lecturerRepo, userRepo, achievementRepo)
-	lecturerService := service.NewLecturerService(lecturerRepo, studentRepo, userRepo)
-	achievementService := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo, userRepo)
-	reportService := service.NewReportService(achievementRepo, studentRepo, lecturerRepo, userRepo)
+	auditService := service.NewAuditService(auditRepo)
+	lockoutService := service.NewLockoutService(lockoutRepo, userRepo, auditService, service.DefaultLockoutPolicy)
+	mfaService := service.NewMFAService(mfaRepo, userRepo, roleRepo, auditService)
+	authService := service.NewAuthService(userRepo, roleRepo, permRepo, refreshTokenRepo, revocationService, lockoutService, mfaService)
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, revocationService, mailer, config.AppConfig.AppBaseURL, config.AppConfig.PasswordResetTTL)
+	authorizer := service.NewAuthorizer(studentRepo, lecturerRepo, delegationRepo, service.DefaultPolicy)
+	userService := service.NewUserService(userRepo, roleRepo, permRepo, studentRepo, lecturerRepo, revocationService, authorizer)
+	studentService := service.NewStudentService(studentRepo, lecturerRepo, userRepo, achievementRepo, authorizer)
+	lecturerService := service.NewLecturerService(lecturerRepo, studentRepo, userRepo, authorizer)
+	delegationService := service.NewDelegationService(delegationRepo, lecturerRepo, userRepo, authorizer, auditService)
+	tagPolicy := service.TagPolicy{CanonicalOnly: config.AppConfig.TagsCanonicalOnly}
+	achievementService := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo, userRepo, pipelineRepo, achievementTypeRepo, pointRuleRepo, duplicateRepo, calendarRepo, tagRepo, authorizer, tagPolicy)
+	pipelineService := service.NewPipelineService(pipelineRepo, authorizer, auditService)
+	achievementTypeService := service.NewAchievementTypeService(achievementTypeRepo, auditService)
+	pointRuleService := service.NewPointRuleService(pointRuleRepo, achievementTypeRepo, auditService)
+	academicCalendarService := service.NewAcademicCalendarService(calendarRepo, auditService)
+	tagService := service.NewTagService(tagRepo, auditService, tagPolicy)
+	commentService := service.NewCommentService(commentRepo, achievementRepo, userRepo, authorizer, service.DefaultCommentPolicy)
+	slaService := service.NewSLAService(slaRepo, lecturerRepo, pipelineRepo, authorizer, mailer, service.SLAPolicy{
+		ReminderAfter: config.AppConfig.VerificationSLA,
+		EscalateAfter: config.AppConfig.VerificationEscalateAfter,
+	})
+	jwksService := service.NewJWKSService()
+	roleService := service.NewRoleService(roleRepo, permRepo, userRepo, revocationService, auditService)
+	trashService := service.NewTrashService(achievementRepo, studentRepo, authorizer, service.TrashPolicy{
+		Retention:  config.AppConfig.TrashRetention,
+		UploadsDir: "./uploads",
+	})
+	reportService := service.NewReportService(achievementRepo, studentRepo, lecturerRepo, userRepo, duplicateRepo, calendarRepo, authorizer)
+
+	// Scheduler SLA verifikasi (reminder pemegang stage & eskalasi admin)
+	slaService.Start(config.AppConfig.VerificationSLAInterval)
+
+	// Purge trash achievement yang melewati masa retensi
+	trashService.Start(config.AppConfig.TrashPurgeInterval)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -103,11 +168,19 @@ func main() {
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
 	// Register API routes
-	routes.AuthRoutes(app, authService)
-	routes.UserRoutes(app, userService)
+	routes.WellKnownRoutes(app, jwksService)
+	routes.AuthRoutes(app, authService, passwordService, mfaService)
+	routes.UserRoutes(app, userService, lockoutService, mfaService)
+	routes.MFAPolicyRoutes(app, mfaService)
+	routes.RoleRoutes(app, roleService)
 	routes.StudentRoutes(app, studentService)
-	routes.LecturerRoutes(app, lecturerService)
-	routes.AchievementRoutes(app, achievementService)
+	routes.LecturerRoutes(app, lecturerService, delegationService, slaService)
+	routes.AchievementRoutes(app, achievementService, commentService, trashService)
+	routes.AchievementTypeRoutes(app, achievementTypeService)
+	routes.PipelineRoutes(app, pipelineService)
+	routes.PointRuleRoutes(app, pointRuleService)
+	routes.AcademicCalendarRoutes(app, academicCalendarService)
+	routes.TagRoutes(app, tagService)
 	routes.ReportRoutes(app, reportService)
 
 	// Start server
@@ -2,11 +2,24 @@ package middleware
 
 import (
 	"strings"
+	"project_uas/app/model"
 	"project_uas/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// TokenRevocationChecker - Cek revocation access token (jti / user) dari cache
+type TokenRevocationChecker interface {
+	IsRevoked(claims *model.JWTClaims) bool
+}
+
+var revocationChecker TokenRevocationChecker
+
+// SetRevocationChecker - Dipasang sekali dari main saat startup
+func SetRevocationChecker(checker TokenRevocationChecker) {
+	revocationChecker = checker
+}
+
 func AuthRequired(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
@@ -20,6 +33,11 @@ func AuthRequired(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"error": "invalid token"})
 	}
 
+	// Token sudah direvoke (logout, user dinonaktifkan/dihapus, role berubah)
+	if revocationChecker != nil && revocationChecker.IsRevoked(claims) {
+		return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+	}
+
 	// ⭐ SET USER CLAIMS
 	c.Locals("user", claims)
 	
@@ -7,40 +7,106 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+//
+// ==================== WELL-KNOWN ROUTES (PUBLIC) ======================
+//
+
+func WellKnownRoutes(app *fiber.App, jwksService *service.JWKSService) {
+	app.Get("/.well-known/jwks.json", jwksService.GetJWKS)
+}
+
 //
 // ==================== AUTH ROUTES ======================
 //
 
-func AuthRoutes(app *fiber.App, authService *service.AuthService) {
+func AuthRoutes(app *fiber.App, authService *service.AuthService, passwordService *service.PasswordService, mfaService *service.MFAService) {
 	auth := app.Group("/api/v1/auth")
 
 	auth.Post("/login", authService.Login)
 	auth.Post("/refresh", authService.Refresh)
+	auth.Post("/password/forgot", passwordService.ForgotPassword)
+	auth.Post("/password/reset", passwordService.ResetPassword)
+
+	// Login step 2 (pakai token mfa_pending dari /login)
+	auth.Post("/mfa/verify", authService.VerifyMFA)
+	auth.Post("/mfa/enroll", mfaService.Enroll)
 
 	// Protected routes
 	protected := auth.Group("/", middleware.AuthRequired)
 	protected.Get("/profile", authService.Profile)
 	protected.Post("/logout", authService.Logout)
+	protected.Post("/password/change", passwordService.ChangePassword)
+
+	// MFA milik user sendiri
+	protected.Get("/mfa", mfaService.GetStatus)
+	protected.Post("/mfa/setup", mfaService.Setup)
+	protected.Post("/mfa/confirm", mfaService.Confirm)
+	protected.Post("/mfa/disable", mfaService.Disable)
+	protected.Post("/mfa/recovery-codes", mfaService.RegenerateRecoveryCodes)
 }
 
 //
 // ==================== USER ROUTES (ADMIN ONLY) ======================
 //
 
-func UserRoutes(app *fiber.App, userService *service.UserService) {
+func UserRoutes(app *fiber.App, userService *service.UserService, lockoutService *service.LockoutService, mfaService *service.MFAService) {
 	users := app.Group("/api/v1/users")
 
 	// Semua endpoint user butuh auth + permission "user:manage"
 	users.Use(middleware.AuthRequired)
 	users.Use(middleware.RequirePermission("user:manage"))
 
-	users.Get("/", userService.GetUsers)          // GET /api/v1/users
-	users.Get("/:id", userService.GetUserByID)    // GET /api/v1/users/:id
-	users.Post("/", userService.CreateUser)       // POST /api/v1/users
-	users.Put("/:id", userService.UpdateUser)     // PUT /api/v1/users/:id
-	users.Delete("/:id", userService.DeleteUser)  // DELETE /api/v1/users/:id
-	users.Put("/:id/role", userService.AssignRole) // PUT /api/v1/users/:id/role
+	users.Get("/", userService.GetUsers)                       // GET /api/v1/users
+	users.Get("/:id", userService.GetUserByID)                 // GET /api/v1/users/:id
+	users.Post("/", userService.CreateUser)                    // POST /api/v1/users
+	users.Put("/:id", userService.UpdateUser)                  // PUT /api/v1/users/:id
+	users.Delete("/:id", userService.DeleteUser)               // DELETE /api/v1/users/:id
+	users.Post("/:id/roles", userService.AddRole)              // POST /api/v1/users/:id/roles
+	users.Delete("/:id/roles/:roleId", userService.RemoveRole) // DELETE /api/v1/users/:id/roles/:roleId
+
+	users.Get("/:id/lockout", lockoutService.GetUserLockout)      // GET /api/v1/users/:id/lockout
+	users.Delete("/:id/lockout", lockoutService.ClearUserLockout) // DELETE /api/v1/users/:id/lockout
+	users.Delete("/:id/mfa", mfaService.ResetUserMFA)             // DELETE /api/v1/users/:id/mfa
+}
+
+//
+// ==================== MFA POLICY ROUTES (ADMIN ONLY) ======================
+//
+
+func MFAPolicyRoutes(app *fiber.App, mfaService *service.MFAService) {
+	policies := app.Group("/api/v1/mfa/policies")
+
+	policies.Use(middleware.AuthRequired)
+	policies.Use(middleware.RequirePermission("user:manage"))
+
+	policies.Get("/", mfaService.GetPolicies)          // GET /api/v1/mfa/policies
+	policies.Put("/:roleId", mfaService.UpdatePolicy) // PUT /api/v1/mfa/policies/:roleId
+}
+//
+// ==================== ROLE & PERMISSION ROUTES (ADMIN ONLY) ======================
+//
+
+func RoleRoutes(app *fiber.App, roleService *service.RoleService) {
+	roles := app.Group("/api/v1/roles")
+	roles.Use(middleware.AuthRequired)
+	roles.Use(middleware.RequirePermission("role:manage"))
+
+	roles.Get("/", roleService.GetRoles)                                         // GET /api/v1/roles
+	roles.Get("/:id", roleService.GetRoleByID)                                   // GET /api/v1/roles/:id
+	roles.Post("/", roleService.CreateRole)                                      // POST /api/v1/roles
+	roles.Put("/:id", roleService.UpdateRole)                                    // PUT /api/v1/roles/:id
+	roles.Delete("/:id", roleService.DeleteRole)                                 // DELETE /api/v1/roles/:id
+	roles.Get("/:id/members", roleService.GetRoleMembers)                        // GET /api/v1/roles/:id/members
+	roles.Post("/:id/permissions", roleService.GrantPermissions)                 // POST /api/v1/roles/:id/permissions
+	roles.Delete("/:id/permissions/:permissionId", roleService.RevokePermission) // DELETE /api/v1/roles/:id/permissions/:permissionId
+
+	permissions := app.Group("/api/v1/permissions")
+	permissions.Use(middleware.AuthRequired)
+	permissions.Use(middleware.RequirePermission("role:manage"))
+
+	permissions.Get("/", roleService.GetPermissions) // GET /api/v1/permissions
 }
+
 //
 // ==================== STUDENT ROUTES ======================
 //
@@ -76,7 +142,7 @@ func StudentRoutes(app *fiber.App, studentService *service.StudentService) {
 // ==================== LECTURER ROUTES ======================
 //
 
-func LecturerRoutes(app *fiber.App, lecturerService *service.LecturerService) {
+func LecturerRoutes(app *fiber.App, lecturerService *service.LecturerService, delegationService *service.DelegationService, slaService *service.SLAService) {
 	lecturers := app.Group("/api/v1/lecturers")
 	lecturers.Use(middleware.AuthRequired)
 
@@ -89,12 +155,138 @@ func LecturerRoutes(app *fiber.App, lecturerService *service.LecturerService) {
 	lecturers.Get("/:id/advisees",
 		lecturerService.GetLecturerAdvisees,
 	)
+
+	// Delegasi verifikasi (Dosen Wali: milik sendiri, Admin: atas nama dosen wali)
+	lecturers.Get("/:id/delegations",
+		delegationService.GetDelegations,
+	)
+	lecturers.Post("/:id/delegations",
+		delegationService.CreateDelegation,
+	)
+	lecturers.Delete("/:id/delegations/:delegationId",
+		delegationService.RevokeDelegation,
+	)
+
+	// GET /lecturers/:id/overdue - Achievement yang menunggu keputusan dosen & melewati SLA verifikasi
+	lecturers.Get("/:id/overdue",
+		slaService.GetOverdue,
+	)
+}
+
+//
+// ==================== VERIFICATION PIPELINE ROUTES (ADMIN ONLY) ======================
+//
+
+func PipelineRoutes(app *fiber.App, pipelineService *service.PipelineService) {
+	pipelines := app.Group("/api/v1/pipelines")
+	pipelines.Use(middleware.AuthRequired)
+	pipelines.Use(middleware.RequirePermission("role:manage"))
+
+	pipelines.Get("/", pipelineService.GetPipelines)         // GET /api/v1/pipelines
+	pipelines.Post("/", pipelineService.CreatePipeline)      // POST /api/v1/pipelines
+	pipelines.Put("/:id", pipelineService.UpdatePipeline)    // PUT /api/v1/pipelines/:id
+	pipelines.Delete("/:id", pipelineService.DeletePipeline) // DELETE /api/v1/pipelines/:id
+}
+
+//
+// ==================== POINT RULE ROUTES (ADMIN ONLY) ======================
+//
+
+func PointRuleRoutes(app *fiber.App, pointRuleService *service.PointRuleService) {
+	rules := app.Group("/api/v1/point-rules")
+	rules.Use(middleware.AuthRequired)
+	rules.Use(middleware.RequirePermission("role:manage"))
+
+	rules.Get("/", pointRuleService.GetPointRules)         // GET /api/v1/point-rules
+	rules.Post("/", pointRuleService.CreatePointRule)      // POST /api/v1/point-rules
+	rules.Put("/:id", pointRuleService.UpdatePointRule)    // PUT /api/v1/point-rules/:id
+	rules.Delete("/:id", pointRuleService.DeletePointRule) // DELETE /api/v1/point-rules/:id
+}
+
+//
+// ==================== ACADEMIC CALENDAR ROUTES ======================
+// Baca: semua user login (filter semester). Ubah: admin.
+//
+
+func AcademicCalendarRoutes(app *fiber.App, academicCalendarService *service.AcademicCalendarService) {
+	calendar := app.Group("/api/v1/academic-calendar")
+	calendar.Use(middleware.AuthRequired)
+
+	calendar.Get("/", academicCalendarService.GetSemesters) // GET /api/v1/academic-calendar?date=YYYY-MM-DD
+
+	calendar.Post("/",
+		middleware.RequirePermission("role:manage"),
+		academicCalendarService.CreateSemester,
+	)
+	calendar.Put("/:id",
+		middleware.RequirePermission("role:manage"),
+		academicCalendarService.UpdateSemester,
+	)
+	calendar.Delete("/:id",
+		middleware.RequirePermission("role:manage"),
+		academicCalendarService.DeleteSemester,
+	)
+}
+
+//
+// ==================== TAG ROUTES ======================
+// Autocomplete: semua user login. Kelola kosakata & merge: admin.
+//
+
+func TagRoutes(app *fiber.App, tagService *service.TagService) {
+	tags := app.Group("/api/v1/tags")
+	tags.Use(middleware.AuthRequired)
+
+	tags.Get("/", tagService.GetTags) // GET /api/v1/tags?q=ai&limit=10
+
+	tags.Get("/unmanaged",
+		middleware.RequirePermission("role:manage"),
+		tagService.GetUnmanagedTags,
+	)
+	tags.Post("/",
+		middleware.RequirePermission("role:manage"),
+		tagService.CreateTag,
+	)
+	tags.Put("/:id",
+		middleware.RequirePermission("role:manage"),
+		tagService.UpdateTag,
+	)
+	tags.Delete("/:id",
+		middleware.RequirePermission("role:manage"),
+		tagService.DeleteTag,
+	)
+	tags.Post("/:id/merge",
+		middleware.RequirePermission("role:manage"),
+		tagService.MergeTags,
+	)
+}
+
+//
+// ==================== ACHIEVEMENT TYPE ROUTES ======================
+// Baca: semua user login (render form details). Ubah: admin.
+//
+
+func AchievementTypeRoutes(app *fiber.App, achievementTypeService *service.AchievementTypeService) {
+	types := app.Group("/api/v1/achievement-types")
+	types.Use(middleware.AuthRequired)
+
+	types.Get("/", achievementTypeService.GetAchievementTypes)     // GET /api/v1/achievement-types
+	types.Get("/:code", achievementTypeService.GetAchievementType) // GET /api/v1/achievement-types/:code
+
+	types.Post("/",
+		middleware.RequirePermission("role:manage"),
+		achievementTypeService.CreateAchievementType,
+	)
+	types.Put("/:code",
+		middleware.RequirePermission("role:manage"),
+		achievementTypeService.UpdateAchievementType,
+	)
 }
 //
 // ==================== ACHIEVEMENT ROUTES ======================
 //
 
-func AchievementRoutes(app *fiber.App, achievementService *service.AchievementService) {
+func AchievementRoutes(app *fiber.App, achievementService *service.AchievementService, commentService *service.CommentService, trashService *service.TrashService) {
 	achievements := app.Group("/api/v1/achievements")
 
 	// Auth required untuk semua endpoint
@@ -109,6 +301,23 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.GetAchievements,
 	)
 
+	// POST /achievements/bulk/verify, /bulk/reject - Verify / reject banyak achievement
+	// (hasil per item; didaftarkan sebelum /:id/...)
+	achievements.Post("/bulk/verify",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.BulkVerifyAchievements,
+	)
+	achievements.Post("/bulk/reject",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.BulkRejectAchievements,
+	)
+
+	// GET /achievements/trash - Achievement milik sendiri di trash (didaftarkan sebelum /:id)
+	achievements.Get("/trash",
+		middleware.RequirePermission("achievement:delete"),
+		trashService.GetTrash,
+	)
+
 	// GET /achievements/:id - Detail achievement
 	achievements.Get("/:id",
 		middleware.RequirePermission("achievement:read"),
@@ -127,12 +336,18 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.UpdateAchievement,
 	)
 
-	// DELETE /achievements/:id - Delete achievement (Mahasiswa only, status = draft)
+	// DELETE /achievements/:id - Pindahkan achievement ke trash (Mahasiswa only, status = draft)
 	achievements.Delete("/:id",
 		middleware.RequirePermission("achievement:delete"),
 		achievementService.DeleteAchievement,
 	)
 
+	// POST /achievements/:id/restore - Kembalikan achievement dari trash sebagai draft
+	achievements.Post("/:id/restore",
+		middleware.RequirePermission("achievement:delete"),
+		trashService.RestoreAchievement,
+	)
+
 	// POST /achievements/:id/submit - Submit for verification (Mahasiswa only)
 	achievements.Post("/:id/submit",
 		middleware.RequirePermission("achievement:update"),
@@ -151,6 +366,48 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.RejectAchievement,
 	)
 
+	// POST /achievements/:id/request-revision - Minta revisi (Dosen Wali only)
+	achievements.Post("/:id/request-revision",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.RequestRevision,
+	)
+
+	// GET /achievements/:id/team - Anggota prestasi tim & status verifikasi masing-masing
+	achievements.Get("/:id/team",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.GetAchievementTeam,
+	)
+
+	// GET /achievements/:id/duplicates - Achievement lain yang mungkin duplikat (verifikator)
+	achievements.Get("/:id/duplicates",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.GetDuplicates,
+	)
+
+	// GET /achievements/:id/points - Poin, rule & riwayat penyesuaian
+	achievements.Get("/:id/points",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.GetPoints,
+	)
+
+	// PUT /achievements/:id/points - Sesuaikan poin dengan justifikasi (verifikator stage aktif)
+	achievements.Put("/:id/points",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.AdjustPoints,
+	)
+
+	// POST /achievements/:id/withdraw - Tarik kembali ke draft (Mahasiswa only, status = submitted)
+	achievements.Post("/:id/withdraw",
+		middleware.RequirePermission("achievement:update"),
+		achievementService.WithdrawAchievement,
+	)
+
+	// POST /achievements/:id/reopen - Buka kembali ke draft (Mahasiswa only, status = rejected / revision_requested)
+	achievements.Post("/:id/reopen",
+		middleware.RequirePermission("achievement:update"),
+		achievementService.ReopenAchievement,
+	)
+
 	// POST /achievements/:id/attachments - Upload attachment (Mahasiswa only)
 	achievements.Post("/:id/attachments",
 		middleware.RequirePermission("achievement:update"),
@@ -162,6 +419,37 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
         middleware.RequirePermission("achievement:read"),
         achievementService.GetAchievementHistory,
     )
+
+	// GET /achievements/:id/versions - Snapshot isi achievement per penyimpanan
+	achievements.Get("/:id/versions",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.GetAchievementVersions,
+	)
+
+	// GET /achievements/:id/versions/diff?from=1&to=2 - Perbedaan dua versi
+	achievements.Get("/:id/versions/diff",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.DiffAchievementVersions,
+	)
+
+	// Diskusi per achievement (mahasiswa pemilik, dosen wali, verifikator)
+	// Edit / hapus hanya oleh penulis dalam batas waktu CommentPolicy
+	achievements.Get("/:id/comments",
+		middleware.RequirePermission("achievement:read"),
+		commentService.GetComments,
+	)
+	achievements.Post("/:id/comments",
+		middleware.RequirePermission("achievement:read"),
+		commentService.CreateComment,
+	)
+	achievements.Put("/:id/comments/:commentId",
+		middleware.RequirePermission("achievement:read"),
+		commentService.UpdateComment,
+	)
+	achievements.Delete("/:id/comments/:commentId",
+		middleware.RequirePermission("achievement:read"),
+		commentService.DeleteComment,
+	)
 }
 
 // ==================== FILE 2: routes.go (UPDATE - Add ReportRoutes) ======================
@@ -181,4 +469,11 @@ func ReportRoutes(app *fiber.App, reportService *service.ReportService) {
 	reports.Get("/student/:id",
 		reportService.GetStudentReport,
 	)
+
+	// GET /reports/duplicates - Kelompok achievement yang mungkin duplikat
+	// Authorization: Admin only
+	reports.Get("/duplicates",
+		middleware.RequirePermission("user:manage"),
+		reportService.GetDuplicateClusters,
+	)
 }
\ No newline at end of file
@@ -2,6 +2,9 @@ package mocks
 
 import (
 	"project_uas/app/model"
+	"project_uas/app/repository"
+	"time"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -11,16 +14,34 @@ type MockAchievementRepository struct {
 	mock.Mock
 }
 
-func (m *MockAchievementRepository) CreateReference(ref *model.AchievementReference) error {
-	args := m.Called(ref)
+func (m *MockAchievementRepository) CreateReference(ref *model.AchievementReference, history *model.AchievementStatusHistory) error {
+	args := m.Called(ref, history)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) CreateReferences(refs []*model.AchievementReference, history *model.AchievementStatusHistory) error {
+	args := m.Called(refs, history)
 	return args.Error(0)
 }
 
-func (m *MockAchievementRepository) UpdateReference(ref *model.AchievementReference) error {
-	args := m.Called(ref)
+func (m *MockAchievementRepository) UpdateReference(ref *model.AchievementReference, from model.ReferenceState, history *model.AchievementStatusHistory) error {
+	args := m.Called(ref, from, history)
 	return args.Error(0)
 }
 
+func (m *MockAchievementRepository) UpdateReferences(refs []*model.AchievementReference, status string, history *model.AchievementStatusHistory) error {
+	args := m.Called(refs, status, history)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) GetStatusHistory(referenceID string) ([]model.AchievementStatusHistory, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementStatusHistory), args.Error(1)
+}
+
 func (m *MockAchievementRepository) GetReferenceByID(id string) (*model.AchievementReference, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -37,6 +58,14 @@ func (m *MockAchievementRepository) GetReferenceByMongoID(mongoID string) (*mode
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
 }
 
+func (m *MockAchievementRepository) GetReferencesByMongoID(mongoID string) ([]model.AchievementReference, error) {
+	args := m.Called(mongoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
 func (m *MockAchievementRepository) GetReferencesByStudentID(studentID string, status string, limit, offset int) ([]model.AchievementReference, error) {
 	args := m.Called(studentID, status, limit, offset)
 	if args.Get(0) == nil {
@@ -50,16 +79,16 @@ func (m *MockAchievementRepository) CountReferencesByStudentID(studentID string,
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockAchievementRepository) GetReferencesByAdvisorID(advisorID string, status string, limit, offset int) ([]model.AchievementReference, error) {
-	args := m.Called(advisorID, status, limit, offset)
+func (m *MockAchievementRepository) GetReferencesInScope(scope repository.ReferenceScope, status string, limit, offset int) ([]model.AchievementReference, error) {
+	args := m.Called(scope, status, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]model.AchievementReference), args.Error(1)
 }
 
-func (m *MockAchievementRepository) CountReferencesByAdvisorID(advisorID string, status string) (int, error) {
-	args := m.Called(advisorID, status)
+func (m *MockAchievementRepository) CountReferencesInScope(scope repository.ReferenceScope, status string) (int, error) {
+	args := m.Called(scope, status)
 	return args.Int(0), args.Error(1)
 }
 
@@ -76,13 +105,42 @@ func (m *MockAchievementRepository) CountAllReferences(status string) (int, erro
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockAchievementRepository) CreateAchievement(achievement *model.Achievement) (string, error) {
-	args := m.Called(achievement)
+func (m *MockAchievementRepository) GetDeletedReferences(studentID string) ([]model.AchievementReference, error) {
+	args := m.Called(studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetDeletedReferencesByMongoID(mongoID string) ([]model.AchievementReference, error) {
+	args := m.Called(mongoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetReferencesDeletedBefore(cutoff time.Time) ([]model.AchievementReference, error) {
+	args := m.Called(cutoff)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) PurgeReferences(mongoID string, referenceIDs []string) error {
+	args := m.Called(mongoID, referenceIDs)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) CreateAchievement(achievement *model.Achievement, version *model.AchievementVersion) (string, error) {
+	args := m.Called(achievement, version)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAchievementRepository) UpdateAchievement(id string, achievement *model.Achievement) error {
-	args := m.Called(id, achievement)
+func (m *MockAchievementRepository) UpdateAchievement(id string, achievement *model.Achievement, version *model.AchievementVersion) error {
+	args := m.Called(id, achievement, version)
 	return args.Error(0)
 }
 
@@ -94,16 +152,50 @@ func (m *MockAchievementRepository) GetAchievementByID(id string) (*model.Achiev
 	return args.Get(0).(*model.Achievement), args.Error(1)
 }
 
+func (m *MockAchievementRepository) GetAchievementIDsByEventDate(from, to *time.Time) (map[string]bool, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockAchievementRepository) SoftDeleteAchievement(id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) RestoreAchievement(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockAchievementRepository) DeleteAchievement(id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockAchievementRepository) AddAttachment(achievementID string, attachment model.Attachment) error {
-	args := m.Called(achievementID, attachment)
+func (m *MockAchievementRepository) AddAttachment(achievementID string, attachment model.Attachment, version *model.AchievementVersion) error {
+	args := m.Called(achievementID, attachment, version)
 	return args.Error(0)
 }
 
+func (m *MockAchievementRepository) GetVersions(achievementID string) ([]model.AchievementVersion, error) {
+	args := m.Called(achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementVersion), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetVersion(achievementID string, version int) (*model.AchievementVersion, error) {
+	args := m.Called(achievementID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementVersion), args.Error(1)
+}
+
 // ==================== MOCK STUDENT REPOSITORY ====================
 
 type MockStudentRepository struct {
@@ -149,6 +241,11 @@ func (m *MockStudentRepository) Delete(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockStudentRepository) SetRetired(id string, retired bool) error {
+	args := m.Called(id, retired)
+	return args.Error(0)
+}
+
 func (m *MockStudentRepository) SetAdvisor(studentID string, advisorID string) error {
 	args := m.Called(studentID, advisorID)
 	return args.Error(0)
@@ -167,6 +264,19 @@ func (m *MockStudentRepository) CountAll() (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockStudentRepository) GetAllByProgramStudies(programStudies []string, limit, offset int) ([]model.Student, error) {
+	args := m.Called(programStudies, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Student), args.Error(1)
+}
+
+func (m *MockStudentRepository) CountAllByProgramStudies(programStudies []string) (int, error) {
+	args := m.Called(programStudies)
+	return args.Int(0), args.Error(1)
+}
+
 // ==================== MOCK LECTURER REPOSITORY ====================
 
 type MockLecturerRepository struct {
@@ -212,6 +322,11 @@ func (m *MockLecturerRepository) Delete(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockLecturerRepository) SetRetired(id string, retired bool) error {
+	args := m.Called(id, retired)
+	return args.Error(0)
+}
+
 func (m *MockLecturerRepository) GetAll(limit, offset int) ([]model.Lecturer, error) {
 	args := m.Called(limit, offset)
 	if args.Get(0) == nil {
@@ -225,6 +340,19 @@ func (m *MockLecturerRepository) CountAll() (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockLecturerRepository) GetAllByDepartments(departments []string, limit, offset int) ([]model.Lecturer, error) {
+	args := m.Called(departments, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Lecturer), args.Error(1)
+}
+
+func (m *MockLecturerRepository) CountAllByDepartments(departments []string) (int, error) {
+	args := m.Called(departments)
+	return args.Int(0), args.Error(1)
+}
+
 // ==================== MOCK USER REPOSITORY ====================
 
 type MockUserRepository struct {
@@ -283,9 +411,35 @@ func (m *MockUserRepository) CountAll(roleName string) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockUserRepository) GetRoleName(roleID string) (string, error) {
-	args := m.Called(roleID)
-	return args.String(0), args.Error(1)
+func (m *MockUserRepository) GetAllByProgramStudies(programStudies []string, limit, offset int, roleName string) ([]model.User, error) {
+	args := m.Called(programStudies, limit, offset, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountAllByProgramStudies(programStudies []string, roleName string) (int, error) {
+	args := m.Called(programStudies, roleName)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetRoles(userID string) ([]model.Role, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+
+func (m *MockUserRepository) AddRole(userID string, roleID string, programStudy string) (bool, error) {
+	args := m.Called(userID, roleID, programStudy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) RemoveRole(userID string, roleID string) (bool, error) {
+	args := m.Called(userID, roleID)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockUserRepository) GetPermissions(role string) ([]string, error) {
@@ -296,8 +450,8 @@ func (m *MockUserRepository) GetPermissions(role string) ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateRole(userID string, roleID string) error {
-	args := m.Called(userID, roleID)
+func (m *MockUserRepository) UpdatePassword(userID string, passwordHash string) error {
+	args := m.Called(userID, passwordHash)
 	return args.Error(0)
 }
 
@@ -323,6 +477,47 @@ func (m *MockRoleRepository) GetRoleByName(name string) (*model.Role, error) {
 	return args.Get(0).(*model.Role), args.Error(1)
 }
 
+func (m *MockRoleRepository) GetAll() ([]model.Role, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) SetMFARequired(id string, required bool) error {
+	args := m.Called(id, required)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Create(role *model.Role) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Update(role *model.Role) error {
+	args := m.Called(role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CountMembers(id string) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetMemberIDs(id string) ([]string, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // ==================== MOCK PERMISSION REPOSITORY ====================
 
 type MockPermissionRepository struct {
@@ -336,3 +531,634 @@ func (m *MockPermissionRepository) GetPermissionsByRoleID(roleID string) ([]stri
 	}
 	return args.Get(0).([]string), args.Error(1)
 }
+
+func (m *MockPermissionRepository) GetAll() ([]model.Permission, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindByName(name string) (*model.Permission, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindByID(id string) (*model.Permission, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) Grant(roleID, permissionID string) (bool, error) {
+	args := m.Called(roleID, permissionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPermissionRepository) Revoke(roleID, permissionID string) (bool, error) {
+	args := m.Called(roleID, permissionID)
+	return args.Bool(0), args.Error(1)
+}
+
+// ==================== MOCK REFRESH TOKEN REPOSITORY ====================
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(token *model.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByID(id string) (*model.RefreshToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(id string, replacedBy string) (bool, error) {
+	args := m.Called(id, replacedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUserID(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// ==================== MOCK TOKEN REVOCATION REPOSITORY ====================
+
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) Create(revocation *model.TokenRevocation) error {
+	args := m.Called(revocation)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) GetActive() ([]model.TokenRevocation, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TokenRevocation), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpired() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// ==================== MOCK LOGIN LOCKOUT REPOSITORY ====================
+
+type MockLoginLockoutRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginLockoutRepository) Get(keyType, keyValue string) (*model.LoginLockout, error) {
+	args := m.Called(keyType, keyValue)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginLockout), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) RecordFailure(keyType, keyValue string, now, windowStart, decayStart time.Time) (int, int, error) {
+	args := m.Called(keyType, keyValue, now, windowStart, decayStart)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockLoginLockoutRepository) Lock(keyType, keyValue string, lockCount int, lockedUntil time.Time) (bool, error) {
+	args := m.Called(keyType, keyValue, lockCount, lockedUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLoginLockoutRepository) ResetFailures(keyType, keyValue string) error {
+	args := m.Called(keyType, keyValue)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) RecordFailedIP(username, ip string) error {
+	args := m.Called(username, ip)
+	return args.Error(0)
+}
+
+func (m *MockLoginLockoutRepository) ClearUsername(username string) ([]string, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// ==================== MOCK AUDIT LOG REPOSITORY ====================
+
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Create(entry *model.AuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) GetByTarget(targetType, targetID string, limit int) ([]model.AuditLog, error) {
+	args := m.Called(targetType, targetID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditLog), args.Error(1)
+}
+
+// ==================== MOCK PASSWORD RESET REPOSITORY ====================
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(token *model.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) FindByHash(tokenHash string) (*model.PasswordResetToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateByUserID(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// ==================== MOCK MFA REPOSITORY ====================
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) FindByUserID(userID string) (*model.UserMFA, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserMFA), args.Error(1)
+}
+
+func (m *MockMFARepository) SaveSecret(userID, secret string) error {
+	args := m.Called(userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) Enable(userID string, step int64) error {
+	args := m.Called(userID, step)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) MarkStepUsed(userID string, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) Delete(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	args := m.Called(userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) CountRecoveryCodes(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+// ==================== MOCK DELEGATION REPOSITORY ====================
+
+type MockDelegationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDelegationRepository) Create(delegation *model.VerificationDelegation) error {
+	args := m.Called(delegation)
+	return args.Error(0)
+}
+
+func (m *MockDelegationRepository) FindByID(id string) (*model.VerificationDelegation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetByLecturerID(lecturerID string) ([]model.VerificationDelegation, error) {
+	args := m.Called(lecturerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) FindActive(lecturerID, delegateID string, at time.Time) (*model.VerificationDelegation, error) {
+	args := m.Called(lecturerID, delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetActiveDelegatorIDs(delegateID string, at time.Time) ([]string, error) {
+	args := m.Called(delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDelegationRepository) Revoke(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+// ==================== MOCK PIPELINE REPOSITORY ====================
+
+type MockPipelineRepository struct {
+	mock.Mock
+}
+
+func (m *MockPipelineRepository) GetAll() ([]model.VerificationPipeline, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VerificationPipeline), args.Error(1)
+}
+
+func (m *MockPipelineRepository) FindByID(id string) (*model.VerificationPipeline, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationPipeline), args.Error(1)
+}
+
+func (m *MockPipelineRepository) FindForAchievement(achievementType, competitionLevel string) (*model.VerificationPipeline, error) {
+	args := m.Called(achievementType, competitionLevel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationPipeline), args.Error(1)
+}
+
+func (m *MockPipelineRepository) Create(pipeline *model.VerificationPipeline) error {
+	args := m.Called(pipeline)
+	return args.Error(0)
+}
+
+func (m *MockPipelineRepository) Update(pipeline *model.VerificationPipeline) error {
+	args := m.Called(pipeline)
+	return args.Error(0)
+}
+
+func (m *MockPipelineRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPipelineRepository) CountInFlight(id string) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+// ==================== MOCK COMMENT REPOSITORY ====================
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(comment *model.AchievementComment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) FindByID(id string) (*model.AchievementComment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementComment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByReferenceID(referenceID string) ([]model.AchievementComment, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementComment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateBody(id, body string) error {
+	args := m.Called(id, body)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) SoftDelete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// ==================== MOCK SLA REPOSITORY ====================
+
+type MockSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockSLARepository) GetOverdue(submittedBefore time.Time, filter repository.OverdueFilter) ([]model.OverdueAchievement, error) {
+	args := m.Called(submittedBefore, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OverdueAchievement), args.Error(1)
+}
+
+func (m *MockSLARepository) GetRoleHolders(role, programStudy string) ([]model.User, error) {
+	args := m.Called(role, programStudy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+func (m *MockSLARepository) GetActiveDelegates(lecturerID string, at time.Time) ([]model.User, error) {
+	args := m.Called(lecturerID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+func (m *MockSLARepository) HasNotified(referenceID, level string, submittedAt time.Time) (bool, error) {
+	args := m.Called(referenceID, level, submittedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSLARepository) RecordNotification(notification *model.SLANotification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+// ==================== MOCK ACHIEVEMENT TYPE REPOSITORY ====================
+
+type MockAchievementTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAchievementTypeRepository) GetAll() ([]model.AchievementType, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementType), args.Error(1)
+}
+
+func (m *MockAchievementTypeRepository) FindByCode(code string) (*model.AchievementType, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementType), args.Error(1)
+}
+
+func (m *MockAchievementTypeRepository) Create(achievementType *model.AchievementType) error {
+	args := m.Called(achievementType)
+	return args.Error(0)
+}
+
+func (m *MockAchievementTypeRepository) Update(achievementType *model.AchievementType) error {
+	args := m.Called(achievementType)
+	return args.Error(0)
+}
+
+// ==================== MOCK POINT RULE REPOSITORY ====================
+
+type MockPointRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockPointRuleRepository) GetAll() ([]model.PointRule, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointRule), args.Error(1)
+}
+
+func (m *MockPointRuleRepository) GetByType(achievementType string) ([]model.PointRule, error) {
+	args := m.Called(achievementType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointRule), args.Error(1)
+}
+
+func (m *MockPointRuleRepository) FindByID(id string) (*model.PointRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PointRule), args.Error(1)
+}
+
+func (m *MockPointRuleRepository) Create(rule *model.PointRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockPointRuleRepository) Update(rule *model.PointRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockPointRuleRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPointRuleRepository) AdjustPoints(ref *model.AchievementReference, adjustment *model.PointAdjustment) error {
+	args := m.Called(ref, adjustment)
+	return args.Error(0)
+}
+
+func (m *MockPointRuleRepository) GetAdjustments(referenceID string) ([]model.PointAdjustment, error) {
+	args := m.Called(referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointAdjustment), args.Error(1)
+}
+
+// ==================== MOCK DUPLICATE REPOSITORY ====================
+
+type MockDuplicateRepository struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateRepository) FindCandidates(achievement *model.Achievement, limit int) ([]model.Achievement, error) {
+	args := m.Called(achievement, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockDuplicateRepository) ReplaceFlags(mongoID string, flags []model.DuplicateFlag) error {
+	args := m.Called(mongoID, flags)
+	return args.Error(0)
+}
+
+func (m *MockDuplicateRepository) GetFlags(mongoID string) ([]model.DuplicateFlag, error) {
+	args := m.Called(mongoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DuplicateFlag), args.Error(1)
+}
+
+func (m *MockDuplicateRepository) FlaggedIDs(mongoIDs []string) (map[string]bool, error) {
+	args := m.Called(mongoIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockDuplicateRepository) GetActiveFlags() ([]model.DuplicateFlag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DuplicateFlag), args.Error(1)
+}
+
+// ==================== MOCK ACADEMIC CALENDAR REPOSITORY ====================
+
+type MockAcademicCalendarRepository struct {
+	mock.Mock
+}
+
+func (m *MockAcademicCalendarRepository) GetAll() ([]model.AcademicSemester, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AcademicSemester), args.Error(1)
+}
+
+func (m *MockAcademicCalendarRepository) FindByID(id string) (*model.AcademicSemester, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AcademicSemester), args.Error(1)
+}
+
+func (m *MockAcademicCalendarRepository) Create(semester *model.AcademicSemester) error {
+	args := m.Called(semester)
+	return args.Error(0)
+}
+
+func (m *MockAcademicCalendarRepository) Update(semester *model.AcademicSemester) error {
+	args := m.Called(semester)
+	return args.Error(0)
+}
+
+func (m *MockAcademicCalendarRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// ==================== MOCK TAG REPOSITORY ====================
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) GetAll() ([]model.Tag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) FindByID(id string) (*model.Tag, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Create(tag *model.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Update(tag *model.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Merge(target *model.Tag, sourceIDs []string) error {
+	args := m.Called(target, sourceIDs)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) CountUsage() (map[string]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockTagRepository) RewriteAchievementTags(variants []string, canonical string) (int, error) {
+	args := m.Called(variants, canonical)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+//
+// ==================== JSON SCHEMA (SUBSET) ======================
+// Validator JSON Schema sederhana untuk achievement details. Keyword yang
+// didukung: type, properties, required, additionalProperties, items, enum,
+// minimum, maximum, minLength, maxLength, minItems, maxItems, pattern, format
+// (date, date-time, email, uri). Schema dengan keyword lain ditolak saat
+// parse supaya admin tidak mengira aturan tsb ikut dicek.
+//
+
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// SchemaError - Satu pelanggaran schema pada field (path mis. "details.members[0].name")
+type SchemaError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var schemaTypes = []string{"object", "array", "string", "integer", "number", "boolean"}
+
+var schemaFormats = []string{"date", "date-time", "email", "uri"}
+
+// ParseJSONSchema - Decode dan cek schema (keyword, type, pattern)
+func ParseJSONSchema(raw []byte) (*JSONSchema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	schema := &JSONSchema{}
+	if err := decoder.Decode(schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("invalid schema: root type must be 'object'")
+	}
+	if err := schema.compile("$"); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *JSONSchema) compile(path string) error {
+	if s.Type != "" && !containsString(schemaTypes, s.Type) {
+		return fmt.Errorf("invalid schema: %s: unsupported type '%s'", path, s.Type)
+	}
+	if s.Format != "" && !containsString(schemaFormats, s.Format) {
+		return fmt.Errorf("invalid schema: %s: unsupported format '%s'", path, s.Format)
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid schema: %s: invalid pattern: %v", path, err)
+		}
+		s.pattern = pattern
+	}
+	for _, value := range s.Enum {
+		switch value.(type) {
+		case string, float64, bool, nil:
+		default:
+			return fmt.Errorf("invalid schema: %s: enum values must be strings, numbers or booleans", path)
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			return fmt.Errorf("invalid schema: %s: required property '%s' is not defined", path, name)
+		}
+	}
+
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("invalid schema: %s.%s: empty schema", path, name)
+		}
+		if err := property.compile(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile(path + "[]")
+	}
+	return nil
+}
+
+// Validate - Cek value terhadap schema. value boleh hasil decode JSON atau
+// BSON (dinormalisasi lewat JSON). field = nama root untuk path error.
+func (s *JSONSchema) Validate(field string, value interface{}) []SchemaError {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return []SchemaError{{Field: field, Message: "value is not valid JSON"}}
+	}
+
+	var normalized interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return []SchemaError{{Field: field, Message: "value is not valid JSON"}}
+	}
+
+	// details kosong diperlakukan sebagai object kosong (supaya required terlapor)
+	if normalized == nil && s.Type == "object" {
+		normalized = map[string]interface{}{}
+	}
+
+	var errs []SchemaError
+	s.validate(field, normalized, &errs)
+	return errs
+}
+
+func (s *JSONSchema) validate(path string, value interface{}, errs *[]SchemaError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, SchemaError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !matchesType(s.Type, value) {
+		fail("must be of type %s", s.Type)
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		fail("must be one of %s", enumList(s.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, SchemaError{Field: path + "." + name, Message: "is required"})
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := s.Properties[key]; ok {
+				property.validate(path+"."+key, v[key], errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, SchemaError{Field: path + "." + key, Message: "is not allowed"})
+			}
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match pattern %s", s.Pattern)
+		}
+		if s.Format != "" && !matchesFormat(s.Format, v) {
+			fail("must be a valid %s", s.Format)
+		}
+
+	case json.Number:
+		number, _ := v.Float64()
+		if s.Minimum != nil && number < *s.Minimum {
+			fail("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && number > *s.Maximum {
+			fail("must be <= %v", *s.Maximum)
+		}
+	}
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := number.Int64(); err == nil {
+			return true
+		}
+		f, err := number.Float64()
+		return err == nil && f == float64(int64(f))
+	}
+	return false
+}
+
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != "" && parsed.Host != ""
+	}
+	return true
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	if number, ok := value.(json.Number); ok {
+		f, _ := number.Float64()
+		value = f
+	}
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, value := range enum {
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, ", ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
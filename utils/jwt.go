@@ -1,60 +1,178 @@
 package utils
 
 import (
-	"time"
+	"errors"
+	"log"
 	"project_uas/app/model"
 	"project_uas/config"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var JwtKey []byte
+var jwtKeys *KeySet
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenTypeMFA     = "mfa_pending"
+
+	AccessTokenTTL  = 2 * time.Hour
+	RefreshTokenTTL = 7 * 24 * time.Hour
+	MFATokenTTL     = 5 * time.Minute
+)
+
+var (
+	ErrInvalidTokenType    = errors.New("invalid token type")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// InitJWT - Initialize JWT keys from config
+func InitJWT() error {
+	cfg := config.AppConfig
+
+	var ks *KeySet
+	var err error
+	if cfg.JWTKeysDir != "" {
+		ks, err = LoadKeySet(cfg.JWTKeysDir, cfg.JWTSigningKeyID)
+	} else if cfg.IsDevelopment() {
+		log.Println("JWT_KEYS_DIR not set, using ephemeral Ed25519 key (development only)")
+		ks, err = NewEphemeralKeySet()
+	} else {
+		err = ErrNoSigningKey
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.JWTAcceptHS256 {
+		ks.AcceptHS256([]byte(cfg.JWTSecret))
+	}
 
-// InitJWT - Initialize JWT key from config
-func InitJWT() {
-	JwtKey = []byte(config.AppConfig.JWTSecret)
+	SetKeySet(ks)
+	log.Printf("JWT signing key: %s", ks.SigningKeyID())
+	return nil
 }
 
-func GenerateJWT(user model.UserResponse) (string, error) {
+// SetKeySet - Ganti key set aktif (dipakai InitJWT dan test)
+func SetKeySet(ks *KeySet) {
+	jwtKeys = ks
+}
+
+// JWKS - Public key untuk endpoint /.well-known/jwks.json
+func JWKS() JWKSet {
+	return jwtKeys.JWKS()
+}
+
+// GenerateJWT - issuedAt = iat (lihat TokenRevocationService.IssuedAt)
+func GenerateJWT(user model.UserResponse, sessionID string, issuedAt time.Time) (string, error) {
 
 	claims := &model.JWTClaims{
 		UserID:      user.ID,
 		Username:    user.Username,
-		Role:        user.Role,
+		Roles:       user.Roles,
+		RoleScopes:  user.RoleScopes,
 		Permissions: user.Permissions,
+		TokenType:   TokenTypeAccess,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Hour)),
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(AccessTokenTTL)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JwtKey)
+	return jwtKeys.Sign(claims)
 }
 
-func GenerateRefreshToken(userID string) (string, error) {
+// GenerateRefreshToken - tokenID menjadi jti, sessionID = family_id di tabel refresh_tokens.
+// role = role yang dipilih saat login, supaya pilihan tetap berlaku setelah refresh.
+func GenerateRefreshToken(userID, tokenID, sessionID, role string, expiresAt time.Time) (string, error) {
 
 	claims := &model.RefreshTokenClaims{
-		UserID: userID,
+		UserID:    userID,
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JwtKey)
+	return jwtKeys.Sign(claims)
 }
 
 func ValidateToken(tokenStr string) (*model.JWTClaims, error) {
 
 	claims := &model.JWTClaims{}
 
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
-		return JwtKey, nil
-	})
+	token, err := jwtKeys.Parse(tokenStr, claims)
 
 	if err != nil || !token.Valid {
 		return nil, err
 	}
 
+	// Refresh token tidak boleh dipakai sebagai access token
+	if claims.TokenType != TokenTypeAccess {
+		return nil, ErrInvalidTokenType
+	}
+
 	return claims, nil
-}
\ No newline at end of file
+}
+
+func ValidateRefreshToken(tokenStr string) (*model.RefreshTokenClaims, error) {
+
+	claims := &model.RefreshTokenClaims{}
+
+	token, err := jwtKeys.Parse(tokenStr, claims)
+
+	// Parse bisa mengembalikan token tidak valid tanpa error; jangan sampai
+	// return (nil, nil)
+	if err != nil || token == nil || !token.Valid {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if claims.TokenType != TokenTypeRefresh || claims.ID == "" {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
+// GenerateMFAToken - Token sementara antara cek password dan cek kode TOTP
+func GenerateMFAToken(userID, username, role string, enrollmentRequired bool) (string, error) {
+
+	claims := &model.MFAPendingClaims{
+		UserID:             userID,
+		Username:           username,
+		TokenType:          TokenTypeMFA,
+		EnrollmentRequired: enrollmentRequired,
+		Role:               role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(MFATokenTTL)),
+		},
+	}
+
+	return jwtKeys.Sign(claims)
+}
+
+func ValidateMFAToken(tokenStr string) (*model.MFAPendingClaims, error) {
+
+	claims := &model.MFAPendingClaims{}
+
+	token, err := jwtKeys.Parse(tokenStr, claims)
+
+	if err != nil || !token.Valid {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeMFA {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,253 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+//
+// ==================== JWT KEY SET ======================
+// Token di-sign dengan satu key aktif (RS256 / EdDSA) dan header "kid".
+// Semua key di JWT_KEYS_DIR dipakai untuk verifikasi, jadi key lama tetap
+// valid selama rotasi. Public key dipublikasikan lewat /.well-known/jwks.json.
+//
+
+const minRSAKeyBits = 2048
+
+var (
+	ErrUnknownKeyID    = errors.New("unknown key id")
+	ErrNoSigningKey    = errors.New("no signing key configured")
+	errUnsupportedType = errors.New("unsupported key type")
+)
+
+type JWTKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey // nil untuk key yang hanya dipakai verifikasi
+	PublicKey  crypto.PublicKey
+}
+
+type KeySet struct {
+	signing      *JWTKey
+	keys         map[string]*JWTKey
+	legacySecret []byte // verifikasi token HS256 lama (tanpa kid)
+}
+
+// JWK - Public key dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeySet - Baca semua <kid>.pem di dir. signingKeyID boleh kosong jika hanya ada satu private key.
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{keys: make(map[string]*JWTKey)}
+	var privateIDs []string
+
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parsePEMKey(kid, raw)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", kid, err)
+		}
+
+		ks.keys[kid] = key
+		if key.PrivateKey != nil {
+			privateIDs = append(privateIDs, kid)
+		}
+	}
+
+	if signingKeyID == "" && len(privateIDs) == 1 {
+		signingKeyID = privateIDs[0]
+	}
+
+	signing, ok := ks.keys[signingKeyID]
+	if !ok || signing.PrivateKey == nil {
+		return nil, fmt.Errorf("%w (JWT_SIGNING_KEY_ID=%q)", ErrNoSigningKey, signingKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// NewEphemeralKeySet - Key Ed25519 sementara (hanya untuk development / test).
+// Token jadi tidak valid setelah restart.
+func NewEphemeralKeySet() (*KeySet, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := GenerateRandomToken(8)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{
+		ID:         "dev-" + kid,
+		Method:     jwt.SigningMethodEdDSA,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}
+
+	return &KeySet{
+		signing: key,
+		keys:    map[string]*JWTKey{key.ID: key},
+	}, nil
+}
+
+// AcceptHS256 - Tetap terima token HS256 lama selama masa migrasi
+func (ks *KeySet) AcceptHS256(secret []byte) {
+	ks.legacySecret = secret
+}
+
+// SigningKeyID - kid yang dipakai untuk token baru
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.ID
+}
+
+// Sign - Sign claims dengan key aktif dan set header kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.PrivateKey)
+}
+
+// Parse - Verifikasi signature berdasarkan kid (atau HS256 lama jika diizinkan)
+func (ks *KeySet) Parse(tokenStr string, claims jwt.Claims) (*jwt.Token, error) {
+	methods := []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+	if ks.legacySecret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	return jwt.ParseWithClaims(tokenStr, claims, ks.keyFunc, jwt.WithValidMethods(methods))
+}
+
+func (ks *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	if t.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return ks.legacySecret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	// alg di header harus sama dengan tipe key (mencegah algorithm confusion)
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return key.PublicKey, nil
+}
+
+// JWKS - Semua public key untuk verifikasi (urut berdasarkan kid)
+func (ks *KeySet) JWKS() JWKSet {
+	ids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		ids = append(ids, kid)
+	}
+	sort.Strings(ids)
+
+	set := JWKSet{Keys: make([]JWK, 0, len(ids))}
+	for _, kid := range ids {
+		key := ks.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// parsePEMKey - Terima private key (PKCS#8 / PKCS#1) atau public key (PKIX)
+func parsePEMKey(kid string, raw []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM %s", errUnsupportedType, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errUnsupportedType
+	}
+
+	if rsaKey, ok := key.PublicKey.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+	}
+	return key, nil
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//
+// ==================== MAILER ======================
+// Service hanya bergantung pada interface Mailer, sehingga implementasi
+// pengiriman (SMTP, API provider, dll.) bisa diganti tanpa mengubah service.
+//
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+// FileOutboxMailer - Menulis email sebagai file .eml ke folder outbox
+// (untuk development lokal dan test, tidak benar-benar mengirim email)
+type FileOutboxMailer struct {
+	Dir string
+}
+
+func NewFileOutboxMailer(dir string) (*FileOutboxMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileOutboxMailer{Dir: dir}, nil
+}
+
+func (m *FileOutboxMailer) Send(mail Mail) error {
+	now := time.Now()
+	filename := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), uuid.New().String())
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&content, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&content, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.WriteString(mail.Body)
+
+	return os.WriteFile(filepath.Join(m.Dir, filename), []byte(content.String()), 0o600)
+}